@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -16,14 +17,17 @@ import (
 	_ "github.com/joho/godotenv/autoload"
 	_ "github.com/lib/pq"
 	"github.com/mccune1224/betrayal/internal/commands/action"
+	"github.com/mccune1224/betrayal/internal/commands/alliance"
 	"github.com/mccune1224/betrayal/internal/commands/buy"
 	"github.com/mccune1224/betrayal/internal/commands/channels"
 	"github.com/mccune1224/betrayal/internal/commands/cycle"
 	"github.com/mccune1224/betrayal/internal/commands/echo"
+	"github.com/mccune1224/betrayal/internal/commands/game"
 	"github.com/mccune1224/betrayal/internal/commands/healthcheck"
 	"github.com/mccune1224/betrayal/internal/commands/help"
 	"github.com/mccune1224/betrayal/internal/commands/inv"
 	"github.com/mccune1224/betrayal/internal/commands/list"
+	"github.com/mccune1224/betrayal/internal/commands/poll"
 	"github.com/mccune1224/betrayal/internal/commands/roll"
 	"github.com/mccune1224/betrayal/internal/commands/search"
 	"github.com/mccune1224/betrayal/internal/commands/setup"
@@ -35,7 +39,11 @@ import (
 	"github.com/mccune1224/betrayal/internal/discord"
 	"github.com/mccune1224/betrayal/internal/logger"
 	"github.com/mccune1224/betrayal/internal/models"
+	cyclesvc "github.com/mccune1224/betrayal/internal/services/cycle"
 	"github.com/mccune1224/betrayal/internal/services/datasync"
+	"github.com/mccune1224/betrayal/internal/services/invsnapshot"
+	"github.com/mccune1224/betrayal/internal/services/lifeboard"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	"github.com/mccune1224/betrayal/internal/util"
 	"github.com/mccune1224/betrayal/internal/web"
 	"github.com/rs/zerolog"
@@ -156,6 +164,15 @@ func main() {
 	if err := dbmigrate.EnsureUpToDate(cfg.database.dsn); err != nil {
 		log.Fatalf("Failed to apply database migrations before startup: %v", err)
 	}
+	// Guilds with their own game use their own schema; see package tenancy.
+	games := tenancy.NewRegistry(pools, cfg.database.dsn)
+	defer games.Close()
+	if err := games.MigrateAll(context.Background()); err != nil {
+		log.Fatalf("Failed to apply game schema migrations before startup: %v", err)
+	}
+	tenancy.Use(games)
+	// Inventories are snapshotted on every cycle change, with or without Discord.
+	invsnapshot.RegisterCycleHooks(pools)
 
 	// Initialize the logger exactly once, with database support.
 	appLogger, err := logger.Init(logger.Config{
@@ -188,6 +205,9 @@ func main() {
 
 	// Initialize audit writer
 	logger.InitAuditWriter(pools, env)
+	logger.GetAuditWriter().SetPoolResolver(func(guildID string) *pgxpool.Pool {
+		return tenancy.PoolFor(guildID, pools)
+	})
 	defer logger.CloseAuditWriter()
 
 	// Create Ken instance with logger integration when Discord is enabled
@@ -229,6 +249,7 @@ func main() {
 
 		application.betrayalManager = km
 
+		pollCommand := new(poll.Poll)
 		tally := application.RegisterBetrayalCommands(
 			new(inv.Inv),
 			new(roll.Roll),
@@ -241,12 +262,17 @@ func main() {
 			new(whisper.Whisper),
 			new(whisper.WhisperAdmin),
 			new(setup.Setup),
+			new(setup.Roster),
+			new(setup.Draft),
 			new(echo.Echo),
 			new(list.List),
 			new(search.Search),
 			new(healthcheck.Healthcheck),
 			new(cycle.Cycle),
+			new(game.Game),
+			new(alliance.Alliance),
 			new(tarot.Tarot),
+			pollCommand,
 		)
 
 		application.betrayalManager.Session().AddHandler(application.logHandler)
@@ -254,6 +280,7 @@ func main() {
 			appLogger.Fatal().Err(err).Msg("Failed to register audit middleware")
 		}
 		application.betrayalManager.Session().AddHandler(paginationHandler)
+		application.betrayalManager.Session().AddHandler(pollCommand.ComponentHandler)
 		defer application.betrayalManager.Unregister()
 
 		if err = bot.Open(); err != nil {
@@ -265,6 +292,45 @@ func main() {
 			Str("bot_name", bot.State.User.Username).
 			Int("command_count", tally).
 			Msg("Bot initialized and running")
+
+		// Every cycle change is announced to the broadcast targets and
+		// re-renders the lifeboard so it shows the phase.
+		cyclesvc.RegisterAnnounceHook(pools, bot)
+		lifeboard.RegisterCycleHooks(pools, bot)
+
+		// Scheduled cycle changes run in the bot process so they can announce;
+		// boundaries are claimed in the database, so extra processes are harmless.
+		// Every game has its own schedule, stopped when the game is deleted.
+		schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+		defer stopScheduler()
+		cyclesvc.NewScheduler(pools, bot).Start(schedulerCtx, appLogger)
+		var schedulersMu sync.Mutex
+		stopGameScheduler := map[string]context.CancelFunc{}
+		startGameScheduler := func(guildID string, pool *pgxpool.Pool) {
+			ctx, cancel := context.WithCancel(schedulerCtx)
+			schedulersMu.Lock()
+			stopGameScheduler[guildID] = cancel
+			schedulersMu.Unlock()
+			cyclesvc.NewScheduler(pool, bot).Start(ctx, appLogger)
+		}
+		games.OnCreate(func(game models.Game, pool *pgxpool.Pool) {
+			startGameScheduler(game.GuildID, pool)
+		})
+		games.OnDelete(func(game models.Game) {
+			schedulersMu.Lock()
+			defer schedulersMu.Unlock()
+			if cancel, ok := stopGameScheduler[game.GuildID]; ok {
+				cancel()
+				delete(stopGameScheduler, game.GuildID)
+			}
+		})
+		if existing, err := games.Games(context.Background()); err != nil {
+			appLogger.Error().Err(err).Msg("Failed to list games; only the primary game is scheduled")
+		} else {
+			for _, game := range existing {
+				startGameScheduler(game.GuildID, tenancy.PoolFor(game.GuildID, pools))
+			}
+		}
 	} else {
 		appLogger.Info().Msg("Discord functionality disabled; running web server only")
 		if strings.Contains(cfg.database.dsn, "roundhouse.proxy.rlwy.net") {
@@ -289,6 +355,7 @@ func main() {
 			Port:             cfg.web.port,
 			AdminPassword:    cfg.web.adminPassword,
 			DatabaseURL:      cfg.database.dsn,
+			Games:            games,
 			Environment:      env,
 			SyncEnvURLs:      datasync.EnvURLsFromEnv(),
 			RailwayToken:     cfg.web.railwayToken,
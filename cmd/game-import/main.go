@@ -0,0 +1,96 @@
+// Command game-import restores a game from an archive bundle downloaded from
+// the admin panel (/api/v1/admin/archives/:id/download) into a database whose
+// game is empty. It only validates unless -apply is given.
+//
+//	game-import -file betrayal-archive-3.json
+//	game-import -file betrayal-archive-3.json -apply
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/mccune1224/betrayal/internal/services/gameimport"
+)
+
+func main() {
+	file := flag.String("file", "", "archive bundle to import (JSON)")
+	apply := flag.Bool("apply", false, "commit the import; without it only the dry-run report is printed")
+	dsn := flag.String("dsn", os.Getenv("DATABASE_URL"), "target database (defaults to DATABASE_URL)")
+	flag.Parse()
+
+	if *file == "" {
+		fmt.Fprintln(os.Stderr, "usage: game-import -file <bundle.json> [-apply] [-dsn <url>]")
+		os.Exit(2)
+	}
+	if *dsn == "" {
+		fmt.Fprintln(os.Stderr, "DATABASE_URL is not set and no -dsn was given")
+		os.Exit(2)
+	}
+	if *apply && os.Getenv("DATABASE_POOLER_URL") != "" && *dsn == os.Getenv("DATABASE_POOLER_URL") {
+		fmt.Fprintln(os.Stderr, "refusing to import into DATABASE_POOLER_URL (production); use the admin panel instead")
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "open bundle: %v\n", err)
+		os.Exit(1)
+	}
+	bundle, err := gameimport.Decode(f)
+	f.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	pool, err := pgxpool.New(ctx, *dsn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "connect: %v\n", err)
+		os.Exit(1)
+	}
+	defer pool.Close()
+
+	report, err := gameimport.New(pool).Import(ctx, bundle, !*apply)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import failed, nothing was committed: %v\n", err)
+		os.Exit(1)
+	}
+	printReport(report)
+	if len(report.Errors) > 0 {
+		os.Exit(1)
+	}
+}
+
+func printReport(r gameimport.Report) {
+	mode := "Dry run"
+	if r.Committed {
+		mode = "Imported"
+	}
+	fmt.Printf("%s: bundle v%d %q\n", mode, r.Version, r.Label)
+	tables := make([]string, 0, len(r.Counts))
+	for table := range r.Counts {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		fmt.Printf("  %-22s %d\n", table, r.Counts[table])
+	}
+	if len(r.Warnings) > 0 {
+		fmt.Printf("Warnings:\n  %s\n", strings.Join(r.Warnings, "\n  "))
+	}
+	if len(r.Errors) > 0 {
+		fmt.Printf("Errors (nothing was committed):\n  %s\n", strings.Join(r.Errors, "\n  "))
+	} else if !r.Committed {
+		fmt.Println("Valid. Re-run with -apply to import.")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/mccune1224/betrayal/internal/discord"
 	"github.com/mccune1224/betrayal/internal/models"
 	"github.com/mccune1224/betrayal/internal/services/inventory"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	"github.com/mccune1224/betrayal/internal/util"
 	"github.com/zekrotja/ken"
 )
@@ -64,6 +65,7 @@ func (*Action) Options() []*discordgo.ApplicationCommandOption {
 // Run implements ken.SlashCommand.
 func (a *Action) Run(ctx ken.Context) (err error) {
 	defer logger.RecoverWithLog(*logger.Get())
+	a = &Action{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), a.dbPool)}
 
 	return ctx.HandleSubCommands(
 		ken.SubCommandHandler{Name: "request", Run: a.request},
@@ -0,0 +1,415 @@
+package alliance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	alliancesvc "github.com/mccune1224/betrayal/internal/services/alliance"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
+	"github.com/mccune1224/betrayal/internal/util"
+	"github.com/zekrotja/ken"
+)
+
+type Alliance struct {
+	dbPool *pgxpool.Pool
+}
+
+func (a *Alliance) Initialize(pool *pgxpool.Pool) {
+	a.dbPool = pool
+}
+
+var _ ken.SlashCommand = (*Alliance)(nil)
+
+// Name implements ken.SlashCommand.
+func (*Alliance) Name() string {
+	return "alliance"
+}
+
+// Description implements ken.SlashCommand.
+func (*Alliance) Description() string {
+	return "Create, join and leave alliances"
+}
+
+// Version implements ken.SlashCommand.
+func (*Alliance) Version() string {
+	return "1.0.0"
+}
+
+func nameArg() *discordgo.ApplicationCommandOption {
+	return discord.StringCommandArg("name", "Alliance name", true)
+}
+
+// Options implements ken.SlashCommand.
+func (*Alliance) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "create",
+			Description: "Start a new alliance with its own private channel",
+			Options:     []*discordgo.ApplicationCommandOption{nameArg()},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "invite",
+			Description: "Invite a player to an alliance you are in",
+			Options:     []*discordgo.ApplicationCommandOption{discord.UserCommandArg(true), nameArg()},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "accept",
+			Description: "Accept an alliance invite",
+			Options:     []*discordgo.ApplicationCommandOption{nameArg()},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "leave",
+			Description: "Leave an alliance or decline its invite",
+			Options:     []*discordgo.ApplicationCommandOption{nameArg()},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "list",
+			Description: "List your alliances and invites",
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+			Name:        "admin",
+			Description: "Admin only: approve and manage alliances",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "pending",
+					Description: "List alliances and join requests awaiting approval",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "approve",
+					Description: "Approve a new alliance, or a player's join request",
+					Options:     []*discordgo.ApplicationCommandOption{nameArg(), discord.UserCommandArg(false)},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "decline",
+					Description: "Decline a new alliance, or a player's join request",
+					Options:     []*discordgo.ApplicationCommandOption{nameArg(), discord.UserCommandArg(false)},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "wipe",
+					Description: "Disband an alliance and delete its channel",
+					Options:     []*discordgo.ApplicationCommandOption{nameArg()},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "approval",
+					Description: "View or set whether alliances and joins need host approval",
+					Options:     []*discordgo.ApplicationCommandOption{discord.BoolCommandArg("enabled", "Require host approval", false)},
+				},
+			},
+		},
+	}
+}
+
+// Run implements ken.SlashCommand.
+func (a *Alliance) Run(ctx ken.Context) (err error) {
+	defer logger.RecoverWithLog(*logger.Get())
+	a = &Alliance{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), a.dbPool)}
+
+	return ctx.HandleSubCommands(
+		ken.SubCommandHandler{Name: "create", Run: a.create},
+		ken.SubCommandHandler{Name: "invite", Run: a.invite},
+		ken.SubCommandHandler{Name: "accept", Run: a.accept},
+		ken.SubCommandHandler{Name: "leave", Run: a.leave},
+		ken.SubCommandHandler{Name: "list", Run: a.list},
+		ken.SubCommandGroup{Name: "admin", SubHandler: []ken.CommandHandler{
+			ken.SubCommandHandler{Name: "pending", Run: a.pending},
+			ken.SubCommandHandler{Name: "approve", Run: a.approve},
+			ken.SubCommandHandler{Name: "decline", Run: a.decline},
+			ken.SubCommandHandler{Name: "wipe", Run: a.wipe},
+			ken.SubCommandHandler{Name: "approval", Run: a.approval},
+		}},
+	)
+}
+
+func (a *Alliance) create(ctx ken.SubCommandContext) (err error) {
+	playerID, ok, err := a.begin(ctx)
+	if !ok {
+		return err
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	res, err := alliancesvc.New(a.dbPool, ctx.GetSession()).Create(dbCtx, ctx.GetEvent().GuildID, playerID, option(ctx, "name"))
+	if err != nil {
+		return failure(ctx, "Could not create the alliance", err)
+	}
+	if res.Pending {
+		return respond(ctx, "Alliance Requested", fmt.Sprintf("The hosts have been asked to approve **%s**. You will be told in your confessional.", res.Alliance.Name), res.Warnings)
+	}
+	return respond(ctx, "Alliance Created", fmt.Sprintf("**%s** is ready: %s", res.Alliance.Name, discord.MentionChannel(res.Alliance.ChannelID)), res.Warnings)
+}
+
+func (a *Alliance) invite(ctx ken.SubCommandContext) (err error) {
+	playerID, ok, err := a.begin(ctx)
+	if !ok {
+		return err
+	}
+	target, err := util.Atoi64(ctx.Options().GetByName("user").UserValue(ctx).ID)
+	if err != nil {
+		return discord.ErrorMessage(ctx, "Could not send the invite", "That user could not be identified.")
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := alliancesvc.New(a.dbPool, ctx.GetSession()).Invite(dbCtx, playerID, target, option(ctx, "name"))
+	if err != nil {
+		return failure(ctx, "Could not send the invite", err)
+	}
+	return respond(ctx, "Invite Sent", fmt.Sprintf("%s has been invited to **%s**.", discord.MentionUser(util.Itoa64(target)), res.Alliance.Name), res.Warnings)
+}
+
+func (a *Alliance) accept(ctx ken.SubCommandContext) (err error) {
+	playerID, ok, err := a.begin(ctx)
+	if !ok {
+		return err
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := alliancesvc.New(a.dbPool, ctx.GetSession()).Accept(dbCtx, playerID, option(ctx, "name"))
+	if err != nil {
+		return failure(ctx, "Could not accept the invite", err)
+	}
+	if res.Pending {
+		return respond(ctx, "Join Requested", fmt.Sprintf("The hosts have been asked to let you into **%s**.", res.Alliance.Name), res.Warnings)
+	}
+	return respond(ctx, "Alliance Joined", fmt.Sprintf("Welcome to **%s**: %s", res.Alliance.Name, discord.MentionChannel(res.Alliance.ChannelID)), res.Warnings)
+}
+
+func (a *Alliance) leave(ctx ken.SubCommandContext) (err error) {
+	playerID, ok, err := a.begin(ctx)
+	if !ok {
+		return err
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := alliancesvc.New(a.dbPool, ctx.GetSession()).Leave(dbCtx, playerID, option(ctx, "name"))
+	if err != nil {
+		return failure(ctx, "Could not leave the alliance", err)
+	}
+	msg := fmt.Sprintf("You are no longer part of **%s**.", res.Alliance.Name)
+	if res.Alliance.Status == alliancesvc.StatusDisbanded {
+		msg += " Nobody is left, so the alliance has been disbanded."
+	}
+	return respond(ctx, "Alliance Left", msg, res.Warnings)
+}
+
+func (a *Alliance) list(ctx ken.SubCommandContext) (err error) {
+	playerID, ok, err := a.begin(ctx)
+	if !ok {
+		return err
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	memberships, err := alliancesvc.New(a.dbPool, ctx.GetSession()).ForPlayer(dbCtx, playerID)
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to list your alliances")
+	}
+	if len(memberships) == 0 {
+		return discord.WarningMessage(ctx, "No Alliances", "You are not in any alliance. Start one with `/alliance create`.")
+	}
+	lines := make([]string, len(memberships))
+	for i, m := range memberships {
+		switch m.Status {
+		case alliancesvc.MemberActive:
+			lines[i] = fmt.Sprintf("**%s** - %s", m.AllianceName, discord.MentionChannel(m.AllianceChannelID))
+		case alliancesvc.MemberInvited:
+			lines[i] = fmt.Sprintf("**%s** - invited, use `/alliance accept %s`", m.AllianceName, m.AllianceName)
+		default:
+			lines[i] = fmt.Sprintf("**%s** - awaiting host approval", m.AllianceName)
+		}
+	}
+	return ctx.RespondEmbed(&discordgo.MessageEmbed{
+		Title:       "Your Alliances",
+		Description: strings.Join(lines, "\n"),
+		Color:       discord.ColorThemeDiamond,
+	})
+}
+
+func (a *Alliance) pending(ctx ken.SubCommandContext) (err error) {
+	if ok, err := a.beginAdmin(ctx); !ok {
+		return err
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	alliances, requests, err := alliancesvc.New(a.dbPool, ctx.GetSession()).Pending(dbCtx)
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to list pending alliance requests")
+	}
+	if len(alliances) == 0 && len(requests) == 0 {
+		return discord.SuccessfulMessage(ctx, "Nothing Pending", "No alliance or join request is awaiting approval.")
+	}
+	embed := &discordgo.MessageEmbed{Title: "Pending Alliance Requests", Color: discord.ColorThemeDiamond}
+	if len(alliances) > 0 {
+		lines := make([]string, len(alliances))
+		for i, al := range alliances {
+			lines[i] = fmt.Sprintf("**%s** by %s", al.Name, discord.MentionUser(util.Itoa64(al.CreatedBy.Int64)))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "New Alliances", Value: strings.Join(lines, "\n")})
+	}
+	if len(requests) > 0 {
+		lines := make([]string, len(requests))
+		for i, r := range requests {
+			lines[i] = fmt.Sprintf("%s wants to join **%s**", discord.MentionUser(util.Itoa64(r.PlayerID)), r.AllianceName)
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Join Requests", Value: strings.Join(lines, "\n")})
+	}
+	return ctx.RespondEmbed(embed)
+}
+
+func (a *Alliance) approve(ctx ken.SubCommandContext) (err error) {
+	if ok, err := a.beginAdmin(ctx); !ok {
+		return err
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	svc := alliancesvc.New(a.dbPool, ctx.GetSession())
+	name := option(ctx, "name")
+	if user, ok := ctx.Options().GetByNameOptional("user"); ok {
+		playerID, err := util.Atoi64(user.UserValue(ctx).ID)
+		if err != nil {
+			return discord.ErrorMessage(ctx, "Could not approve the request", "That user could not be identified.")
+		}
+		res, err := svc.ApproveMember(dbCtx, name, playerID)
+		if err != nil {
+			return failure(ctx, "Could not approve the request", err)
+		}
+		return respond(ctx, "Join Approved", fmt.Sprintf("%s joined **%s**.", discord.MentionUser(util.Itoa64(playerID)), res.Alliance.Name), res.Warnings)
+	}
+	res, err := svc.ApproveAlliance(dbCtx, ctx.GetEvent().GuildID, name)
+	if err != nil {
+		return failure(ctx, "Could not approve the alliance", err)
+	}
+	return respond(ctx, "Alliance Approved", fmt.Sprintf("**%s** is open: %s", res.Alliance.Name, discord.MentionChannel(res.Alliance.ChannelID)), res.Warnings)
+}
+
+func (a *Alliance) decline(ctx ken.SubCommandContext) (err error) {
+	if ok, err := a.beginAdmin(ctx); !ok {
+		return err
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	svc := alliancesvc.New(a.dbPool, ctx.GetSession())
+	name := option(ctx, "name")
+	if user, ok := ctx.Options().GetByNameOptional("user"); ok {
+		playerID, err := util.Atoi64(user.UserValue(ctx).ID)
+		if err != nil {
+			return discord.ErrorMessage(ctx, "Could not decline the request", "That user could not be identified.")
+		}
+		res, err := svc.DeclineMember(dbCtx, name, playerID)
+		if err != nil {
+			return failure(ctx, "Could not decline the request", err)
+		}
+		return respond(ctx, "Join Declined", fmt.Sprintf("%s will not join **%s**.", discord.MentionUser(util.Itoa64(playerID)), res.Alliance.Name), res.Warnings)
+	}
+	res, err := svc.DeclineAlliance(dbCtx, name)
+	if err != nil {
+		return failure(ctx, "Could not decline the alliance", err)
+	}
+	return respond(ctx, "Alliance Declined", fmt.Sprintf("**%s** will not be created.", res.Alliance.Name), res.Warnings)
+}
+
+func (a *Alliance) wipe(ctx ken.SubCommandContext) (err error) {
+	if ok, err := a.beginAdmin(ctx); !ok {
+		return err
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	res, err := alliancesvc.New(a.dbPool, ctx.GetSession()).Wipe(dbCtx, option(ctx, "name"))
+	if err != nil {
+		return failure(ctx, "Could not wipe the alliance", err)
+	}
+	return respond(ctx, "Alliance Wiped", fmt.Sprintf("**%s** has been disbanded and its channel deleted.", res.Alliance.Name), res.Warnings)
+}
+
+func (a *Alliance) approval(ctx ken.SubCommandContext) (err error) {
+	if ok, err := a.beginAdmin(ctx); !ok {
+		return err
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	q := models.New(a.dbPool)
+	if opt, ok := ctx.Options().GetByNameOptional("enabled"); ok {
+		if err := alliancesvc.SetApproval(dbCtx, q, opt.BoolValue()); err != nil {
+			logger.Get().Error().Err(err).Msg("operation failed")
+			return discord.AlexError(ctx, "Failed to save the alliance approval setting")
+		}
+	}
+	if alliancesvc.Approval(dbCtx, q) {
+		return discord.SuccessfulMessage(ctx, "Alliance Approval On", "New alliances and joins wait for a host.")
+	}
+	return discord.SuccessfulMessage(ctx, "Alliance Approval Off", "New alliances and joins take effect immediately.")
+}
+
+// begin defers the reply and resolves the invoking player.
+func (a *Alliance) begin(ctx ken.SubCommandContext) (int64, bool, error) {
+	if err := ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return 0, false, err
+	}
+	event := ctx.GetEvent()
+	if event == nil || event.Member == nil || event.Member.User == nil {
+		return 0, false, discord.ErrorMessage(ctx, "Alliance unavailable", "This command could not identify you.")
+	}
+	playerID, err := util.Atoi64(event.Member.User.ID)
+	if err != nil {
+		return 0, false, discord.ErrorMessage(ctx, "Alliance unavailable", "This command could not identify you.")
+	}
+	return playerID, true, nil
+}
+
+func (a *Alliance) beginAdmin(ctx ken.SubCommandContext) (bool, error) {
+	if err := ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return false, err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return false, discord.NotAdminError(ctx)
+	}
+	return true, nil
+}
+
+func option(ctx ken.SubCommandContext, name string) string {
+	return strings.TrimSpace(ctx.Options().GetByName(name).StringValue())
+}
+
+// failure reports rule violations to the player as they are and anything
+// else as an internal error.
+func failure(ctx ken.SubCommandContext, title string, err error) error {
+	for _, known := range []error{
+		alliancesvc.ErrNotPlayer, alliancesvc.ErrDead, alliancesvc.ErrInvalidName, alliancesvc.ErrNameTaken,
+		alliancesvc.ErrNotFound, alliancesvc.ErrNotMember, alliancesvc.ErrAlreadyMember, alliancesvc.ErrNoInvite,
+		alliancesvc.ErrNotPending, alliancesvc.ErrNoDiscord,
+	} {
+		if errors.Is(err, known) {
+			msg := err.Error()
+			return discord.ErrorMessage(ctx, title, strings.ToUpper(msg[:1])+msg[1:]+".")
+		}
+	}
+	logger.Get().Error().Err(err).Msg("operation failed")
+	return discord.AlexError(ctx, title)
+}
+
+func respond(ctx ken.SubCommandContext, title, msg string, warnings []string) error {
+	if len(warnings) > 0 {
+		return discord.WarningMessage(ctx, title, msg+"\n\n"+strings.Join(warnings, "\n"))
+	}
+	return discord.SuccessfulMessage(ctx, title, msg)
+}
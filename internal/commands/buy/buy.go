@@ -11,6 +11,7 @@ import (
 	"github.com/mccune1224/betrayal/internal/discord"
 	"github.com/mccune1224/betrayal/internal/models"
 	"github.com/mccune1224/betrayal/internal/services/inventory"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	"github.com/mccune1224/betrayal/internal/util"
 	"github.com/zekrotja/ken"
 )
@@ -45,6 +46,7 @@ func (*Buy) Options() []*discordgo.ApplicationCommandOption {
 // Run implements ken.SlashCommand.
 func (b *Buy) Run(ctx ken.Context) (err error) {
 	defer logger.RecoverWithLog(*logger.Get())
+	b = &Buy{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), b.dbPool)}
 
 	if err := ctx.Defer(); err != nil {
 		logger.Get().Error().Err(err).Msg("operation failed")
@@ -117,6 +119,7 @@ func (b *Buy) Run(ctx ken.Context) (err error) {
 		logger.Get().Error().Err(err).Msg("operation failed")
 		return discord.AlexError(ctx, "Failed to update player coins")
 	}
+	inventory.UpdateInventoryMessage(ctx.GetSession())
 	return discord.SuccessfulMessage(ctx, fmt.Sprintf("You bought %s", item.Name), fmt.Sprintf("%d -> %d", player.Coins+item.Cost, player.Coins))
 }
 
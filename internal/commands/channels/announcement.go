@@ -0,0 +1,84 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	cyclesvc "github.com/mccune1224/betrayal/internal/services/cycle"
+	"github.com/zekrotja/ken"
+)
+
+func (c *Channel) announcementCommandGroupBuilder() ken.SubCommandGroup {
+	return ken.SubCommandGroup{Name: "announcement", SubHandler: []ken.CommandHandler{
+		ken.SubCommandHandler{Name: "update", Run: c.updateAnnouncementChannel},
+		ken.SubCommandHandler{Name: "view", Run: c.viewAnnouncementChannel},
+	}}
+}
+
+func (c *Channel) announcementCommandArgBuilder() *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+		Name:        "announcement",
+		Description: "Set the channel that receives game announcements",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "update",
+				Description: "Update the announcement channel",
+				Options: []*discordgo.ApplicationCommandOption{
+					discord.ChannelCommandArg(true),
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "view",
+				Description: "View the current announcement channel",
+			},
+		},
+	}
+}
+
+func (c *Channel) updateAnnouncementChannel(ctx ken.SubCommandContext) (err error) {
+	if err = ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	newChannel := ctx.Options().GetByName("channel").ChannelValue(ctx)
+	q := models.New(c.dbPool)
+	_, err = q.UpsertGameConfig(context.Background(), models.UpsertGameConfigParams{
+		Key:   cyclesvc.AnnouncementChannelConfigKey,
+		Value: newChannel.ID,
+	})
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to update announcement channel")
+	}
+	return discord.SuccessfulMessage(ctx, "Announcement Channel Updated", fmt.Sprintf("Announcement channel updated to %s", newChannel.Mention()))
+}
+
+func (c *Channel) viewAnnouncementChannel(ctx ken.SubCommandContext) (err error) {
+	if err = ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	q := models.New(c.dbPool)
+	channelID, err := q.GetGameConfig(context.Background(), cyclesvc.AnnouncementChannelConfigKey)
+	if err != nil {
+		return discord.ErrorMessage(ctx, "Failed to get announcement channel", "Unable to find announcement channel")
+	}
+
+	return ctx.RespondEmbed(&discordgo.MessageEmbed{
+		Title:       "Current Announcement Channel",
+		Description: fmt.Sprintf("Announcement channel is %s", discord.MentionChannel(channelID)),
+	})
+}
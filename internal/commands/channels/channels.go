@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mccune1224/betrayal/internal/discord"
 	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	"github.com/mccune1224/betrayal/internal/util"
 	"github.com/zekrotja/ken"
 )
@@ -52,12 +53,15 @@ func (c *Channel) Options() []*discordgo.ApplicationCommandOption {
 		c.actionCommandArgBuilder(),
 		c.lifeboardCommandArgBuilder(),
 		c.logCommandArgBuilder(),
+		c.announcementCommandArgBuilder(),
+		c.confessionalCategoryCommandArgBuilder(),
 	}
 }
 
 // Run implements ken.SlashCommand.
 func (c *Channel) Run(ctx ken.Context) (err error) {
 	defer logger.RecoverWithLog(*logger.Get())
+	c = &Channel{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), c.dbPool)}
 
 	return ctx.HandleSubCommands(
 		c.voteCommandGroupBuilder(),
@@ -65,6 +69,8 @@ func (c *Channel) Run(ctx ken.Context) (err error) {
 		c.actionCommandGroupBuilder(),
 		c.lifeboardCommandGroupBuilder(),
 		c.logCommandGroupBuilder(),
+		c.announcementCommandGroupBuilder(),
+		c.confessionalCategoryCommandGroupBuilder(),
 		ken.SubCommandHandler{Name: "confessionals", Run: c.viewConfessionals},
 	)
 }
@@ -0,0 +1,85 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/provision"
+	"github.com/zekrotja/ken"
+)
+
+func (c *Channel) confessionalCategoryCommandGroupBuilder() ken.SubCommandGroup {
+	return ken.SubCommandGroup{Name: "confessional-category", SubHandler: []ken.CommandHandler{
+		ken.SubCommandHandler{Name: "update", Run: c.updateConfessionalCategory},
+		ken.SubCommandHandler{Name: "view", Run: c.viewConfessionalCategory},
+	}}
+}
+
+func (c *Channel) confessionalCategoryCommandArgBuilder() *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+		Name:        "confessional-category",
+		Description: "Set the category new confessionals are created in",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "update",
+				Description: "Update the confessional category",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionChannel,
+						Name:         "category",
+						Description:  "Category to create confessionals in",
+						ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildCategory},
+						Required:     true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "view",
+				Description: "View the current confessional category",
+			},
+		},
+	}
+}
+
+func (c *Channel) updateConfessionalCategory(ctx ken.SubCommandContext) (err error) {
+	if err = ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	category := ctx.Options().GetByName("category").ChannelValue(ctx)
+	q := models.New(c.dbPool)
+	_, err = q.UpsertGameConfig(context.Background(), models.UpsertGameConfigParams{
+		Key:   provision.ConfessionalCategoryConfigKey,
+		Value: category.Name,
+	})
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to update confessional category")
+	}
+	return discord.SuccessfulMessage(ctx, "Confessional Category Updated", fmt.Sprintf("New confessionals will be created in **%s**", category.Name))
+}
+
+func (c *Channel) viewConfessionalCategory(ctx ken.SubCommandContext) (err error) {
+	if err = ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	name := provision.ConfessionalCategory(context.Background(), models.New(c.dbPool))
+	return ctx.RespondEmbed(&discordgo.MessageEmbed{
+		Title:       "Current Confessional Category",
+		Description: fmt.Sprintf("New confessionals are created in **%s**", name),
+	})
+}
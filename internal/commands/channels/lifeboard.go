@@ -2,21 +2,22 @@ package channels
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/mccune1224/betrayal/internal/logger"
-	"sort"
-	"strings"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/mccune1224/betrayal/internal/discord"
 	"github.com/mccune1224/betrayal/internal/models"
-	"github.com/mccune1224/betrayal/internal/util"
+	"github.com/mccune1224/betrayal/internal/services/lifeboard"
 	"github.com/zekrotja/ken"
 )
 
 func (c *Channel) lifeboardCommandGroupBuilder() ken.SubCommandGroup {
 	return ken.SubCommandGroup{Name: "lifeboard", SubHandler: []ken.CommandHandler{
 		ken.SubCommandHandler{Name: "set", Run: c.setLifeboardChannel},
+		ken.SubCommandHandler{Name: "refresh", Run: c.refreshLifeboard},
+		ken.SubCommandHandler{Name: "reveal_roles", Run: c.revealLifeboardRoles},
 	}}
 }
 
@@ -34,6 +35,19 @@ func (c *Channel) lifeboardCommandArgBuilder() *discordgo.ApplicationCommandOpti
 					discord.ChannelCommandArg(true),
 				},
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "refresh",
+				Description: "Re-render the posted lifeboard",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "reveal_roles",
+				Description: "Show or hide the roles of dead players on the lifeboard",
+				Options: []*discordgo.ApplicationCommandOption{
+					discord.BoolCommandArg("enabled", "Show dead players' roles", true),
+				},
+			},
 		},
 	}
 }
@@ -43,10 +57,10 @@ func (c *Channel) setLifeboardChannel(ctx ken.SubCommandContext) (err error) {
 
 	q := models.New(c.dbPool)
 	dbCtx := context.Background()
-	playerStatuses, err := q.ListPlayerLifeboard(dbCtx)
+	msg, err := lifeboard.Message(dbCtx, c.dbPool, ctx.GetSession(), ctx.GetEvent().GuildID)
 	if err != nil {
 		logger.Get().Error().Err(err).Msg("operation failed")
-		return discord.AlexError(ctx, "Failed to get player statuses")
+		return discord.AlexError(ctx, "Failed to build user lifeboard message")
 	}
 
 	oldBoard, _ := q.GetPlayerLifeboard(dbCtx)
@@ -55,12 +69,6 @@ func (c *Channel) setLifeboardChannel(ctx ken.SubCommandContext) (err error) {
 		q.DeletePlayerLifeboard(dbCtx)
 	}
 
-	msg, err := UserLifeboardMessageBuilder(ctx.GetSession(), ctx.GetEvent().GuildID, playerStatuses)
-	if err != nil {
-		logger.Get().Error().Err(err).Msg("operation failed")
-		return discord.AlexError(ctx, "Failed to build user lifeboard message")
-	}
-
 	sentMsg, err := ctx.GetSession().ChannelMessageSendEmbed(targetChannel.ID, msg)
 	if err != nil {
 		logger.Get().Error().Err(err).Msg("operation failed")
@@ -85,51 +93,45 @@ func (c *Channel) setLifeboardChannel(ctx ken.SubCommandContext) (err error) {
 	return discord.SuccessfulMessage(ctx, "Lifeboard Channel Set", fmt.Sprintf("Lifeboard set in %s", targetChannel.Mention()))
 }
 
-func UserLifeboardMessageBuilder(sesh *discordgo.Session, guildID string, playerStatuses []models.ListPlayerLifeboardRow) (*discordgo.MessageEmbed, error) {
-	aliveTally := 0
-	fields := []*discordgo.MessageEmbedField{}
-
-	// temporary struct so that I can sort by alive status as well as by Nick
-	type MemberAlive struct {
-		Member *discordgo.Member
-		Alive  bool
+func (c *Channel) refreshLifeboard(ctx ken.SubCommandContext) (err error) {
+	if err = ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
 	}
-	activePlayers := []MemberAlive{}
-	for _, s := range playerStatuses {
-		dgMember, _ := sesh.GuildMember(guildID, util.Itoa64(s.ID))
-		activePlayers = append(activePlayers, MemberAlive{dgMember, s.Alive})
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
 	}
+	err = lifeboard.Refresh(context.Background(), c.dbPool, ctx.GetSession())
+	if errors.Is(err, lifeboard.ErrNotConfigured) {
+		return discord.ErrorMessage(ctx, "No Lifeboard", "Run `/channel lifeboard set` to post one.")
+	}
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to refresh the lifeboard")
+	}
+	return discord.SuccessfulMessage(ctx, "Lifeboard Refreshed", "The lifeboard shows the current players and cycle.")
+}
 
-	// should be sorted by alive status first, then by nick
-	sort.Slice(activePlayers, func(i, j int) bool {
-		if activePlayers[i].Alive == activePlayers[j].Alive {
-			l := strings.ToLower(activePlayers[i].Member.DisplayName())
-			r := strings.ToLower(activePlayers[j].Member.DisplayName())
-			return l < r
-		}
-		return activePlayers[i].Alive
-	})
-
-	for i := range activePlayers {
-		name := activePlayers[i].Member.DisplayName()
-		if activePlayers[i].Alive {
-			aliveTally++
-			fields = append(fields, &discordgo.MessageEmbedField{
-				Name: fmt.Sprintf("%s %s", discord.EmojiAlive, name),
-			})
-		} else {
-			fields = append(fields, &discordgo.MessageEmbedField{
-				Name: fmt.Sprintf("%s %s", discord.EmojiDead, name),
-			})
-		}
+func (c *Channel) revealLifeboardRoles(ctx ken.SubCommandContext) (err error) {
+	if err = ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
 	}
-	msg := &discordgo.MessageEmbed{
-		Title:       "Player Status Board",
-		Description: fmt.Sprintf("%d/%d players alive", aliveTally, len(playerStatuses)),
-		Fields:      fields,
-		Footer: &discordgo.MessageEmbedFooter{
-			Text: "Last updated: " + util.GetEstTimeStamp() + " (EST)",
-		},
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	enabled := ctx.Options().GetByName("enabled").BoolValue()
+	dbCtx := context.Background()
+	if err = lifeboard.SetRevealRoles(dbCtx, models.New(c.dbPool), enabled); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to update the lifeboard")
+	}
+	message := "Dead players' roles are hidden."
+	if enabled {
+		message = "Dead players' roles are shown."
+	}
+	if !lifeboard.RefreshQuietly(dbCtx, c.dbPool, ctx.GetSession()) {
+		return discord.WarningMessage(ctx, "Lifeboard Updated", message+" The posted lifeboard could not be refreshed.")
 	}
-	return msg, nil
+	return discord.SuccessfulMessage(ctx, "Lifeboard Updated", message)
 }
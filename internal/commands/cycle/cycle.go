@@ -2,18 +2,15 @@ package cycle
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"github.com/mccune1224/betrayal/internal/logger"
 	"strconv"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mccune1224/betrayal/internal/discord"
-	"github.com/mccune1224/betrayal/internal/models"
 	cyclesvc "github.com/mccune1224/betrayal/internal/services/cycle"
-	"github.com/mccune1224/betrayal/internal/util"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	"github.com/zekrotja/ken"
 )
 
@@ -68,6 +65,23 @@ func (c *Cycle) Options() []*discordgo.ApplicationCommandOption {
 				discord.IntCommandArg("number", "i.e Day [# here]", true),
 			},
 		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Description: "View or set where cycle announcements are posted (shared with the web panel)",
+			Name:        "broadcast",
+			Options: []*discordgo.ApplicationCommandOption{
+				discord.StringCommandArg("targets", "Comma separated: announcement, vote, action, confessionals, alliances", false),
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Description: "List recent phases with their start and end times",
+			Name:        "history",
+			Options: []*discordgo.ApplicationCommandOption{
+				discord.IntCommandArg("limit", "How many phases to show (max 25)", false),
+			},
+		},
+		c.scheduleCommandArgBuilder(),
 	}
 }
 
@@ -79,11 +93,15 @@ func (c *Cycle) Version() string {
 // Run implements ken.SlashCommand.
 func (c *Cycle) Run(ctx ken.Context) (err error) {
 	defer logger.RecoverWithLog(*logger.Get())
+	c = &Cycle{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), c.dbPool)}
 
 	return ctx.HandleSubCommands(
 		ken.SubCommandHandler{Name: "next", Run: c.next},
 		ken.SubCommandHandler{Name: "set", Run: c.set},
-		ken.SubCommandHandler{Name: "current", Run: c.current})
+		ken.SubCommandHandler{Name: "current", Run: c.current},
+		ken.SubCommandHandler{Name: "broadcast", Run: c.broadcast},
+		ken.SubCommandHandler{Name: "history", Run: c.history},
+		c.scheduleCommandGroupBuilder())
 }
 
 func (c *Cycle) current(ctx ken.SubCommandContext) error {
@@ -123,30 +141,23 @@ func (c *Cycle) set(ctx ken.SubCommandContext) error {
 	}
 	phaseNameOpt := ctx.Options().GetByName("phase").StringValue()
 	phaseNumberOpt := ctx.Options().GetByName("number").IntValue()
-	channels, err := c.getCycleChannelIDs(ctx.GetSession(), ctx.GetEvent())
-	if err != nil {
+	// Resolve the broadcast channels up front so a misconfigured target stops
+	// the change; the announce hook posts once it is committed.
+	if _, err := c.getCycleChannelIDs(ctx.GetSession(), ctx.GetEvent()); err != nil {
 		logger.Get().Error().Err(err).Msg("operation failed")
 		return discord.AlexError(ctx, fmt.Sprintf("Failed to get channels for cycle messages: %v", err))
 	}
 	svc := cyclesvc.New(c.dbPool)
 	dbCtx := context.Background()
 	isElimination := phaseNameOpt == "Elimination"
-	updatedCycle, err := svc.Set(dbCtx, isElimination, int32(phaseNumberOpt))
+	report, err := svc.SetPhase(dbCtx, isElimination, int32(phaseNumberOpt), ctx.GetEvent().Member.User.ID)
 
 	if err != nil {
 		logger.Get().Error().Err(err).Msg("operation failed")
 		return discord.AlexError(ctx, "Failed to set cycle")
 	}
 
-	for _, channelID := range channels {
-		_, err := ctx.GetSession().ChannelMessageSend(channelID, cyclesvc.FormatMessage(updatedCycle))
-		if err != nil {
-			logger.Get().Error().Err(err).Msg("operation failed")
-			return discord.AlexError(ctx, err.Error())
-		}
-	}
-
-	return discord.SuccessfulMessage(ctx, "Next Cycle messages posted", "")
+	return respondReport(ctx, report)
 }
 
 type confessionalChannelDetails struct {
@@ -164,77 +175,66 @@ func (c *Cycle) next(ctx ken.SubCommandContext) error {
 	}
 
 	sesh := ctx.GetSession()
-	channelIDSendList, err := c.getCycleChannelIDs(sesh, ctx.GetEvent())
-	if err != nil {
+	if _, err := c.getCycleChannelIDs(sesh, ctx.GetEvent()); err != nil {
 		logger.Get().Error().Err(err).Msg("operation failed")
 		return discord.AlexError(ctx, fmt.Sprintf("Failed to get channels for cycle update messages: %v", err))
 	}
 
 	dbCtx := context.Background()
-	updatedCycle, err := cyclesvc.New(c.dbPool).Increment(dbCtx)
+	report, err := cyclesvc.New(c.dbPool).Advance(dbCtx, ctx.GetEvent().Member.User.ID)
 	if err != nil {
 		logger.Get().Error().Err(err).Msg("operation failed")
 		return discord.AlexError(ctx, "Failed to update game cycle")
 	}
 
-	msg := cyclesvc.FormatMessage(updatedCycle)
+	return respondReport(ctx, report)
+}
 
-	for _, channelID := range channelIDSendList {
-		_, err := sesh.ChannelMessageSend(channelID, msg)
-		if err != nil {
-			logger.Get().Error().Err(err).Msg("operation failed")
-			return discord.AlexError(ctx, err.Error())
-		}
+// respondReport confirms the transition and lists per-hook results, the
+// announcement included, switching to a warning when any hook failed.
+func respondReport(ctx ken.SubCommandContext, report cyclesvc.Report) error {
+	summary := report.Summary()
+	failed := report.Failed()
+	for _, result := range failed {
+		logger.Get().Error().Err(result.Err).Str("hook", result.Name).Msg("cycle hook failed")
 	}
-
-	return discord.SuccessfulMessage(ctx, "Next Cycle messages posted", "")
+	if len(failed) > 0 {
+		return discord.WarningMessage(ctx, "Cycle changed with failures", summary)
+	}
+	return discord.SuccessfulMessage(ctx, "Next Cycle messages posted", summary)
 }
 
-// All channels that need to be notified of the next phase of the game.
-// This includes confessionals, funnel, and alliance channels
-func (c *Cycle) getCycleChannelIDs(sesh *discordgo.Session, event *discordgo.InteractionCreate) ([]string, error) {
-	channels := []string{}
-
-	q := models.New(c.dbPool)
-	dbCtx := context.Background()
-	playerConfessionals, err := q.ListPlayerConfessional(dbCtx)
-	if err != nil {
-		return channels, err
+func (c *Cycle) broadcast(ctx ken.SubCommandContext) error {
+	if err := ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
 	}
-	playerChannelIds := []string{}
-	for _, conf := range playerConfessionals {
-		channel, err := sesh.Channel(util.Itoa64(conf.ChannelID))
-		if err != nil {
-			return channels, fmt.Errorf("Unable to get confessional channel %s", util.Itoa64(conf.ChannelID))
-		}
-		playerChannelIds = append(playerChannelIds, channel.ID)
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
 	}
-
-	actionChannelID, err := q.GetActionChannel(dbCtx)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return channels, fmt.Errorf("action channel not configured (run /channel action update)")
+	broadcaster := cyclesvc.NewBroadcaster(c.dbPool, ctx.GetSession())
+	dbCtx := context.Background()
+	if opt, ok := ctx.Options().GetByNameOptional("targets"); ok {
+		targets, err := cyclesvc.ParseTargets(opt.StringValue())
+		if err != nil {
+			return discord.ErrorMessage(ctx, "Invalid broadcast targets", fmt.Sprintf("%v. Choose from: %s", err, cyclesvc.FormatTargets(cyclesvc.AllTargets)))
 		}
-		return channels, err
-	}
-	voteChannelID, err := q.GetVoteChannel(dbCtx)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return channels, fmt.Errorf("vote channel not configured (run /channel vote update)")
+		if err := broadcaster.SetTargets(dbCtx, targets); err != nil {
+			logger.Get().Error().Err(err).Msg("operation failed")
+			return discord.AlexError(ctx, "Failed to save broadcast targets")
 		}
-		return channels, err
 	}
-	allianceChannelIDs := []string{}
-	allianceChannels, err := discord.GetChannelsWithinCategory(sesh, event, "alliances")
+	targets, err := broadcaster.Targets(dbCtx)
 	if err != nil {
-		logger.Get().Warn().Err(err).Msg("unable to get alliance channels; continuing without alliance broadcasts")
-	} else {
-		for _, ch := range allianceChannels {
-			allianceChannelIDs = append(allianceChannelIDs, ch.ID)
-		}
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to load broadcast targets")
 	}
-	channels = []string{voteChannelID, actionChannelID}
-	channels = append(channels, playerChannelIds...)
-	channels = append(channels, allianceChannelIDs...)
-	return channels, nil
+	return discord.SuccessfulMessage(ctx, "Cycle Broadcast Targets", cyclesvc.FormatTargets(targets),
+		"Targets: "+cyclesvc.FormatTargets(cyclesvc.AllTargets))
+}
+
+// All channels that need to be notified of the next phase of the game, as
+// selected by the broadcast target setting shared with the web panel.
+func (c *Cycle) getCycleChannelIDs(sesh *discordgo.Session, event *discordgo.InteractionCreate) ([]string, error) {
+	return cyclesvc.NewBroadcaster(c.dbPool, sesh).Channels(context.Background(), discord.InteractionGuildID(event))
 }
@@ -0,0 +1,74 @@
+package cycle
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	cyclesvc "github.com/mccune1224/betrayal/internal/services/cycle"
+	"github.com/zekrotja/ken"
+)
+
+// maxHistoryFields is Discord's per-embed field limit.
+const maxHistoryFields = 25
+
+func (c *Cycle) history(ctx ken.SubCommandContext) error {
+	if err := ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	limit := int32(10)
+	if opt, ok := ctx.Options().GetByNameOptional("limit"); ok {
+		limit = int32(min(max(opt.IntValue(), 1), maxHistoryFields))
+	}
+	entries, err := cyclesvc.New(c.dbPool).History(context.Background(), limit)
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Unable to get cycle history")
+	}
+	if len(entries) == 0 {
+		return discord.WarningMessage(ctx, "Cycle History", "No phase changes have been recorded yet.")
+	}
+	return ctx.RespondEmbed(historyEmbed(entries, time.Now()))
+}
+
+func historyEmbed(entries []models.CycleHistory, now time.Time) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       "Cycle History",
+		Description: "Most recent phases first",
+		Color:       discord.ColorThemeDiamond,
+	}
+	for _, entry := range entries {
+		ended := "running"
+		if entry.EndedAt.Valid {
+			ended = fmt.Sprintf("<t:%d:f>", entry.EndedAt.Time.Unix())
+		}
+		value := fmt.Sprintf("<t:%d:f> → %s (%s)", entry.StartedAt.Time.Unix(), ended,
+			cyclesvc.PhaseDuration(entry, now).Round(time.Minute))
+		if by := advancedBy(entry.AdvancedBy); by != "" {
+			value += "\nAdvanced by " + by
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  cyclesvc.PhaseLabel(entry.IsElimination, entry.Day),
+			Value: value,
+		})
+	}
+	return embed
+}
+
+// advancedBy renders the recorded trigger: Discord user IDs become mentions,
+// other sources ("web", "migration") are shown as is.
+func advancedBy(by string) string {
+	if _, err := strconv.ParseUint(by, 10, 64); err == nil {
+		return discord.MentionUser(by)
+	}
+	return by
+}
@@ -0,0 +1,192 @@
+package cycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/logger"
+	cyclesvc "github.com/mccune1224/betrayal/internal/services/cycle"
+	"github.com/zekrotja/ken"
+)
+
+func (c *Cycle) scheduleCommandGroupBuilder() ken.SubCommandGroup {
+	return ken.SubCommandGroup{Name: "schedule", SubHandler: []ken.CommandHandler{
+		ken.SubCommandHandler{Name: "view", Run: c.viewSchedule},
+		ken.SubCommandHandler{Name: "set", Run: c.setSchedule},
+		ken.SubCommandHandler{Name: "clear", Run: c.clearSchedule},
+		ken.SubCommandHandler{Name: "pause", Run: c.pauseSchedule},
+		ken.SubCommandHandler{Name: "resume", Run: c.resumeSchedule},
+	}}
+}
+
+func (c *Cycle) scheduleCommandArgBuilder() *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+		Name:        "schedule",
+		Description: "Advance the cycle automatically at fixed times",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "view",
+				Description: "View the cycle schedule and the next boundary",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "set",
+				Description: "Set the times at which the cycle advances",
+				Options: []*discordgo.ApplicationCommandOption{
+					discord.StringCommandArg("times", "Comma separated 24h times, i.e 08:00,20:00", true),
+					discord.StringCommandArg("days", "Comma separated weekdays (default every day), i.e mon,wed,fri", false),
+					discord.StringCommandArg("timezone", "IANA timezone (default America/New_York)", false),
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "clear",
+				Description: "Remove the cycle schedule",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "pause",
+				Description: "Stop automatic advances until resumed",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "resume",
+				Description: "Resume automatic advances (boundaries missed while paused are skipped)",
+			},
+		},
+	}
+}
+
+func (c *Cycle) viewSchedule(ctx ken.SubCommandContext) error {
+	if err := ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	return c.respondSchedule(ctx, "Cycle Schedule")
+}
+
+func (c *Cycle) setSchedule(ctx ken.SubCommandContext) error {
+	if err := ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	times := ctx.Options().GetByName("times").StringValue()
+	days, timezone := "", ""
+	if opt, ok := ctx.Options().GetByNameOptional("days"); ok {
+		days = opt.StringValue()
+	}
+	if opt, ok := ctx.Options().GetByNameOptional("timezone"); ok {
+		timezone = opt.StringValue()
+	}
+	schedule, err := cyclesvc.ParseSchedule(timezone, times, days)
+	if err != nil {
+		return discord.ErrorMessage(ctx, "Invalid schedule", err.Error())
+	}
+	if err := cyclesvc.NewScheduler(c.dbPool, nil).Save(context.Background(), schedule, time.Now()); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to save cycle schedule")
+	}
+	return c.respondSchedule(ctx, "Cycle Schedule Updated")
+}
+
+func (c *Cycle) clearSchedule(ctx ken.SubCommandContext) error {
+	if err := ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	if err := cyclesvc.NewScheduler(c.dbPool, nil).Clear(context.Background()); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to clear cycle schedule")
+	}
+	return discord.SuccessfulMessage(ctx, "Cycle Schedule Cleared", "The cycle will only change with `/cycle next` or `/cycle set`.")
+}
+
+func (c *Cycle) pauseSchedule(ctx ken.SubCommandContext) error {
+	return c.togglePause(ctx, true)
+}
+
+func (c *Cycle) resumeSchedule(ctx ken.SubCommandContext) error {
+	return c.togglePause(ctx, false)
+}
+
+func (c *Cycle) togglePause(ctx ken.SubCommandContext, paused bool) error {
+	if err := ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	err := cyclesvc.NewScheduler(c.dbPool, nil).SetPaused(context.Background(), paused, time.Now())
+	if errors.Is(err, cyclesvc.ErrNoSchedule) {
+		return discord.ErrorMessage(ctx, "No cycle schedule", "Set one first with `/cycle schedule set`.")
+	}
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to update cycle schedule")
+	}
+	if paused {
+		return c.respondSchedule(ctx, "Cycle Schedule Paused")
+	}
+	return c.respondSchedule(ctx, "Cycle Schedule Resumed")
+}
+
+func (c *Cycle) respondSchedule(ctx ken.SubCommandContext, title string) error {
+	state, err := cyclesvc.NewScheduler(c.dbPool, nil).State(context.Background(), time.Now())
+	if errors.Is(err, cyclesvc.ErrNoSchedule) {
+		return discord.WarningMessage(ctx, title, "No cycle schedule is configured. Use `/cycle schedule set`.")
+	}
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Unable to load cycle schedule")
+	}
+	return ctx.RespondEmbed(scheduleEmbed(title, state))
+}
+
+func scheduleEmbed(title string, state cyclesvc.ScheduleState) *discordgo.MessageEmbed {
+	loc, _ := state.Schedule.Location()
+	lines := make([]string, 0, len(state.Schedule.Boundaries))
+	for _, b := range state.Schedule.Boundaries {
+		days := "every day"
+		if len(b.Days) > 0 {
+			days = strings.Join(b.Days, ", ")
+		}
+		lines = append(lines, fmt.Sprintf("%s (%s)", b.At, days))
+	}
+	status, next := "Active", "None"
+	if state.Paused {
+		status = "⏸️ Paused"
+	}
+	if !state.Next.IsZero() {
+		next = fmt.Sprintf("<t:%d:F> (<t:%d:R>)", state.Next.Unix(), state.Next.Unix())
+	}
+	return &discordgo.MessageEmbed{
+		Title: title,
+		Color: discord.ColorThemeDiamond,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Status", Value: status, Inline: true},
+			{Name: "Timezone", Value: loc.String(), Inline: true},
+			{Name: "Boundaries", Value: strings.Join(lines, "\n")},
+			{Name: "Next Boundary", Value: next},
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Countdown warnings go to the announcement channel 1 hour and 10 minutes before each boundary.",
+		},
+	}
+}
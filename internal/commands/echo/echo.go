@@ -7,6 +7,7 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	"github.com/zekrotja/ken"
 )
 
@@ -58,6 +59,7 @@ func (e *Echo) Options() []*discordgo.ApplicationCommandOption {
 // Run implements ken.SlashCommand.
 func (e *Echo) Run(ctx ken.Context) (err error) {
 	defer logger.RecoverWithLog(*logger.Get())
+	e = &Echo{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), e.dbPool)}
 
 	return ctx.HandleSubCommands(
 		ken.SubCommandHandler{Name: "message", Run: e.message},
@@ -0,0 +1,275 @@
+package game
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
+	"github.com/mccune1224/betrayal/internal/services/wincon"
+	"github.com/zekrotja/ken"
+)
+
+type Game struct {
+	dbPool *pgxpool.Pool
+}
+
+func (g *Game) Initialize(pool *pgxpool.Pool) {
+	g.dbPool = pool
+}
+
+var _ ken.SlashCommand = (*Game)(nil)
+
+// Name implements ken.SlashCommand.
+func (*Game) Name() string {
+	return "game"
+}
+
+// Description implements ken.SlashCommand.
+func (*Game) Description() string {
+	return "Admin only: Check which side is winning, manage win conditions and per-server games"
+}
+
+// Version implements ken.SlashCommand.
+func (*Game) Version() string {
+	return "1.0.0"
+}
+
+// Options implements ken.SlashCommand.
+func (*Game) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "status",
+			Description: "Show alive players per alignment and any met or imminent win condition",
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "rules",
+			Description: "View or replace the win conditions (shared with the web panel)",
+			Options: []*discordgo.ApplicationCommandOption{
+				discord.StringCommandArg("json", `i.e {"alignments":{"GOOD":[{"eliminate":["EVIL"]}]},"roles":{"Jester":[{"dead":true}]}}`, false),
+				discord.BoolCommandArg("reset", "Go back to the default win conditions", false),
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "register",
+			Description: "Give this server its own game, separate from the primary one",
+			Options: []*discordgo.ApplicationCommandOption{
+				discord.StringCommandArg("name", "Name shown in the web panel's game selector", true),
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "unregister",
+			Description: "Delete this server's game and everything in it; the server plays the primary game again",
+			Options: []*discordgo.ApplicationCommandOption{
+				discord.BoolCommandArg("confirm", "Confirm deleting every player, channel and vote of this server's game", true),
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "list",
+			Description: "List the games running on this bot",
+		},
+	}
+}
+
+// Run implements ken.SlashCommand.
+func (g *Game) Run(ctx ken.Context) (err error) {
+	defer logger.RecoverWithLog(*logger.Get())
+	g = &Game{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), g.dbPool)}
+
+	return ctx.HandleSubCommands(
+		ken.SubCommandHandler{Name: "status", Run: g.status},
+		ken.SubCommandHandler{Name: "rules", Run: g.rules},
+		ken.SubCommandHandler{Name: "register", Run: g.register},
+		ken.SubCommandHandler{Name: "unregister", Run: g.unregister},
+		ken.SubCommandHandler{Name: "list", Run: g.list},
+	)
+}
+
+func (g *Game) status(ctx ken.SubCommandContext) (err error) {
+	// The status may spoil the game, so only the invoking host sees it.
+	ctx.SetEphemeral(true)
+	if err = ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	status, err := wincon.New(g.dbPool).Status(dbCtx)
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to evaluate the win conditions")
+	}
+	return ctx.RespondEmbed(wincon.Embed(status))
+}
+
+func (g *Game) rules(ctx ken.SubCommandContext) (err error) {
+	if err = ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	svc := wincon.New(g.dbPool)
+	if opt, ok := ctx.Options().GetByNameOptional("reset"); ok && opt.BoolValue() {
+		if err := models.New(g.dbPool).DeleteGameConfig(dbCtx, wincon.ConfigKey); err != nil {
+			logger.Get().Error().Err(err).Msg("operation failed")
+			return discord.AlexError(ctx, "Failed to reset the win conditions")
+		}
+	} else if opt, ok := ctx.Options().GetByNameOptional("json"); ok {
+		var rules wincon.Rules
+		if err := json.Unmarshal([]byte(opt.StringValue()), &rules); err != nil {
+			return discord.ErrorMessage(ctx, "Invalid JSON", err.Error())
+		}
+		if _, err := svc.SetRules(dbCtx, rules); errors.Is(err, wincon.ErrInvalidRules) {
+			return discord.ErrorMessage(ctx, "Invalid Win Conditions", err.Error())
+		} else if err != nil {
+			logger.Get().Error().Err(err).Msg("operation failed")
+			return discord.AlexError(ctx, "Failed to save the win conditions")
+		}
+	}
+	rules, err := svc.Rules(dbCtx)
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to load the win conditions")
+	}
+	return ctx.RespondEmbed(rulesEmbed(rules))
+}
+
+func (g *Game) register(ctx ken.SubCommandContext) (err error) {
+	if err = ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	games := tenancy.Current()
+	if games == nil {
+		return discord.ErrorMessage(ctx, "Games Unavailable", "This bot only runs the primary game")
+	}
+	name := ctx.Options().GetByName("name").StringValue()
+	// Migrating and seeding a fresh schema takes longer than a plain query.
+	dbCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	game, err := games.Create(dbCtx, discord.InteractionGuildID(ctx.GetEvent()), name)
+	switch {
+	case errors.Is(err, tenancy.ErrGameExists), errors.Is(err, tenancy.ErrInvalidName), errors.Is(err, tenancy.ErrInvalidGuild):
+		return discord.ErrorMessage(ctx, "Cannot Register Game", err.Error())
+	case err != nil:
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to create the game")
+	}
+	return discord.SuccessfulMessage(ctx, "Game Registered",
+		fmt.Sprintf("This server now plays **%s**. Players, channels, votes and config start empty; the catalog was copied from the primary game.", game.Name))
+}
+
+func (g *Game) unregister(ctx ken.SubCommandContext) (err error) {
+	if err = ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	games := tenancy.Current()
+	if games == nil {
+		return discord.ErrorMessage(ctx, "Games Unavailable", "This bot only runs the primary game")
+	}
+	if !ctx.Options().GetByName("confirm").BoolValue() {
+		return discord.WarningMessage(ctx, "Not Confirmed", "Nothing was deleted")
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	game, err := games.Delete(dbCtx, discord.InteractionGuildID(ctx.GetEvent()))
+	if errors.Is(err, tenancy.ErrNoGame) {
+		return discord.ErrorMessage(ctx, "No Game", err.Error())
+	}
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to delete the game")
+	}
+	return discord.SuccessfulMessage(ctx, "Game Deleted",
+		fmt.Sprintf("**%s** was deleted; this server plays the primary game again.", game.Name))
+}
+
+func (g *Game) list(ctx ken.SubCommandContext) (err error) {
+	if err = ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	games := tenancy.Current()
+	if games == nil {
+		return discord.ErrorMessage(ctx, "Games Unavailable", "This bot only runs the primary game")
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	list, err := games.Games(dbCtx)
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to list the games")
+	}
+	guildID := discord.InteractionGuildID(ctx.GetEvent())
+	lines := []string{"- Primary (every server without its own game)"}
+	for _, game := range list {
+		line := fmt.Sprintf("- **%s**: server `%s`", game.Name, game.GuildID)
+		if game.GuildID == guildID {
+			line += " (this server)"
+		}
+		lines = append(lines, line)
+	}
+	return ctx.RespondEmbed(&discordgo.MessageEmbed{Title: "Games", Description: strings.Join(lines, "\n")})
+}
+
+func rulesEmbed(rules wincon.Rules) *discordgo.MessageEmbed {
+	describe := func(conds []wincon.Condition) string {
+		if len(conds) == 0 {
+			return "none"
+		}
+		lines := make([]string, len(conds))
+		for i, c := range conds {
+			lines[i] = "- " + c.String()
+		}
+		return strings.Join(lines, "\n")
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       "Win Conditions",
+		Description: "Checked after every death and revival; any one listed condition wins. Hosts are alerted in the first admin channel.",
+	}
+	for _, a := range []models.Alignment{models.AlignmentGOOD, models.AlignmentEVIL, models.AlignmentNEUTRAL} {
+		if conds, ok := rules.Alignments[a]; ok {
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: string(a), Value: describe(conds)})
+		}
+	}
+	roles := make([]string, 0, len(rules.Roles))
+	for role := range rules.Roles {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	for _, role := range roles {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: fmt.Sprintf("Role: %s", role), Value: describe(rules.Roles[role])})
+	}
+	return embed
+}
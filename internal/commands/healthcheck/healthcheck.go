@@ -9,6 +9,7 @@ import (
 	"github.com/mccune1224/betrayal/internal/discord"
 	"github.com/mccune1224/betrayal/internal/logger"
 	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	"github.com/zekrotja/ken"
 )
 
@@ -45,6 +46,7 @@ func (*Healthcheck) Options() []*discordgo.ApplicationCommandOption {
 // Run implements ken.SlashCommand.
 func (h *Healthcheck) Run(ctx ken.Context) (err error) {
 	defer logger.RecoverWithLog(*logger.Get())
+	h = &Healthcheck{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), h.dbPool)}
 
 	if err = ctx.Defer(); err != nil {
 		logger.Get().Error().Err(err).Msg("operation failed")
@@ -32,6 +32,10 @@ func (h *Help) adminOverview(ctx ken.SubCommandContext) (err error) {
 				Name:  "Cycle",
 				Value: "`/cycle` controls game phases and progression through Day/Elimination cycles. Use `/help admin cycle` for phase management.",
 			},
+			{
+				Name:  "Poll",
+				Value: "`/poll create` posts a button ballot over custom answers or up to 20 players (every alive player, or the ones mentioned in `players`), limited to all, alive, a role, or an alignment. `/poll close` and `/poll results` report the tally.",
+			},
 			{
 				Name:  "Roll",
 				Value: "`/roll` allows you to roll game events, items, and abilities on the fly. Use `/help admin roll` for more information.",
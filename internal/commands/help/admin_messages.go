@@ -27,12 +27,24 @@ func adminInventoryEmbed() *discordgo.MessageEmbed {
 			},
 			{
 				Name:  "Inventory Creation & Deletion",
-				Value: "`/inv create [role] [player]` - Create inventory for a player (run in their confessional to auto-pin). `/inv delete [player]` - Delete inventory and remove pinned message.",
+				Value: "`/inv create [role] [player]` - Create inventory for a player (run in their confessional to auto-pin); the role's starting statuses, immunities, items and bonuses come from its setup in the web catalog. `/inv delete [player]` - Delete inventory and remove pinned message.",
 			},
 			{
 				Name:  "Whitelist Management",
 				Value: "`/inv whitelist [add/remove] [channel]` - Add or remove a channel from the whitelist for inventory commands. `/inv whitelist list` - View all whitelisted channels. Whitelisted channels allow inventory modifications outside confessionals.",
 			},
+			{
+				Name:  "Inventory History",
+				Value: "`/inv diff [day] [to] [player]` - Every inventory is snapshotted when the cycle changes. Shows what changed since the start of a day (or between two days) for one player, or game-wide outside a confessional. Add `elimination:true` to compare elimination phases.",
+			},
+			{
+				Name:  "Notes",
+				Value: "`/inv notes add [note] [tags] [day]` - Host-only notes. `#tags` and `@mentions` in the text are recorded with the author and the cycle day (today unless `day` is set). `/list notes [query] [tag] [day] [player]` - Search every player's notes, i.e `query:protect day:2` for who used a protect ability on Day 2.",
+			},
+			{
+				Name:  "Bulk Changes",
+				Value: "`/inv bulk [kind] [amount] [name]` - Give or take coins, luck, an item, a status or ability charges for every matching player at once (negative amounts remove). Narrow with `alive`, `alignment`, `role`, `status` held or a `players` list. Shows a preview first; nothing changes until you press Apply.",
+			},
 		},
 	}
 
@@ -42,28 +54,28 @@ func adminInventoryEmbed() *discordgo.MessageEmbed {
 func adminAllianceEmbed() *discordgo.MessageEmbed {
 	msg := &discordgo.MessageEmbed{
 		Title:       "Alliance Admin Commands",
-		Description: "Manage alliance requests and approvals. All admin commands follow the flow of `/alliance admin [command] [args]`. Admins act on requests submitted by players.",
+		Description: "Manage alliance requests and approvals. All admin commands follow the flow of `/alliance admin [command] [args]`. Requests only wait for a host while approval is on; hosts are pinged in the first admin channel. Membership history is on the web panel's alliances view.",
 
 		Fields: []*discordgo.MessageEmbedField{
 			{
-				Name:  "View Pending Requests",
-				Value: "`/alliance admin pending` - View all pending alliance requests (creation and invite requests) awaiting approval.",
+				Name:  "Approval",
+				Value: "`/alliance admin approval [enabled]` - View or set whether new alliances and joins need a host's approval.",
 			},
 			{
-				Name:  "Approve Alliance Creation",
-				Value: "`/alliance admin create [alliance name]` - Approve a player's request to create an alliance. This creates the alliance, generates a Discord channel, and adds the requesting player.",
+				Name:  "View Pending Requests",
+				Value: "`/alliance admin pending` - View all pending alliance requests (creation and join requests) awaiting approval.",
 			},
 			{
-				Name:  "Decline Alliance Creation",
-				Value: "`/alliance admin decline [alliance name]` - Reject a player's alliance creation request. Deletes the request and notifies the player in their confessional.",
+				Name:  "Approve",
+				Value: "`/alliance admin approve [alliance name]` - Approve a player's request to create an alliance. This opens the alliance, generates a Discord channel, and adds the requesting player.\n`/alliance admin approve [alliance name] [player]` - Approve a player's request to join an alliance. Adds the player to the alliance channel, then notifies them in their confessional.",
 			},
 			{
-				Name:  "Approve Alliance Invite",
-				Value: "`/alliance admin invite [player] [alliance channel]` - Approve a player's invite to join an alliance. Adds the player to the alliance and channel, then notifies them in their confessional.",
+				Name:  "Decline",
+				Value: "`/alliance admin decline [alliance name] [player]` - Reject an alliance creation request, or a player's join request when a player is given. Notifies the player in their confessional.",
 			},
 			{
 				Name:  "Wipe Alliance",
-				Value: "`/alliance admin wipe [alliance name]` - Remove an alliance entirely. Deletes the alliance, channel, and all pending requests. **This is destructive and cannot be undone.**",
+				Value: "`/alliance admin wipe [alliance name]` - Disband an alliance, delete its channel and drop all pending requests. The membership history is kept. **The channel cannot be restored.**",
 			},
 		},
 	}
@@ -121,6 +133,12 @@ func adminKillEmebd() *discordgo.MessageEmbed {
 			{
 				Value: "`/kill location [channel]`. Set the location to show status board for players that are marked as dead. This board ideally should be put in a channel that is not accessible to all players.",
 			},
+			{
+				Value: "`/game status`. After every death or revival the win conditions are checked; when a side has won or is one death away the hosts are alerted in the first admin channel. `/game rules [json]` views or replaces the conditions.",
+			},
+			{
+				Value: "`/game register [name]`. Gives this server its own game (players, channels, votes, config) so a test game can run beside the primary one. `/game unregister confirm:true` deletes it again and `/game list` shows every game.",
+			},
 		},
 	}
 	return msg
@@ -188,6 +206,10 @@ func adminChannelsEmbed() *discordgo.MessageEmbed {
 				Name:  "Confessionals",
 				Value: "`/channel confessionals` - View all current player confessional channels and their details.",
 			},
+			{
+				Name:  "Announcement Channel",
+				Value: "`/channel announcement update [channel]` - Set the channel that receives game announcements.\n`/channel announcement view` - View the current announcement channel.",
+			},
 		},
 	}
 	return msg
@@ -211,9 +233,17 @@ func adminCycleEmbed() *discordgo.MessageEmbed {
 				Name:  "Manually Set Phase",
 				Value: "`/cycle set [phase] [number]` - Manually override the current phase. Phase options: **Day** or **Elimination**. Example: `/cycle set Day 3` sets the game to Day 3.",
 			},
+			{
+				Name:  "Phase History",
+				Value: "`/cycle history [limit]` - List recent phases with when they started and ended and who advanced them.",
+			},
+			{
+				Name:  "Automatic Schedule",
+				Value: "`/cycle schedule set [times] [days] [timezone]` - Advance the cycle automatically at fixed local times, i.e `20:00`. Countdown warnings post to the announcement channel 1 hour and 10 minutes before.\n`/cycle schedule pause` / `resume` - Hold automatic advances (missed boundaries are skipped).\n`/cycle schedule view` / `clear` - Inspect or remove the schedule.",
+			},
 			{
 				Name:  "Broadcasting",
-				Value: "When advancing to a new cycle, the game automatically broadcasts the new phase to all player confessionals, alliance channels, and funnel channels.",
+				Value: "When the cycle changes (from Discord or the web panel), the new phase is broadcast to the configured targets. By default that is all player confessionals, alliance channels, and funnel channels.\n`/cycle broadcast [targets]` - View or set the targets (announcement, vote, action, confessionals, alliances). Set the announcement channel with `/channel announcement update [channel]`.",
 			},
 		},
 	}
@@ -3,7 +3,9 @@ package help
 import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/discord"
 	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	"github.com/zekrotja/ken"
 )
 
@@ -146,6 +148,7 @@ func (*Help) Options() []*discordgo.ApplicationCommandOption {
 // Run implements ken.SlashCommand.
 func (h *Help) Run(ctx ken.Context) (err error) {
 	defer logger.RecoverWithLog(*logger.Get())
+	h = &Help{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), h.dbPool)}
 
 	return ctx.HandleSubCommands(
 		ken.SubCommandGroup{Name: "player", SubHandler: []ken.CommandHandler{
@@ -56,16 +56,19 @@ func playerInventoryHelpEmbed() *discordgo.MessageEmbed {
 func playerAllianceHelpEmbed() *discordgo.MessageEmbed {
 	msg := &discordgo.MessageEmbed{
 		Title:       "Alliances",
-		Description: "`/alliance` allows you request creating, joining, and leaving alliances. Mostly everything alliance based needs admin approval. Once approved, you will be notified in your confessional.",
+		Description: "`/alliance` allows you to create, join, and leave alliances. Each alliance gets a private channel. If the hosts require approval, you will be notified in your confessional once they decide. Dead members can still read their alliance channels but can no longer write in them.",
 		Fields: []*discordgo.MessageEmbedField{
 			{
-				Value: "`/alliance request [your alliance name]` to request making a new alliance. Once admin approved, you will be notified in your confessional and a channel will be created for your alliance.",
+				Value: "`/alliance create [your alliance name]` to make a new alliance. A channel will be created for your alliance, after admin approval if required.",
 			},
 			{
-				Value: "`/alliance invite [player name] [alliance name]` to invite a player. You must already be a member within the alliance to send the invite. An invite will be sent to the player's confessional. If they accept, they will be added to the alliance channel after admin approval.",
+				Value: "`/alliance invite [player name] [alliance name]` to invite a player. You must already be a member within the alliance to send the invite. An invite will be sent to the player's confessional. If they accept, they will be added to the alliance channel.",
 			},
 			{
-				Value: "`/alliance accept [alliance name]` to request accepting alliance invite. Once an admin approves, you will be added to the alliance channel.",
+				Value: "`/alliance accept [alliance name]` to accept an alliance invite. You will be added to the alliance channel, after admin approval if required.",
+			},
+			{
+				Value: "`/alliance list` to see your alliances and pending invites.",
 			},
 			{
 				Value: "`/alliance leave [alliance name]` to leave the alliance. You will be no longer be associated with the requested alliance and be removed from the alliance channel automatically.",
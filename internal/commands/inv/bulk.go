@@ -0,0 +1,163 @@
+package inv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/bulkinv"
+	"github.com/zekrotja/ken"
+)
+
+// bulkEmbedLimit keeps the per-player list under Discord's description limit.
+const bulkEmbedLimit = 3800
+
+func (i *Inv) bulkCommandArgBuilder() *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommand,
+		Name:        "bulk",
+		Description: "Preview, then apply one change to every matching player",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "kind",
+				Description: "What to change",
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Coins", Value: bulkinv.KindCoins},
+					{Name: "Luck", Value: bulkinv.KindLuck},
+					{Name: "Item", Value: bulkinv.KindItem},
+					{Name: "Status", Value: bulkinv.KindStatus},
+					{Name: "Ability charges", Value: bulkinv.KindAbility},
+				},
+			},
+			discord.IntCommandArg("amount", "Amount to add; negative removes", true),
+			discord.StringCommandArg("name", "Item, status or ability name", false),
+			discord.BoolCommandArg("alive", "Only alive (true) or dead (false) players", false),
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "alignment",
+				Description: "Only players of this alignment",
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Good", Value: string(models.AlignmentGOOD)},
+					{Name: "Neutral", Value: string(models.AlignmentNEUTRAL)},
+					{Name: "Evil", Value: string(models.AlignmentEVIL)},
+				},
+			},
+			discord.StringCommandArg("role", "Only players with this role", false),
+			discord.StatusCommandArg("status", "Only players holding this status", false),
+			discord.StringCommandArg("players", "Only these players (mentions or IDs)", false),
+		},
+	}
+}
+
+func (i *Inv) bulk(ctx ken.SubCommandContext) (err error) {
+	if err = ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	opts := ctx.Options()
+	op := bulkinv.Operation{Kind: opts.GetByName("kind").StringValue(), Amount: int32(opts.GetByName("amount").IntValue())}
+	if opt, ok := opts.GetByNameOptional("name"); ok {
+		op.Name = opt.StringValue()
+	}
+	req := bulkinv.Request{Operations: []bulkinv.Operation{op}}
+	if opt, ok := opts.GetByNameOptional("alive"); ok {
+		alive := opt.BoolValue()
+		req.Filter.Alive = &alive
+	}
+	if opt, ok := opts.GetByNameOptional("alignment"); ok {
+		req.Filter.Alignment = models.Alignment(opt.StringValue())
+	}
+	if opt, ok := opts.GetByNameOptional("role"); ok {
+		req.Filter.Role = opt.StringValue()
+	}
+	if opt, ok := opts.GetByNameOptional("status"); ok {
+		req.Filter.Status = opt.StringValue()
+	}
+	if opt, ok := opts.GetByNameOptional("players"); ok {
+		if req.Filter.PlayerIDs, err = bulkinv.ParsePlayerIDs(opt.StringValue()); err != nil {
+			return discord.ErrorMessage(ctx, "Invalid Players", err.Error())
+		}
+	}
+
+	dbCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	svc := bulkinv.New(i.dbPool, ctx.GetSession())
+	preview, err := svc.Preview(dbCtx, req)
+	if err != nil {
+		return bulkError(ctx, err)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Bulk Change Preview",
+		Description: preview.Summary(bulkEmbedLimit),
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Nothing has changed yet."},
+	}
+	b := ctx.FollowUpEmbed(embed)
+	// The component handlers get their own ctx; keep the command's.
+	sctx := ctx
+	b.AddComponents(func(cb *ken.ComponentBuilder) {
+		cb.AddActionsRow(func(b ken.ComponentAssembler) {
+			b.Add(discordgo.Button{
+				Style:    discordgo.SuccessButton,
+				CustomID: "confirm-inv-bulk",
+				Label:    "Apply",
+			}, logger.WrapKenComponent(func(ctx ken.ComponentContext) bool {
+				applyCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+				defer cancel()
+				// Re-run the filter: the player set may have changed since
+				// the preview.
+				report, err := svc.Apply(applyCtx, req, nil)
+				if err != nil {
+					bulkError(sctx, err)
+					return true
+				}
+				embed := &discordgo.MessageEmbed{
+					Title:       fmt.Sprintf("Bulk Change Applied (by %s)", ctx.User().Username),
+					Description: report.Summary(bulkEmbedLimit),
+					Color:       discord.ColorThemeGreen,
+				}
+				sctx.RespondEmbed(embed)
+				return true
+			}), true)
+			b.Add(discordgo.Button{
+				Style:    discordgo.DangerButton,
+				CustomID: "cancel-inv-bulk",
+				Label:    "Cancel",
+			}, logger.WrapKenComponent(func(ctx ken.ComponentContext) bool {
+				discord.WarningMessage(sctx, "Bulk Change Cancelled", fmt.Sprintf("Cancelled by %s; nothing changed.", ctx.User().Username))
+				return true
+			}), true)
+		}, true).
+			Condition(func(cctx ken.ComponentContext) bool {
+				// Only the host who previewed the change may apply it.
+				return cctx.User().ID == sctx.User().ID
+			})
+	})
+
+	fum := b.Send()
+	return fum.Error
+}
+
+func bulkError(ctx ken.Context, err error) error {
+	switch {
+	case errors.Is(err, bulkinv.ErrNoPlayers):
+		return discord.WarningMessage(ctx, "No Players", "No players match that filter.")
+	case errors.Is(err, bulkinv.ErrUnknownTarget):
+		return discord.ErrorMessage(ctx, "Not Found", err.Error())
+	case errors.Is(err, bulkinv.ErrNoOperations), errors.Is(err, bulkinv.ErrInvalidKind),
+		errors.Is(err, bulkinv.ErrZeroAmount), errors.Is(err, bulkinv.ErrMissingName), errors.Is(err, bulkinv.ErrBadAlignment):
+		return discord.ErrorMessage(ctx, "Invalid Bulk Change", err.Error())
+	}
+	logger.Get().Error().Err(err).Msg("operation failed")
+	return discord.AlexError(ctx, "Failed to run the bulk change; nothing was changed")
+}
@@ -2,13 +2,16 @@ package inv
 
 import (
 	"context"
-	"github.com/mccune1224/betrayal/internal/logger"
+	"errors"
+	"fmt"
 	"math/rand"
+	"strings"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/mccune1224/betrayal/internal/commands/channels"
 	"github.com/mccune1224/betrayal/internal/discord"
-	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/services/death"
 	"github.com/mccune1224/betrayal/internal/services/inventory"
 	"github.com/zekrotja/ken"
 )
@@ -37,9 +40,11 @@ func (i *Inv) deathCommandGroupBuilder() ken.SubCommandGroup {
 	return ken.SubCommandGroup{Name: "death_status", SubHandler: []ken.CommandHandler{
 		ken.SubCommandHandler{Name: "alive", Run: i.setAlive},
 		ken.SubCommandHandler{Name: "dead", Run: i.setDead},
+		ken.SubCommandHandler{Name: "config", Run: i.deathConfig},
 	}}
 }
 func (i *Inv) deathCommandArgBuilder() *discordgo.ApplicationCommandOption {
+	announce := discord.BoolCommandArg("announce", "Announce it in the announcement channel (defaults to the pipeline config)", false)
 	return &discordgo.ApplicationCommandOption{
 		Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
 		Name:        "death_status",
@@ -51,6 +56,7 @@ func (i *Inv) deathCommandArgBuilder() *discordgo.ApplicationCommandOption {
 				Description: "set the player to alive",
 				Options: []*discordgo.ApplicationCommandOption{
 					discord.UserCommandArg(false),
+					announce,
 				},
 			},
 			{
@@ -59,6 +65,26 @@ func (i *Inv) deathCommandArgBuilder() *discordgo.ApplicationCommandOption {
 				Description: "set the player to dead",
 				Options: []*discordgo.ApplicationCommandOption{
 					discord.UserCommandArg(false),
+					announce,
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "config",
+				Description: "View or change what happens when a player dies or is revived",
+				Options: []*discordgo.ApplicationCommandOption{
+					{Type: discordgo.ApplicationCommandOptionRole, Name: "dead_role", Description: "Add a role given to dead players"},
+					{Type: discordgo.ApplicationCommandOptionRole, Name: "alive_role", Description: "Add a role taken from dead players"},
+					discord.BoolCommandArg("clear_roles", "Forget all configured dead and alive roles", false),
+					discord.BoolCommandArg("read_only", "Lock the confessional while the player is dead", false),
+					{
+						Type:         discordgo.ApplicationCommandOptionChannel,
+						Name:         "graveyard",
+						Description:  "Category confessionals move to on death",
+						ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildCategory},
+					},
+					discord.BoolCommandArg("clear_graveyard", "Leave confessionals where they are", false),
+					discord.BoolCommandArg("announce", "Announce deaths and revivals by default", false),
 				},
 			},
 		},
@@ -66,6 +92,14 @@ func (i *Inv) deathCommandArgBuilder() *discordgo.ApplicationCommandOption {
 }
 
 func (i *Inv) setAlive(ctx ken.SubCommandContext) (err error) {
+	return i.setDeathStatus(ctx, true)
+}
+
+func (i *Inv) setDead(ctx ken.SubCommandContext) (err error) {
+	return i.setDeathStatus(ctx, false)
+}
+
+func (i *Inv) setDeathStatus(ctx ken.SubCommandContext, alive bool) (err error) {
 	if err = ctx.Defer(); err != nil {
 		logger.Get().Error().Err(err).Msg("operation failed")
 		return err
@@ -78,39 +112,39 @@ func (i *Inv) setAlive(ctx ken.SubCommandContext) (err error) {
 		logger.Get().Error().Err(err).Msg("operation failed")
 		return discord.AlexError(ctx, "failed to init inv handler")
 	}
-	defer h.UpdateInventoryMessage(ctx.GetSession())
-	if h.GetPlayer().Alive {
-		return discord.ErrorMessage(ctx, "Already Alive", "Player is already alive, bummer...")
+	opts := death.Options{GuildID: ctx.GetEvent().GuildID}
+	if opt, ok := ctx.Options().GetByNameOptional("announce"); ok {
+		announce := opt.BoolValue()
+		opts.Announce = &announce
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	result, err := death.New(i.dbPool, ctx.GetSession()).SetAlive(dbCtx, h.GetPlayer().ID, alive, opts)
+	if errors.Is(err, death.ErrUnchanged) {
+		if alive {
+			return discord.ErrorMessage(ctx, "Already Alive", "Player is already alive, bummer...")
+		}
+		return discord.ErrorMessage(ctx, "Already Dead", "Player is already dead, Great!")
 	}
-	q := models.New(i.dbPool)
-	_, err = q.UpdatePlayerAlive(context.Background(), models.UpdatePlayerAliveParams{
-		ID:    h.GetPlayer().ID,
-		Alive: true,
-	})
 	if err != nil {
 		logger.Get().Error().Err(err).Msg("operation failed")
-		return discord.AlexError(ctx, "Failed to set player alive")
+		return discord.AlexError(ctx, "Failed to update player death status")
 	}
 
-	lifeboard, err := q.GetPlayerLifeboard(context.Background())
-	if err != nil {
-		logger.Get().Error().Err(err).Msg("operation failed")
-		return discord.AlexError(ctx, "Failed to get player lifeboard")
+	title, message := "Player Alive", "Player is now alive\n"+getRandomItem(playerSetAliveMessages)
+	if !alive {
+		title, message = "Player Dead", "Player is now dead\n"+getRandomItem(playerSetDeadMessages)
 	}
-
-	playerLifeStatuses, _ := q.ListPlayerLifeboard(context.Background())
-	msg, err := channels.UserLifeboardMessageBuilder(ctx.GetSession(), ctx.GetEvent().GuildID, playerLifeStatuses)
-	if err != nil {
-		logger.Get().Error().Err(err).Msg("operation failed")
-		return discord.AlexError(ctx, "Failed to build user lifeboard message")
+	if result.LuckyCoin {
+		message += "\n\n**Host/s, this inventory holds the Lucky Coin!**"
 	}
-
-	ctx.GetSession().ChannelMessageEditEmbed(lifeboard.ChannelID, lifeboard.MessageID, msg)
-
-	return discord.SuccessfulMessage(ctx, "Player Alive", "Player is now alive\n"+getRandomItem(playerSetAliveMessages))
+	if len(result.Warnings) > 0 {
+		return discord.WarningMessage(ctx, title, message+"\n\n"+strings.Join(result.Warnings, "\n"))
+	}
+	return discord.SuccessfulMessage(ctx, title, message)
 }
 
-func (i *Inv) setDead(ctx ken.SubCommandContext) (err error) {
+func (i *Inv) deathConfig(ctx ken.SubCommandContext) (err error) {
 	if err = ctx.Defer(); err != nil {
 		logger.Get().Error().Err(err).Msg("operation failed")
 		return err
@@ -118,58 +152,76 @@ func (i *Inv) setDead(ctx ken.SubCommandContext) (err error) {
 	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
 		return discord.NotAdminError(ctx)
 	}
-	h, err := inventory.NewInventoryHandler(ctx, i.dbPool)
+	dbCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	pipeline := death.New(i.dbPool, ctx.GetSession())
+	cfg, err := pipeline.Config(dbCtx)
 	if err != nil {
 		logger.Get().Error().Err(err).Msg("operation failed")
-		return discord.AlexError(ctx, "failed to init inv handler")
+		return discord.AlexError(ctx, "Failed to load the death pipeline config")
 	}
-	defer h.UpdateInventoryMessage(ctx.GetSession())
-	if !h.GetPlayer().Alive {
-		return discord.ErrorMessage(ctx, "Already Dead", "Player is already alive, Great!")
+	opts := ctx.Options()
+	changed := false
+	if opt, ok := opts.GetByNameOptional("clear_roles"); ok && opt.BoolValue() {
+		cfg.DeadRoleIDs, cfg.AliveRoleIDs, changed = nil, nil, true
 	}
-	q := models.New(i.dbPool)
-	_, err = q.UpdatePlayerAlive(context.Background(), models.UpdatePlayerAliveParams{
-		ID:    h.GetPlayer().ID,
-		Alive: false,
-	})
-	if err != nil {
-		logger.Get().Error().Err(err).Msg("operation failed")
-		return discord.AlexError(ctx, "Failed to set player dead")
+	if opt, ok := opts.GetByNameOptional("dead_role"); ok {
+		cfg.DeadRoleIDs, changed = append(cfg.DeadRoleIDs, opt.RoleValue(ctx).ID), true
 	}
-
-	lifeboard, err := q.GetPlayerLifeboard(context.Background())
-	if err != nil {
-		logger.Get().Error().Err(err).Msg("operation failed")
-		return discord.AlexError(ctx, "Failed to get player lifeboard")
+	if opt, ok := opts.GetByNameOptional("alive_role"); ok {
+		cfg.AliveRoleIDs, changed = append(cfg.AliveRoleIDs, opt.RoleValue(ctx).ID), true
 	}
-
-	playerLifeStatuses, _ := q.ListPlayerLifeboard(context.Background())
-	msg, err := channels.UserLifeboardMessageBuilder(ctx.GetSession(), ctx.GetEvent().GuildID, playerLifeStatuses)
-	if err != nil {
-		logger.Get().Error().Err(err).Msg("operation failed")
-		return discord.AlexError(ctx, "Failed to build user lifeboard message")
+	if opt, ok := opts.GetByNameOptional("read_only"); ok {
+		cfg.ReadOnly, changed = opt.BoolValue(), true
 	}
-
-	inv, err := h.FetchInventory()
-	if err != nil {
-		ctx.GetSession().ChannelMessageEditEmbed(lifeboard.ChannelID, lifeboard.MessageID, msg)
-		return discord.SuccessfulMessage(ctx, "Player Dead", "Player is now dead\n"+getRandomItem(playerSetDeadMessages)+"\n\n**Make sure to check for Lucky Coin**")
+	if opt, ok := opts.GetByNameOptional("clear_graveyard"); ok && opt.BoolValue() {
+		cfg.GraveyardCategory, changed = "", true
 	}
-
-	hasLuckyCoin := false
-	for _, item := range inv.Items {
-		if item.Name == "Lucky Coin" {
-			hasLuckyCoin = true
-			break
+	if opt, ok := opts.GetByNameOptional("graveyard"); ok {
+		cfg.GraveyardCategory, changed = opt.ChannelValue(ctx).Name, true
+	}
+	if opt, ok := opts.GetByNameOptional("announce"); ok {
+		cfg.Announce, changed = opt.BoolValue(), true
+	}
+	if changed {
+		if cfg, err = pipeline.SetConfig(dbCtx, cfg); err != nil {
+			logger.Get().Error().Err(err).Msg("operation failed")
+			return discord.AlexError(ctx, "Failed to save the death pipeline config")
 		}
 	}
+	return ctx.RespondEmbed(deathConfigEmbed(cfg))
+}
 
-	messageContent := "Player is now dead\n" + getRandomItem(playerSetDeadMessages)
-	if hasLuckyCoin {
-		messageContent += "\n\n**Host/s, this inventory holds the Lucky Coin!**"
+func deathConfigEmbed(cfg death.Config) *discordgo.MessageEmbed {
+	roles := func(ids []string) string {
+		if len(ids) == 0 {
+			return "none"
+		}
+		mentions := make([]string, len(ids))
+		for i, id := range ids {
+			mentions[i] = "<@&" + id + ">"
+		}
+		return strings.Join(mentions, ", ")
+	}
+	graveyard := "not moved"
+	if cfg.GraveyardCategory != "" {
+		graveyard = fmt.Sprintf("**%s**", cfg.GraveyardCategory)
+	}
+	onOff := func(b bool) string {
+		if b {
+			return "on"
+		}
+		return "off"
+	}
+	return &discordgo.MessageEmbed{
+		Title:       "Death Pipeline",
+		Description: "Runs on `/inv death_status` and when the web panel changes a player's alive state; revival reverses it.",
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Given on death", Value: roles(cfg.DeadRoleIDs), Inline: true},
+			{Name: "Taken on death", Value: roles(cfg.AliveRoleIDs), Inline: true},
+			{Name: "Read-only confessional", Value: onOff(cfg.ReadOnly), Inline: true},
+			{Name: "Graveyard category", Value: graveyard, Inline: true},
+			{Name: "Announce", Value: onOff(cfg.Announce), Inline: true},
+		},
 	}
-
-	ctx.GetSession().ChannelMessageEditEmbed(lifeboard.ChannelID, lifeboard.MessageID, msg)
-	return discord.SuccessfulMessage(ctx, "Player Dead", messageContent)
-
 }
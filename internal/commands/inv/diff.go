@@ -0,0 +1,100 @@
+package inv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/invsnapshot"
+	"github.com/mccune1224/betrayal/internal/util"
+	"github.com/zekrotja/ken"
+)
+
+// diffEmbedLimit keeps the recap under Discord's 4096-character description
+// limit with room for the overflow note.
+const diffEmbedLimit = 3800
+
+func (i *Inv) diffCommandArgBuilder() *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommand,
+		Name:        "diff",
+		Description: "Show what changed in inventories since the start of a day",
+		Options: []*discordgo.ApplicationCommandOption{
+			discord.IntCommandArg("day", "Compare from the start of this day", true),
+			discord.IntCommandArg("to", "Compare to the start of this day instead of now", false),
+			discord.BoolCommandArg("elimination", "Use the elimination phases of those days instead", false),
+			discord.UserCommandArg(false),
+		},
+	}
+}
+
+func (i *Inv) diff(ctx ken.SubCommandContext) (err error) {
+	if err = ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	opts := ctx.Options()
+	elimination := false
+	if opt, ok := opts.GetByNameOptional("elimination"); ok {
+		elimination = opt.BoolValue()
+	}
+	from := invsnapshot.Phase{Day: int32(opts.GetByName("day").IntValue()), Elimination: elimination}
+	var to *invsnapshot.Phase
+	if opt, ok := opts.GetByNameOptional("to"); ok {
+		to = &invsnapshot.Phase{Day: int32(opt.IntValue()), Elimination: elimination}
+	}
+
+	dbCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	// One player when named or when run in a confessional, otherwise the
+	// whole game.
+	playerID := int64(0)
+	if opt, ok := opts.GetByNameOptional("user"); ok {
+		playerID, _ = util.Atoi64(opt.UserValue(ctx).ID)
+	} else if channelID, err := util.Atoi64(ctx.GetEvent().ChannelID); err == nil {
+		if confessional, err := models.New(i.dbPool).GetPlayerConfessionalByChannelID(dbCtx, channelID); err == nil {
+			playerID = confessional.PlayerID
+		}
+	}
+
+	diffs, err := invsnapshot.New(i.dbPool).Diff(dbCtx, from, to, playerID)
+	if errors.Is(err, invsnapshot.ErrNoSnapshot) {
+		return discord.ErrorMessage(ctx, "No Snapshot", "Inventories are snapshotted when the cycle changes; there is none for that phase.")
+	}
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to compare inventories")
+	}
+
+	target := "now"
+	if to != nil {
+		target = "the start of " + to.String()
+	}
+	title := fmt.Sprintf("Inventory changes: start of %s → %s", from, target)
+	if len(diffs) == 0 {
+		return ctx.RespondEmbed(&discordgo.MessageEmbed{Title: title, Description: "Nothing changed."})
+	}
+	var b strings.Builder
+	for n, d := range diffs {
+		lines := make([]string, len(d.Changes))
+		for j, c := range d.Changes {
+			lines[j] = c.String()
+		}
+		block := fmt.Sprintf("%s\n```diff\n%s\n```\n", discord.MentionUser(fmt.Sprint(d.PlayerID)), strings.Join(lines, "\n"))
+		if b.Len()+len(block) > diffEmbedLimit {
+			fmt.Fprintf(&b, "…and %d more players; see the web panel for the full recap.", len(diffs)-n)
+			break
+		}
+		b.WriteString(block)
+	}
+	return ctx.RespondEmbed(&discordgo.MessageEmbed{Title: title, Description: b.String()})
+}
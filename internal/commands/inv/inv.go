@@ -5,6 +5,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mccune1224/betrayal/internal/discord"
 	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	"github.com/zekrotja/ken"
 )
 
@@ -40,10 +41,13 @@ func (i *Inv) Options() []*discordgo.ApplicationCommandOption {
 		i.statusCommandArgBuilder(),
 		i.perkCommandArgBuilder(),
 		i.notesCommandArgBuilder(),
+		i.substituteCommandArgBuilder(),
+		i.diffCommandArgBuilder(),
+		i.bulkCommandArgBuilder(),
 		{
 			Type:        discordgo.ApplicationCommandOptionSubCommand,
 			Name:        "create",
-			Description: "Create a player's inventory",
+			Description: "Create a player's inventory and confessional",
 			Options: []*discordgo.ApplicationCommandOption{
 				discord.StringCommandArg("role", "Role to create inventory for", true),
 				discord.UserCommandArg(true),
@@ -80,10 +84,14 @@ func (i *Inv) Options() []*discordgo.ApplicationCommandOption {
 // Run implements ken.SlashCommand.
 func (i *Inv) Run(ctx ken.Context) (err error) {
 	defer logger.RecoverWithLog(*logger.Get())
+	i = &Inv{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), i.dbPool)}
 
 	return ctx.HandleSubCommands(
 		ken.SubCommandHandler{Name: "create", Run: i.create},
 		ken.SubCommandHandler{Name: "delete", Run: i.delete},
+		ken.SubCommandHandler{Name: "substitute", Run: i.substitute},
+		ken.SubCommandHandler{Name: "diff", Run: i.diff},
+		ken.SubCommandHandler{Name: "bulk", Run: i.bulk},
 		ken.SubCommandHandler{Name: "get", Run: i.get},
 		ken.SubCommandHandler{Name: "me", Run: i.me},
 		i.abilityCommandGroupBuilder(),
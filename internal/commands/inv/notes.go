@@ -3,6 +3,7 @@ package inv
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/mccune1224/betrayal/internal/discord"
@@ -32,8 +33,10 @@ func (i *Inv) notesCommandArgBuilder() *discordgo.ApplicationCommandOption {
 				Name:        "add",
 				Description: "Add a note",
 				Options: []*discordgo.ApplicationCommandOption{
-					discord.StringCommandArg("note", "Note to add", true),
+					discord.StringCommandArg("note", "Note to add; #tags and @mentions are picked up", true),
 					discord.UserCommandArg(false),
+					discord.StringCommandArg("tags", "Extra tags, i.e suspicious, ability-used", false),
+					discord.IntCommandArg("day", "Cycle day the note is about (defaults to today)", false),
 				},
 			},
 			{
@@ -52,6 +55,7 @@ func (i *Inv) notesCommandArgBuilder() *discordgo.ApplicationCommandOption {
 					discord.IntCommandArg("position", "Position of note to update", true),
 					discord.StringCommandArg("note", "Note to update", true),
 					discord.UserCommandArg(false),
+					discord.StringCommandArg("tags", "Replace the extra tags, i.e suspicious, ability-used", false),
 				},
 			},
 			{
@@ -82,7 +86,12 @@ func (i *Inv) addNote(ctx ken.SubCommandContext) (err error) {
 	}
 	defer h.UpdateInventoryMessage(ctx.GetSession())
 	noteArg := ctx.Options().GetByName("note").StringValue()
-	_, err = playernotes.New(i.dbPool).Add(context.Background(), playernotes.DiscordAdminAuthorization(), h.GetPlayer().ID, noteArg)
+	draft := playernotes.Draft{Info: noteArg, Tags: noteTagsArg(ctx), Author: ctx.User().Username}
+	if opt, ok := ctx.Options().GetByNameOptional("day"); ok {
+		day := int32(opt.IntValue())
+		draft.Day = &day
+	}
+	_, err = playernotes.New(i.dbPool).AddNote(context.Background(), playernotes.DiscordAdminAuthorization(), h.GetPlayer().ID, draft)
 	if err != nil {
 		logger.Get().Error().Err(err).Msg("operation failed")
 		return discord.AlexError(ctx, "Failed to add note")
@@ -115,6 +124,7 @@ func (i *Inv) listNote(ctx ken.SubCommandContext) (err error) {
 	for _, note := range playerNotes {
 		fields = append(fields, &discordgo.MessageEmbedField{
 			Name:   fmt.Sprintf("%d. %s", note.Position, note.Info),
+			Value:  playernotes.Describe(note),
 			Inline: false,
 		})
 	}
@@ -165,7 +175,8 @@ func (i *Inv) updateNote(ctx ken.SubCommandContext) (err error) {
 	defer h.UpdateInventoryMessage(ctx.GetSession())
 	noteArg := ctx.Options().GetByName("note").StringValue()
 	positionArg := ctx.Options().GetByName("position").IntValue()
-	_, err = playernotes.New(i.dbPool).Update(context.Background(), playernotes.DiscordAdminAuthorization(), h.GetPlayer().ID, int(positionArg), noteArg)
+	draft := playernotes.Draft{Info: noteArg, Tags: noteTagsArg(ctx)}
+	_, err = playernotes.New(i.dbPool).UpdateNote(context.Background(), playernotes.DiscordAdminAuthorization(), h.GetPlayer().ID, int(positionArg), draft)
 	if err != nil {
 		logger.Get().Error().Err(err).Msg("operation failed")
 		return discord.AlexError(ctx, "Failed to update note")
@@ -173,3 +184,13 @@ func (i *Inv) updateNote(ctx ken.SubCommandContext) (err error) {
 
 	return discord.SuccessfulMessage(ctx, "Note Updated", fmt.Sprintf("Updated note %s from position %d", noteArg, positionArg))
 }
+
+// noteTagsArg reads the comma or space separated tags option; nil when it
+// was not given.
+func noteTagsArg(ctx ken.SubCommandContext) []string {
+	opt, ok := ctx.Options().GetByNameOptional("tags")
+	if !ok {
+		return nil
+	}
+	return strings.FieldsFunc(opt.StringValue(), func(r rune) bool { return r == ',' || r == ' ' })
+}
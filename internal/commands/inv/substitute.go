@@ -0,0 +1,72 @@
+package inv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/services/substitution"
+	"github.com/zekrotja/ken"
+)
+
+func (i *Inv) substituteCommandArgBuilder() *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommand,
+		Name:        "substitute",
+		Description: "Hand a player's seat, inventory and confessional to a substitute",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionUser, Name: "old", Description: "Player leaving the game", Required: true},
+			{Type: discordgo.ApplicationCommandOptionUser, Name: "new", Description: "Substitute taking over", Required: true},
+			discord.StringCommandArg("reason", "Why the seat changed hands", false),
+		},
+	}
+}
+
+func (i *Inv) substitute(ctx ken.SubCommandContext) (err error) {
+	if err = ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	oldUser := ctx.Options().GetByName("old").UserValue(ctx)
+	newUser := ctx.Options().GetByName("new").UserValue(ctx)
+	if newUser.Bot {
+		return discord.ErrorMessage(ctx, "Invalid Substitute", "Bots cannot take over a seat.")
+	}
+	req := substitution.Request{By: ctx.User().Username}
+	req.OldID, _ = strconv.ParseInt(oldUser.ID, 10, 64)
+	req.NewID, _ = strconv.ParseInt(newUser.ID, 10, 64)
+	if opt, ok := ctx.Options().GetByNameOptional("reason"); ok {
+		req.Reason = opt.StringValue()
+	}
+
+	dbCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	result, err := substitution.New(i.dbPool).Substitute(dbCtx, ctx.GetSession(), req)
+	switch {
+	case errors.Is(err, substitution.ErrPlayerNotFound):
+		return discord.ErrorMessage(ctx, "Player Not Found", fmt.Sprintf("%s does not have a player", oldUser.Username))
+	case errors.Is(err, substitution.ErrAlreadyPlayer), errors.Is(err, substitution.ErrSameUser):
+		return discord.ErrorMessage(ctx, "Invalid Substitute", fmt.Sprintf("%s: %s", newUser.Username, err))
+	case err != nil:
+		logger.Get().Error().Err(err).Str("player_id", oldUser.ID).Msg("substitution failed")
+		return discord.ErrorMessage(ctx, "Substitution Failed", fmt.Sprintf("Nothing was changed: %s", err))
+	}
+
+	message := fmt.Sprintf("%s now plays %s's seat", discord.MentionUser(newUser.ID), oldUser.Username)
+	if result.ChannelID != "" {
+		message += " in " + discord.MentionChannel(result.ChannelID)
+	}
+	if len(result.Warnings) > 0 {
+		return discord.WarningMessage(ctx, "Player Substituted", message+"\n"+strings.Join(result.Warnings, "\n"))
+	}
+	return discord.SuccessfulMessage(ctx, "Player Substituted", message)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/mccune1224/betrayal/internal/discord"
 	"github.com/mccune1224/betrayal/internal/models"
 	"github.com/mccune1224/betrayal/internal/services/playernotes"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	"github.com/mccune1224/betrayal/internal/util"
 	"github.com/zekrotja/ken"
 )
@@ -36,6 +37,13 @@ var (
 	}
 )
 
+const (
+	// notesSearchLimit caps /list notes; the web search can return more.
+	notesSearchLimit = 25
+	// notesEmbedLimit keeps the results under Discord's description limit.
+	notesEmbedLimit = 3800
+)
+
 type List struct {
 	dbPool *pgxpool.Pool
 }
@@ -84,8 +92,14 @@ func (*List) Options() []*discordgo.ApplicationCommandOption {
 		},
 		{
 			Name:        "notes",
-			Description: "(Admin Only) List all current player notes",
+			Description: "(Admin Only) Search every player's notes",
 			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Options: []*discordgo.ApplicationCommandOption{
+				discord.StringCommandArg("query", `Words to find, i.e protect or "used a protect"`, false),
+				discord.StringCommandArg("tag", "Only notes with these tags, i.e suspicious", false),
+				discord.IntCommandArg("day", "Only notes about this cycle day", false),
+				discord.UserCommandArg(false),
+			},
 		},
 	}
 }
@@ -93,6 +107,7 @@ func (*List) Options() []*discordgo.ApplicationCommandOption {
 // Run implements ken.SlashCommand.
 func (l *List) Run(ctx ken.Context) (err error) {
 	defer logger.RecoverWithLog(*logger.Get())
+	l = &List{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), l.dbPool)}
 
 	return ctx.HandleSubCommands(
 		ken.SubCommandHandler{Name: "items", Run: l.listItems},
@@ -242,42 +257,44 @@ func (l *List) listNotes(ctx ken.SubCommandContext) (err error) {
 	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
 		return discord.NotAdminError(ctx)
 	}
-	q := models.New(l.dbPool)
-	players, err := q.ListPlayer(context.Background())
+	opts := ctx.Options()
+	search := playernotes.Search{Limit: notesSearchLimit}
+	if opt, ok := opts.GetByNameOptional("query"); ok {
+		search.Query = opt.StringValue()
+	}
+	if opt, ok := opts.GetByNameOptional("tag"); ok {
+		search.Tags = strings.FieldsFunc(opt.StringValue(), func(r rune) bool { return r == ',' || r == ' ' })
+	}
+	if opt, ok := opts.GetByNameOptional("day"); ok {
+		day := int32(opt.IntValue())
+		search.Day = &day
+	}
+	if opt, ok := opts.GetByNameOptional("user"); ok {
+		search.PlayerID, _ = util.Atoi64(opt.UserValue(ctx).ID)
+	}
+	notes, err := playernotes.New(l.dbPool).Search(context.Background(), playernotes.DiscordAdminAuthorization(), search)
 	if err != nil {
-		return discord.AlexError(ctx, "Failed to get players")
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to search notes")
 	}
-	groupedNotes := make(map[int64][]models.PlayerNote)
-	notesService := playernotes.New(l.dbPool)
-	for _, player := range players {
-		notes, err := notesService.List(context.Background(), playernotes.DiscordAdminAuthorization(), player.ID)
-		if err != nil {
-			return discord.AlexError(ctx, "Failed to get notes")
-		}
-		if len(notes) > 0 {
-			groupedNotes[player.ID] = notes
-		}
+	if len(notes) == 0 {
+		return ctx.RespondEmbed(&discordgo.MessageEmbed{Title: "Notes", Description: "No notes match."})
 	}
-	fields := []*discordgo.MessageEmbedField{}
-	for playerID, notes := range groupedNotes {
-		fieldName := ""
-		for _, player := range players {
-			if player.ID == playerID {
-				discordPlayer, _ := ctx.GetSession().GuildMember(ctx.GetEvent().GuildID, util.Itoa64(player.ID))
-				fieldName = discordPlayer.DisplayName()
-				break
-			}
+	var b strings.Builder
+	for n, note := range notes {
+		line := fmt.Sprintf("%s #%d: %s", discord.MentionUser(util.Itoa64(note.PlayerID)), note.Position, note.Info)
+		if meta := playernotes.Describe(note); meta != "" {
+			line += "\n-# " + meta
 		}
-		fields = append(fields, &discordgo.MessageEmbedField{
-			Name:   fmt.Sprintf("%s (%d)", discord.Bold(fieldName), len(notes)),
-			Value:  fmt.Sprintf("%s", discord.Code(notes[0].Info)),
-			Inline: false,
-		})
+		if b.Len()+len(line) > notesEmbedLimit {
+			fmt.Fprintf(&b, "…and %d more; narrow the search or use the web panel.", len(notes)-n)
+			break
+		}
+		b.WriteString(line + "\n")
 	}
 	return ctx.RespondEmbed(&discordgo.MessageEmbed{
-		Title:       "Notes",
-		Description: "All notes for the game",
-		Fields:      fields,
+		Title:       fmt.Sprintf("Notes (%d)", len(notes)),
+		Description: b.String(),
 	})
 }
 
@@ -0,0 +1,551 @@
+package poll
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	pollsvc "github.com/mccune1224/betrayal/internal/services/poll"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
+	"github.com/mccune1224/betrayal/internal/util"
+	"github.com/zekrotja/ken"
+)
+
+// componentPrefix marks button custom IDs owned by the poll component handler
+// (format: poll:<pollID>:opt:<optionID> or poll:<pollID>:clear).
+const componentPrefix = "poll:"
+
+type Poll struct {
+	dbPool *pgxpool.Pool
+}
+
+var _ ken.SlashCommand = (*Poll)(nil)
+
+// Initialize implements main.BetrayalCommand.
+func (p *Poll) Initialize(pool *pgxpool.Pool) {
+	p.dbPool = pool
+}
+
+// Description implements ken.SlashCommand.
+func (*Poll) Description() string {
+	return "Host-run polls with button ballots"
+}
+
+// Name implements ken.SlashCommand.
+func (*Poll) Name() string {
+	return discord.DebugCmd + "poll"
+}
+
+// Version implements ken.SlashCommand.
+func (*Poll) Version() string {
+	return "1.0.0"
+}
+
+// Options implements ken.SlashCommand.
+func (*Poll) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "create",
+			Description: "Post a new poll (Admin Only)",
+			Options: []*discordgo.ApplicationCommandOption{
+				discord.StringCommandArg("question", "Question to ask", true),
+				discord.StringCommandArg("options", "Comma separated answers (omit to list players)", false),
+				discord.StringCommandArg("players", "Mention the players to list (default: every alive player)", false),
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "voters",
+					Description: "Who may vote (default: alive players)",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "All players", Value: string(pollsvc.EligibleAll)},
+						{Name: "Alive players", Value: string(pollsvc.EligibleAlive)},
+						{Name: "Role", Value: string(pollsvc.EligibleRole)},
+						{Name: "Alignment", Value: string(pollsvc.EligibleAlignment)},
+					},
+				},
+				discord.StringCommandArg("voter_filter", "Role name or alignment when voters is Role/Alignment (living players only)", false),
+				discord.BoolCommandArg("ranked", "Ranked choice (instant runoff) instead of single choice", false),
+				discord.IntCommandArg("closes_in", "Minutes until the poll closes", false),
+				discord.ChannelCommandArg(false),
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "close",
+			Description: "Close a poll and post its results (Admin Only)",
+			Options: []*discordgo.ApplicationCommandOption{
+				discord.IntCommandArg("id", "Poll ID", true),
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "results",
+			Description: "Show the current results of a poll (Admin Only)",
+			Options: []*discordgo.ApplicationCommandOption{
+				discord.IntCommandArg("id", "Poll ID", true),
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "list",
+			Description: "List polls (Admin Only)",
+		},
+	}
+}
+
+// Run implements ken.SlashCommand.
+func (p *Poll) Run(ctx ken.Context) (err error) {
+	defer logger.RecoverWithLog(*logger.Get())
+	p = &Poll{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), p.dbPool)}
+
+	return ctx.HandleSubCommands(
+		ken.SubCommandHandler{Name: "create", Run: p.create},
+		ken.SubCommandHandler{Name: "close", Run: p.close},
+		ken.SubCommandHandler{Name: "results", Run: p.results},
+		ken.SubCommandHandler{Name: "list", Run: p.list},
+	)
+}
+
+func (p *Poll) create(ctx ken.SubCommandContext) (err error) {
+	if err := ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+
+	sesh := ctx.GetSession()
+	event := ctx.GetEvent()
+	dbCtx := context.Background()
+
+	params := pollsvc.CreateParams{
+		Question:    ctx.Options().GetByName("question").StringValue(),
+		Kind:        pollsvc.OptionKindText,
+		Eligibility: pollsvc.EligibleAlive,
+		CreatedBy:   event.Member.User.ID,
+	}
+	if opt, ok := ctx.Options().GetByNameOptional("voters"); ok {
+		params.Eligibility = pollsvc.Eligibility(opt.StringValue())
+	}
+	if opt, ok := ctx.Options().GetByNameOptional("voter_filter"); ok {
+		params.EligibilityValue = strings.TrimSpace(opt.StringValue())
+	}
+	if opt, ok := ctx.Options().GetByNameOptional("ranked"); ok {
+		params.Ranked = opt.BoolValue()
+	}
+	if opt, ok := ctx.Options().GetByNameOptional("closes_in"); ok {
+		minutes := opt.IntValue()
+		if minutes <= 0 {
+			return discord.ErrorMessage(ctx, "Invalid close time", "closes_in must be a positive number of minutes")
+		}
+		params.ClosesAt = time.Now().Add(time.Duration(minutes) * time.Minute)
+	}
+	channelID := event.ChannelID
+	if opt, ok := ctx.Options().GetByNameOptional("channel"); ok {
+		channelID = opt.ChannelValue(ctx).ID
+	}
+
+	if opt, ok := ctx.Options().GetByNameOptional("options"); ok {
+		for _, label := range strings.Split(opt.StringValue(), ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				params.Options = append(params.Options, pollsvc.OptionInput{Label: label})
+			}
+		}
+	} else {
+		params.Kind = pollsvc.OptionKindPlayer
+		players, err := models.New(p.dbPool).ListPlayer(dbCtx)
+		if err != nil {
+			logger.Get().Error().Err(err).Msg("operation failed")
+			return discord.AlexError(ctx, "Failed to list players")
+		}
+		picks := ""
+		if opt, ok := ctx.Options().GetByNameOptional("players"); ok {
+			picks = opt.StringValue()
+		}
+		ids, unknown := playerOptionIDs(players, picks)
+		if len(unknown) > 0 {
+			return discord.ErrorMessage(ctx, "Unknown players", "These are not registered players: "+strings.Join(unknown, ", "))
+		}
+		if len(ids) > pollsvc.MaxOptions {
+			return discord.ErrorMessage(ctx, "Too many players",
+				fmt.Sprintf("A ballot holds at most %d options but %d players would be listed. Mention the candidates with the players option.", pollsvc.MaxOptions, len(ids)))
+		}
+		for _, id := range ids {
+			params.Options = append(params.Options, pollsvc.OptionInput{
+				Label:    memberLabel(sesh, event.GuildID, util.Itoa64(id)),
+				PlayerID: id,
+			})
+		}
+	}
+
+	svc := pollsvc.New(p.dbPool)
+	created, err := svc.Create(dbCtx, params)
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.ErrorMessage(ctx, "Failed to create poll", err.Error())
+	}
+
+	msg, err := sesh.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{pollEmbed(created)},
+		Components: ballotComponents(created),
+	})
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		// Nobody can vote without the ballot, so do not leave the poll open.
+		if _, closeErr := svc.Close(dbCtx, created.ID); closeErr != nil {
+			logger.Get().Error().Err(closeErr).Int64("poll_id", created.ID).Msg("failed to close unposted poll")
+		}
+		return discord.AlexError(ctx, "The ballot message could not be posted, so the poll was closed")
+	}
+	if err := svc.AttachMessage(dbCtx, created.ID, msg.ChannelID, msg.ID); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+	}
+
+	return discord.SuccessfulMessage(ctx, fmt.Sprintf("Poll #%d posted", created.ID),
+		fmt.Sprintf("Ballot posted in %s", discord.MentionChannel(channelID)))
+}
+
+func (p *Poll) close(ctx ken.SubCommandContext) (err error) {
+	if err := ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	id := ctx.Options().GetByName("id").IntValue()
+	dbCtx := context.Background()
+	svc := pollsvc.New(p.dbPool)
+	closed, err := svc.Close(dbCtx, id)
+	if err != nil {
+		if errors.Is(err, pollsvc.ErrPollNotFound) {
+			return discord.ErrorMessage(ctx, "Poll not found", fmt.Sprintf("No poll with ID %d", id))
+		}
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to close poll")
+	}
+	results, err := svc.Results(dbCtx, id)
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Poll closed but results could not be tallied")
+	}
+
+	// Strip the buttons from the ballot message so nobody keeps clicking.
+	if closed.ChannelID.Valid && closed.MessageID.Valid {
+		empty := []discordgo.MessageComponent{}
+		if _, err := ctx.GetSession().ChannelMessageEditComplex(&discordgo.MessageEdit{
+			Channel:    closed.ChannelID.String,
+			ID:         closed.MessageID.String,
+			Embeds:     &[]*discordgo.MessageEmbed{pollEmbed(closed)},
+			Components: &empty,
+		}); err != nil {
+			logger.Get().Warn().Err(err).Int64("poll_id", id).Msg("failed to remove poll ballot buttons")
+		}
+	}
+
+	return ctx.RespondEmbed(resultsEmbed(results))
+}
+
+func (p *Poll) results(ctx ken.SubCommandContext) (err error) {
+	if err := ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	id := ctx.Options().GetByName("id").IntValue()
+	results, err := pollsvc.New(p.dbPool).Results(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, pollsvc.ErrPollNotFound) {
+			return discord.ErrorMessage(ctx, "Poll not found", fmt.Sprintf("No poll with ID %d", id))
+		}
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to tally poll")
+	}
+	return ctx.RespondEmbed(resultsEmbed(results))
+}
+
+func (p *Poll) list(ctx ken.SubCommandContext) (err error) {
+	if err := ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+	polls, err := pollsvc.New(p.dbPool).List(context.Background())
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "Failed to list polls")
+	}
+	if len(polls) == 0 {
+		return discord.SuccessfulMessage(ctx, "No polls", "Create one with /poll create")
+	}
+	now := time.Now()
+	lines := make([]string, 0, len(polls))
+	for _, poll := range polls {
+		state := "open"
+		if !poll.Open(now) {
+			state = "closed"
+		}
+		lines = append(lines, fmt.Sprintf("**#%d** %s (%s)", poll.ID, poll.Question, state))
+	}
+	return ctx.RespondEmbed(&discordgo.MessageEmbed{
+		Title:       "Polls",
+		Description: strings.Join(lines, "\n"),
+		Color:       discord.ColorThemeOrange,
+	})
+}
+
+// ComponentHandler records ballots from poll buttons. It is registered on the
+// Discord session alongside the pagination handler.
+func (p *Poll) ComponentHandler(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionMessageComponent {
+		return
+	}
+	customID := i.MessageComponentData().CustomID
+	if !strings.HasPrefix(customID, componentPrefix) {
+		return
+	}
+	defer logger.RecoverWithLog(*logger.Get())
+
+	pollID, optionID, clear, ok := parseComponentID(customID)
+	if !ok || i.Member == nil || i.Member.User == nil {
+		return
+	}
+	voterID, err := util.Atoi64(i.Member.User.ID)
+	if err != nil {
+		return
+	}
+
+	dbCtx := context.Background()
+	svc := pollsvc.New(tenancy.PoolFor(i.GuildID, p.dbPool))
+	reply := ""
+	if clear {
+		err = svc.ClearBallot(dbCtx, pollID, voterID, time.Now())
+		reply = "Your ballot has been cleared."
+	} else {
+		var ballot []models.PollOption
+		ballot, err = svc.Cast(dbCtx, pollID, voterID, optionID, time.Now())
+		reply = ballotSummary(ballot)
+	}
+	if err != nil {
+		reply = ballotError(err)
+		if reply == "" {
+			logger.Get().Error().Err(err).Int64("poll_id", pollID).Msg("failed to record poll ballot")
+			reply = "Something went wrong recording your ballot."
+		}
+	}
+
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: reply,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+func parseComponentID(customID string) (pollID, optionID int64, clear bool, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(customID, componentPrefix), ":")
+	if len(parts) < 2 {
+		return 0, 0, false, false
+	}
+	pollID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, false, false
+	}
+	switch {
+	case len(parts) == 2 && parts[1] == "clear":
+		return pollID, 0, true, true
+	case len(parts) == 3 && parts[1] == "opt":
+		optionID, err = strconv.ParseInt(parts[2], 10, 64)
+		return pollID, optionID, false, err == nil
+	}
+	return 0, 0, false, false
+}
+
+func ballotError(err error) string {
+	switch {
+	case errors.Is(err, pollsvc.ErrNotAPlayer):
+		return "Only players can vote in polls."
+	case errors.Is(err, pollsvc.ErrNotEligible):
+		return "You are not eligible to vote in this poll."
+	case errors.Is(err, pollsvc.ErrPollClosed):
+		return "This poll is closed."
+	case errors.Is(err, pollsvc.ErrPollNotFound), errors.Is(err, pollsvc.ErrUnknownOption):
+		return "This poll no longer exists."
+	case errors.Is(err, pollsvc.ErrAlreadyRanked):
+		return "You already ranked that option. Clear your ballot to start over."
+	}
+	return ""
+}
+
+func ballotSummary(ballot []models.PollOption) string {
+	if len(ballot) == 1 {
+		return fmt.Sprintf("Your vote: **%s**", ballot[0].Label)
+	}
+	lines := []string{"Your ranking:"}
+	for i, option := range ballot {
+		lines = append(lines, fmt.Sprintf("%d. %s", i+1, option.Label))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func pollEmbed(poll pollsvc.Poll) *discordgo.MessageEmbed {
+	how := "Pick one option."
+	if poll.Ranked {
+		how = "Click options in order of preference. Clear your ballot to start over."
+	}
+	voters := "Alive players"
+	switch pollsvc.Eligibility(poll.Eligibility) {
+	case pollsvc.EligibleAll:
+		voters = "All players"
+	case pollsvc.EligibleRole:
+		voters = "Alive " + poll.EligibilityValue.String + " players"
+	case pollsvc.EligibleAlignment:
+		voters = "Alive " + strings.ToUpper(poll.EligibilityValue.String) + " players"
+	}
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Voters", Value: voters, Inline: true},
+	}
+	switch {
+	case poll.ClosedAt.Valid:
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Status", Value: "Closed", Inline: true})
+	case poll.ClosesAt.Valid:
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Closes", Value: discord.RelativeTimestamp(poll.ClosesAt.Time.Unix()), Inline: true})
+	}
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Poll #%d: %s", poll.ID, poll.Question),
+		Description: how,
+		Fields:      fields,
+		Color:       discord.ColorThemeOrange,
+	}
+}
+
+func ballotComponents(poll pollsvc.Poll) []discordgo.MessageComponent {
+	rows := []discordgo.MessageComponent{}
+	row := discordgo.ActionsRow{}
+	for _, option := range poll.Options {
+		if len(row.Components) == 5 {
+			rows = append(rows, row)
+			row = discordgo.ActionsRow{}
+		}
+		row.Components = append(row.Components, discordgo.Button{
+			Label:    truncateLabel(option.Label),
+			Style:    discordgo.PrimaryButton,
+			CustomID: fmt.Sprintf("%s%d:opt:%d", componentPrefix, poll.ID, option.ID),
+		})
+	}
+	if len(row.Components) > 0 {
+		rows = append(rows, row)
+	}
+	rows = append(rows, discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{
+			Label:    "Clear ballot",
+			Style:    discordgo.SecondaryButton,
+			CustomID: fmt.Sprintf("%s%d:clear", componentPrefix, poll.ID),
+		},
+	}})
+	return rows
+}
+
+func resultsEmbed(results pollsvc.Results) *discordgo.MessageEmbed {
+	final := results.Final()
+	lines := make([]string, 0, len(final))
+	for _, tally := range final {
+		lines = append(lines, fmt.Sprintf("%s — %d", tally.Label, tally.Votes))
+	}
+	winner := "No ballots cast"
+	if len(results.Winners) > 0 {
+		names := make([]string, 0, len(results.Winners))
+		for _, option := range results.Winners {
+			names = append(names, option.Label)
+		}
+		winner = strings.Join(names, ", ")
+		if len(names) > 1 {
+			winner = "Tie: " + winner
+		}
+	}
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Winner", Value: winner},
+		{Name: "Voters", Value: strconv.Itoa(results.Voters), Inline: true},
+	}
+	if results.Poll.Ranked {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Runoff rounds", Value: strconv.Itoa(len(results.Rounds)), Inline: true})
+	}
+	description := strings.Join(lines, "\n")
+	if description == "" {
+		description = "No options"
+	}
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Poll #%d results: %s", results.Poll.ID, results.Poll.Question),
+		Description: description,
+		Fields:      fields,
+		Color:       discord.ColorThemeOrange,
+	}
+}
+
+// truncateLabel keeps button labels within Discord's 80 character limit.
+func truncateLabel(label string) string {
+	runes := []rune(label)
+	if len(runes) <= 80 {
+		return label
+	}
+	return string(runes[:77]) + "..."
+}
+
+var mentionPattern = regexp.MustCompile(`<@!?(\d+)>`)
+
+// playerOptionIDs picks the players a player poll lists: the players
+// mentioned in picks, in order and without repeats, or every alive player
+// when picks mentions nobody. Mentions of unregistered users are returned
+// as unknown.
+func playerOptionIDs(players []models.Player, picks string) (ids []int64, unknown []string) {
+	registered := make(map[int64]bool, len(players))
+	for _, player := range players {
+		registered[player.ID] = true
+	}
+	seen := map[int64]bool{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(picks, -1) {
+		id, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if !registered[id] {
+			unknown = append(unknown, match[0])
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(seen) > 0 {
+		return ids, unknown
+	}
+	for _, player := range players {
+		if player.Alive {
+			ids = append(ids, player.ID)
+		}
+	}
+	return ids, nil
+}
+
+func memberLabel(s *discordgo.Session, guildID, userID string) string {
+	member, err := s.GuildMember(guildID, userID)
+	if err != nil || member == nil || member.User == nil {
+		return userID
+	}
+	return member.DisplayName()
+}
@@ -0,0 +1,85 @@
+package poll
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mccune1224/betrayal/internal/models"
+	pollsvc "github.com/mccune1224/betrayal/internal/services/poll"
+)
+
+func TestParseComponentID(t *testing.T) {
+	cases := []struct {
+		id       string
+		poll     int64
+		option   int64
+		clear    bool
+		accepted bool
+	}{
+		{"poll:7:opt:42", 7, 42, false, true},
+		{"poll:7:clear", 7, 0, true, true},
+		{"poll:x:opt:42", 0, 0, false, false},
+		{"poll:7:opt", 0, 0, false, false},
+		{"poll:7:opt:y", 7, 0, false, false},
+		{"poll:7:vote:1", 0, 0, false, false},
+	}
+	for _, tc := range cases {
+		pollID, optionID, clear, ok := parseComponentID(tc.id)
+		if ok != tc.accepted {
+			t.Errorf("%s: ok = %v, want %v", tc.id, ok, tc.accepted)
+			continue
+		}
+		if ok && (pollID != tc.poll || optionID != tc.option || clear != tc.clear) {
+			t.Errorf("%s: got (%d, %d, %v), want (%d, %d, %v)", tc.id, pollID, optionID, clear, tc.poll, tc.option, tc.clear)
+		}
+	}
+}
+
+func TestBallotComponentsFitDiscordLimits(t *testing.T) {
+	poll := pollsvc.Poll{Poll: models.Poll{ID: 3}}
+	for i := 1; i <= pollsvc.MaxOptions; i++ {
+		poll.Options = append(poll.Options, models.PollOption{ID: int64(i), Label: strings.Repeat("x", 100)})
+	}
+	rows := ballotComponents(poll)
+	if len(rows) > 5 {
+		t.Fatalf("rows = %d, Discord allows at most 5", len(rows))
+	}
+	seen := map[string]bool{}
+	for _, row := range rows {
+		buttons := row.(discordgo.ActionsRow).Components
+		if len(buttons) > 5 {
+			t.Fatalf("row has %d buttons, Discord allows at most 5", len(buttons))
+		}
+		for _, component := range buttons {
+			button := component.(discordgo.Button)
+			if len([]rune(button.Label)) > 80 {
+				t.Errorf("button label %q exceeds 80 characters", button.Label)
+			}
+			if _, _, _, ok := parseComponentID(button.CustomID); !ok {
+				t.Errorf("button custom ID %q does not round-trip", button.CustomID)
+			}
+			seen[button.CustomID] = true
+		}
+	}
+	if !seen["poll:3:clear"] || !seen["poll:3:opt:20"] {
+		t.Fatalf("missing expected buttons: %v", seen)
+	}
+}
+
+func TestPlayerOptionIDs(t *testing.T) {
+	players := []models.Player{{ID: 1, Alive: true}, {ID: 2, Alive: false}, {ID: 3, Alive: true}}
+
+	ids, unknown := playerOptionIDs(players, "")
+	if len(unknown) != 0 || len(ids) != 2 || ids[0] != 1 || ids[1] != 3 {
+		t.Fatalf("default = %v, unknown %v; want the alive players", ids, unknown)
+	}
+
+	ids, unknown = playerOptionIDs(players, "<@3> <@!2> <@3> <@9>")
+	if len(ids) != 2 || ids[0] != 3 || ids[1] != 2 {
+		t.Fatalf("picked = %v; want mentioned players in order, dead ones included", ids)
+	}
+	if len(unknown) != 1 || unknown[0] != "<@9>" {
+		t.Fatalf("unknown = %v", unknown)
+	}
+}
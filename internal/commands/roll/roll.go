@@ -13,6 +13,7 @@ import (
 	"github.com/mccune1224/betrayal/internal/models"
 	"github.com/mccune1224/betrayal/internal/services/inventory"
 	rollsvc "github.com/mccune1224/betrayal/internal/services/roll"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	"github.com/zekrotja/ken"
 )
 
@@ -143,6 +144,7 @@ func (*Roll) Options() []*discordgo.ApplicationCommandOption {
 // Run implements ken.SlashCommand.
 func (r *Roll) Run(ctx ken.Context) (err error) {
 	defer logger.RecoverWithLog(*logger.Get())
+	r = &Roll{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), r.dbPool)}
 
 	return ctx.HandleSubCommands(
 		ken.SubCommandHandler{Name: "manual", Run: r.luckManual},
@@ -11,6 +11,7 @@ import (
 	"github.com/mccune1224/betrayal/internal/discord"
 	"github.com/mccune1224/betrayal/internal/logger"
 	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	"github.com/zekrotja/ken"
 )
 
@@ -73,6 +74,7 @@ func (*Search) Options() []*discordgo.ApplicationCommandOption {
 // Run implements ken.SlashCommand.
 func (s *Search) Run(ctx ken.Context) (err error) {
 	defer logger.RecoverWithLog(*logger.Get())
+	s = &Search{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), s.dbPool)}
 
 	return ctx.HandleSubCommands(
 		ken.SubCommandHandler{Name: "ability", Run: s.searchAbility},
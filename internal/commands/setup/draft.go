@@ -0,0 +1,280 @@
+package setup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/services/roledraft"
+	"github.com/mccune1224/betrayal/internal/services/roster"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
+	"github.com/zekrotja/ken"
+)
+
+// Draft manages the role drafts /setup creates: deceptionist picks, finishing
+// a draft for /roster, and the active role list drafts are drawn from.
+type Draft struct {
+	dbPool *pgxpool.Pool
+}
+
+func (d *Draft) Initialize(pool *pgxpool.Pool) {
+	d.dbPool = pool
+}
+
+var _ ken.SlashCommand = (*Draft)(nil)
+
+// Description implements ken.SlashCommand.
+func (*Draft) Description() string {
+	return "Manage role drafts and the active role list"
+}
+
+// Name implements ken.SlashCommand.
+func (*Draft) Name() string {
+	return "draft"
+}
+
+// Options implements ken.SlashCommand.
+func (*Draft) Options() []*discordgo.ApplicationCommandOption {
+	draftID := discord.IntCommandArg("draft_id", "Draft number (defaults to the latest)", false)
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "view",
+			Description: "Show a draft with its picks",
+			Options:     []*discordgo.ApplicationCommandOption{draftID},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "pick",
+			Description: "Record the role a deceptionist picked",
+			Options: []*discordgo.ApplicationCommandOption{
+				discord.IntCommandArg("slot", "Deceptionist slot number", true),
+				discord.StringCommandArg("role", "Picked role", true),
+				discord.UserCommandArg(false),
+				draftID,
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "finish",
+			Description: "Freeze a draft and get its roster file for /roster",
+			Options:     []*discordgo.ApplicationCommandOption{draftID},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+			Name:        "active-roles",
+			Description: "Roles new drafts are drawn from",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "list",
+					Description: "List the active roles",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "add",
+					Description: "Add a role to the active list",
+					Options:     []*discordgo.ApplicationCommandOption{discord.StringCommandArg("role", "Role name", true)},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "remove",
+					Description: "Remove a role from the active list",
+					Options:     []*discordgo.ApplicationCommandOption{discord.StringCommandArg("role", "Role name", true)},
+				},
+			},
+		},
+	}
+}
+
+// Version implements ken.SlashCommand.
+func (*Draft) Version() string {
+	return "1.0.0"
+}
+
+// Run implements ken.SlashCommand.
+func (d *Draft) Run(ctx ken.Context) (err error) {
+	defer logger.RecoverWithLog(*logger.Get())
+	d = &Draft{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), d.dbPool)}
+	return ctx.HandleSubCommands(
+		ken.SubCommandHandler{Name: "view", Run: d.view},
+		ken.SubCommandHandler{Name: "pick", Run: d.pick},
+		ken.SubCommandHandler{Name: "finish", Run: d.finish},
+		ken.SubCommandGroup{Name: "active-roles", SubHandler: []ken.CommandHandler{
+			ken.SubCommandHandler{Name: "list", Run: d.listActiveRoles},
+			ken.SubCommandHandler{Name: "add", Run: d.addActiveRole},
+			ken.SubCommandHandler{Name: "remove", Run: d.removeActiveRole},
+		}},
+	)
+}
+
+func (d *Draft) adminContext(ctx ken.SubCommandContext) (context.Context, context.CancelFunc, error) {
+	if err := ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return nil, nil, err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return nil, nil, discord.NotAdminError(ctx)
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	return dbCtx, cancel, nil
+}
+
+// load resolves the draft_id option, falling back to the latest draft.
+func (d *Draft) load(dbCtx context.Context, ctx ken.SubCommandContext) (roledraft.Draft, error) {
+	svc := roledraft.New(d.dbPool)
+	if opt, ok := ctx.Options().GetByNameOptional("draft_id"); ok {
+		return svc.Get(dbCtx, opt.IntValue())
+	}
+	return svc.Latest(dbCtx)
+}
+
+func (d *Draft) view(ctx ken.SubCommandContext) error {
+	dbCtx, cancel, err := d.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	draft, err := d.load(dbCtx, ctx)
+	if err != nil {
+		return draftCommandError(ctx, err)
+	}
+	return ctx.RespondEmbed(draftEmbed(draft))
+}
+
+func (d *Draft) pick(ctx ken.SubCommandContext) error {
+	dbCtx, cancel, err := d.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	draft, err := d.load(dbCtx, ctx)
+	if err != nil {
+		return draftCommandError(ctx, err)
+	}
+	var playerID int64
+	if opt, ok := ctx.Options().GetByNameOptional("user"); ok {
+		playerID, _ = strconv.ParseInt(opt.UserValue(ctx).ID, 10, 64)
+	}
+	slot := int(ctx.Options().GetByName("slot").IntValue())
+	draft, err = roledraft.New(d.dbPool).Pick(dbCtx, draft.ID, slot-1, ctx.Options().GetByName("role").StringValue(), playerID, ctx.User().Username)
+	if err != nil {
+		return draftCommandError(ctx, err)
+	}
+	return ctx.RespondEmbed(draftEmbed(draft))
+}
+
+func (d *Draft) finish(ctx ken.SubCommandContext) error {
+	dbCtx, cancel, err := d.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	draft, err := d.load(dbCtx, ctx)
+	if err != nil {
+		return draftCommandError(ctx, err)
+	}
+	if draft, err = roledraft.New(d.dbPool).Finish(dbCtx, draft.ID); err != nil {
+		return draftCommandError(ctx, err)
+	}
+	embed := draftEmbed(draft)
+	embed.Description = "Fill in the missing user IDs in the attached roster and upload it with `/roster`."
+	return ctx.FollowUp(true, &discordgo.WebhookParams{
+		Embeds: []*discordgo.MessageEmbed{embed},
+		Files: []*discordgo.File{{
+			Name:        fmt.Sprintf("draft-%d-roster.csv", draft.ID),
+			ContentType: "text/csv",
+			Reader:      bytes.NewReader(roster.Template(draft.Lineup())),
+		}},
+	}).Send().Error
+}
+
+func (d *Draft) listActiveRoles(ctx ken.SubCommandContext) error {
+	dbCtx, cancel, err := d.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	names, err := roledraft.New(d.dbPool).ActiveRoleNames(dbCtx)
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "failed to load active roles")
+	}
+	return ctx.RespondEmbed(&discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Active Roles (%d)", len(names)),
+		Description: truncate(strings.Join(names, ", "), 4000),
+	})
+}
+
+func (d *Draft) addActiveRole(ctx ken.SubCommandContext) error {
+	dbCtx, cancel, err := d.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	role, err := roledraft.New(d.dbPool).AddActiveRole(dbCtx, ctx.Options().GetByName("role").StringValue())
+	if errors.Is(err, roledraft.ErrUnknownRole) {
+		return discord.ErrorMessage(ctx, "Role Not Found", err.Error())
+	}
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "failed to update active roles")
+	}
+	return discord.SuccessfulMessage(ctx, "Active Role Added", fmt.Sprintf("**%s** can now be drafted", role.Name))
+}
+
+func (d *Draft) removeActiveRole(ctx ken.SubCommandContext) error {
+	dbCtx, cancel, err := d.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	name := ctx.Options().GetByName("role").StringValue()
+	err = roledraft.New(d.dbPool).RemoveActiveRole(dbCtx, name)
+	if errors.Is(err, roledraft.ErrRoleNotActive) {
+		return discord.ErrorMessage(ctx, "Role Not Active", fmt.Sprintf("**%s** is not in the active role list", name))
+	}
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "failed to update active roles")
+	}
+	return discord.SuccessfulMessage(ctx, "Active Role Removed", fmt.Sprintf("**%s** will no longer be drafted", name))
+}
+
+func draftCommandError(ctx ken.SubCommandContext, err error) error {
+	switch {
+	case errors.Is(err, roledraft.ErrDraftNotFound):
+		return discord.ErrorMessage(ctx, "Draft Not Found", "Run /setup to create a role draft.")
+	case errors.Is(err, roledraft.ErrDraftFinished), errors.Is(err, roledraft.ErrInvalidPick):
+		return discord.ErrorMessage(ctx, "Draft Not Updated", err.Error())
+	}
+	logger.Get().Error().Err(err).Msg("operation failed")
+	return discord.AlexError(ctx, "failed to update role draft")
+}
+
+func draftEmbed(draft roledraft.Draft) *discordgo.MessageEmbed {
+	embed := roleSetupEmbed(&rolePool{deceptionOptions: draft.Pool.DeceptionOptions, randomPool: draft.Pool.RandomPool})
+	embed.Title = fmt.Sprintf("Draft #%d (%s, %d players)", draft.ID, draft.Status, draft.PlayerCount)
+	for _, pick := range draft.Picks {
+		for _, role := range draft.Pool.DeceptionOptions[pick.Slot] {
+			if role.ID != pick.RoleID {
+				continue
+			}
+			value := fmt.Sprintf("%s **%s**", discord.EmojiSuccess, role.Name)
+			if pick.PlayerID.Valid {
+				value += " for " + discord.MentionUser(strconv.FormatInt(pick.PlayerID.Int64, 10))
+			}
+			embed.Fields[pick.Slot].Value = value
+		}
+	}
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("seed %d", draft.Seed)}
+	return embed
+}
@@ -0,0 +1,196 @@
+package setup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/services/roster"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
+	"github.com/zekrotja/ken"
+)
+
+// maxRosterBytes caps roster uploads; a full game is a few kilobytes.
+const maxRosterBytes = 1 << 20
+
+// Roster bulk-creates players from an uploaded CSV or JSON roster.
+type Roster struct {
+	dbPool *pgxpool.Pool
+}
+
+func (r *Roster) Initialize(pool *pgxpool.Pool) {
+	r.dbPool = pool
+}
+
+var _ ken.SlashCommand = (*Roster)(nil)
+
+// Description implements ken.SlashCommand.
+func (*Roster) Description() string {
+	return "Create every player and confessional from a roster file"
+}
+
+// Name implements ken.SlashCommand.
+func (*Roster) Name() string {
+	return "roster"
+}
+
+// Options implements ken.SlashCommand.
+func (*Roster) Options() []*discordgo.ApplicationCommandOption {
+	return []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionAttachment,
+			Name:        "file",
+			Description: "CSV (user_id,role) or JSON roster",
+			Required:    true,
+		},
+	}
+}
+
+// Version implements ken.SlashCommand.
+func (*Roster) Version() string {
+	return "1.0.0"
+}
+
+// Run implements ken.SlashCommand. The roster is validated and previewed
+// first; only the host who uploaded it can confirm the creation.
+func (r *Roster) Run(ctx ken.Context) (err error) {
+	defer logger.RecoverWithLog(*logger.Get())
+	r = &Roster{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), r.dbPool)}
+	if err = ctx.Defer(); err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return err
+	}
+	if !discord.IsAdminRole(ctx, discord.AdminRoles...) {
+		return discord.NotAdminError(ctx)
+	}
+
+	raw, err := rosterAttachment(ctx)
+	if err != nil {
+		return discord.ErrorMessage(ctx, "Could not read roster", err.Error())
+	}
+	entries, err := roster.Parse(raw)
+	if err != nil {
+		return discord.ErrorMessage(ctx, "Could not read roster", err.Error())
+	}
+	dbCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	svc := roster.New(r.dbPool)
+	report, err := svc.Preview(dbCtx, entries)
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("roster preview failed")
+		return discord.AlexError(ctx, "failed to validate roster")
+	}
+	if !report.Valid() {
+		return discord.ErrorMessage(ctx, "Roster needs fixing", truncate(strings.Join(report.Problems(), "\n"), 4000))
+	}
+
+	invoker := ctx.User().ID
+	guildID := discord.InteractionGuildID(ctx.GetEvent())
+	b := ctx.FollowUpEmbed(rosterPreviewEmbed(report))
+	b.AddComponents(func(cb *ken.ComponentBuilder) {
+		cb.AddActionsRow(func(b ken.ComponentAssembler) {
+			b.Add(discordgo.Button{
+				Style:    discordgo.SuccessButton,
+				CustomID: "confirm-roster",
+				Label:    "Create players",
+			}, logger.WrapKenComponent(func(cctx ken.ComponentContext) bool {
+				if err := cctx.Defer(); err != nil {
+					logger.Get().Error().Err(err).Msg("operation failed")
+					return true
+				}
+				applyCtx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+				defer cancel()
+				_, results, err := svc.Apply(applyCtx, entries, cctx.GetSession(), guildID)
+				if err != nil {
+					cctx.FollowUpEmbed(&discordgo.MessageEmbed{Title: "Roster not applied", Description: err.Error(), Color: discord.ColorThemeRed}).Send()
+					return true
+				}
+				cctx.FollowUpEmbed(rosterResultEmbed(results)).Send()
+				return true
+			}), true)
+			b.Add(discordgo.Button{
+				Style:    discordgo.DangerButton,
+				CustomID: "cancel-roster",
+				Label:    "Cancel",
+			}, logger.WrapKenComponent(func(cctx ken.ComponentContext) bool {
+				cctx.RespondEmbed(&discordgo.MessageEmbed{Title: "Roster cancelled", Description: "No players were created."})
+				return true
+			}), true)
+		}, true).
+			Condition(func(cctx ken.ComponentContext) bool {
+				return cctx.User().ID == invoker
+			})
+	})
+	return b.Send().Error
+}
+
+// rosterAttachment downloads the uploaded roster file.
+func rosterAttachment(ctx ken.Context) ([]byte, error) {
+	id := ctx.Options().GetByName("file").StringValue()
+	data := ctx.GetEvent().ApplicationCommandData()
+	if data.Resolved == nil || data.Resolved.Attachments[id] == nil {
+		return nil, errors.New("no file was attached")
+	}
+	attachment := data.Resolved.Attachments[id]
+	if attachment.Size > maxRosterBytes {
+		return nil, fmt.Errorf("%s is too large (max 1 MB)", attachment.Filename)
+	}
+	resp, err := http.Get(attachment.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s failed: %s", attachment.Filename, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxRosterBytes))
+}
+
+func rosterPreviewEmbed(report roster.Report) *discordgo.MessageEmbed {
+	lines := make([]string, 0, len(report.Rows))
+	for _, row := range report.Rows {
+		lines = append(lines, fmt.Sprintf("%s → **%s**", discord.MentionUser(row.UserID), row.Matched))
+	}
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Roster Preview (%d players)", len(report.Rows)),
+		Description: truncate(strings.Join(lines, "\n"), 4000),
+		Footer: &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Good %d · Neutral %d · Evil %d",
+			report.Alignments["GOOD"], report.Alignments["NEUTRAL"], report.Alignments["EVIL"])},
+	}
+}
+
+func rosterResultEmbed(results []roster.Result) *discordgo.MessageEmbed {
+	created := 0
+	lines := make([]string, 0, len(results))
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			lines = append(lines, fmt.Sprintf("%s %s: %s", discord.EmojiError, discord.MentionUser(result.UserID), result.Error))
+		case len(result.Warnings) > 0:
+			created++
+			lines = append(lines, fmt.Sprintf("%s %s: %s", discord.EmojiWarning, discord.MentionUser(result.UserID), strings.Join(result.Warnings, " ")))
+		default:
+			created++
+			lines = append(lines, fmt.Sprintf("%s %s → %s", discord.EmojiSuccess, discord.MentionUser(result.UserID), discord.MentionChannel(result.ChannelID)))
+		}
+	}
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Roster applied: %d of %d players created", created, len(results)),
+		Description: truncate(strings.Join(lines, "\n"), 4000),
+	}
+}
+
+func truncate(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	return text[:limit-1] + "…"
+}
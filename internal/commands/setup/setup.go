@@ -2,41 +2,21 @@ package setup
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/mccune1224/betrayal/internal/logger"
 	"math/rand"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mccune1224/betrayal/internal/discord"
 	"github.com/mccune1224/betrayal/internal/models"
 	"github.com/mccune1224/betrayal/internal/services/roledraft"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	"github.com/zekrotja/ken"
 )
 
-var activeRoleList = []string{
-	"Agent", "Amalgamation", "Anarchist",
-	"Analyst", "Backstabber", "Arsonist",
-	"Biker", "Bard", "Bartender",
-	"Cerberus", "Bomber", "Consort",
-	"Detective", "Cheater", "Director",
-	"Fisherman", "Entertainer", "Doll",
-	"Gunman", "Empress", "Forsaken Angel",
-	"Hero", "Ghost", "Gatekeeper",
-	"Hydra", "Goliath", "Hacker",
-	"Judge", "Incubus", "Highwayman",
-	"Knight", "Magician", "Hunter",
-	"The Major", "Masochist", "Jester",
-	"Medium", "Mercenary", "Juggernaut",
-	"Nurse", "Mimic", "Overlord",
-	"Seraph", "Pathologist", "Parasite",
-	"Terminal", "Salesman", "Phantom",
-	"Time Traveler", "Siren", "Psychotherapist",
-	"Undercover", "Sidekick", "Slaughterer",
-	"Wizard", "Villager", "Threatener",
-	"Yeti", "Wanderer", "Witchdoctor",
-}
-
 type Setup struct {
 	dbPool *pgxpool.Pool
 }
@@ -62,12 +42,22 @@ func (*Setup) Options() []*discordgo.ApplicationCommandOption {
 	return []*discordgo.ApplicationCommandOption{
 		discord.IntCommandArg("player_count", "number of players for the game", true),
 		discord.IntCommandArg("decept_count", "number of deceptionists for the game", false),
+		discord.IntCommandArg("seed", "seed to reproduce an earlier draw", false),
+		discord.IntCommandArg("min_good", "at least this many good roles", false),
+		discord.IntCommandArg("max_good", "at most this many good roles", false),
+		discord.IntCommandArg("min_evil", "at least this many evil roles", false),
+		discord.IntCommandArg("max_evil", "at most this many evil roles", false),
+		discord.IntCommandArg("min_neutral", "at least this many neutral roles", false),
+		discord.IntCommandArg("max_neutral", "at most this many neutral roles", false),
+		discord.StringCommandArg("banned", "comma-separated roles to leave out", false),
+		discord.StringCommandArg("required", "comma-separated roles that must be drawn", false),
 	}
 }
 
 // Run implements ken.SlashCommand.
 func (s *Setup) Run(ctx ken.Context) (err error) {
 	defer logger.RecoverWithLog(*logger.Get())
+	s = &Setup{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), s.dbPool)}
 
 	// This will prob take more than 3 seconds to run
 	if err = ctx.Defer(); err != nil {
@@ -77,7 +67,7 @@ func (s *Setup) Run(ctx ken.Context) (err error) {
 	// generate role pool
 	// and make embed view
 
-	rolePool, err := generateRoleSelectPool(s.dbPool)
+	activeRoles, err := generateRoleSelectPool(s.dbPool)
 	if err != nil {
 		logger.Get().Error().Err(err).Msg("operation failed")
 		return discord.AlexError(ctx, "failed to generate role pool")
@@ -86,9 +76,9 @@ func (s *Setup) Run(ctx ken.Context) (err error) {
 	playerCount := int(ctx.Options().GetByName("player_count").IntValue())
 
 	// Validate player count against available roles
-	if playerCount > len(rolePool) {
+	if playerCount > len(activeRoles) {
 		return discord.ErrorMessage(ctx, "Invalid Player Count",
-			fmt.Sprintf("Player count (%d) cannot exceed available roles (%d)", playerCount, len(rolePool)))
+			fmt.Sprintf("Player count (%d) cannot exceed available roles (%d)", playerCount, len(activeRoles)))
 	}
 
 	// Default grab all deceptionists from server if not specified
@@ -96,9 +86,9 @@ func (s *Setup) Run(ctx ken.Context) (err error) {
 	if decepArg, ok := ctx.Options().GetByNameOptional("decept_count"); ok {
 		decepCount = int(decepArg.IntValue())
 		// Validate deceptionist count
-		if decepCount > len(rolePool) {
+		if decepCount > len(activeRoles) {
 			return discord.ErrorMessage(ctx, "Invalid Deceptionist Count",
-				fmt.Sprintf("Deceptionist count (%d) cannot exceed available roles (%d)", decepCount, len(rolePool)))
+				fmt.Sprintf("Deceptionist count (%d) cannot exceed available roles (%d)", decepCount, len(activeRoles)))
 		}
 	} else {
 		decepts, err := getDeceptionist(ctx.GetSession(), ctx.GetEvent().GuildID)
@@ -109,11 +99,54 @@ func (s *Setup) Run(ctx ken.Context) (err error) {
 		decepCount = len(decepts)
 	}
 
-	rp := generateRolePools(rolePool, playerCount, decepCount)
-	msg := roleSetupEmbed(rp)
+	opts := roledraft.Options{Players: playerCount, Deceptionists: decepCount, Seed: rand.Int63(), Constraints: setupConstraints(ctx)}
+	if seedArg, ok := ctx.Options().GetByNameOptional("seed"); ok {
+		opts.Seed = seedArg.IntValue()
+	}
+	draft, err := roledraft.New(s.dbPool).Create(context.Background(), opts, ctx.User().Username)
+	if errors.Is(err, roledraft.ErrInvalidDraw) {
+		return discord.ErrorMessage(ctx, "Invalid Setup", err.Error())
+	}
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("operation failed")
+		return discord.AlexError(ctx, "failed to save role draft")
+	}
+	msg := roleSetupEmbed(&rolePool{deceptionOptions: draft.Pool.DeceptionOptions, randomPool: draft.Pool.RandomPool})
+	msg.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Draft #%d · seed %d · /draft pick, then /draft finish", draft.ID, draft.Seed)}
 	return ctx.RespondEmbed(msg)
 }
 
+// setupConstraints reads the optional alignment limits and role lists.
+func setupConstraints(ctx ken.Context) roledraft.Constraints {
+	c := roledraft.Constraints{Min: map[models.Alignment]int{}, Max: map[models.Alignment]int{}}
+	for _, alignment := range roledraft.Alignments {
+		name := strings.ToLower(string(alignment))
+		if opt, ok := ctx.Options().GetByNameOptional("min_" + name); ok {
+			c.Min[alignment] = int(opt.IntValue())
+		}
+		if opt, ok := ctx.Options().GetByNameOptional("max_" + name); ok {
+			c.Max[alignment] = int(opt.IntValue())
+		}
+	}
+	if opt, ok := ctx.Options().GetByNameOptional("banned"); ok {
+		c.Banned = splitRoleNames(opt.StringValue())
+	}
+	if opt, ok := ctx.Options().GetByNameOptional("required"); ok {
+		c.Required = splitRoleNames(opt.StringValue())
+	}
+	return c
+}
+
+func splitRoleNames(raw string) []string {
+	var names []string
+	for _, name := range strings.Split(raw, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // Version implements ken.SlashCommand.
 func (*Setup) Version() string {
 	return "1.0.0"
@@ -169,14 +202,6 @@ type rolePool struct {
 	randomPool []models.Role
 }
 
-func generateRolePools(roles []models.Role, playerCount, decepCount int) *rolePool {
-	generated, err := roledraft.Generate(roles, playerCount, decepCount)
-	if err != nil {
-		return &rolePool{}
-	}
-	return &rolePool{deceptionOptions: generated.DeceptionOptions, randomPool: generated.RandomPool}
-}
-
 func roleSetupEmbed(rp *rolePool) *discordgo.MessageEmbed {
 	embed := &discordgo.MessageEmbed{
 		Title:       fmt.Sprintf("Role Setup (%d)", len(rp.randomPool)),
@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mccune1224/betrayal/internal/discord"
 	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	"github.com/mccune1224/betrayal/internal/util"
 	"github.com/zekrotja/ken"
 )
@@ -92,6 +93,7 @@ func (*Tarot) Options() []*discordgo.ApplicationCommandOption {
 
 func (t *Tarot) Run(ctx ken.Context) (err error) {
 	defer logger.RecoverWithLog(*logger.Get())
+	t = &Tarot{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), t.dbPool)}
 	return ctx.HandleSubCommands(
 		ken.SubCommandHandler{Name: "draw", Run: t.draw},
 		ken.SubCommandHandler{Name: "reset", Run: t.reset},
@@ -10,6 +10,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mccune1224/betrayal/internal/discord"
 	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	"github.com/zekrotja/ken"
 )
 
@@ -115,6 +116,7 @@ func (v *View) Options() []*discordgo.ApplicationCommandOption {
 
 func (v *View) Run(ctx ken.Context) (err error) {
 	defer logger.RecoverWithLog(*logger.Get())
+	v = &View{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), v.dbPool)}
 
 	err = ctx.HandleSubCommands(
 		ken.SubCommandHandler{Name: "role", Run: v.viewRole},
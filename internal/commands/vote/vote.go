@@ -10,6 +10,7 @@ import (
 	"github.com/mccune1224/betrayal/internal/discord"
 	"github.com/mccune1224/betrayal/internal/logger"
 	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	votesvc "github.com/mccune1224/betrayal/internal/services/vote"
 	"github.com/mccune1224/betrayal/internal/util"
 	"github.com/zekrotja/ken"
@@ -94,6 +95,7 @@ func (*Vote) Options() []*discordgo.ApplicationCommandOption {
 // Run implements ken.SlashCommand.
 func (v *Vote) Run(ctx ken.Context) (err error) {
 	defer logger.RecoverWithLog(*logger.Get())
+	v = &Vote{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), v.dbPool)}
 
 	return ctx.HandleSubCommands(
 		ken.SubCommandHandler{Name: "batch", Run: v.batch},
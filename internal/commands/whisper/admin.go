@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/mccune1224/betrayal/internal/discord"
 	"github.com/mccune1224/betrayal/internal/logger"
 	"github.com/mccune1224/betrayal/internal/models"
@@ -27,6 +28,17 @@ func (w *Whisper) adminCommandGroupBuilder() ken.SubCommandGroup {
 		ken.SubCommandHandler{Name: "message-enable", Run: w.adminMessageEnable},
 		ken.SubCommandHandler{Name: "message-disable", Run: w.adminMessageDisable},
 		ken.SubCommandHandler{Name: "message-delete", Run: w.adminMessageDelete},
+		ken.SubCommandHandler{Name: "transcript", Run: w.adminTranscript},
+		ken.SubCommandHandler{Name: "quota-set", Run: w.adminQuotaSet},
+		ken.SubCommandHandler{Name: "quota-view", Run: w.adminQuotaView},
+		ken.SubCommandHandler{Name: "quota-grant", Run: w.adminQuotaGrant},
+		ken.SubCommandHandler{Name: "quota-reset", Run: w.adminQuotaReset},
+		ken.SubCommandHandler{Name: "bonus-list", Run: w.adminBonusList},
+		ken.SubCommandHandler{Name: "bonus-set", Run: w.adminBonusSet},
+		ken.SubCommandHandler{Name: "suspicion-set", Run: w.adminSuspicionSet},
+		ken.SubCommandHandler{Name: "eavesdrop-add", Run: w.adminEavesdropAdd},
+		ken.SubCommandHandler{Name: "eavesdrop-list", Run: w.adminEavesdropList},
+		ken.SubCommandHandler{Name: "eavesdrop-remove", Run: w.adminEavesdropRemove},
 	}}
 }
 
@@ -41,11 +53,46 @@ func (w *Whisper) adminCommandArgBuilder() *discordgo.ApplicationCommandOption {
 			adminSubcommand("member-add", "Add a player to a linked-player group", discord.IntCommandArg("group_id", "Group ID", true), discord.UserCommandArg(true)),
 			adminSubcommand("member-remove", "Remove a player from a linked-player group", discord.IntCommandArg("group_id", "Group ID", true), discord.UserCommandArg(true)),
 			adminSubcommand("message-list", "List doubt messages"),
-			adminSubcommand("message-create", "Add a doubt message", discord.StringCommandArg("message", "Doubt message", true)),
+			adminSubcommand("message-create", "Add a doubt message",
+				discord.StringCommandArg("message", "Doubt message", true),
+				discord.IntCommandArg("group_id", "Only for this group's private pool", false)),
 			adminSubcommand("message-update", "Edit a doubt message", discord.IntCommandArg("message_id", "Message ID", true), discord.StringCommandArg("message", "Doubt message", true)),
 			adminSubcommand("message-enable", "Enable a doubt message", discord.IntCommandArg("message_id", "Message ID", true)),
 			adminSubcommand("message-disable", "Disable a doubt message", discord.IntCommandArg("message_id", "Message ID", true)),
 			adminSubcommand("message-delete", "Soft-delete a doubt message", discord.IntCommandArg("message_id", "Message ID", true)),
+			adminSubcommand("transcript", "Review delivered whispers",
+				discord.IntCommandArg("group_id", "Only this group", false),
+				discord.IntCommandArg("day", "Only this cycle day", false),
+				discord.IntCommandArg("limit", "How many whispers to show (max 10)", false)),
+			adminSubcommand("quota-set", "Set a group's whispers per phase",
+				discord.IntCommandArg("group_id", "Group ID", true),
+				discord.IntCommandArg("allowance", "Whispers per phase (omit for unlimited)", false),
+				discord.StringCommandArg("scope", "player (each member, default) or group (shared)", false)),
+			adminSubcommand("quota-view", "View a player's remaining whispers", discord.UserCommandArg(true)),
+			adminSubcommand("quota-grant", "Grant extra whispers for this phase",
+				discord.IntCommandArg("amount", "Extra whispers", true),
+				discord.UserCommandArg(false),
+				discord.IntCommandArg("group_id", "Whole group (when no user is given)", false)),
+			adminSubcommand("quota-reset", "Restore the full allowance for the rest of this phase",
+				discord.UserCommandArg(false),
+				discord.IntCommandArg("group_id", "Whole group (when no user is given)", false)),
+			adminSubcommand("bonus-list", "List items and perks that grant extra whispers"),
+			adminSubcommand("bonus-set", "Set extra whispers per phase for an item or perk (0 removes)",
+				discord.IntCommandArg("extra", "Extra whispers per phase", true),
+				discord.StringCommandArg("item", "Item name (each held copy counts)", false),
+				discord.StringCommandArg("perk", "Perk name", false)),
+			adminSubcommand("suspicion-set", "Set a group's doubt chance and mode",
+				discord.IntCommandArg("group_id", "Group ID", true),
+				discord.IntCommandArg("chance", "Doubt chance in percent, 0-100 (omit for the global 5%)", false),
+				discord.StringCommandArg("mode", "replace (whole message, default) or garble (some words)", false)),
+			adminSubcommand("eavesdrop-add", "Let a player secretly overhear a group's whispers",
+				discord.UserCommandArg(true),
+				discord.IntCommandArg("group_id", "Group to overhear", true),
+				discord.IntCommandArg("phases", "End after this many cycle phases", false),
+				discord.IntCommandArg("messages", "End after this many overheard whispers", false),
+				discord.BoolCommandArg("redacted", "Black out every second word", false)),
+			adminSubcommand("eavesdrop-list", "List running eavesdrops", discord.IntCommandArg("group_id", "Only this group", false)),
+			adminSubcommand("eavesdrop-remove", "End an eavesdrop early", discord.IntCommandArg("eavesdrop_id", "Eavesdrop ID", true)),
 		},
 	}
 }
@@ -106,7 +153,8 @@ func (w *Whisper) adminGroupList(ctx ken.SubCommandContext) error {
 			continue
 		}
 		seen[row.ID] = true
-		lines = append(lines, fmt.Sprintf("**%s** — group `%d`", row.Name, row.ID))
+		group := models.WhisperGroup{WhisperAllowance: row.WhisperAllowance, AllowanceScope: row.AllowanceScope, SuspicionChance: row.SuspicionChance, DoubtMode: row.DoubtMode}
+		lines = append(lines, fmt.Sprintf("**%s** — group `%d` · %s · %s", row.Name, row.ID, allowanceLabel(group), suspicionLabel(group)))
 	}
 	return discord.SuccessfulMessage(ctx, "Whisper groups", strings.Join(lines, "\n"))
 }
@@ -183,11 +231,21 @@ func (w *Whisper) adminMessageCreate(ctx ken.SubCommandContext) error {
 	if message == "" || len([]rune(message)) > maxMessageLength {
 		return discord.ErrorMessage(ctx, "Invalid doubt message", "The message must contain 1 to 1000 characters.")
 	}
-	created, err := models.New(w.dbPool).CreateWhisperDoubtMessage(dbCtx, message)
+	params := models.CreateWhisperDoubtMessageParams{Message: message}
+	pool := "the global pool"
+	if opt, ok := ctx.Options().GetByNameOptional("group_id"); ok {
+		group, err := models.New(w.dbPool).GetWhisperGroup(dbCtx, opt.IntValue())
+		if err != nil {
+			return whisperCommandDBError(ctx, "Could not find whisper group", err)
+		}
+		params.GroupID = pgtype.Int8{Int64: group.ID, Valid: true}
+		pool = fmt.Sprintf("the private pool of **%s**", group.Name)
+	}
+	created, err := models.New(w.dbPool).CreateWhisperDoubtMessage(dbCtx, params)
 	if err != nil {
 		return whisperCommandDBError(ctx, "Could not create doubt message", err)
 	}
-	return discord.SuccessfulMessage(ctx, "Doubt message created", fmt.Sprintf("Created doubt message %d and enabled it.", created.ID))
+	return discord.SuccessfulMessage(ctx, "Doubt message created", fmt.Sprintf("Created doubt message %d in %s and enabled it.", created.ID, pool))
 }
 
 func (w *Whisper) adminMessageList(ctx ken.SubCommandContext) error {
@@ -205,7 +263,11 @@ func (w *Whisper) adminMessageList(ctx ken.SubCommandContext) error {
 	}
 	lines := make([]string, 0, len(rows))
 	for _, row := range rows {
-		lines = append(lines, fmt.Sprintf("**%d** — %s (%s)", row.ID, row.Message, enabledLabel(row.Enabled)))
+		pool := ""
+		if row.GroupID.Valid {
+			pool = fmt.Sprintf(", group %d only", row.GroupID.Int64)
+		}
+		lines = append(lines, fmt.Sprintf("**%d** — %s (%s%s)", row.ID, row.Message, enabledLabel(row.Enabled), pool))
 	}
 	return discord.SuccessfulMessage(ctx, "Doubt messages", strings.Join(lines, "\n"))
 }
@@ -0,0 +1,119 @@
+package whisper
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/models"
+	whispersvc "github.com/mccune1224/betrayal/internal/services/whisper"
+	"github.com/mccune1224/betrayal/internal/util"
+	"github.com/zekrotja/ken"
+)
+
+func (w *Whisper) adminEavesdropAdd(ctx ken.SubCommandContext) error {
+	dbCtx, cancel, err := w.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	user := ctx.Options().GetByName("user").UserValue(ctx)
+	playerID, err := strconv.ParseInt(user.ID, 10, 64)
+	if err != nil {
+		return discord.ErrorMessage(ctx, "Invalid eavesdrop", "The selected Discord player is invalid.")
+	}
+	effect := whispersvc.Eavesdrop{
+		PlayerID: playerID,
+		GroupID:  ctx.Options().GetByName("group_id").IntValue(),
+		By:       ctx.GetEvent().Member.User.ID,
+	}
+	if opt, ok := ctx.Options().GetByNameOptional("phases"); ok {
+		effect.Phases = int(opt.IntValue())
+	}
+	if opt, ok := ctx.Options().GetByNameOptional("messages"); ok {
+		effect.Messages = int(opt.IntValue())
+	}
+	if effect.Phases < 0 || effect.Messages < 0 {
+		return discord.ErrorMessage(ctx, "Invalid eavesdrop", "Limits cannot be negative.")
+	}
+	if opt, ok := ctx.Options().GetByNameOptional("redacted"); ok {
+		effect.Redacted = opt.BoolValue()
+	}
+	group, err := models.New(w.dbPool).GetWhisperGroup(dbCtx, effect.GroupID)
+	if err != nil {
+		return whisperCommandDBError(ctx, "Could not find whisper group", err)
+	}
+	row, err := whispersvc.New(w.dbPool).AddEavesdrop(dbCtx, effect)
+	if err != nil {
+		return whisperCommandDBError(ctx, "Could not add eavesdrop", err)
+	}
+	return discord.SuccessfulMessage(ctx, "Eavesdrop added", fmt.Sprintf(
+		"Eavesdrop `%d`: %s now overhears **%s** (%s). The group is not told.",
+		row.ID, discord.MentionUser(user.ID), group.Name, eavesdropLimitLabel(row.Redacted, row.Phases, 0, row.MessagesLeft)))
+}
+
+func (w *Whisper) adminEavesdropList(ctx ken.SubCommandContext) error {
+	dbCtx, cancel, err := w.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	var groupID *int64
+	if opt, ok := ctx.Options().GetByNameOptional("group_id"); ok {
+		value := opt.IntValue()
+		groupID = &value
+	}
+	rows, err := whispersvc.New(w.dbPool).Eavesdrops(dbCtx, groupID)
+	if err != nil {
+		return whisperCommandDBError(ctx, "Could not list eavesdrops", err)
+	}
+	if len(rows) == 0 {
+		return discord.SuccessfulMessage(ctx, "Eavesdrops", "No eavesdrops are running.")
+	}
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		line := fmt.Sprintf("`%d` — %s → group `%d` · %s", row.ID, discord.MentionUser(util.Itoa64(row.PlayerID)), row.GroupID,
+			eavesdropLimitLabel(row.Redacted, row.Phases, row.PhasesElapsed, row.MessagesLeft))
+		if !row.Alive || !row.ChannelID.Valid {
+			line += " (paused: listener dead or without a confessional)"
+		}
+		lines = append(lines, line)
+	}
+	return discord.SuccessfulMessage(ctx, "Eavesdrops", strings.Join(lines, "\n"))
+}
+
+func (w *Whisper) adminEavesdropRemove(ctx ken.SubCommandContext) error {
+	dbCtx, cancel, err := w.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	ended, err := whispersvc.New(w.dbPool).EndEavesdrop(dbCtx, ctx.Options().GetByName("eavesdrop_id").IntValue())
+	if errors.Is(err, whispersvc.ErrEavesdropNotFound) {
+		return discord.ErrorMessage(ctx, "Eavesdrop not found", "That eavesdrop does not exist or has already ended.")
+	}
+	if err != nil {
+		return whisperCommandDBError(ctx, "Could not end eavesdrop", err)
+	}
+	return discord.SuccessfulMessage(ctx, "Eavesdrop ended", fmt.Sprintf("%s no longer overhears group `%d`.", discord.MentionUser(util.Itoa64(ended.PlayerID)), ended.GroupID))
+}
+
+func eavesdropLimitLabel(redacted bool, phases pgtype.Int4, elapsed int32, messagesLeft pgtype.Int4) string {
+	parts := []string{"full copies"}
+	if redacted {
+		parts[0] = "redacted copies"
+	}
+	if phases.Valid {
+		parts = append(parts, fmt.Sprintf("%d of %d phase(s) left", phases.Int32-elapsed, phases.Int32))
+	}
+	if messagesLeft.Valid {
+		parts = append(parts, fmt.Sprintf("%d whisper(s) left", messagesLeft.Int32))
+	}
+	if !phases.Valid && !messagesLeft.Valid {
+		parts = append(parts, "until removed")
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,219 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/models"
+	whispersvc "github.com/mccune1224/betrayal/internal/services/whisper"
+	"github.com/mccune1224/betrayal/internal/util"
+	"github.com/zekrotja/ken"
+)
+
+func (w *Whisper) adminQuotaSet(ctx ken.SubCommandContext) error {
+	dbCtx, cancel, err := w.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	groupID := ctx.Options().GetByName("group_id").IntValue()
+	var allowance *int
+	if opt, ok := ctx.Options().GetByNameOptional("allowance"); ok {
+		value := int(opt.IntValue())
+		if value < 0 {
+			return discord.ErrorMessage(ctx, "Invalid whisper quota", "The allowance cannot be negative.")
+		}
+		allowance = &value
+	}
+	rawScope := ""
+	if opt, ok := ctx.Options().GetByNameOptional("scope"); ok {
+		rawScope = opt.StringValue()
+	}
+	scope, err := whispersvc.ParseScope(rawScope)
+	if err != nil {
+		return discord.ErrorMessage(ctx, "Invalid whisper quota", err.Error())
+	}
+	group, err := whispersvc.New(w.dbPool).SetAllowance(dbCtx, groupID, allowance, scope)
+	if err != nil {
+		return whisperCommandDBError(ctx, "Could not update whisper quota", err)
+	}
+	return discord.SuccessfulMessage(ctx, "Whisper quota updated", fmt.Sprintf("**%s** now has %s.", group.Name, allowanceLabel(group)))
+}
+
+func (w *Whisper) adminQuotaView(ctx ken.SubCommandContext) error {
+	dbCtx, cancel, err := w.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	playerID, groupID, err := w.quotaTarget(dbCtx, ctx, true)
+	if err != nil {
+		return err
+	}
+	quota, err := whispersvc.New(w.dbPool).Quota(dbCtx, groupID, *playerID)
+	if err != nil {
+		return whisperCommandDBError(ctx, "Could not load whisper quota", err)
+	}
+	if !quota.Limited {
+		return discord.SuccessfulMessage(ctx, "Whisper quota", fmt.Sprintf("%s has unlimited whispers (group %d).", discord.MentionUser(util.Itoa64(*playerID)), groupID))
+	}
+	return discord.SuccessfulMessage(ctx, "Whisper quota", fmt.Sprintf(
+		"%s (group %d, %s allowance)\nRemaining: **%d** of %d\nAllowance %d · Bonus %d · Granted %d · Used %d",
+		discord.MentionUser(util.Itoa64(*playerID)), groupID, quota.Scope,
+		quota.Remaining(), quota.Total(), quota.Allowance, quota.Bonus, quota.Granted, quota.Used))
+}
+
+func (w *Whisper) adminQuotaGrant(ctx ken.SubCommandContext) error {
+	dbCtx, cancel, err := w.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	amount := int(ctx.Options().GetByName("amount").IntValue())
+	if amount <= 0 {
+		return discord.ErrorMessage(ctx, "Invalid whisper grant", "The amount must be positive.")
+	}
+	playerID, groupID, err := w.quotaTarget(dbCtx, ctx, false)
+	if err != nil {
+		return err
+	}
+	if _, err := whispersvc.New(w.dbPool).Grant(dbCtx, groupID, playerID, amount, ctx.GetEvent().Member.User.ID); err != nil {
+		return whisperCommandDBError(ctx, "Could not grant whispers", err)
+	}
+	return discord.SuccessfulMessage(ctx, "Whispers granted", fmt.Sprintf("Granted %d extra whisper(s) to %s for this phase.", amount, quotaTargetLabel(playerID, groupID)))
+}
+
+func (w *Whisper) adminQuotaReset(ctx ken.SubCommandContext) error {
+	dbCtx, cancel, err := w.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	playerID, groupID, err := w.quotaTarget(dbCtx, ctx, false)
+	if err != nil {
+		return err
+	}
+	if _, err := whispersvc.New(w.dbPool).Reset(dbCtx, groupID, playerID, ctx.GetEvent().Member.User.ID); err != nil {
+		return whisperCommandDBError(ctx, "Could not reset whisper quota", err)
+	}
+	return discord.SuccessfulMessage(ctx, "Whisper quota reset", fmt.Sprintf("Restored the full allowance of %s for the rest of this phase.", quotaTargetLabel(playerID, groupID)))
+}
+
+// quotaTarget resolves the optional user and group_id options. A user wins and
+// is looked up in its group; otherwise the whole group is targeted. When
+// needPlayer is set a user is required. Failures are already responded to.
+func (w *Whisper) quotaTarget(dbCtx context.Context, ctx ken.SubCommandContext, needPlayer bool) (*int64, int64, error) {
+	if opt, ok := ctx.Options().GetByNameOptional("user"); ok {
+		user := opt.UserValue(ctx)
+		playerID, err := strconv.ParseInt(user.ID, 10, 64)
+		if err != nil {
+			return nil, 0, discord.ErrorMessage(ctx, "Invalid whisper quota target", "The selected Discord player is invalid.")
+		}
+		groupID, err := whispersvc.New(w.dbPool).GroupOf(dbCtx, playerID)
+		if errors.Is(err, whispersvc.ErrNotInGroup) {
+			return nil, 0, discord.ErrorMessage(ctx, "Invalid whisper quota target", "That player is not in a whisper group.")
+		}
+		if err != nil {
+			return nil, 0, whisperCommandDBError(ctx, "Could not find whisper group", err)
+		}
+		return &playerID, groupID, nil
+	}
+	if needPlayer {
+		return nil, 0, discord.ErrorMessage(ctx, "Invalid whisper quota target", "Choose a player.")
+	}
+	opt, ok := ctx.Options().GetByNameOptional("group_id")
+	if !ok {
+		return nil, 0, discord.ErrorMessage(ctx, "Invalid whisper quota target", "Choose a player or a group_id.")
+	}
+	group, err := models.New(w.dbPool).GetWhisperGroup(dbCtx, opt.IntValue())
+	if err != nil {
+		return nil, 0, whisperCommandDBError(ctx, "Could not find whisper group", err)
+	}
+	return nil, group.ID, nil
+}
+
+func (w *Whisper) adminBonusList(ctx ken.SubCommandContext) error {
+	dbCtx, cancel, err := w.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	bonuses, err := whispersvc.New(w.dbPool).Bonuses(dbCtx)
+	if err != nil {
+		return whisperCommandDBError(ctx, "Could not list whisper bonuses", err)
+	}
+	if len(bonuses) == 0 {
+		return discord.SuccessfulMessage(ctx, "Whisper bonuses", "No items or perks grant extra whispers.")
+	}
+	lines := make([]string, 0, len(bonuses))
+	for _, bonus := range bonuses {
+		kind := "perk"
+		if bonus.ItemID.Valid {
+			kind = "item, per copy"
+		}
+		lines = append(lines, fmt.Sprintf("**%s** (%s) — +%d per phase", bonus.SourceName, kind, bonus.Extra))
+	}
+	return discord.SuccessfulMessage(ctx, "Whisper bonuses", strings.Join(lines, "\n"))
+}
+
+func (w *Whisper) adminBonusSet(ctx ken.SubCommandContext) error {
+	dbCtx, cancel, err := w.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	extra := int(ctx.Options().GetByName("extra").IntValue())
+	itemOpt, hasItem := ctx.Options().GetByNameOptional("item")
+	perkOpt, hasPerk := ctx.Options().GetByNameOptional("perk")
+	if hasItem == hasPerk {
+		return discord.ErrorMessage(ctx, "Invalid whisper bonus", "Choose exactly one item or perk.")
+	}
+	q := models.New(w.dbPool)
+	svc := whispersvc.New(w.dbPool)
+	var name string
+	if hasItem {
+		item, err := q.GetItemByFuzzy(dbCtx, itemOpt.StringValue())
+		if err != nil {
+			return whisperCommandDBError(ctx, "Could not find item", err)
+		}
+		name, err = item.Name, svc.SetItemBonus(dbCtx, item.ID, extra)
+		if err != nil {
+			return whisperCommandDBError(ctx, "Could not update whisper bonus", err)
+		}
+	} else {
+		perk, err := q.GetPerkInfoByFuzzy(dbCtx, perkOpt.StringValue())
+		if err != nil {
+			return whisperCommandDBError(ctx, "Could not find perk", err)
+		}
+		name, err = perk.Name, svc.SetPerkBonus(dbCtx, perk.ID, extra)
+		if err != nil {
+			return whisperCommandDBError(ctx, "Could not update whisper bonus", err)
+		}
+	}
+	if extra <= 0 {
+		return discord.SuccessfulMessage(ctx, "Whisper bonus removed", fmt.Sprintf("**%s** no longer grants extra whispers.", name))
+	}
+	return discord.SuccessfulMessage(ctx, "Whisper bonus updated", fmt.Sprintf("**%s** now grants %d extra whisper(s) per phase.", name, extra))
+}
+
+func allowanceLabel(group models.WhisperGroup) string {
+	if !group.WhisperAllowance.Valid {
+		return "unlimited whispers"
+	}
+	per := "each member"
+	if whispersvc.Scope(group.AllowanceScope) == whispersvc.ScopeGroup {
+		per = "the group, shared"
+	}
+	return fmt.Sprintf("%d whisper(s) per phase for %s", group.WhisperAllowance.Int32, per)
+}
+
+func quotaTargetLabel(playerID *int64, groupID int64) string {
+	if playerID != nil {
+		return discord.MentionUser(util.Itoa64(*playerID))
+	}
+	return fmt.Sprintf("group %d", groupID)
+}
@@ -0,0 +1,53 @@
+package whisper
+
+import (
+	"fmt"
+
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/models"
+	whispersvc "github.com/mccune1224/betrayal/internal/services/whisper"
+	"github.com/zekrotja/ken"
+)
+
+func (w *Whisper) adminSuspicionSet(ctx ken.SubCommandContext) error {
+	dbCtx, cancel, err := w.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	groupID := ctx.Options().GetByName("group_id").IntValue()
+	var chance *float64
+	if opt, ok := ctx.Options().GetByNameOptional("chance"); ok {
+		percent := opt.IntValue()
+		if percent < 0 || percent > 100 {
+			return discord.ErrorMessage(ctx, "Invalid suspicion", "The chance must be between 0 and 100 percent.")
+		}
+		value := float64(percent) / 100
+		chance = &value
+	}
+	rawMode := ""
+	if opt, ok := ctx.Options().GetByNameOptional("mode"); ok {
+		rawMode = opt.StringValue()
+	}
+	mode, err := whispersvc.ParseDoubtMode(rawMode)
+	if err != nil {
+		return discord.ErrorMessage(ctx, "Invalid suspicion", err.Error())
+	}
+	group, err := whispersvc.New(w.dbPool).SetSuspicion(dbCtx, groupID, chance, mode)
+	if err != nil {
+		return whisperCommandDBError(ctx, "Could not update suspicion", err)
+	}
+	return discord.SuccessfulMessage(ctx, "Suspicion updated", fmt.Sprintf("**%s** now has %s.", group.Name, suspicionLabel(group)))
+}
+
+func suspicionLabel(group models.WhisperGroup) string {
+	suspicion := whispersvc.SuspicionOf(group)
+	label := fmt.Sprintf("%g%% doubt", suspicion.Chance*100)
+	if !group.SuspicionChance.Valid {
+		label += " (global)"
+	}
+	if suspicion.Mode == whispersvc.DoubtGarble {
+		return label + ", garbling words"
+	}
+	return label + ", replacing messages"
+}
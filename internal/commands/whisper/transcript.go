@@ -0,0 +1,88 @@
+package whisper
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/models"
+	whispersvc "github.com/mccune1224/betrayal/internal/services/whisper"
+	"github.com/mccune1224/betrayal/internal/util"
+	"github.com/zekrotja/ken"
+)
+
+// maxTranscriptEntries keeps the transcript embed within Discord's 25 field
+// and 6000 character limits.
+const maxTranscriptEntries = 10
+
+func (w *Whisper) adminTranscript(ctx ken.SubCommandContext) error {
+	dbCtx, cancel, err := w.adminContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	filter := whispersvc.TranscriptFilter{Limit: maxTranscriptEntries}
+	if opt, ok := ctx.Options().GetByNameOptional("group_id"); ok {
+		id := opt.IntValue()
+		filter.GroupID = &id
+	}
+	if opt, ok := ctx.Options().GetByNameOptional("day"); ok {
+		day := int32(opt.IntValue())
+		filter.CycleDay = &day
+	}
+	if opt, ok := ctx.Options().GetByNameOptional("limit"); ok {
+		filter.Limit = int32(min(max(opt.IntValue(), 1), maxTranscriptEntries))
+	}
+	entries, err := whispersvc.New(w.dbPool).Transcripts(dbCtx, filter)
+	if err != nil {
+		return whisperCommandDBError(ctx, "Could not load whisper transcript", err)
+	}
+	if len(entries) == 0 {
+		return discord.SuccessfulMessage(ctx, "Whisper transcript", "No whispers match that filter.")
+	}
+	return ctx.RespondEmbed(transcriptEmbed(entries))
+}
+
+func transcriptEmbed(entries []models.WhisperTranscript) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       "Whisper transcript",
+		Description: "Most recent whispers first. Only hosts can see this.",
+		Color:       discord.ColorThemeAmethyst,
+	}
+	for _, entry := range entries {
+		phase := "Day"
+		if entry.CycleIsElimination {
+			phase = "Elimination"
+		}
+		recipients := make([]string, 0, len(entry.RecipientIds))
+		for _, id := range entry.RecipientIds {
+			recipients = append(recipients, discord.MentionUser(util.Itoa64(id)))
+		}
+		to := strings.Join(recipients, ", ")
+		if to == "" {
+			to = "nobody"
+		}
+		value := fmt.Sprintf("%s → %s\n> %s", discord.MentionUser(util.Itoa64(entry.SenderID)), to, truncate(entry.OriginalMessage, 400))
+		if entry.WarningSent {
+			value += "\n⚠️ Doubt replaced it with: " + truncate(entry.DeliveredMessage, 300)
+		}
+		if entry.DeadRecipients > 0 {
+			value += fmt.Sprintf("\n💀 %d dead recipient(s) skipped", entry.DeadRecipients)
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("#%d · %s · %s %d · <t:%d:t>", entry.ID, entry.GroupName, phase, entry.CycleDay, entry.CreatedAt.Time.Unix()),
+			Value: value,
+		})
+	}
+	return embed
+}
+
+func truncate(text string, limit int) string {
+	text = strings.ReplaceAll(text, "\n", " ")
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
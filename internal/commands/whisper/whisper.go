@@ -4,6 +4,7 @@ import (
 	"context"
 	cryptorand "crypto/rand"
 	"errors"
+	"fmt"
 	"math/big"
 	"strings"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"github.com/mccune1224/betrayal/internal/discord"
 	"github.com/mccune1224/betrayal/internal/logger"
 	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	whispersvc "github.com/mccune1224/betrayal/internal/services/whisper"
 	"github.com/mccune1224/betrayal/internal/util"
 	"github.com/zekrotja/ken"
@@ -49,11 +51,13 @@ func (w *WhisperAdmin) Options() []*discordgo.ApplicationCommandOption {
 
 func (w *Whisper) Run(ctx ken.Context) error {
 	defer logger.RecoverWithLog(*logger.Get())
+	w = &Whisper{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), w.dbPool)}
 	return w.send(ctx)
 }
 
 func (w *WhisperAdmin) Run(ctx ken.Context) error {
 	defer logger.RecoverWithLog(*logger.Get())
+	w = &WhisperAdmin{Whisper{dbPool: tenancy.PoolFor(discord.InteractionGuildID(ctx.GetEvent()), w.dbPool)}}
 	group := w.Whisper.adminCommandGroupBuilder()
 	return ctx.HandleSubCommands(group.SubHandler...)
 }
@@ -96,7 +100,11 @@ func (w *Whisper) send(ctx ken.Context) error {
 		return discord.ErrorMessage(ctx, "Whisper unavailable", "Whisper delivery is temporarily unavailable.")
 	}
 	confessionals := make([]whispersvc.PlayerConfessional, 0, len(rows)+1)
+	var senderGroup models.ListWhisperGroupMembersRow
 	for _, row := range rows {
+		if row.PlayerID == senderID {
+			senderGroup = row
+		}
 		channelID := ""
 		if row.ChannelID.Valid {
 			channelID = util.Itoa64(row.ChannelID.Int64)
@@ -110,13 +118,18 @@ func (w *Whisper) send(ctx ken.Context) error {
 		}
 		return discord.ErrorMessage(ctx, "Whisper unavailable", "That player is not available for complete whisper delivery.")
 	}
-	warnings, err := q.ListEnabledWhisperDoubtMessages(dbCtx)
+	suspicion, warningPool, err := whispersvc.New(w.dbPool).Doubt(dbCtx, senderGroup.GroupID)
 	if err != nil {
 		return discord.ErrorMessage(ctx, "Whisper unavailable", "Whisper delivery is temporarily unavailable.")
 	}
-	warningPool := make([]string, 0, len(warnings))
-	for _, warning := range warnings {
-		warningPool = append(warningPool, warning.Message)
+	// The whisper is drawn from the allowance before delivery and handed back
+	// only when nobody received it.
+	reservation, err := whispersvc.New(w.dbPool).Reserve(dbCtx, senderGroup.GroupID, senderID)
+	if errors.Is(err, whispersvc.ErrQuotaExhausted) {
+		return discord.ErrorMessage(ctx, "Whisper unavailable", "The mirrors have gone quiet for you. No whispers remain this phase.")
+	}
+	if err != nil {
+		return discord.ErrorMessage(ctx, "Whisper unavailable", "Whisper delivery is temporarily unavailable.")
 	}
 
 	sender := sessionSender{session: ctx.GetSession()}
@@ -127,12 +140,42 @@ func (w *Whisper) send(ctx ken.Context) error {
 		GroupSize:           delivery.GroupSize,
 		AliveRecipients:     delivery.AliveRecipients,
 		DeadRecipients:      delivery.DeadRecipients,
+		Suspicion:           &suspicion,
 	}, sender, secureRoller{}, warningPool)
+	if len(result.DeliveredRecipientChannels) > 0 {
+		w.recordTranscript(dbCtx, senderID, message, senderGroup, delivery, result)
+		// Eavesdroppers are served silently and hear what the group heard;
+		// the sender's receipt never reveals them.
+		if _, err := whispersvc.New(w.dbPool).Intercept(dbCtx, senderGroup.GroupID, result.DeliveredText, sender); err != nil {
+			logger.Get().Error().Err(err).Int64("group_id", senderGroup.GroupID).Msg("whisper eavesdrop delivery failed")
+		}
+	} else if releaseErr := whispersvc.New(w.dbPool).Release(dbCtx, reservation); releaseErr != nil {
+		logger.Get().Error().Err(releaseErr).Int64("sender_id", senderID).Msg("whisper reservation could not be released")
+	}
 	if err != nil {
 		return discord.ErrorMessage(ctx, "Whisper delivery failed", "The complete whisper could not be delivered. Please try again later.")
 	}
+	receipt := result.SenderStatus
+	if quota := reservation.Quota; quota.Limited {
+		receipt += fmt.Sprintf("\n\nWhispers remaining this phase: **%d**", quota.Remaining())
+	}
 	ctx.SetEphemeral(true)
-	return ctx.RespondEmbed(&discordgo.MessageEmbed{Title: "Whisper sent", Description: result.SenderStatus, Color: discord.ColorThemeGreen})
+	return ctx.RespondEmbed(&discordgo.MessageEmbed{Title: "Whisper sent", Description: receipt, Color: discord.ColorThemeGreen})
+}
+
+// recordTranscript stores the delivered whisper for host review. A storage
+// failure is logged but never undoes a whisper players have already seen.
+func (w *Whisper) recordTranscript(ctx context.Context, senderID int64, message string, group models.ListWhisperGroupMembersRow, delivery whispersvc.SenderDelivery, result whispersvc.DeliveryResult) {
+	entry := whispersvc.NewTranscriptEntry(senderID, message, delivery, result)
+	entry.GroupID, entry.GroupName = group.GroupID, group.Name
+	cycle, err := models.New(w.dbPool).GetCycle(ctx)
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("whisper transcript: unable to read cycle")
+	}
+	entry.Cycle = cycle
+	if _, err := whispersvc.New(w.dbPool).RecordTranscript(ctx, entry); err != nil {
+		logger.Get().Error().Err(err).Int64("sender_id", senderID).Msg("whisper transcript could not be stored")
+	}
 }
 
 type sessionSender struct{ session *discordgo.Session }
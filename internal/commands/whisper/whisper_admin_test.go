@@ -16,7 +16,7 @@ func TestOptionsExposeAdminWhisperManagement(t *testing.T) {
 		t.Fatal("player /whisper command must not expose admin subcommands")
 	}
 	admin := (&WhisperAdmin{}).Options()
-	for _, name := range []string{"group-list", "group-create", "group-delete", "member-add", "member-remove", "message-list", "message-create", "message-update", "message-enable", "message-disable", "message-delete"} {
+	for _, name := range []string{"group-list", "group-create", "group-delete", "member-add", "member-remove", "message-list", "message-create", "message-update", "message-enable", "message-disable", "message-delete", "transcript", "quota-set", "quota-view", "quota-grant", "quota-reset", "bonus-list", "bonus-set", "suspicion-set", "eavesdrop-add", "eavesdrop-list", "eavesdrop-remove"} {
 		if findOption(admin, name, discordgo.ApplicationCommandOptionSubCommand) == nil {
 			t.Errorf("missing /whisper-admin %s subcommand", name)
 		}
@@ -71,3 +71,24 @@ func TestEnsureUpToDateRejectsDirtyVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestEnsureUpToDateMigratesGameSchema(t *testing.T) {
+	dsn := scratchDB(t)
+	require.NoError(t, dbmigrate.EnsureUpToDate(dsn))
+
+	conn, err := pgx.Connect(context.Background(), dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close(context.Background()) })
+	_, err = conn.Exec(context.Background(), "CREATE SCHEMA game_42")
+	require.NoError(t, err)
+
+	schemaDSN, err := dbmigrate.SchemaDSN(dsn, "game_42")
+	require.NoError(t, err)
+	require.NoError(t, dbmigrate.EnsureUpToDate(schemaDSN))
+
+	var players int
+	err = conn.QueryRow(context.Background(), `SELECT count(*) FROM information_schema.tables
+		WHERE table_schema = 'game_42' AND table_name IN ('player', 'schema_migrations')`).Scan(&players)
+	require.NoError(t, err)
+	require.Equal(t, 2, players, "the game schema must get its own tables and migration version")
+}
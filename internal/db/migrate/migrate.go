@@ -9,6 +9,7 @@ package dbmigrate
 import (
 	"embed"
 	"fmt"
+	"net/url"
 	"sort"
 	"strconv"
 	"strings"
@@ -41,6 +42,21 @@ type Runner struct {
 	m *migrate.Migrate
 }
 
+// SchemaDSN points dsn at schema: tables are created and found there, with
+// public kept on the search path for extension functions such as levenshtein.
+// Both the migrations and pgx honour the search_path parameter, so one game
+// schema migrates and queries exactly like the public one.
+func SchemaDSN(dsn, schema string) (string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil || u.Scheme == "" {
+		return "", fmt.Errorf("dbmigrate: schema DSN needs a postgres:// URL")
+	}
+	q := u.Query()
+	q.Set("search_path", schema+",public")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
 // EnsureUpToDate applies every embedded migration before the application
 // starts serving requests. A deploy must not advertise a healthy web server
 // while a required table such as sync_source is absent.
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS poll_ballot;
+DROP TABLE IF EXISTS poll_option;
+DROP TABLE IF EXISTS poll;
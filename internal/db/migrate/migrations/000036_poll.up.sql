@@ -0,0 +1,33 @@
+CREATE TABLE poll (
+    id BIGSERIAL PRIMARY KEY,
+    question TEXT NOT NULL CHECK (char_length(btrim(question)) BETWEEN 1 AND 500),
+    option_kind TEXT NOT NULL CHECK (option_kind IN ('player', 'text')),
+    eligibility TEXT NOT NULL DEFAULT 'alive' CHECK (eligibility IN ('all', 'alive', 'role', 'alignment')),
+    eligibility_value TEXT,
+    ranked BOOLEAN NOT NULL DEFAULT FALSE,
+    channel_id TEXT,
+    message_id TEXT,
+    created_by TEXT NOT NULL,
+    closes_at TIMESTAMPTZ,
+    closed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE poll_option (
+    id BIGSERIAL PRIMARY KEY,
+    poll_id BIGINT NOT NULL REFERENCES poll(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    label TEXT NOT NULL,
+    player_id BIGINT REFERENCES player(id) ON DELETE CASCADE,
+    UNIQUE (poll_id, position)
+);
+
+CREATE TABLE poll_ballot (
+    poll_id BIGINT NOT NULL REFERENCES poll(id) ON DELETE CASCADE,
+    voter_id BIGINT NOT NULL REFERENCES player(id) ON DELETE CASCADE,
+    option_id BIGINT NOT NULL REFERENCES poll_option(id) ON DELETE CASCADE,
+    rank INTEGER NOT NULL DEFAULT 1 CHECK (rank >= 1),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (poll_id, voter_id, rank),
+    UNIQUE (poll_id, voter_id, option_id)
+);
@@ -0,0 +1,17 @@
+-- One row per game phase. The open row (ended_at IS NULL) mirrors game_cycle;
+-- each transition closes it and opens the next in the same transaction.
+CREATE TABLE cycle_history (
+    id BIGSERIAL PRIMARY KEY,
+    day INTEGER NOT NULL,
+    is_elimination BOOLEAN NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ended_at TIMESTAMPTZ,
+    advanced_by TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX cycle_history_started_at_idx ON cycle_history (started_at);
+CREATE UNIQUE INDEX cycle_history_open_idx ON cycle_history ((ended_at IS NULL)) WHERE ended_at IS NULL;
+
+-- Start the log at the current phase; its true start time is unknown.
+INSERT INTO cycle_history (day, is_elimination, advanced_by)
+SELECT day, is_elimination, 'migration' FROM game_cycle LIMIT 1;
@@ -0,0 +1,20 @@
+-- Every delivered whisper, kept for host review. Player and group IDs are not
+-- foreign keys so the record survives roster and group changes; group_name is
+-- snapshotted for the same reason.
+CREATE TABLE whisper_transcript (
+    id BIGSERIAL PRIMARY KEY,
+    sender_id BIGINT NOT NULL,
+    group_id BIGINT NOT NULL,
+    group_name TEXT NOT NULL,
+    original_message TEXT NOT NULL,
+    delivered_message TEXT NOT NULL,
+    warning_sent BOOLEAN NOT NULL,
+    recipient_ids BIGINT[] NOT NULL DEFAULT '{}',
+    dead_recipients INTEGER NOT NULL DEFAULT 0,
+    cycle_day INTEGER NOT NULL,
+    cycle_is_elimination BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX whisper_transcript_group_day_idx ON whisper_transcript (group_id, cycle_day);
+CREATE INDEX whisper_transcript_created_at_idx ON whisper_transcript (created_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS whisper_transcript_sender_created_idx;
+DROP TABLE IF EXISTS whisper_quota_adjustment;
+DROP TABLE IF EXISTS whisper_bonus;
+ALTER TABLE whisper_group
+    DROP COLUMN IF EXISTS allowance_scope,
+    DROP COLUMN IF EXISTS whisper_allowance;
@@ -0,0 +1,31 @@
+-- Per-phase whisper allowance. NULL whisper_allowance means unlimited (the
+-- previous behaviour). 'player' gives every member the allowance; 'group'
+-- shares one allowance across the whole group.
+ALTER TABLE whisper_group
+    ADD COLUMN whisper_allowance INTEGER CHECK (whisper_allowance >= 0),
+    ADD COLUMN allowance_scope TEXT NOT NULL DEFAULT 'player' CHECK (allowance_scope IN ('player', 'group'));
+
+-- Extra whispers per phase granted by holding an item (per copy) or a perk.
+CREATE TABLE whisper_bonus (
+    id BIGSERIAL PRIMARY KEY,
+    item_id INTEGER UNIQUE REFERENCES item(id) ON DELETE CASCADE,
+    perk_id INTEGER UNIQUE REFERENCES perk_info(id) ON DELETE CASCADE,
+    extra INTEGER NOT NULL CHECK (extra > 0),
+    CHECK ((item_id IS NULL) <> (perk_id IS NULL))
+);
+
+-- Host grants and resets, scoped to the cycle_history phase they were made in.
+-- A row without player_id applies to the whole group.
+CREATE TABLE whisper_quota_adjustment (
+    id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL REFERENCES whisper_group(id) ON DELETE CASCADE,
+    player_id BIGINT,
+    kind TEXT NOT NULL CHECK (kind IN ('grant', 'reset')),
+    amount INTEGER NOT NULL DEFAULT 0,
+    cycle_history_id BIGINT REFERENCES cycle_history(id) ON DELETE CASCADE,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX whisper_quota_adjustment_phase_idx ON whisper_quota_adjustment (group_id, cycle_history_id);
+CREATE INDEX whisper_transcript_sender_created_idx ON whisper_transcript (sender_id, created_at);
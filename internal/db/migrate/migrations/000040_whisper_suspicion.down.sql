@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS whisper_doubt_message_group_idx;
+ALTER TABLE whisper_doubt_message DROP COLUMN IF EXISTS group_id;
+ALTER TABLE whisper_group
+    DROP COLUMN IF EXISTS doubt_mode,
+    DROP COLUMN IF EXISTS suspicion_chance;
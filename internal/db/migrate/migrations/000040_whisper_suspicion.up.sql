@@ -0,0 +1,13 @@
+-- Per-group suspicion. NULL suspicion_chance falls back to the global
+-- whisper.SuspicionChance. 'replace' swaps the whole whisper for a doubt
+-- message; 'garble' corrupts some of its words instead.
+ALTER TABLE whisper_group
+    ADD COLUMN suspicion_chance DOUBLE PRECISION CHECK (suspicion_chance >= 0 AND suspicion_chance <= 1),
+    ADD COLUMN doubt_mode TEXT NOT NULL DEFAULT 'replace' CHECK (doubt_mode IN ('replace', 'garble'));
+
+-- Doubt messages with a group_id form that group's private pool; the rest
+-- stay in the global pool.
+ALTER TABLE whisper_doubt_message
+    ADD COLUMN group_id BIGINT REFERENCES whisper_group(id) ON DELETE CASCADE;
+
+CREATE INDEX whisper_doubt_message_group_idx ON whisper_doubt_message (group_id);
@@ -0,0 +1,18 @@
+-- Host-attached eavesdrop effects. The player receives a copy (optionally
+-- redacted) of every whisper sent within group_id. phases limits the effect to
+-- that many cycle changes after started_cycle_history_id; messages_left limits
+-- it to that many intercepted whispers. NULL means no limit of that kind.
+CREATE TABLE whisper_eavesdrop (
+    id BIGSERIAL PRIMARY KEY,
+    player_id BIGINT NOT NULL REFERENCES player(id) ON DELETE CASCADE,
+    group_id BIGINT NOT NULL REFERENCES whisper_group(id) ON DELETE CASCADE,
+    redacted BOOLEAN NOT NULL DEFAULT FALSE,
+    phases INTEGER CHECK (phases > 0),
+    messages_left INTEGER CHECK (messages_left >= 0),
+    started_cycle_history_id BIGINT REFERENCES cycle_history(id) ON DELETE SET NULL,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ended_at TIMESTAMPTZ
+);
+
+CREATE INDEX whisper_eavesdrop_open_idx ON whisper_eavesdrop (group_id) WHERE ended_at IS NULL;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS role_draft_pick;
+DROP TABLE IF EXISTS role_draft_option;
+DROP TABLE IF EXISTS role_draft_role;
+DROP TABLE IF EXISTS role_draft;
+DROP TABLE IF EXISTS active_role;
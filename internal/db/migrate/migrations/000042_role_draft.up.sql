@@ -0,0 +1,119 @@
+-- Roles eligible for a new game's role draft. Names are matched against
+-- role.name so the list survives catalog re-syncs.
+CREATE TABLE active_role (
+    name TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO active_role (name) VALUES
+    ('Agent'),
+    ('Amalgamation'),
+    ('Analyst'),
+    ('Anarchist'),
+    ('Arsonist'),
+    ('Backstabber'),
+    ('Bard'),
+    ('Bartender'),
+    ('Biker'),
+    ('Bomber'),
+    ('Cerberus'),
+    ('Cheater'),
+    ('Consort'),
+    ('Detective'),
+    ('Director'),
+    ('Doll'),
+    ('Empress'),
+    ('Entertainer'),
+    ('Fisherman'),
+    ('Forsaken Angel'),
+    ('Gatekeeper'),
+    ('Ghost'),
+    ('Goliath'),
+    ('Gunman'),
+    ('Hacker'),
+    ('Hero'),
+    ('Highwayman'),
+    ('Hunter'),
+    ('Hydra'),
+    ('Incubus'),
+    ('Jester'),
+    ('Judge'),
+    ('Juggernaut'),
+    ('Knight'),
+    ('Magician'),
+    ('Masochist'),
+    ('Medium'),
+    ('Mercenary'),
+    ('Mimic'),
+    ('Nurse'),
+    ('Overlord'),
+    ('Parasite'),
+    ('Pathologist'),
+    ('Phantom'),
+    ('Psychotherapist'),
+    ('Salesman'),
+    ('Seraph'),
+    ('Sidekick'),
+    ('Siren'),
+    ('Slaughterer'),
+    ('Terminal'),
+    ('The Major'),
+    ('Threatener'),
+    ('Time Traveler'),
+    ('Undercover'),
+    ('Villager'),
+    ('Wanderer'),
+    ('Witchdoctor'),
+    ('Wizard'),
+    ('Yeti');
+
+-- A persisted role draft. seed reproduces the draw for the same active
+-- roles; the min/max columns bound how many roles of each alignment the random
+-- pool may hold (NULL means unbounded). A finished draft is frozen and can be
+-- handed to player creation.
+CREATE TABLE role_draft (
+    id BIGSERIAL PRIMARY KEY,
+    seed BIGINT NOT NULL,
+    player_count INTEGER NOT NULL CHECK (player_count >= 0),
+    deceptionist_count INTEGER NOT NULL CHECK (deceptionist_count >= 0),
+    min_good INTEGER CHECK (min_good >= 0),
+    max_good INTEGER CHECK (max_good >= 0),
+    min_evil INTEGER CHECK (min_evil >= 0),
+    max_evil INTEGER CHECK (max_evil >= 0),
+    min_neutral INTEGER CHECK (min_neutral >= 0),
+    max_neutral INTEGER CHECK (max_neutral >= 0),
+    banned_roles TEXT[] NOT NULL DEFAULT '{}',
+    required_roles TEXT[] NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'finished')),
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ
+);
+
+-- The random pool of a draft, in dealing order.
+CREATE TABLE role_draft_role (
+    draft_id BIGINT NOT NULL REFERENCES role_draft(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    role_id INTEGER NOT NULL REFERENCES role(id) ON DELETE CASCADE,
+    PRIMARY KEY (draft_id, position)
+);
+
+-- The good/neutral/evil choices reserved for each deceptionist slot.
+CREATE TABLE role_draft_option (
+    draft_id BIGINT NOT NULL REFERENCES role_draft(id) ON DELETE CASCADE,
+    slot INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    role_id INTEGER NOT NULL REFERENCES role(id) ON DELETE CASCADE,
+    PRIMARY KEY (draft_id, slot, position)
+);
+
+-- The role a deceptionist picked from their slot's options.
+CREATE TABLE role_draft_pick (
+    draft_id BIGINT NOT NULL REFERENCES role_draft(id) ON DELETE CASCADE,
+    slot INTEGER NOT NULL,
+    role_id INTEGER NOT NULL REFERENCES role(id) ON DELETE CASCADE,
+    player_id BIGINT,
+    picked_by TEXT NOT NULL,
+    picked_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (draft_id, slot)
+);
@@ -0,0 +1,14 @@
+-- Seat history: the player formerly keyed by previous_player_id was handed to
+-- player_id (a Discord user ID) mid-game. Rows follow the seat through later
+-- substitutions.
+CREATE TABLE player_substitution (
+    id BIGSERIAL PRIMARY KEY,
+    player_id BIGINT NOT NULL REFERENCES player(id) ON DELETE CASCADE,
+    previous_player_id BIGINT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    cycle_history_id BIGINT REFERENCES cycle_history(id) ON DELETE SET NULL,
+    substituted_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX player_substitution_player_idx ON player_substitution (player_id);
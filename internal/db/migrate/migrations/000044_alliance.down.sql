@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS alliance_member;
+DROP TABLE IF EXISTS alliance;
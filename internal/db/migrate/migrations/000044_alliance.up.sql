@@ -0,0 +1,33 @@
+-- Player alliances. With alliance approval on, an alliance stays 'pending'
+-- until a host approves it; 'active' alliances have a private channel in the
+-- alliances category. 'declined' and 'disbanded' rows are kept for history.
+CREATE TABLE alliance (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'active', 'declined', 'disbanded')),
+    channel_id TEXT NOT NULL DEFAULT '',
+    created_by BIGINT REFERENCES player(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    closed_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX alliance_open_name_idx ON alliance (lower(name)) WHERE status IN ('pending', 'active');
+
+-- One row per membership spell, so rejoining adds a row and the history shows
+-- who was in an alliance when. 'invited' waits for the player, 'requested'
+-- for a host; 'active' members see the channel; 'left' and 'declined' close
+-- the spell.
+CREATE TABLE alliance_member (
+    id BIGSERIAL PRIMARY KEY,
+    alliance_id BIGINT NOT NULL REFERENCES alliance(id) ON DELETE CASCADE,
+    player_id BIGINT NOT NULL REFERENCES player(id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('invited', 'requested', 'active', 'left', 'declined')),
+    invited_by BIGINT REFERENCES player(id) ON DELETE SET NULL,
+    cycle_history_id BIGINT REFERENCES cycle_history(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    joined_at TIMESTAMPTZ,
+    left_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX alliance_member_open_idx ON alliance_member (alliance_id, player_id) WHERE status IN ('invited', 'requested', 'active');
+CREATE INDEX alliance_member_player_idx ON alliance_member (player_id);
@@ -0,0 +1,11 @@
+-- Games other than the primary one. Each row ties a Discord guild to a
+-- Postgres schema holding its own copy of every table; guilds without a row
+-- play the primary game in the public schema. Only public.game is read:
+-- the migrations also create an unused copy inside every game schema.
+CREATE TABLE game (
+    id BIGSERIAL PRIMARY KEY,
+    guild_id TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    schema_name TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
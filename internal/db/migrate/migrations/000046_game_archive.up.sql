@@ -0,0 +1,12 @@
+-- Snapshots of a finished game taken before a reset clears it. bundle is the
+-- versioned JSON document (one array of rows per table); counts repeats the
+-- row count per table so archives can be listed without loading the bundle.
+-- The reset never truncates this table.
+CREATE TABLE game_archive (
+    id BIGSERIAL PRIMARY KEY,
+    version INTEGER NOT NULL,
+    label TEXT NOT NULL DEFAULT '',
+    counts JSONB NOT NULL DEFAULT '{}',
+    bundle JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,15 @@
+-- Every player's inventory at the start of each phase, written after the
+-- cycle changes. inventory is the compact snapshot document (names and
+-- quantities, no catalog descriptions). A phase entered twice keeps its
+-- latest snapshot.
+CREATE TABLE inventory_snapshot (
+    id BIGSERIAL PRIMARY KEY,
+    player_id BIGINT NOT NULL REFERENCES player(id) ON DELETE CASCADE,
+    day INTEGER NOT NULL,
+    is_elimination BOOLEAN NOT NULL,
+    inventory JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (day, is_elimination, player_id)
+);
+
+CREATE INDEX inventory_snapshot_player_idx ON inventory_snapshot (player_id);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS player_note_tags_idx;
+DROP INDEX IF EXISTS player_note_search_idx;
+
+ALTER TABLE player_note
+    DROP COLUMN IF EXISTS linked_player_ids,
+    DROP COLUMN IF EXISTS cycle_day,
+    DROP COLUMN IF EXISTS author,
+    DROP COLUMN IF EXISTS tags;
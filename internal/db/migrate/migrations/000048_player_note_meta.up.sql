@@ -0,0 +1,11 @@
+-- Notes gain tags (#suspicious, #ability-used), the host who wrote them, the
+-- cycle day they are about and the players they mention. The expression
+-- index backs the cross-player full-text search.
+ALTER TABLE player_note
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN author TEXT NOT NULL DEFAULT '',
+    ADD COLUMN cycle_day INTEGER,
+    ADD COLUMN linked_player_ids BIGINT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX player_note_search_idx ON player_note USING GIN (to_tsvector('english', info));
+CREATE INDEX player_note_tags_idx ON player_note USING GIN (tags);
@@ -0,0 +1,45 @@
+-- Creation-time adjustments for a role (starting statuses, immunities and
+-- items, item limit delta, bonus coins and luck) used to live in a Go map in
+-- the provision service. They are catalog data now so hosts can edit them.
+-- Rows are keyed by the lowercased role name rather than role.id so the seed
+-- applies before the role sheets are synced and survives a role being
+-- recreated by a sync. An item name listed twice starts the player with two.
+CREATE TABLE IF NOT EXISTS role_setup (
+    role_name TEXT PRIMARY KEY,
+    statuses TEXT[] NOT NULL DEFAULT '{}',
+    immunities TEXT[] NOT NULL DEFAULT '{}',
+    items TEXT[] NOT NULL DEFAULT '{}',
+    item_limit_delta INTEGER NOT NULL DEFAULT 0,
+    bonus_coins INTEGER NOT NULL DEFAULT 0,
+    bonus_luck INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Seeded from the previous map. The absolute item limits (fisherman 8,
+-- threatener 6) become deltas against the default limit of 4.
+INSERT INTO role_setup (role_name, statuses, immunities, item_limit_delta) VALUES
+    -- Good roles
+    ('cerberus', '{}', '{Frozen,Burned}', 0),
+    ('detective', '{}', '{Blackmailed,Disabled,Despaired}', 0),
+    ('fisherman', '{}', '{}', 4),
+    ('hero', '{}', '{Madness}', 0),
+    ('nurse', '{}', '{"Death Cursed",Frozen,Paralyzed,Burned,Empowered,Drunk,Restrained,Disabled,Blackmailed,Despaired,Madness,Unlucky}', 0),
+    ('terminal', '{}', '{"Death Cursed",Frozen,Paralyzed,Burned,Empowered,Drunk,Restrained,Disabled,Blackmailed,Despaired,Madness,Unlucky}', 0),
+    ('wizard', '{}', '{Frozen,Paralyzed,Burned,Cursed}', 0),
+    ('yeti', '{}', '{Frozen}', 0),
+    -- Neutral roles
+    ('cyborg', '{}', '{Paralyzed,Frozen,Burned,Despaired,Blackmailed,Drunk}', 0),
+    ('entertainer', '{Lucky}', '{Unlucky}', 0),
+    ('magician', '{Lucky}', '{Unlucky}', 0),
+    ('masochist', '{}', '{Lucky}', 0),
+    ('succubus', '{}', '{Blackmailed}', 0),
+    -- Evil roles
+    ('arsonist', '{}', '{Burned}', 0),
+    ('cultist', '{}', '{Cursed}', 0),
+    ('director', '{}', '{Despaired,Blackmailed,Drunk}', 0),
+    ('gatekeeper', '{}', '{Restrained,Paralyzed,Frozen}', 0),
+    ('hacker', '{}', '{Disabled,Blackmailed}', 0),
+    ('highwayman', '{}', '{Madness}', 0),
+    ('imp', '{}', '{Despaired,Paralyzed}', 0),
+    ('threatener', '{}', '{}', 2)
+ON CONFLICT (role_name) DO NOTHING;
@@ -0,0 +1,17 @@
+-- Whisper allowance usage. A row is reserved under a lock on the group before
+-- a whisper is delivered and removed again when delivery fails, so
+-- concurrent sends cannot overdraw the allowance and a lost transcript write
+-- cannot refund one. Existing transcripts seed the usage so far.
+CREATE TABLE whisper_usage (
+    id BIGSERIAL PRIMARY KEY,
+    group_id BIGINT NOT NULL REFERENCES whisper_group(id) ON DELETE CASCADE,
+    sender_id BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX whisper_usage_group_created_idx ON whisper_usage (group_id, created_at);
+
+INSERT INTO whisper_usage (group_id, sender_id, created_at)
+SELECT t.group_id, t.sender_id, t.created_at
+FROM whisper_transcript t
+JOIN whisper_group g ON g.id = t.group_id;
@@ -0,0 +1,13 @@
+-- name: ListActiveRoleNames :many
+SELECT name
+FROM active_role
+ORDER BY name;
+
+-- name: AddActiveRole :exec
+INSERT INTO active_role (name)
+VALUES ($1)
+ON CONFLICT (name) DO NOTHING;
+
+-- name: DeleteActiveRole :execrows
+DELETE FROM active_role
+WHERE name = $1;
@@ -0,0 +1,74 @@
+-- name: CreateAlliance :one
+INSERT INTO alliance (name, status, created_by)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetAlliance :one
+SELECT *
+FROM alliance
+WHERE id = $1;
+
+-- name: GetOpenAllianceByName :one
+SELECT *
+FROM alliance
+WHERE lower(name) = lower($1) AND status IN ('pending', 'active');
+
+-- name: ListAlliances :many
+SELECT *
+FROM alliance
+ORDER BY created_at, id;
+
+-- name: UpdateAllianceStatus :one
+UPDATE alliance
+SET status = $2,
+    closed_at = CASE WHEN $2 IN ('declined', 'disbanded') THEN NOW() ELSE closed_at END
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateAllianceChannel :one
+UPDATE alliance
+SET channel_id = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteAlliance :exec
+DELETE FROM alliance
+WHERE id = $1;
+
+-- name: CreateAllianceMember :one
+INSERT INTO alliance_member (alliance_id, player_id, status, invited_by, cycle_history_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetOpenAllianceMember :one
+SELECT *
+FROM alliance_member
+WHERE alliance_id = $1 AND player_id = $2 AND status IN ('invited', 'requested', 'active');
+
+-- name: UpdateAllianceMemberStatus :one
+UPDATE alliance_member
+SET status = $2,
+    joined_at = CASE WHEN $2 = 'active' THEN NOW() ELSE joined_at END,
+    left_at = CASE WHEN $2 IN ('left', 'declined') THEN NOW() ELSE left_at END
+WHERE id = $1
+RETURNING *;
+
+-- name: ListAllianceMembers :many
+SELECT *
+FROM alliance_member
+WHERE alliance_id = $1
+ORDER BY created_at, id;
+
+-- name: ListPlayerAllianceMembers :many
+SELECT alliance_member.*, alliance.name AS alliance_name, alliance.channel_id AS alliance_channel_id
+FROM alliance_member
+JOIN alliance ON alliance.id = alliance_member.alliance_id
+WHERE alliance_member.player_id = $1 AND alliance_member.status IN ('invited', 'requested', 'active')
+ORDER BY alliance.name;
+
+-- name: ListRequestedAllianceMembers :many
+SELECT alliance_member.*, alliance.name AS alliance_name
+FROM alliance_member
+JOIN alliance ON alliance.id = alliance_member.alliance_id
+WHERE alliance_member.status = 'requested' AND alliance.status = 'active'
+ORDER BY alliance_member.created_at, alliance_member.id;
@@ -3,6 +3,14 @@ select *
 from game_cycle
 limit 1;
 
+-- name: GetCycleForUpdate :one
+-- Locks the cycle row until the transaction ends, so concurrent transitions
+-- apply one after another.
+select *
+from game_cycle
+limit 1
+for update;
+
 -- name: UpdateCycle :one
 update game_cycle
 set is_elimination = $1,
@@ -0,0 +1,27 @@
+-- name: CloseCycleHistory :exec
+UPDATE cycle_history
+SET ended_at = NOW()
+WHERE ended_at IS NULL;
+
+-- name: CreateCycleHistory :one
+INSERT INTO cycle_history (day, is_elimination, advanced_by)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListCycleHistory :many
+SELECT * FROM cycle_history
+ORDER BY started_at DESC, id DESC
+LIMIT $1;
+
+-- name: GetCycleHistoryAt :one
+SELECT * FROM cycle_history
+WHERE started_at <= sqlc.arg(at)::timestamptz
+  AND (ended_at IS NULL OR ended_at > sqlc.arg(at)::timestamptz)
+ORDER BY started_at DESC, id DESC
+LIMIT 1;
+
+-- name: GetOpenCycleHistory :one
+SELECT * FROM cycle_history
+WHERE ended_at IS NULL
+ORDER BY started_at DESC, id DESC
+LIMIT 1;
@@ -0,0 +1,23 @@
+-- name: CreateGame :one
+INSERT INTO game (guild_id, name, schema_name)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetGame :one
+SELECT *
+FROM game
+WHERE id = $1;
+
+-- name: GetGameByGuild :one
+SELECT *
+FROM game
+WHERE guild_id = $1;
+
+-- name: ListGames :many
+SELECT *
+FROM game
+ORDER BY id;
+
+-- name: DeleteGame :exec
+DELETE FROM game
+WHERE id = $1;
@@ -0,0 +1,14 @@
+-- name: CreateGameArchive :one
+INSERT INTO game_archive (version, label, counts, bundle)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetGameArchive :one
+SELECT *
+FROM game_archive
+WHERE id = $1;
+
+-- name: ListGameArchives :many
+SELECT id, version, label, counts, created_at
+FROM game_archive
+ORDER BY id DESC;
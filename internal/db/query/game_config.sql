@@ -18,3 +18,14 @@ ORDER BY key;
 -- name: DeleteGameConfig :exec
 DELETE FROM game_config
 WHERE key = $1;
+
+-- name: ClaimGameConfig :one
+-- Stores value only when it differs from the current one and returns the key
+-- only in that case, so concurrent callers can claim a one-shot marker.
+INSERT INTO game_config (key, value)
+VALUES ($1, $2)
+ON CONFLICT (key) DO UPDATE SET
+    value = EXCLUDED.value,
+    updated_at = NOW()
+WHERE game_config.value IS DISTINCT FROM EXCLUDED.value
+RETURNING key;
@@ -0,0 +1,17 @@
+-- name: UpsertInventorySnapshot :exec
+INSERT INTO inventory_snapshot (player_id, day, is_elimination, inventory)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (day, is_elimination, player_id)
+DO UPDATE SET inventory = EXCLUDED.inventory, created_at = NOW();
+
+-- name: ListInventorySnapshots :many
+SELECT *
+FROM inventory_snapshot
+WHERE day = $1 AND is_elimination = $2
+ORDER BY player_id;
+
+-- name: ListInventorySnapshotPhases :many
+SELECT day, is_elimination, count(*) AS players, max(created_at)::timestamptz AS taken_at
+FROM inventory_snapshot
+GROUP BY day, is_elimination
+ORDER BY day, is_elimination;
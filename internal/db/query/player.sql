@@ -10,8 +10,9 @@ from player
 ;
 
 -- name: ListPlayerLifeboard :many
-select id, alive
+select player.id, player.alive, coalesce(role.name, '')::text as role_name
 from player
+left join role on role.id = player.role_id
 ;
 
 -- name: CreatePlayer :one
@@ -1,7 +1,7 @@
 -- name: CreatePlayerNote :one
 insert into player_note 
-  (player_id, position, info) 
-values ($1, $2, $3) 
+  (player_id, position, info, tags, author, cycle_day, linked_player_ids) 
+values ($1, $2, $3, $4, $5, $6, $7) 
 returning *;
 
 -- name: DeletePlayerNote :exec
@@ -40,8 +40,22 @@ where player_id = $1
 
 -- name: UpdatePlayerNoteByPosition :one
 update player_note 
-set info = $3, updated_at = now()
+set info = $3, tags = $4, linked_player_ids = $5, updated_at = now()
 where player_id = $1 and position = $2
 returning *
 ;
 
+
+-- name: SearchPlayerNotes :many
+select *
+from player_note
+where (sqlc.narg('query')::text is null
+    or to_tsvector('english', info) @@ websearch_to_tsquery('english', sqlc.narg('query')::text))
+  and tags @> sqlc.arg('tags')::text[]
+  and (sqlc.narg('cycle_day')::int is null or cycle_day = sqlc.narg('cycle_day')::int)
+  and (sqlc.narg('player_id')::bigint is null
+    or player_id = sqlc.narg('player_id')::bigint
+    or sqlc.narg('player_id')::bigint = any(linked_player_ids))
+order by cycle_day desc nulls last, player_id, position
+limit sqlc.arg('row_limit')
+;
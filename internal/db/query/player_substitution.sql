@@ -0,0 +1,10 @@
+-- name: CreatePlayerSubstitution :one
+INSERT INTO player_substitution (player_id, previous_player_id, reason, cycle_history_id, substituted_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListPlayerSubstitutions :many
+SELECT *
+FROM player_substitution
+WHERE player_id = $1
+ORDER BY created_at, id;
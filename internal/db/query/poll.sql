@@ -0,0 +1,46 @@
+-- name: CreatePoll :one
+INSERT INTO poll (question, option_kind, eligibility, eligibility_value, ranked, closes_at, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetPoll :one
+SELECT * FROM poll WHERE id = $1;
+
+-- name: ListPolls :many
+SELECT * FROM poll
+ORDER BY created_at DESC, id DESC;
+
+-- name: SetPollMessage :exec
+UPDATE poll SET channel_id = $2, message_id = $3 WHERE id = $1;
+
+-- name: ClosePoll :one
+UPDATE poll SET closed_at = NOW()
+WHERE id = $1 AND closed_at IS NULL
+RETURNING *;
+
+-- name: CreatePollOption :one
+INSERT INTO poll_option (poll_id, position, label, player_id)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListPollOptions :many
+SELECT * FROM poll_option
+WHERE poll_id = $1
+ORDER BY position;
+
+-- name: ListPollBallots :many
+SELECT * FROM poll_ballot
+WHERE poll_id = $1
+ORDER BY voter_id, rank;
+
+-- name: ListVoterPollBallot :many
+SELECT * FROM poll_ballot
+WHERE poll_id = $1 AND voter_id = $2
+ORDER BY rank;
+
+-- name: InsertPollBallot :exec
+INSERT INTO poll_ballot (poll_id, voter_id, option_id, rank)
+VALUES ($1, $2, $3, $4);
+
+-- name: DeleteVoterPollBallot :exec
+DELETE FROM poll_ballot WHERE poll_id = $1 AND voter_id = $2;
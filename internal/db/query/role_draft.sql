@@ -0,0 +1,56 @@
+-- name: CreateRoleDraft :one
+INSERT INTO role_draft (seed, player_count, deceptionist_count, min_good, max_good, min_evil, max_evil, min_neutral, max_neutral, banned_roles, required_roles, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING *;
+
+-- name: GetRoleDraft :one
+SELECT *
+FROM role_draft
+WHERE id = $1;
+
+-- name: ListRoleDrafts :many
+SELECT *
+FROM role_draft
+ORDER BY id DESC
+LIMIT $1;
+
+-- name: FinishRoleDraft :one
+UPDATE role_draft
+SET status = 'finished', finished_at = NOW()
+WHERE id = $1 AND status = 'open'
+RETURNING *;
+
+-- name: AddRoleDraftRole :exec
+INSERT INTO role_draft_role (draft_id, position, role_id)
+VALUES ($1, $2, $3);
+
+-- name: ListRoleDraftRoles :many
+SELECT r.*
+FROM role_draft_role d
+JOIN role r ON r.id = d.role_id
+WHERE d.draft_id = $1
+ORDER BY d.position;
+
+-- name: AddRoleDraftOption :exec
+INSERT INTO role_draft_option (draft_id, slot, position, role_id)
+VALUES ($1, $2, $3, $4);
+
+-- name: ListRoleDraftOptions :many
+SELECT o.slot, r.id, r.name, r.description, r.alignment
+FROM role_draft_option o
+JOIN role r ON r.id = o.role_id
+WHERE o.draft_id = $1
+ORDER BY o.slot, o.position;
+
+-- name: UpsertRoleDraftPick :one
+INSERT INTO role_draft_pick (draft_id, slot, role_id, player_id, picked_by)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (draft_id, slot) DO UPDATE
+SET role_id = EXCLUDED.role_id, player_id = EXCLUDED.player_id, picked_by = EXCLUDED.picked_by, picked_at = NOW()
+RETURNING *;
+
+-- name: ListRoleDraftPicks :many
+SELECT *
+FROM role_draft_pick
+WHERE draft_id = $1
+ORDER BY slot;
@@ -0,0 +1,31 @@
+-- name: GetRoleSetup :one
+SELECT * FROM role_setup
+WHERE role_name = $1;
+
+-- name: ListRoleSetup :many
+SELECT * FROM role_setup
+ORDER BY role_name;
+
+-- name: UpsertRoleSetup :one
+INSERT INTO role_setup (role_name, statuses, immunities, items, item_limit_delta, bonus_coins, bonus_luck)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (role_name) DO UPDATE SET
+    statuses = EXCLUDED.statuses,
+    immunities = EXCLUDED.immunities,
+    items = EXCLUDED.items,
+    item_limit_delta = EXCLUDED.item_limit_delta,
+    bonus_coins = EXCLUDED.bonus_coins,
+    bonus_luck = EXCLUDED.bonus_luck,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteRoleSetup :exec
+DELETE FROM role_setup
+WHERE role_name = $1;
+
+-- name: RenameRoleSetup :exec
+-- Follows a role rename so its setup keeps applying. A setup already stored
+-- under the new name wins and the old row is left alone.
+UPDATE role_setup SET role_name = sqlc.arg('new_name'), updated_at = NOW()
+WHERE role_name = sqlc.arg('old_name')
+  AND NOT EXISTS (SELECT 1 FROM role_setup WHERE role_name = sqlc.arg('new_name'));
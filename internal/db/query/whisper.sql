@@ -7,7 +7,7 @@ JOIN player p ON p.id = gm.player_id
 ORDER BY g.name, gm.player_id;
 
 -- name: ListWhisperGroups :many
-SELECT g.id, g.name, gm.player_id
+SELECT g.id, g.name, g.whisper_allowance, g.allowance_scope, g.suspicion_chance, g.doubt_mode, gm.player_id
 FROM whisper_group g
 LEFT JOIN whisper_group_member gm ON gm.group_id = g.id
 ORDER BY g.name, gm.player_id;
@@ -21,6 +21,12 @@ DELETE FROM whisper_group WHERE id = $1;
 -- name: GetWhisperGroup :one
 SELECT * FROM whisper_group WHERE id = $1;
 
+-- name: SetWhisperGroupSuspicion :one
+UPDATE whisper_group
+SET suspicion_chance = $2, doubt_mode = $3
+WHERE id = $1
+RETURNING *;
+
 -- name: AddWhisperGroupMember :exec
 INSERT INTO whisper_group_member (group_id, player_id) VALUES ($1, $2);
 
@@ -29,7 +35,12 @@ DELETE FROM whisper_group_member WHERE group_id = $1 AND player_id = $2;
 
 -- name: ListEnabledWhisperDoubtMessages :many
 SELECT * FROM whisper_doubt_message
-WHERE enabled AND deleted_at IS NULL
+WHERE enabled AND deleted_at IS NULL AND group_id IS NULL
+ORDER BY id;
+
+-- name: ListEnabledWhisperGroupDoubtMessages :many
+SELECT * FROM whisper_doubt_message
+WHERE enabled AND deleted_at IS NULL AND group_id = $1
 ORDER BY id;
 
 -- name: ListWhisperDoubtMessages :many
@@ -42,7 +53,7 @@ SELECT * FROM whisper_doubt_message
 WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: CreateWhisperDoubtMessage :one
-INSERT INTO whisper_doubt_message (message) VALUES ($1) RETURNING *;
+INSERT INTO whisper_doubt_message (message, group_id) VALUES ($1, $2) RETURNING *;
 
 -- name: UpdateWhisperDoubtMessage :one
 UPDATE whisper_doubt_message
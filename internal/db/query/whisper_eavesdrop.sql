@@ -0,0 +1,29 @@
+-- name: CreateWhisperEavesdrop :one
+INSERT INTO whisper_eavesdrop (player_id, group_id, redacted, phases, messages_left, started_cycle_history_id, created_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListActiveWhisperEavesdrops :many
+SELECT e.id, e.player_id, e.group_id, e.redacted, e.phases, e.messages_left, e.created_by, e.created_at,
+    (SELECT COUNT(*) FROM cycle_history h WHERE h.id > COALESCE(e.started_cycle_history_id, 0))::integer AS phases_elapsed,
+    pc.channel_id, p.alive
+FROM whisper_eavesdrop e
+JOIN player p ON p.id = e.player_id
+LEFT JOIN player_confessional pc ON pc.player_id = e.player_id
+WHERE e.ended_at IS NULL
+  AND (sqlc.narg(group_id)::bigint IS NULL OR e.group_id = sqlc.narg(group_id))
+  AND (e.messages_left IS NULL OR e.messages_left > 0)
+  AND (e.phases IS NULL OR (SELECT COUNT(*) FROM cycle_history h WHERE h.id > COALESCE(e.started_cycle_history_id, 0)) < e.phases)
+ORDER BY e.id;
+
+-- name: ConsumeWhisperEavesdrop :exec
+UPDATE whisper_eavesdrop
+SET messages_left = messages_left - 1,
+    ended_at = CASE WHEN messages_left = 1 THEN NOW() ELSE ended_at END
+WHERE id = $1 AND messages_left > 0;
+
+-- name: EndWhisperEavesdrop :one
+UPDATE whisper_eavesdrop
+SET ended_at = NOW()
+WHERE id = $1 AND ended_at IS NULL
+RETURNING *;
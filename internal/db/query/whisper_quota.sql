@@ -0,0 +1,76 @@
+-- name: SetWhisperGroupAllowance :one
+UPDATE whisper_group
+SET whisper_allowance = $2, allowance_scope = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: GetWhisperGroupIDByPlayer :one
+SELECT group_id FROM whisper_group_member WHERE player_id = $1;
+
+-- name: ListWhisperGroupMemberIDs :many
+SELECT player_id FROM whisper_group_member
+WHERE group_id = $1
+ORDER BY player_id;
+
+-- name: LockWhisperGroup :one
+-- Serialises allowance checks for one group until the transaction ends.
+SELECT id FROM whisper_group WHERE id = $1 FOR UPDATE;
+
+-- name: CountWhisperUsageSince :one
+SELECT COUNT(*) FROM whisper_usage
+WHERE group_id = sqlc.arg(group_id)
+  AND (sqlc.narg(sender_id)::bigint IS NULL OR sender_id = sqlc.narg(sender_id))
+  AND created_at >= sqlc.arg(since)::timestamptz;
+
+-- name: CreateWhisperUsage :one
+INSERT INTO whisper_usage (group_id, sender_id) VALUES ($1, $2)
+RETURNING id;
+
+-- name: DeleteWhisperUsage :exec
+DELETE FROM whisper_usage WHERE id = $1;
+
+-- name: CreateWhisperQuotaAdjustment :one
+INSERT INTO whisper_quota_adjustment (group_id, player_id, kind, amount, cycle_history_id, created_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListWhisperQuotaAdjustments :many
+SELECT * FROM whisper_quota_adjustment
+WHERE group_id = $1 AND cycle_history_id IS NOT DISTINCT FROM $2
+ORDER BY created_at, id;
+
+-- name: SumWhisperBonus :one
+SELECT (
+    COALESCE((
+        SELECT SUM(b.extra * pi.quantity) FROM player_item pi
+        JOIN whisper_bonus b ON b.item_id = pi.item_id
+        WHERE pi.player_id = ANY(sqlc.arg(player_ids)::bigint[])
+    ), 0) + COALESCE((
+        SELECT SUM(b.extra) FROM player_perk pp
+        JOIN whisper_bonus b ON b.perk_id = pp.perk_id
+        WHERE pp.player_id = ANY(sqlc.arg(player_ids)::bigint[])
+    ), 0)
+)::integer AS extra;
+
+-- name: ListWhisperBonuses :many
+SELECT b.id, b.item_id, b.perk_id, b.extra, COALESCE(i.name, p.name)::text AS source_name
+FROM whisper_bonus b
+LEFT JOIN item i ON i.id = b.item_id
+LEFT JOIN perk_info p ON p.id = b.perk_id
+ORDER BY source_name;
+
+-- name: UpsertWhisperItemBonus :one
+INSERT INTO whisper_bonus (item_id, extra) VALUES ($1, $2)
+ON CONFLICT (item_id) DO UPDATE SET extra = EXCLUDED.extra
+RETURNING *;
+
+-- name: UpsertWhisperPerkBonus :one
+INSERT INTO whisper_bonus (perk_id, extra) VALUES ($1, $2)
+ON CONFLICT (perk_id) DO UPDATE SET extra = EXCLUDED.extra
+RETURNING *;
+
+-- name: DeleteWhisperItemBonus :exec
+DELETE FROM whisper_bonus WHERE item_id = $1;
+
+-- name: DeleteWhisperPerkBonus :exec
+DELETE FROM whisper_bonus WHERE perk_id = $1;
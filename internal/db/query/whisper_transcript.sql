@@ -0,0 +1,13 @@
+-- name: CreateWhisperTranscript :one
+INSERT INTO whisper_transcript (
+    sender_id, group_id, group_name, original_message, delivered_message,
+    warning_sent, recipient_ids, dead_recipients, cycle_day, cycle_is_elimination
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: ListWhisperTranscripts :many
+SELECT * FROM whisper_transcript
+WHERE (sqlc.narg(group_id)::bigint IS NULL OR group_id = sqlc.narg(group_id))
+  AND (sqlc.narg(cycle_day)::integer IS NULL OR cycle_day = sqlc.narg(cycle_day))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(row_limit);
@@ -42,6 +42,9 @@ type AuditWriter struct {
 	batchSize   int
 	flushTimer  *time.Ticker
 	environment string
+	// poolFor, when set, picks the database of the game a guild plays so
+	// each game keeps its own audit trail.
+	poolFor func(guildID string) *pgxpool.Pool
 }
 
 // NewAuditWriter creates a new audit writer with async batching
@@ -84,6 +87,15 @@ func (aw *AuditWriter) LogCommand(audit CommandAudit) {
 	}
 }
 
+// SetPoolResolver routes each audit to the pool poolFor returns for its
+// guild instead of the writer's own pool.
+func (aw *AuditWriter) SetPoolResolver(poolFor func(guildID string) *pgxpool.Pool) {
+	if aw == nil {
+		return
+	}
+	aw.poolFor = poolFor
+}
+
 // batchWorker accumulates audit entries and inserts them in batches
 func (aw *AuditWriter) batchWorker() {
 	defer aw.wg.Done()
@@ -136,7 +148,11 @@ func (aw *AuditWriter) insertBatch(batch []CommandAudit) {
 			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		`
 
-		if err := aw.pool.QueryRow(ctx, query,
+		pool := aw.pool
+		if aw.poolFor != nil {
+			pool = aw.poolFor(audit.GuildID)
+		}
+		if err := pool.QueryRow(ctx, query,
 			audit.CorrelationID,
 			audit.CommandName,
 			audit.UserID,
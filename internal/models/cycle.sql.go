@@ -22,6 +22,22 @@ func (q *Queries) GetCycle(ctx context.Context) (GameCycle, error) {
 	return i, err
 }
 
+const getCycleForUpdate = `-- name: GetCycleForUpdate :one
+select id, is_elimination, day
+from game_cycle
+limit 1
+for update
+`
+
+// Locks the cycle row until the transaction ends, so concurrent transitions
+// apply one after another.
+func (q *Queries) GetCycleForUpdate(ctx context.Context) (GameCycle, error) {
+	row := q.db.QueryRow(ctx, getCycleForUpdate)
+	var i GameCycle
+	err := row.Scan(&i.ID, &i.IsElimination, &i.Day)
+	return i, err
+}
+
 const updateCycle = `-- name: UpdateCycle :one
 update game_cycle
 set is_elimination = $1,
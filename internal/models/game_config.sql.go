@@ -9,6 +9,30 @@ import (
 	"context"
 )
 
+const claimGameConfig = `-- name: ClaimGameConfig :one
+INSERT INTO game_config (key, value)
+VALUES ($1, $2)
+ON CONFLICT (key) DO UPDATE SET
+    value = EXCLUDED.value,
+    updated_at = NOW()
+WHERE game_config.value IS DISTINCT FROM EXCLUDED.value
+RETURNING key
+`
+
+type ClaimGameConfigParams struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Stores value only when it differs from the current one and returns the key
+// only in that case, so concurrent callers can claim a one-shot marker.
+func (q *Queries) ClaimGameConfig(ctx context.Context, arg ClaimGameConfigParams) (string, error) {
+	row := q.db.QueryRow(ctx, claimGameConfig, arg.Key, arg.Value)
+	var key string
+	err := row.Scan(&key)
+	return key, err
+}
+
 const deleteGameConfig = `-- name: DeleteGameConfig :exec
 DELETE FROM game_config
 WHERE key = $1
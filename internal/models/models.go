@@ -126,10 +126,37 @@ type ActionChannel struct {
 	ChannelID string `json:"channel_id"`
 }
 
+type ActiveRole struct {
+	Name      string             `json:"name"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
 type AdminChannel struct {
 	ChannelID string `json:"channel_id"`
 }
 
+type Alliance struct {
+	ID        int64              `json:"id"`
+	Name      string             `json:"name"`
+	Status    string             `json:"status"`
+	ChannelID string             `json:"channel_id"`
+	CreatedBy pgtype.Int8        `json:"created_by"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ClosedAt  pgtype.Timestamptz `json:"closed_at"`
+}
+
+type AllianceMember struct {
+	ID             int64              `json:"id"`
+	AllianceID     int64              `json:"alliance_id"`
+	PlayerID       int64              `json:"player_id"`
+	Status         string             `json:"status"`
+	InvitedBy      pgtype.Int8        `json:"invited_by"`
+	CycleHistoryID pgtype.Int8        `json:"cycle_history_id"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	JoinedAt       pgtype.Timestamptz `json:"joined_at"`
+	LeftAt         pgtype.Timestamptz `json:"left_at"`
+}
+
 type Category struct {
 	ID   int32  `json:"id"`
 	Name string `json:"name"`
@@ -160,6 +187,32 @@ type CommandLogChannel struct {
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
 }
 
+type CycleHistory struct {
+	ID            int64              `json:"id"`
+	Day           int32              `json:"day"`
+	IsElimination bool               `json:"is_elimination"`
+	StartedAt     pgtype.Timestamptz `json:"started_at"`
+	EndedAt       pgtype.Timestamptz `json:"ended_at"`
+	AdvancedBy    string             `json:"advanced_by"`
+}
+
+type Game struct {
+	ID         int64              `json:"id"`
+	GuildID    string             `json:"guild_id"`
+	Name       string             `json:"name"`
+	SchemaName string             `json:"schema_name"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type GameArchive struct {
+	ID        int64              `json:"id"`
+	Version   int32              `json:"version"`
+	Label     string             `json:"label"`
+	Counts    []byte             `json:"counts"`
+	Bundle    []byte             `json:"bundle"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
 type GameConfig struct {
 	Key       string           `json:"key"`
 	Value     string           `json:"value"`
@@ -172,6 +225,15 @@ type GameCycle struct {
 	Day           int32 `json:"day"`
 }
 
+type InventorySnapshot struct {
+	ID            int64              `json:"id"`
+	PlayerID      int64              `json:"player_id"`
+	Day           int32              `json:"day"`
+	IsElimination bool               `json:"is_elimination"`
+	Inventory     []byte             `json:"inventory"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
 type Item struct {
 	ID          int32  `json:"id"`
 	Name        string `json:"name"`
@@ -256,11 +318,15 @@ type PlayerLifeboard struct {
 }
 
 type PlayerNote struct {
-	PlayerID  int64            `json:"player_id"`
-	NoteID    int32            `json:"note_id"`
-	Position  int32            `json:"position"`
-	Info      string           `json:"info"`
-	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	PlayerID        int64            `json:"player_id"`
+	NoteID          int32            `json:"note_id"`
+	Position        int32            `json:"position"`
+	Info            string           `json:"info"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+	Tags            []string         `json:"tags"`
+	Author          string           `json:"author"`
+	CycleDay        pgtype.Int4      `json:"cycle_day"`
+	LinkedPlayerIds []int64          `json:"linked_player_ids"`
 }
 
 type PlayerPerk struct {
@@ -275,6 +341,47 @@ type PlayerStatus struct {
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 }
 
+type PlayerSubstitution struct {
+	ID               int64              `json:"id"`
+	PlayerID         int64              `json:"player_id"`
+	PreviousPlayerID int64              `json:"previous_player_id"`
+	Reason           string             `json:"reason"`
+	CycleHistoryID   pgtype.Int8        `json:"cycle_history_id"`
+	SubstitutedBy    string             `json:"substituted_by"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+}
+
+type Poll struct {
+	ID               int64              `json:"id"`
+	Question         string             `json:"question"`
+	OptionKind       string             `json:"option_kind"`
+	Eligibility      string             `json:"eligibility"`
+	EligibilityValue pgtype.Text        `json:"eligibility_value"`
+	Ranked           bool               `json:"ranked"`
+	ChannelID        pgtype.Text        `json:"channel_id"`
+	MessageID        pgtype.Text        `json:"message_id"`
+	CreatedBy        string             `json:"created_by"`
+	ClosesAt         pgtype.Timestamptz `json:"closes_at"`
+	ClosedAt         pgtype.Timestamptz `json:"closed_at"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+}
+
+type PollBallot struct {
+	PollID    int64              `json:"poll_id"`
+	VoterID   int64              `json:"voter_id"`
+	OptionID  int64              `json:"option_id"`
+	Rank      int32              `json:"rank"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type PollOption struct {
+	ID       int64       `json:"id"`
+	PollID   int64       `json:"poll_id"`
+	Position int32       `json:"position"`
+	Label    string      `json:"label"`
+	PlayerID pgtype.Int8 `json:"player_id"`
+}
+
 type Role struct {
 	ID          int32     `json:"id"`
 	Name        string    `json:"name"`
@@ -287,6 +394,47 @@ type RoleAbility struct {
 	AbilityID int32 `json:"ability_id"`
 }
 
+type RoleDraft struct {
+	ID                int64              `json:"id"`
+	Seed              int64              `json:"seed"`
+	PlayerCount       int32              `json:"player_count"`
+	DeceptionistCount int32              `json:"deceptionist_count"`
+	MinGood           pgtype.Int4        `json:"min_good"`
+	MaxGood           pgtype.Int4        `json:"max_good"`
+	MinEvil           pgtype.Int4        `json:"min_evil"`
+	MaxEvil           pgtype.Int4        `json:"max_evil"`
+	MinNeutral        pgtype.Int4        `json:"min_neutral"`
+	MaxNeutral        pgtype.Int4        `json:"max_neutral"`
+	BannedRoles       []string           `json:"banned_roles"`
+	RequiredRoles     []string           `json:"required_roles"`
+	Status            string             `json:"status"`
+	CreatedBy         string             `json:"created_by"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	FinishedAt        pgtype.Timestamptz `json:"finished_at"`
+}
+
+type RoleDraftOption struct {
+	DraftID  int64 `json:"draft_id"`
+	Slot     int32 `json:"slot"`
+	Position int32 `json:"position"`
+	RoleID   int32 `json:"role_id"`
+}
+
+type RoleDraftPick struct {
+	DraftID  int64              `json:"draft_id"`
+	Slot     int32              `json:"slot"`
+	RoleID   int32              `json:"role_id"`
+	PlayerID pgtype.Int8        `json:"player_id"`
+	PickedBy string             `json:"picked_by"`
+	PickedAt pgtype.Timestamptz `json:"picked_at"`
+}
+
+type RoleDraftRole struct {
+	DraftID  int64 `json:"draft_id"`
+	Position int32 `json:"position"`
+	RoleID   int32 `json:"role_id"`
+}
+
 type RoleNameMap struct {
 	ID          int32       `json:"id"`
 	CanonicalID interface{} `json:"canonical_id"`
@@ -297,6 +445,17 @@ type RolePerk struct {
 	PerkID int32 `json:"perk_id"`
 }
 
+type RoleSetup struct {
+	RoleName       string             `json:"role_name"`
+	Statuses       []string           `json:"statuses"`
+	Immunities     []string           `json:"immunities"`
+	Items          []string           `json:"items"`
+	ItemLimitDelta int32              `json:"item_limit_delta"`
+	BonusCoins     int32              `json:"bonus_coins"`
+	BonusLuck      int32              `json:"bonus_luck"`
+	UpdatedAt      pgtype.Timestamptz `json:"updated_at"`
+}
+
 type Status struct {
 	ID           int32  `json:"id"`
 	Name         string `json:"name"`
@@ -353,15 +512,73 @@ type WhisperDoubtMessage struct {
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
 	DeletedAt pgtype.Timestamptz `json:"deleted_at"`
+	GroupID   pgtype.Int8        `json:"group_id"`
+}
+
+type WhisperBonus struct {
+	ID     int64       `json:"id"`
+	ItemID pgtype.Int4 `json:"item_id"`
+	PerkID pgtype.Int4 `json:"perk_id"`
+	Extra  int32       `json:"extra"`
+}
+
+type WhisperEavesdrop struct {
+	ID                    int64              `json:"id"`
+	PlayerID              int64              `json:"player_id"`
+	GroupID               int64              `json:"group_id"`
+	Redacted              bool               `json:"redacted"`
+	Phases                pgtype.Int4        `json:"phases"`
+	MessagesLeft          pgtype.Int4        `json:"messages_left"`
+	StartedCycleHistoryID pgtype.Int8        `json:"started_cycle_history_id"`
+	CreatedBy             string             `json:"created_by"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+	EndedAt               pgtype.Timestamptz `json:"ended_at"`
 }
 
 type WhisperGroup struct {
-	ID        int64              `json:"id"`
-	Name      string             `json:"name"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	ID               int64              `json:"id"`
+	Name             string             `json:"name"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	WhisperAllowance pgtype.Int4        `json:"whisper_allowance"`
+	AllowanceScope   string             `json:"allowance_scope"`
+	SuspicionChance  pgtype.Float8      `json:"suspicion_chance"`
+	DoubtMode        string             `json:"doubt_mode"`
 }
 
 type WhisperGroupMember struct {
 	GroupID  int64 `json:"group_id"`
 	PlayerID int64 `json:"player_id"`
 }
+
+type WhisperQuotaAdjustment struct {
+	ID             int64              `json:"id"`
+	GroupID        int64              `json:"group_id"`
+	PlayerID       pgtype.Int8        `json:"player_id"`
+	Kind           string             `json:"kind"`
+	Amount         int32              `json:"amount"`
+	CycleHistoryID pgtype.Int8        `json:"cycle_history_id"`
+	CreatedBy      string             `json:"created_by"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type WhisperTranscript struct {
+	ID                 int64              `json:"id"`
+	SenderID           int64              `json:"sender_id"`
+	GroupID            int64              `json:"group_id"`
+	GroupName          string             `json:"group_name"`
+	OriginalMessage    string             `json:"original_message"`
+	DeliveredMessage   string             `json:"delivered_message"`
+	WarningSent        bool               `json:"warning_sent"`
+	RecipientIds       []int64            `json:"recipient_ids"`
+	DeadRecipients     int32              `json:"dead_recipients"`
+	CycleDay           int32              `json:"cycle_day"`
+	CycleIsElimination bool               `json:"cycle_is_elimination"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+}
+
+type WhisperUsage struct {
+	ID        int64              `json:"id"`
+	GroupID   int64              `json:"group_id"`
+	SenderID  int64              `json:"sender_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
@@ -199,13 +199,15 @@ func (q *Queries) ListPlayer(ctx context.Context) ([]Player, error) {
 }
 
 const listPlayerLifeboard = `-- name: ListPlayerLifeboard :many
-select id, alive
+select player.id, player.alive, coalesce(role.name, '')::text as role_name
 from player
+left join role on role.id = player.role_id
 `
 
 type ListPlayerLifeboardRow struct {
-	ID    int64 `json:"id"`
-	Alive bool  `json:"alive"`
+	ID       int64  `json:"id"`
+	Alive    bool   `json:"alive"`
+	RoleName string `json:"role_name"`
 }
 
 func (q *Queries) ListPlayerLifeboard(ctx context.Context) ([]ListPlayerLifeboardRow, error) {
@@ -217,7 +219,7 @@ func (q *Queries) ListPlayerLifeboard(ctx context.Context) ([]ListPlayerLifeboar
 	var items []ListPlayerLifeboardRow
 	for rows.Next() {
 		var i ListPlayerLifeboardRow
-		if err := rows.Scan(&i.ID, &i.Alive); err != nil {
+		if err := rows.Scan(&i.ID, &i.Alive, &i.RoleName); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
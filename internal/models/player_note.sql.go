@@ -7,23 +7,37 @@ package models
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createPlayerNote = `-- name: CreatePlayerNote :one
 insert into player_note 
-  (player_id, position, info) 
-values ($1, $2, $3) 
-returning player_id, note_id, position, info, updated_at
+  (player_id, position, info, tags, author, cycle_day, linked_player_ids) 
+values ($1, $2, $3, $4, $5, $6, $7) 
+returning player_id, note_id, position, info, updated_at, tags, author, cycle_day, linked_player_ids
 `
 
 type CreatePlayerNoteParams struct {
-	PlayerID int64  `json:"player_id"`
-	Position int32  `json:"position"`
-	Info     string `json:"info"`
+	PlayerID        int64       `json:"player_id"`
+	Position        int32       `json:"position"`
+	Info            string      `json:"info"`
+	Tags            []string    `json:"tags"`
+	Author          string      `json:"author"`
+	CycleDay        pgtype.Int4 `json:"cycle_day"`
+	LinkedPlayerIds []int64     `json:"linked_player_ids"`
 }
 
 func (q *Queries) CreatePlayerNote(ctx context.Context, arg CreatePlayerNoteParams) (PlayerNote, error) {
-	row := q.db.QueryRow(ctx, createPlayerNote, arg.PlayerID, arg.Position, arg.Info)
+	row := q.db.QueryRow(ctx, createPlayerNote,
+		arg.PlayerID,
+		arg.Position,
+		arg.Info,
+		arg.Tags,
+		arg.Author,
+		arg.CycleDay,
+		arg.LinkedPlayerIds,
+	)
 	var i PlayerNote
 	err := row.Scan(
 		&i.PlayerID,
@@ -31,6 +45,10 @@ func (q *Queries) CreatePlayerNote(ctx context.Context, arg CreatePlayerNotePara
 		&i.Position,
 		&i.Info,
 		&i.UpdatedAt,
+		&i.Tags,
+		&i.Author,
+		&i.CycleDay,
+		&i.LinkedPlayerIds,
 	)
 	return i, err
 }
@@ -66,7 +84,7 @@ func (q *Queries) DeletePlayerNoteByPosition(ctx context.Context, arg DeletePlay
 }
 
 const getPlayerNote = `-- name: GetPlayerNote :one
-select player_id, note_id, position, info, updated_at
+select player_id, note_id, position, info, updated_at, tags, author, cycle_day, linked_player_ids
 from player_note
 where player_id = $1 and note_id = $2
 `
@@ -85,12 +103,16 @@ func (q *Queries) GetPlayerNote(ctx context.Context, arg GetPlayerNoteParams) (P
 		&i.Position,
 		&i.Info,
 		&i.UpdatedAt,
+		&i.Tags,
+		&i.Author,
+		&i.CycleDay,
+		&i.LinkedPlayerIds,
 	)
 	return i, err
 }
 
 const getPlayerNoteByPosition = `-- name: GetPlayerNoteByPosition :one
-select player_id, note_id, position, info, updated_at
+select player_id, note_id, position, info, updated_at, tags, author, cycle_day, linked_player_ids
 from player_note
 where player_id = $1 and position = $2
 `
@@ -109,6 +131,10 @@ func (q *Queries) GetPlayerNoteByPosition(ctx context.Context, arg GetPlayerNote
 		&i.Position,
 		&i.Info,
 		&i.UpdatedAt,
+		&i.Tags,
+		&i.Author,
+		&i.CycleDay,
+		&i.LinkedPlayerIds,
 	)
 	return i, err
 }
@@ -127,7 +153,7 @@ func (q *Queries) GetPlayerNoteCount(ctx context.Context, playerID int64) (int64
 }
 
 const listPlayerNote = `-- name: ListPlayerNote :many
-select player_id, note_id, position, info, updated_at
+select player_id, note_id, position, info, updated_at, tags, author, cycle_day, linked_player_ids
 from player_note
 where player_id = $1
 `
@@ -147,6 +173,68 @@ func (q *Queries) ListPlayerNote(ctx context.Context, playerID int64) ([]PlayerN
 			&i.Position,
 			&i.Info,
 			&i.UpdatedAt,
+			&i.Tags,
+			&i.Author,
+			&i.CycleDay,
+			&i.LinkedPlayerIds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchPlayerNotes = `-- name: SearchPlayerNotes :many
+select player_id, note_id, position, info, updated_at, tags, author, cycle_day, linked_player_ids
+from player_note
+where ($1::text is null
+    or to_tsvector('english', info) @@ websearch_to_tsquery('english', $1::text))
+  and tags @> $2::text[]
+  and ($3::int is null or cycle_day = $3::int)
+  and ($4::bigint is null
+    or player_id = $4::bigint
+    or $4::bigint = any(linked_player_ids))
+order by cycle_day desc nulls last, player_id, position
+limit $5
+`
+
+type SearchPlayerNotesParams struct {
+	Query    pgtype.Text `json:"query"`
+	Tags     []string    `json:"tags"`
+	CycleDay pgtype.Int4 `json:"cycle_day"`
+	PlayerID pgtype.Int8 `json:"player_id"`
+	RowLimit int32       `json:"row_limit"`
+}
+
+func (q *Queries) SearchPlayerNotes(ctx context.Context, arg SearchPlayerNotesParams) ([]PlayerNote, error) {
+	rows, err := q.db.Query(ctx, searchPlayerNotes,
+		arg.Query,
+		arg.Tags,
+		arg.CycleDay,
+		arg.PlayerID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PlayerNote
+	for rows.Next() {
+		var i PlayerNote
+		if err := rows.Scan(
+			&i.PlayerID,
+			&i.NoteID,
+			&i.Position,
+			&i.Info,
+			&i.UpdatedAt,
+			&i.Tags,
+			&i.Author,
+			&i.CycleDay,
+			&i.LinkedPlayerIds,
 		); err != nil {
 			return nil, err
 		}
@@ -160,19 +248,27 @@ func (q *Queries) ListPlayerNote(ctx context.Context, playerID int64) ([]PlayerN
 
 const updatePlayerNoteByPosition = `-- name: UpdatePlayerNoteByPosition :one
 update player_note 
-set info = $3, updated_at = now()
+set info = $3, tags = $4, linked_player_ids = $5, updated_at = now()
 where player_id = $1 and position = $2
-returning player_id, note_id, position, info, updated_at
+returning player_id, note_id, position, info, updated_at, tags, author, cycle_day, linked_player_ids
 `
 
 type UpdatePlayerNoteByPositionParams struct {
-	PlayerID int64  `json:"player_id"`
-	Position int32  `json:"position"`
-	Info     string `json:"info"`
+	PlayerID        int64    `json:"player_id"`
+	Position        int32    `json:"position"`
+	Info            string   `json:"info"`
+	Tags            []string `json:"tags"`
+	LinkedPlayerIds []int64  `json:"linked_player_ids"`
 }
 
 func (q *Queries) UpdatePlayerNoteByPosition(ctx context.Context, arg UpdatePlayerNoteByPositionParams) (PlayerNote, error) {
-	row := q.db.QueryRow(ctx, updatePlayerNoteByPosition, arg.PlayerID, arg.Position, arg.Info)
+	row := q.db.QueryRow(ctx, updatePlayerNoteByPosition,
+		arg.PlayerID,
+		arg.Position,
+		arg.Info,
+		arg.Tags,
+		arg.LinkedPlayerIds,
+	)
 	var i PlayerNote
 	err := row.Scan(
 		&i.PlayerID,
@@ -180,6 +276,10 @@ func (q *Queries) UpdatePlayerNoteByPosition(ctx context.Context, arg UpdatePlay
 		&i.Position,
 		&i.Info,
 		&i.UpdatedAt,
+		&i.Tags,
+		&i.Author,
+		&i.CycleDay,
+		&i.LinkedPlayerIds,
 	)
 	return i, err
 }
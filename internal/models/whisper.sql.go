@@ -26,11 +26,16 @@ func (q *Queries) AddWhisperGroupMember(ctx context.Context, arg AddWhisperGroup
 }
 
 const createWhisperDoubtMessage = `-- name: CreateWhisperDoubtMessage :one
-INSERT INTO whisper_doubt_message (message) VALUES ($1) RETURNING id, message, enabled, created_at, updated_at, deleted_at
+INSERT INTO whisper_doubt_message (message, group_id) VALUES ($1, $2) RETURNING id, message, enabled, created_at, updated_at, deleted_at, group_id
 `
 
-func (q *Queries) CreateWhisperDoubtMessage(ctx context.Context, message string) (WhisperDoubtMessage, error) {
-	row := q.db.QueryRow(ctx, createWhisperDoubtMessage, message)
+type CreateWhisperDoubtMessageParams struct {
+	Message string      `json:"message"`
+	GroupID pgtype.Int8 `json:"group_id"`
+}
+
+func (q *Queries) CreateWhisperDoubtMessage(ctx context.Context, arg CreateWhisperDoubtMessageParams) (WhisperDoubtMessage, error) {
+	row := q.db.QueryRow(ctx, createWhisperDoubtMessage, arg.Message, arg.GroupID)
 	var i WhisperDoubtMessage
 	err := row.Scan(
 		&i.ID,
@@ -39,18 +44,27 @@ func (q *Queries) CreateWhisperDoubtMessage(ctx context.Context, message string)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.GroupID,
 	)
 	return i, err
 }
 
 const createWhisperGroup = `-- name: CreateWhisperGroup :one
-INSERT INTO whisper_group (name) VALUES ($1) RETURNING id, name, created_at
+INSERT INTO whisper_group (name) VALUES ($1) RETURNING id, name, created_at, whisper_allowance, allowance_scope, suspicion_chance, doubt_mode
 `
 
 func (q *Queries) CreateWhisperGroup(ctx context.Context, name string) (WhisperGroup, error) {
 	row := q.db.QueryRow(ctx, createWhisperGroup, name)
 	var i WhisperGroup
-	err := row.Scan(&i.ID, &i.Name, &i.CreatedAt)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.WhisperAllowance,
+		&i.AllowanceScope,
+		&i.SuspicionChance,
+		&i.DoubtMode,
+	)
 	return i, err
 }
 
@@ -74,7 +88,7 @@ func (q *Queries) DeleteWhisperGroup(ctx context.Context, id int64) error {
 }
 
 const getWhisperDoubtMessage = `-- name: GetWhisperDoubtMessage :one
-SELECT id, message, enabled, created_at, updated_at, deleted_at FROM whisper_doubt_message
+SELECT id, message, enabled, created_at, updated_at, deleted_at, group_id FROM whisper_doubt_message
 WHERE id = $1 AND deleted_at IS NULL
 `
 
@@ -88,24 +102,33 @@ func (q *Queries) GetWhisperDoubtMessage(ctx context.Context, id int64) (Whisper
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.GroupID,
 	)
 	return i, err
 }
 
 const getWhisperGroup = `-- name: GetWhisperGroup :one
-SELECT id, name, created_at FROM whisper_group WHERE id = $1
+SELECT id, name, created_at, whisper_allowance, allowance_scope, suspicion_chance, doubt_mode FROM whisper_group WHERE id = $1
 `
 
 func (q *Queries) GetWhisperGroup(ctx context.Context, id int64) (WhisperGroup, error) {
 	row := q.db.QueryRow(ctx, getWhisperGroup, id)
 	var i WhisperGroup
-	err := row.Scan(&i.ID, &i.Name, &i.CreatedAt)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.WhisperAllowance,
+		&i.AllowanceScope,
+		&i.SuspicionChance,
+		&i.DoubtMode,
+	)
 	return i, err
 }
 
 const listEnabledWhisperDoubtMessages = `-- name: ListEnabledWhisperDoubtMessages :many
-SELECT id, message, enabled, created_at, updated_at, deleted_at FROM whisper_doubt_message
-WHERE enabled AND deleted_at IS NULL
+SELECT id, message, enabled, created_at, updated_at, deleted_at, group_id FROM whisper_doubt_message
+WHERE enabled AND deleted_at IS NULL AND group_id IS NULL
 ORDER BY id
 `
 
@@ -125,6 +148,41 @@ func (q *Queries) ListEnabledWhisperDoubtMessages(ctx context.Context) ([]Whispe
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.GroupID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEnabledWhisperGroupDoubtMessages = `-- name: ListEnabledWhisperGroupDoubtMessages :many
+SELECT id, message, enabled, created_at, updated_at, deleted_at, group_id FROM whisper_doubt_message
+WHERE enabled AND deleted_at IS NULL AND group_id = $1
+ORDER BY id
+`
+
+func (q *Queries) ListEnabledWhisperGroupDoubtMessages(ctx context.Context, groupID pgtype.Int8) ([]WhisperDoubtMessage, error) {
+	rows, err := q.db.Query(ctx, listEnabledWhisperGroupDoubtMessages, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WhisperDoubtMessage
+	for rows.Next() {
+		var i WhisperDoubtMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.Message,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.GroupID,
 		); err != nil {
 			return nil, err
 		}
@@ -137,7 +195,7 @@ func (q *Queries) ListEnabledWhisperDoubtMessages(ctx context.Context) ([]Whispe
 }
 
 const listWhisperDoubtMessages = `-- name: ListWhisperDoubtMessages :many
-SELECT id, message, enabled, created_at, updated_at, deleted_at FROM whisper_doubt_message
+SELECT id, message, enabled, created_at, updated_at, deleted_at, group_id FROM whisper_doubt_message
 WHERE deleted_at IS NULL
 ORDER BY id
 `
@@ -158,6 +216,7 @@ func (q *Queries) ListWhisperDoubtMessages(ctx context.Context) ([]WhisperDoubtM
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.GroupID,
 		); err != nil {
 			return nil, err
 		}
@@ -213,16 +272,20 @@ func (q *Queries) ListWhisperGroupMembers(ctx context.Context) ([]ListWhisperGro
 }
 
 const listWhisperGroups = `-- name: ListWhisperGroups :many
-SELECT g.id, g.name, gm.player_id
+SELECT g.id, g.name, g.whisper_allowance, g.allowance_scope, g.suspicion_chance, g.doubt_mode, gm.player_id
 FROM whisper_group g
 LEFT JOIN whisper_group_member gm ON gm.group_id = g.id
 ORDER BY g.name, gm.player_id
 `
 
 type ListWhisperGroupsRow struct {
-	ID       int64       `json:"id"`
-	Name     string      `json:"name"`
-	PlayerID pgtype.Int8 `json:"player_id"`
+	ID               int64         `json:"id"`
+	Name             string        `json:"name"`
+	WhisperAllowance pgtype.Int4   `json:"whisper_allowance"`
+	AllowanceScope   string        `json:"allowance_scope"`
+	SuspicionChance  pgtype.Float8 `json:"suspicion_chance"`
+	DoubtMode        string        `json:"doubt_mode"`
+	PlayerID         pgtype.Int8   `json:"player_id"`
 }
 
 func (q *Queries) ListWhisperGroups(ctx context.Context) ([]ListWhisperGroupsRow, error) {
@@ -234,7 +297,15 @@ func (q *Queries) ListWhisperGroups(ctx context.Context) ([]ListWhisperGroupsRow
 	var items []ListWhisperGroupsRow
 	for rows.Next() {
 		var i ListWhisperGroupsRow
-		if err := rows.Scan(&i.ID, &i.Name, &i.PlayerID); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.WhisperAllowance,
+			&i.AllowanceScope,
+			&i.SuspicionChance,
+			&i.DoubtMode,
+			&i.PlayerID,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -259,11 +330,39 @@ func (q *Queries) RemoveWhisperGroupMember(ctx context.Context, arg RemoveWhispe
 	return err
 }
 
+const setWhisperGroupSuspicion = `-- name: SetWhisperGroupSuspicion :one
+UPDATE whisper_group
+SET suspicion_chance = $2, doubt_mode = $3
+WHERE id = $1
+RETURNING id, name, created_at, whisper_allowance, allowance_scope, suspicion_chance, doubt_mode
+`
+
+type SetWhisperGroupSuspicionParams struct {
+	ID              int64         `json:"id"`
+	SuspicionChance pgtype.Float8 `json:"suspicion_chance"`
+	DoubtMode       string        `json:"doubt_mode"`
+}
+
+func (q *Queries) SetWhisperGroupSuspicion(ctx context.Context, arg SetWhisperGroupSuspicionParams) (WhisperGroup, error) {
+	row := q.db.QueryRow(ctx, setWhisperGroupSuspicion, arg.ID, arg.SuspicionChance, arg.DoubtMode)
+	var i WhisperGroup
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.WhisperAllowance,
+		&i.AllowanceScope,
+		&i.SuspicionChance,
+		&i.DoubtMode,
+	)
+	return i, err
+}
+
 const updateWhisperDoubtMessage = `-- name: UpdateWhisperDoubtMessage :one
 UPDATE whisper_doubt_message
 SET message = $2, enabled = $3, updated_at = NOW()
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, message, enabled, created_at, updated_at, deleted_at
+RETURNING id, message, enabled, created_at, updated_at, deleted_at, group_id
 `
 
 type UpdateWhisperDoubtMessageParams struct {
@@ -282,6 +381,7 @@ func (q *Queries) UpdateWhisperDoubtMessage(ctx context.Context, arg UpdateWhisp
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.GroupID,
 	)
 	return i, err
 }
@@ -0,0 +1,642 @@
+// Package alliance lets players form alliances, each with a private channel
+// in the alliances category. When alliance approval is on, creating an
+// alliance and accepting an invite wait for a host. Channel permissions
+// follow membership and life state: living members write, dead members keep
+// read access and members who leave lose the channel. Membership rows are
+// never deleted, so the history shows who was allied with whom and when.
+package alliance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/cycle"
+)
+
+// ApprovalConfigKey names the game_config row ("true"/"false") that makes
+// new alliances and joins wait for a host.
+const ApprovalConfigKey = "alliance_approval"
+
+// Alliance statuses.
+const (
+	StatusPending   = "pending"
+	StatusActive    = "active"
+	StatusDeclined  = "declined"
+	StatusDisbanded = "disbanded"
+)
+
+// Member statuses. Invited waits for the player, requested for a host.
+const (
+	MemberInvited   = "invited"
+	MemberRequested = "requested"
+	MemberActive    = "active"
+	MemberLeft      = "left"
+	MemberDeclined  = "declined"
+)
+
+// MemberAccess is what a living member may do in the alliance channel.
+const MemberAccess = discordgo.PermissionViewChannel | discordgo.PermissionSendMessages | discordgo.PermissionReadMessageHistory
+
+// Dead members keep reading the channel but can no longer write in it.
+const (
+	deadAllow = discordgo.PermissionViewChannel | discordgo.PermissionReadMessageHistory
+	deadDeny  = discordgo.PermissionSendMessages | discordgo.PermissionAddReactions
+)
+
+// MaxNameLength bounds alliance names so the channel name stays valid.
+const MaxNameLength = 50
+
+var (
+	ErrNotPlayer     = errors.New("not a player")
+	ErrDead          = errors.New("dead players cannot do that")
+	ErrInvalidName   = fmt.Errorf("alliance names must be 1-%d characters", MaxNameLength)
+	ErrNameTaken     = errors.New("an alliance with that name already exists")
+	ErrNotFound      = errors.New("alliance not found")
+	ErrNotMember     = errors.New("not a member of that alliance")
+	ErrAlreadyMember = errors.New("already a member of or invited to that alliance")
+	ErrNoInvite      = errors.New("no invite to that alliance")
+	ErrNotPending    = errors.New("nothing is awaiting approval")
+	ErrNoDiscord     = errors.New("Discord is not connected")
+)
+
+// Result is the outcome of an alliance change. Pending is set when a host
+// must approve it; Warnings lists the notices that could not be posted.
+type Result struct {
+	Alliance models.Alliance
+	Member   models.AllianceMember
+	Pending  bool
+	Warnings []string
+}
+
+// History is an alliance with every membership it ever had.
+type History struct {
+	Alliance models.Alliance
+	Members  []models.AllianceMember
+}
+
+type Service struct {
+	pool    *pgxpool.Pool
+	session *discordgo.Session
+}
+
+// New returns a service posting through session. Reads work without one;
+// changes that touch channels return ErrNoDiscord.
+func New(pool *pgxpool.Pool, session *discordgo.Session) *Service {
+	return &Service{pool: pool, session: session}
+}
+
+// Approval reports whether alliances and joins wait for a host.
+func Approval(ctx context.Context, q *models.Queries) bool {
+	raw, err := q.GetGameConfig(ctx, ApprovalConfigKey)
+	if err != nil {
+		return false
+	}
+	enabled, _ := strconv.ParseBool(raw)
+	return enabled
+}
+
+// SetApproval stores whether alliances and joins wait for a host.
+func SetApproval(ctx context.Context, q *models.Queries, enabled bool) error {
+	_, err := q.UpsertGameConfig(ctx, models.UpsertGameConfigParams{Key: ApprovalConfigKey, Value: strconv.FormatBool(enabled)})
+	return err
+}
+
+var channelNameDisallowed = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// ChannelName derives the Discord channel name of an alliance (pure,
+// unit-testable).
+func ChannelName(name string) string {
+	channel := channelNameDisallowed.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "-")
+	channel = strings.Trim(channel, "-")
+	if channel == "" {
+		return "alliance"
+	}
+	return channel
+}
+
+// Create starts an alliance led by playerID. Without approval the channel is
+// created in guildID's alliances category straight away.
+func (s *Service) Create(ctx context.Context, guildID string, playerID int64, name string) (Result, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > MaxNameLength {
+		return Result{}, ErrInvalidName
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	defer tx.Rollback(ctx)
+	q := models.New(tx)
+	if err := livingPlayer(ctx, q, playerID); err != nil {
+		return Result{}, err
+	}
+	if _, err := q.GetOpenAllianceByName(ctx, name); err == nil {
+		return Result{}, ErrNameTaken
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return Result{}, err
+	}
+	pending := Approval(ctx, q)
+	if !pending && s.session == nil {
+		return Result{}, ErrNoDiscord
+	}
+	status, memberStatus := StatusActive, MemberActive
+	if pending {
+		status, memberStatus = StatusPending, MemberRequested
+	}
+	creator := pgtype.Int8{Int64: playerID, Valid: true}
+	a, err := q.CreateAlliance(ctx, models.CreateAllianceParams{Name: name, Status: status, CreatedBy: creator})
+	if err != nil {
+		return Result{}, err
+	}
+	member, err := q.CreateAllianceMember(ctx, models.CreateAllianceMemberParams{
+		AllianceID: a.ID, PlayerID: playerID, Status: memberStatus, CycleHistoryID: openPhase(ctx, q),
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Alliance: a, Member: member, Pending: pending}
+	if pending {
+		if err := tx.Commit(ctx); err != nil {
+			return Result{}, err
+		}
+		result.warn(s.notifyHosts(ctx, fmt.Sprintf("%s asked to create the alliance **%s**. Approve it with `/alliance admin approve %s`.", mention(playerID), name, name)), "The hosts could not be notified")
+		return result, nil
+	}
+	if result.Alliance, err = s.openChannel(ctx, tx, q, guildID, a, []int64{playerID}); err != nil {
+		return Result{}, err
+	}
+	return result, nil
+}
+
+// Invite lets an active, living member invite another player.
+func (s *Service) Invite(ctx context.Context, inviterID, targetID int64, name string) (Result, error) {
+	q := models.New(s.pool)
+	a, err := activeAlliance(ctx, q, name)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := livingPlayer(ctx, q, inviterID); err != nil {
+		return Result{}, err
+	}
+	if m, err := q.GetOpenAllianceMember(ctx, models.GetOpenAllianceMemberParams{AllianceID: a.ID, PlayerID: inviterID}); err != nil || m.Status != MemberActive {
+		return Result{}, ErrNotMember
+	}
+	if _, err := q.GetPlayer(ctx, targetID); err != nil {
+		return Result{}, ErrNotPlayer
+	}
+	if _, err := q.GetOpenAllianceMember(ctx, models.GetOpenAllianceMemberParams{AllianceID: a.ID, PlayerID: targetID}); err == nil {
+		return Result{}, ErrAlreadyMember
+	}
+	member, err := q.CreateAllianceMember(ctx, models.CreateAllianceMemberParams{
+		AllianceID: a.ID, PlayerID: targetID, Status: MemberInvited,
+		InvitedBy: pgtype.Int8{Int64: inviterID, Valid: true}, CycleHistoryID: openPhase(ctx, q),
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Alliance: a, Member: member}
+	result.warn(s.notifyPlayer(ctx, targetID, fmt.Sprintf("%s invited you to the alliance **%s**. Use `/alliance accept %s` to join.", mention(inviterID), a.Name, a.Name)), "The invited player could not be notified")
+	return result, nil
+}
+
+// Accept takes up an invite. With approval on it becomes a join request.
+func (s *Service) Accept(ctx context.Context, playerID int64, name string) (Result, error) {
+	q := models.New(s.pool)
+	a, err := activeAlliance(ctx, q, name)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := livingPlayer(ctx, q, playerID); err != nil {
+		return Result{}, err
+	}
+	member, err := q.GetOpenAllianceMember(ctx, models.GetOpenAllianceMemberParams{AllianceID: a.ID, PlayerID: playerID})
+	if err != nil || member.Status != MemberInvited {
+		return Result{}, ErrNoInvite
+	}
+	if Approval(ctx, q) {
+		if member, err = q.UpdateAllianceMemberStatus(ctx, models.UpdateAllianceMemberStatusParams{ID: member.ID, Status: MemberRequested}); err != nil {
+			return Result{}, err
+		}
+		result := Result{Alliance: a, Member: member, Pending: true}
+		result.warn(s.notifyHosts(ctx, fmt.Sprintf("%s asked to join the alliance **%s**. Approve it with `/alliance admin approve %s` and the player.", mention(playerID), a.Name, a.Name)), "The hosts could not be notified")
+		return result, nil
+	}
+	return s.admit(ctx, q, a, member)
+}
+
+// Leave ends playerID's membership or declines their invite. An alliance
+// without active members is disbanded.
+func (s *Service) Leave(ctx context.Context, playerID int64, name string) (Result, error) {
+	q := models.New(s.pool)
+	a, err := q.GetOpenAllianceByName(ctx, strings.TrimSpace(name))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Result{}, ErrNotFound
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	member, err := q.GetOpenAllianceMember(ctx, models.GetOpenAllianceMemberParams{AllianceID: a.ID, PlayerID: playerID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Result{}, ErrNotMember
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	wasActive := member.Status == MemberActive
+	status := MemberLeft
+	if member.Status == MemberInvited {
+		status = MemberDeclined
+	}
+	if member, err = q.UpdateAllianceMemberStatus(ctx, models.UpdateAllianceMemberStatusParams{ID: member.ID, Status: status}); err != nil {
+		return Result{}, err
+	}
+	result := Result{Alliance: a, Member: member}
+	if !wasActive {
+		return result, nil
+	}
+	if a.ChannelID != "" && s.session != nil {
+		if err := s.session.ChannelPermissionDelete(a.ChannelID, strconv.FormatInt(playerID, 10)); err != nil {
+			result.warn(err, "The alliance channel could not be closed to the player")
+		}
+		if _, err := s.session.ChannelMessageSend(a.ChannelID, mention(playerID)+" left the alliance."); err != nil {
+			result.warn(err, "The alliance could not be told")
+		}
+	}
+	members, err := q.ListAllianceMembers(ctx, a.ID)
+	if err != nil {
+		return result, err
+	}
+	for _, m := range members {
+		if m.Status == MemberActive {
+			return result, nil
+		}
+	}
+	result.Alliance, err = s.close(ctx, q, a, StatusDisbanded)
+	return result, err
+}
+
+// ApproveAlliance opens a pending alliance and its channel in guildID.
+func (s *Service) ApproveAlliance(ctx context.Context, guildID, name string) (Result, error) {
+	if s.session == nil {
+		return Result{}, ErrNoDiscord
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	defer tx.Rollback(ctx)
+	q := models.New(tx)
+	a, err := pendingAlliance(ctx, q, name)
+	if err != nil {
+		return Result{}, err
+	}
+	if a, err = q.UpdateAllianceStatus(ctx, models.UpdateAllianceStatusParams{ID: a.ID, Status: StatusActive}); err != nil {
+		return Result{}, err
+	}
+	members, err := q.ListAllianceMembers(ctx, a.ID)
+	if err != nil {
+		return Result{}, err
+	}
+	var admitted []int64
+	result := Result{}
+	for _, m := range members {
+		if m.Status != MemberRequested {
+			continue
+		}
+		if result.Member, err = q.UpdateAllianceMemberStatus(ctx, models.UpdateAllianceMemberStatusParams{ID: m.ID, Status: MemberActive}); err != nil {
+			return Result{}, err
+		}
+		admitted = append(admitted, m.PlayerID)
+	}
+	if result.Alliance, err = s.openChannel(ctx, tx, q, guildID, a, admitted); err != nil {
+		return Result{}, err
+	}
+	for _, id := range admitted {
+		result.warn(s.notifyPlayer(ctx, id, fmt.Sprintf("Your alliance **%s** was approved: <#%s>", a.Name, result.Alliance.ChannelID)), "The player could not be notified")
+	}
+	return result, nil
+}
+
+// DeclineAlliance turns a pending alliance down.
+func (s *Service) DeclineAlliance(ctx context.Context, name string) (Result, error) {
+	q := models.New(s.pool)
+	a, err := pendingAlliance(ctx, q, name)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{}
+	if result.Alliance, err = s.close(ctx, q, a, StatusDeclined); err != nil {
+		return Result{}, err
+	}
+	if a.CreatedBy.Valid {
+		result.warn(s.notifyPlayer(ctx, a.CreatedBy.Int64, fmt.Sprintf("Your alliance **%s** was declined by the hosts.", a.Name)), "The player could not be notified")
+	}
+	return result, nil
+}
+
+// ApproveMember admits a player whose join request is waiting.
+func (s *Service) ApproveMember(ctx context.Context, name string, playerID int64) (Result, error) {
+	q := models.New(s.pool)
+	a, err := activeAlliance(ctx, q, name)
+	if err != nil {
+		return Result{}, err
+	}
+	member, err := q.GetOpenAllianceMember(ctx, models.GetOpenAllianceMemberParams{AllianceID: a.ID, PlayerID: playerID})
+	if err != nil || member.Status != MemberRequested {
+		return Result{}, ErrNotPending
+	}
+	return s.admit(ctx, q, a, member)
+}
+
+// DeclineMember turns a join request down.
+func (s *Service) DeclineMember(ctx context.Context, name string, playerID int64) (Result, error) {
+	q := models.New(s.pool)
+	a, err := activeAlliance(ctx, q, name)
+	if err != nil {
+		return Result{}, err
+	}
+	member, err := q.GetOpenAllianceMember(ctx, models.GetOpenAllianceMemberParams{AllianceID: a.ID, PlayerID: playerID})
+	if err != nil || member.Status != MemberRequested {
+		return Result{}, ErrNotPending
+	}
+	if member, err = q.UpdateAllianceMemberStatus(ctx, models.UpdateAllianceMemberStatusParams{ID: member.ID, Status: MemberDeclined}); err != nil {
+		return Result{}, err
+	}
+	result := Result{Alliance: a, Member: member}
+	result.warn(s.notifyPlayer(ctx, playerID, fmt.Sprintf("Your request to join **%s** was declined by the hosts.", a.Name)), "The player could not be notified")
+	return result, nil
+}
+
+// Wipe disbands an open alliance and deletes its channel. The membership
+// history is kept.
+func (s *Service) Wipe(ctx context.Context, name string) (Result, error) {
+	q := models.New(s.pool)
+	a, err := q.GetOpenAllianceByName(ctx, strings.TrimSpace(name))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Result{}, ErrNotFound
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{}
+	if a.ChannelID != "" {
+		if s.session == nil {
+			return Result{}, ErrNoDiscord
+		}
+		if _, err := s.session.ChannelDelete(a.ChannelID); err != nil {
+			result.warn(err, "The alliance channel could not be deleted")
+		}
+	}
+	result.Alliance, err = s.close(ctx, q, a, StatusDisbanded)
+	return result, err
+}
+
+// SyncPlayer re-applies playerID's access to every alliance channel they
+// are an active member of, after their life state changed.
+func (s *Service) SyncPlayer(ctx context.Context, playerID int64) []string {
+	if s.session == nil {
+		return nil
+	}
+	q := models.New(s.pool)
+	player, err := q.GetPlayer(ctx, playerID)
+	if err != nil {
+		return nil
+	}
+	memberships, err := q.ListPlayerAllianceMembers(ctx, playerID)
+	if err != nil {
+		logger.Get().Error().Err(err).Int64("player_id", playerID).Msg("alliance access not synced")
+		return []string{"Alliance channel access could not be updated."}
+	}
+	var warnings []string
+	for _, m := range memberships {
+		if m.Status != MemberActive || m.AllianceChannelID == "" {
+			continue
+		}
+		if err := s.grant(m.AllianceChannelID, playerID, player.Alive); err != nil {
+			logger.Get().Error().Err(err).Int64("player_id", playerID).Str("channel_id", m.AllianceChannelID).Msg("alliance access not synced")
+			warnings = append(warnings, fmt.Sprintf("Access to the alliance %s could not be updated.", m.AllianceName))
+		}
+	}
+	return warnings
+}
+
+// ForPlayer lists playerID's open memberships and invites.
+func (s *Service) ForPlayer(ctx context.Context, playerID int64) ([]models.ListPlayerAllianceMembersRow, error) {
+	return models.New(s.pool).ListPlayerAllianceMembers(ctx, playerID)
+}
+
+// Pending lists the alliances and join requests awaiting a host.
+func (s *Service) Pending(ctx context.Context) ([]models.Alliance, []models.ListRequestedAllianceMembersRow, error) {
+	q := models.New(s.pool)
+	all, err := q.ListAlliances(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var alliances []models.Alliance
+	for _, a := range all {
+		if a.Status == StatusPending {
+			alliances = append(alliances, a)
+		}
+	}
+	requests, err := q.ListRequestedAllianceMembers(ctx)
+	return alliances, requests, err
+}
+
+// History lists every alliance with its memberships, oldest first.
+func (s *Service) History(ctx context.Context) ([]History, error) {
+	q := models.New(s.pool)
+	alliances, err := q.ListAlliances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]History, 0, len(alliances))
+	for _, a := range alliances {
+		members, err := q.ListAllianceMembers(ctx, a.ID)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, History{Alliance: a, Members: members})
+	}
+	return history, nil
+}
+
+// admit makes member active and opens the channel to them.
+func (s *Service) admit(ctx context.Context, q *models.Queries, a models.Alliance, member models.AllianceMember) (Result, error) {
+	if s.session == nil {
+		return Result{}, ErrNoDiscord
+	}
+	player, err := q.GetPlayer(ctx, member.PlayerID)
+	if err != nil {
+		return Result{}, ErrNotPlayer
+	}
+	if a.ChannelID != "" {
+		if err := s.grant(a.ChannelID, member.PlayerID, player.Alive); err != nil {
+			return Result{}, fmt.Errorf("grant alliance access: %w", err)
+		}
+	}
+	if member, err = q.UpdateAllianceMemberStatus(ctx, models.UpdateAllianceMemberStatusParams{ID: member.ID, Status: MemberActive}); err != nil {
+		return Result{}, err
+	}
+	result := Result{Alliance: a, Member: member}
+	if a.ChannelID != "" {
+		if _, err := s.session.ChannelMessageSend(a.ChannelID, mention(member.PlayerID)+" joined the alliance."); err != nil {
+			result.warn(err, "The alliance could not be told")
+		}
+	}
+	result.warn(s.notifyPlayer(ctx, member.PlayerID, fmt.Sprintf("You joined the alliance **%s**: <#%s>", a.Name, a.ChannelID)), "The player could not be notified")
+	return result, nil
+}
+
+// openChannel creates the alliance channel, lets members in and commits tx.
+// The channel is deleted again when the commit fails.
+func (s *Service) openChannel(ctx context.Context, tx pgx.Tx, q *models.Queries, guildID string, a models.Alliance, members []int64) (models.Alliance, error) {
+	// The channel helpers read the guild from an interaction; one is
+	// synthesised as in provision.
+	e := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{GuildID: guildID}}
+	channel, err := discord.CreateChannelWithinCategory(s.session, e, cycle.AllianceCategory, ChannelName(a.Name), true)
+	if err != nil {
+		return models.Alliance{}, fmt.Errorf("create alliance channel in category %q: %w", cycle.AllianceCategory, err)
+	}
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		if _, err := s.session.ChannelDelete(channel.ID); err != nil {
+			logger.Get().Error().Err(err).Str("channel_id", channel.ID).Msg("orphaned alliance channel not deleted")
+		}
+	}()
+	for _, id := range members {
+		if err := s.grant(channel.ID, id, true); err != nil {
+			return models.Alliance{}, fmt.Errorf("grant alliance access: %w", err)
+		}
+	}
+	if a, err = q.UpdateAllianceChannel(ctx, models.UpdateAllianceChannelParams{ID: a.ID, ChannelID: channel.ID}); err != nil {
+		return models.Alliance{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return models.Alliance{}, err
+	}
+	committed = true
+	return a, nil
+}
+
+// close ends an alliance and every open membership in it.
+func (s *Service) close(ctx context.Context, q *models.Queries, a models.Alliance, status string) (models.Alliance, error) {
+	members, err := q.ListAllianceMembers(ctx, a.ID)
+	if err != nil {
+		return a, err
+	}
+	for _, m := range members {
+		end := MemberLeft
+		switch m.Status {
+		case MemberActive:
+		case MemberInvited, MemberRequested:
+			end = MemberDeclined
+		default:
+			continue
+		}
+		if _, err := q.UpdateAllianceMemberStatus(ctx, models.UpdateAllianceMemberStatusParams{ID: m.ID, Status: end}); err != nil {
+			return a, err
+		}
+	}
+	return q.UpdateAllianceStatus(ctx, models.UpdateAllianceStatusParams{ID: a.ID, Status: status})
+}
+
+func (s *Service) grant(channelID string, playerID int64, alive bool) error {
+	allow, deny := int64(MemberAccess), int64(0)
+	if !alive {
+		allow, deny = deadAllow, deadDeny
+	}
+	return s.session.ChannelPermissionSet(channelID, strconv.FormatInt(playerID, 10), discordgo.PermissionOverwriteTypeMember, allow, deny)
+}
+
+func (s *Service) notifyPlayer(ctx context.Context, playerID int64, message string) error {
+	if s.session == nil {
+		return ErrNoDiscord
+	}
+	conf, err := models.New(s.pool).GetPlayerConfessional(ctx, playerID)
+	if err != nil {
+		return err
+	}
+	_, err = s.session.ChannelMessageSend(strconv.FormatInt(conf.ChannelID, 10), message)
+	return err
+}
+
+func (s *Service) notifyHosts(ctx context.Context, message string) error {
+	if s.session == nil {
+		return ErrNoDiscord
+	}
+	channels, err := models.New(s.pool).ListAdminChannel(ctx)
+	if err != nil {
+		return err
+	}
+	if len(channels) == 0 {
+		return errors.New("no admin channel (run /channel admin add)")
+	}
+	_, err = s.session.ChannelMessageSend(channels[0], message)
+	return err
+}
+
+func (r *Result) warn(err error, msg string) {
+	if err == nil {
+		return
+	}
+	logger.Get().Error().Err(err).Int64("alliance_id", r.Alliance.ID).Msg(msg)
+	r.Warnings = append(r.Warnings, msg+".")
+}
+
+func livingPlayer(ctx context.Context, q *models.Queries, playerID int64) error {
+	player, err := q.GetPlayer(ctx, playerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotPlayer
+	}
+	if err != nil {
+		return err
+	}
+	if !player.Alive {
+		return ErrDead
+	}
+	return nil
+}
+
+func activeAlliance(ctx context.Context, q *models.Queries, name string) (models.Alliance, error) {
+	a, err := q.GetOpenAllianceByName(ctx, strings.TrimSpace(name))
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && a.Status != StatusActive) {
+		return models.Alliance{}, ErrNotFound
+	}
+	return a, err
+}
+
+func pendingAlliance(ctx context.Context, q *models.Queries, name string) (models.Alliance, error) {
+	a, err := q.GetOpenAllianceByName(ctx, strings.TrimSpace(name))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Alliance{}, ErrNotFound
+	}
+	if err == nil && a.Status != StatusPending {
+		return models.Alliance{}, ErrNotPending
+	}
+	return a, err
+}
+
+func openPhase(ctx context.Context, q *models.Queries) pgtype.Int8 {
+	if open, err := q.GetOpenCycleHistory(ctx); err == nil {
+		return pgtype.Int8{Int64: open.ID, Valid: true}
+	}
+	return pgtype.Int8{}
+}
+
+func mention(playerID int64) string {
+	return discord.MentionUser(strconv.FormatInt(playerID, 10))
+}
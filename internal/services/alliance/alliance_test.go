@@ -0,0 +1,13 @@
+package alliance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelName(t *testing.T) {
+	require.Equal(t, "the-night-owls", ChannelName("  The Night Owls! "))
+	require.Equal(t, "wolves_2", ChannelName("Wolves_2"))
+	require.Equal(t, "alliance", ChannelName("???"))
+}
@@ -0,0 +1,139 @@
+// Package archive snapshots a game into a versioned JSON bundle stored in the
+// game_archive table, so a reset for a new season does not lose the previous
+// one. Archives are read-only once written.
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+// Version is bumped whenever the bundle layout changes in a way readers must
+// know about; adding a table does not count.
+const Version = 1
+
+// Tables are copied into every bundle. The catalog tables are included so the
+// role, item, ability, perk and status ids in player rows still resolve after
+// the reset re-imports the catalog. logs is left out: it is operational noise
+// and can be far larger than the game itself.
+var Tables = []string{
+	"player",
+	"player_item",
+	"player_ability",
+	"player_perk",
+	"player_status",
+	"player_immunity",
+	"player_note",
+	"player_confessional",
+	"player_substitution",
+	"inventory_snapshot",
+	"vote",
+	"poll",
+	"poll_option",
+	"poll_ballot",
+	"whisper_group",
+	"whisper_group_member",
+	"whisper_transcript",
+	"whisper_usage",
+	"whisper_quota_adjustment",
+	"whisper_eavesdrop",
+	"alliance",
+	"alliance_member",
+	"game_cycle",
+	"cycle_history",
+	"game_config",
+	"command_audit",
+	"role",
+	"role_setup",
+	"item",
+	"ability_info",
+	"perk_info",
+	"status",
+}
+
+var ErrUnknownTable = errors.New("the archive has no such table")
+
+// Bundle is the archived document. Tables maps each table name to its rows as
+// a JSON array of objects keyed by column name.
+type Bundle struct {
+	Version   int                        `json:"version"`
+	Label     string                     `json:"label"`
+	CreatedAt time.Time                  `json:"created_at"`
+	Tables    map[string]json.RawMessage `json:"tables"`
+}
+
+type Service struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Service {
+	return &Service{pool: pool}
+}
+
+// Snapshot reads every archived table from one consistent snapshot.
+func (s *Service) Snapshot(ctx context.Context, label string) (Bundle, map[string]int64, error) {
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return Bundle{}, nil, err
+	}
+	defer tx.Rollback(ctx)
+	bundle := Bundle{Version: Version, Label: label, CreatedAt: time.Now().UTC(), Tables: map[string]json.RawMessage{}}
+	counts := map[string]int64{}
+	for _, table := range Tables {
+		var n int64
+		var rows []byte
+		stmt := "SELECT count(*), COALESCE(json_agg(t), '[]'::json) FROM " + pgx.Identifier{table}.Sanitize() + " t"
+		if err := tx.QueryRow(ctx, stmt).Scan(&n, &rows); err != nil {
+			return Bundle{}, nil, fmt.Errorf("archive %s: %w", table, err)
+		}
+		bundle.Tables[table] = rows
+		counts[table] = n
+	}
+	return bundle, counts, nil
+}
+
+// Create snapshots the game and stores the bundle.
+func (s *Service) Create(ctx context.Context, label string) (models.GameArchive, error) {
+	bundle, counts, err := s.Snapshot(ctx, label)
+	if err != nil {
+		return models.GameArchive{}, err
+	}
+	body, err := json.Marshal(bundle)
+	if err != nil {
+		return models.GameArchive{}, err
+	}
+	countsJSON, err := json.Marshal(counts)
+	if err != nil {
+		return models.GameArchive{}, err
+	}
+	return models.New(s.pool).CreateGameArchive(ctx, models.CreateGameArchiveParams{
+		Version: Version, Label: label, Counts: countsJSON, Bundle: body,
+	})
+}
+
+// Counts decodes an archive's per-table row counts.
+func Counts(raw []byte) map[string]int64 {
+	counts := map[string]int64{}
+	_ = json.Unmarshal(raw, &counts)
+	return counts
+}
+
+// Table returns the archived rows of one table.
+func Table(a models.GameArchive, table string) (json.RawMessage, error) {
+	var bundle Bundle
+	if err := json.Unmarshal(a.Bundle, &bundle); err != nil {
+		return nil, err
+	}
+	rows, ok := bundle.Tables[table]
+	if !ok {
+		return nil, ErrUnknownTable
+	}
+	return rows, nil
+}
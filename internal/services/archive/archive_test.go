@@ -0,0 +1,42 @@
+package archive
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+func TestTablesAreUnique(t *testing.T) {
+	seen := map[string]bool{}
+	for _, table := range Tables {
+		if seen[table] {
+			t.Fatalf("%s archived twice", table)
+		}
+		if table == "game_archive" {
+			t.Fatal("archives must not archive themselves")
+		}
+		seen[table] = true
+	}
+}
+
+func TestTable(t *testing.T) {
+	body, _ := json.Marshal(Bundle{Version: Version, Tables: map[string]json.RawMessage{"player": json.RawMessage(`[{"id":1}]`)}})
+	a := models.GameArchive{Bundle: body}
+	rows, err := Table(a, "player")
+	if err != nil || string(rows) != `[{"id":1}]` {
+		t.Fatalf("rows = %s, err = %v", rows, err)
+	}
+	if _, err := Table(a, "vote"); err != ErrUnknownTable {
+		t.Fatalf("missing table: %v", err)
+	}
+}
+
+func TestCounts(t *testing.T) {
+	if got := Counts([]byte(`{"player":3}`)); got["player"] != 3 {
+		t.Fatalf("counts = %v", got)
+	}
+	if got := Counts(nil); len(got) != 0 {
+		t.Fatalf("counts = %v", got)
+	}
+}
@@ -0,0 +1,519 @@
+// Package bulkinv applies the same inventory change to every player matching
+// a filter ("everyone gets 50 coins", "all evil players gain Cursed"). A
+// request is previewed by running it in a transaction that is rolled back, so
+// the preview shows exactly what applying would do to each player.
+package bulkinv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/inventory"
+)
+
+// Operation kinds.
+const (
+	KindCoins   = "coins"
+	KindLuck    = "luck"
+	KindItem    = "item"
+	KindStatus  = "status"
+	KindAbility = "ability"
+)
+
+// unlimitedCharges marks an ability with infinite charges; bulk changes
+// leave it alone.
+const unlimitedCharges = 999999
+
+var (
+	ErrNoOperations  = errors.New("at least one operation is required")
+	ErrInvalidKind   = errors.New("operation kind must be coins, luck, item, status or ability")
+	ErrZeroAmount    = errors.New("operation amount must not be zero")
+	ErrMissingName   = errors.New("item, status and ability operations need a name")
+	ErrBadAlignment  = errors.New("alignment must be GOOD, EVIL or NEUTRAL")
+	ErrNoPlayers     = errors.New("no players match the filter")
+	ErrUnknownTarget = errors.New("no catalog entry matches that name")
+)
+
+// Filter selects players; every set field must match. The zero Filter
+// matches everyone.
+type Filter struct {
+	Alive     *bool            `json:"alive,omitempty"`
+	Alignment models.Alignment `json:"alignment,omitempty"`
+	Role      string           `json:"role,omitempty"`
+	Status    string           `json:"status,omitempty"`
+	PlayerIDs []int64          `json:"player_ids,omitempty"`
+}
+
+// Operation adds (positive Amount) or removes (negative Amount) coins, luck,
+// or a named item, status or ability. Name is matched fuzzily; the report
+// carries the resolved name.
+type Operation struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name,omitempty"`
+	Amount int32  `json:"amount"`
+}
+
+func (o Operation) String() string {
+	label := o.Kind
+	if o.Name != "" {
+		label = o.Name
+	}
+	return fmt.Sprintf("%+d %s", o.Amount, label)
+}
+
+type Request struct {
+	Filter     Filter      `json:"filter"`
+	Operations []Operation `json:"operations"`
+}
+
+// PlayerResult is what the request did, or would do, to one player.
+// Warnings flag changes hosts usually want to double-check, the same ones
+// /inv item add and /inv status add raise.
+type PlayerResult struct {
+	PlayerID int64    `json:"player_id,string"`
+	Changes  []string `json:"changes"`
+	Error    string   `json:"error,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+
+	changes []inventory.Change
+}
+
+// Report is the outcome of Preview or Apply. Nothing was written unless
+// Applied is true.
+type Report struct {
+	Applied    bool           `json:"applied"`
+	Operations []Operation    `json:"operations"`
+	Players    []PlayerResult `json:"players"`
+}
+
+// Summary renders the operations and per-player changes for Discord, cut
+// off with an overflow note once it would exceed limit bytes.
+func (r Report) Summary(limit int) string {
+	ops := make([]string, len(r.Operations))
+	for i, op := range r.Operations {
+		ops[i] = op.String()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s** for %d players\n", strings.Join(ops, ", "), len(r.Players))
+	for n, p := range r.Players {
+		line := fmt.Sprintf("<@%d>: ", p.PlayerID)
+		switch {
+		case p.Error != "":
+			line += "❌ " + p.Error
+		case len(p.Changes) == 0:
+			line += "no change"
+		default:
+			line += strings.Join(p.Changes, ", ")
+		}
+		if len(p.Warnings) > 0 {
+			line += " ⚠️ " + strings.Join(p.Warnings, "; ")
+		}
+		if b.Len()+len(line) > limit {
+			fmt.Fprintf(&b, "…and %d more players", len(r.Players)-n)
+			break
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// Validate checks a request before it touches the database (pure,
+// unit-testable).
+func (r Request) Validate() error {
+	if len(r.Operations) == 0 {
+		return ErrNoOperations
+	}
+	switch r.Filter.Alignment {
+	case "", models.AlignmentGOOD, models.AlignmentEVIL, models.AlignmentNEUTRAL:
+	default:
+		return ErrBadAlignment
+	}
+	for _, op := range r.Operations {
+		switch op.Kind {
+		case KindCoins, KindLuck:
+		case KindItem, KindStatus, KindAbility:
+			if strings.TrimSpace(op.Name) == "" {
+				return ErrMissingName
+			}
+		default:
+			return ErrInvalidKind
+		}
+		if op.Amount == 0 {
+			return ErrZeroAmount
+		}
+	}
+	return nil
+}
+
+type Service struct {
+	pool    *pgxpool.Pool
+	session *discordgo.Session
+}
+
+// New returns a service; session may be nil (web-only mode), in which case
+// pinned inventories catch up on the next /inv command.
+func New(pool *pgxpool.Pool, session *discordgo.Session) *Service {
+	return &Service{pool: pool, session: session}
+}
+
+// Preview reports what Apply would do without changing anything.
+func (s *Service) Preview(ctx context.Context, req Request) (Report, error) {
+	return s.run(ctx, req, false)
+}
+
+// Apply changes every matching player in one transaction; if any player
+// fails, nothing is written and that player's result carries the error.
+// Once committed, each changed player's pinned inventory is re-rendered.
+// notice overrides the inventory-notice default as in inventory.Notifier.
+func (s *Service) Apply(ctx context.Context, req Request, notice *bool) (Report, error) {
+	report, err := s.run(ctx, req, true)
+	if err != nil || !report.Applied {
+		return report, err
+	}
+	notifier := inventory.NewNotifier(s.pool, s.session)
+	for i := range report.Players {
+		p := &report.Players[i]
+		if len(p.changes) > 0 {
+			p.Warnings = append(p.Warnings, notifier.Changed(ctx, p.PlayerID, notice, p.changes...)...)
+		}
+	}
+	return report, nil
+}
+
+func (s *Service) run(ctx context.Context, req Request, commit bool) (Report, error) {
+	if err := req.Validate(); err != nil {
+		return Report{}, err
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+	defer tx.Rollback(ctx)
+	q := models.New(tx)
+
+	ops, err := resolve(ctx, q, req.Operations)
+	if err != nil {
+		return Report{}, err
+	}
+	players, err := match(ctx, q, req.Filter)
+	if err != nil {
+		return Report{}, err
+	}
+	if len(players) == 0 {
+		return Report{}, ErrNoPlayers
+	}
+
+	report := Report{Operations: make([]Operation, len(ops)), Players: make([]PlayerResult, 0, len(players))}
+	for i, op := range ops {
+		report.Operations[i] = op.Operation
+	}
+	for _, player := range players {
+		result := PlayerResult{PlayerID: player.ID, Changes: []string{}}
+		for _, op := range ops {
+			change, err := op.apply(ctx, q, &player)
+			if err != nil {
+				// The transaction is unusable now; report who failed.
+				result.Error = err.Error()
+				report.Players = append(report.Players, result)
+				return report, fmt.Errorf("player %d: %w", player.ID, err)
+			}
+			if change == nil {
+				continue
+			}
+			result.changes = append(result.changes, *change)
+			result.Changes = append(result.Changes, change.String())
+			warnings, err := op.warnings(ctx, q, player)
+			if err != nil {
+				result.Error = err.Error()
+				report.Players = append(report.Players, result)
+				return report, fmt.Errorf("player %d: %w", player.ID, err)
+			}
+			result.Warnings = append(result.Warnings, warnings...)
+		}
+		report.Players = append(report.Players, result)
+	}
+	if !commit {
+		return report, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return report, err
+	}
+	report.Applied = true
+	return report, nil
+}
+
+// match returns the players the filter selects, in ID order.
+func match(ctx context.Context, q *models.Queries, f Filter) ([]models.Player, error) {
+	players, err := q.ListPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i].ID < players[j].ID })
+	var roleID, statusID int32
+	if f.Role != "" {
+		role, err := q.GetRoleByFuzzy(ctx, f.Role)
+		if err != nil {
+			return nil, fmt.Errorf("role %q: %w", f.Role, ErrUnknownTarget)
+		}
+		roleID = role.ID
+	}
+	if f.Status != "" {
+		status, err := q.GetStatusByFuzzy(ctx, f.Status)
+		if err != nil {
+			return nil, fmt.Errorf("status %q: %w", f.Status, ErrUnknownTarget)
+		}
+		statusID = status.ID
+	}
+	ids := map[int64]bool{}
+	for _, id := range f.PlayerIDs {
+		ids[id] = true
+	}
+	var out []models.Player
+	for _, p := range players {
+		if f.Alive != nil && p.Alive != *f.Alive {
+			continue
+		}
+		if f.Alignment != "" && p.Alignment != f.Alignment {
+			continue
+		}
+		if roleID != 0 && (!p.RoleID.Valid || p.RoleID.Int32 != roleID) {
+			continue
+		}
+		if len(ids) > 0 && !ids[p.ID] {
+			continue
+		}
+		if statusID != 0 {
+			statuses, err := q.ListPlayerStatusInventory(ctx, p.ID)
+			if err != nil {
+				return nil, err
+			}
+			held := false
+			for _, st := range statuses {
+				held = held || st.ID == statusID
+			}
+			if !held {
+				continue
+			}
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// resolved is an operation with its catalog entry looked up.
+type resolved struct {
+	Operation
+	id int32
+}
+
+func resolve(ctx context.Context, q *models.Queries, ops []Operation) ([]resolved, error) {
+	out := make([]resolved, len(ops))
+	for i, op := range ops {
+		r := resolved{Operation: op}
+		switch op.Kind {
+		case KindItem:
+			item, err := q.GetItemByFuzzy(ctx, op.Name)
+			if err != nil {
+				return nil, fmt.Errorf("item %q: %w", op.Name, ErrUnknownTarget)
+			}
+			r.id, r.Name = item.ID, item.Name
+		case KindStatus:
+			status, err := q.GetStatusByFuzzy(ctx, op.Name)
+			if err != nil {
+				return nil, fmt.Errorf("status %q: %w", op.Name, ErrUnknownTarget)
+			}
+			r.id, r.Name = status.ID, status.Name
+		case KindAbility:
+			ability, err := q.GetAbilityInfoByFuzzy(ctx, op.Name)
+			if err != nil {
+				return nil, fmt.Errorf("ability %q: %w", op.Name, ErrUnknownTarget)
+			}
+			r.id, r.Name = ability.ID, ability.Name
+		default:
+			r.Name = ""
+		}
+		out[i] = r
+	}
+	return out, nil
+}
+
+// apply performs op on player and returns the change actually made, or nil
+// when there was nothing to change (removing something not held).
+func (op resolved) apply(ctx context.Context, q *models.Queries, player *models.Player) (*inventory.Change, error) {
+	switch op.Kind {
+	case KindCoins:
+		coins := max(player.Coins+op.Amount, 0)
+		if coins == player.Coins {
+			return nil, nil
+		}
+		if _, err := q.UpdatePlayerCoins(ctx, models.UpdatePlayerCoinsParams{ID: player.ID, Coins: coins}); err != nil {
+			return nil, err
+		}
+		delta := coins - player.Coins
+		player.Coins = coins
+		return &inventory.Change{Name: "Coins", Delta: delta}, nil
+	case KindLuck:
+		if _, err := q.UpdatePlayerLuck(ctx, models.UpdatePlayerLuckParams{ID: player.ID, Luck: player.Luck + op.Amount}); err != nil {
+			return nil, err
+		}
+		player.Luck += op.Amount
+		return &inventory.Change{Name: "Luck", Delta: op.Amount}, nil
+	case KindItem:
+		return op.applyItem(ctx, q, player.ID)
+	case KindStatus:
+		return op.applyStatus(ctx, q, player.ID)
+	case KindAbility:
+		return op.applyAbility(ctx, q, player.ID)
+	}
+	return nil, ErrInvalidKind
+}
+
+// warnings checks the player right after op added something: a status they
+// are immune to, or more items than their limit.
+func (op resolved) warnings(ctx context.Context, q *models.Queries, player models.Player) ([]string, error) {
+	if op.Amount <= 0 {
+		return nil, nil
+	}
+	switch op.Kind {
+	case KindStatus:
+		immunities, err := q.ListPlayerImmunity(ctx, player.ID)
+		if err != nil {
+			return nil, err
+		}
+		if warning := immunityWarning(op.Name, immunities); warning != "" {
+			return []string{warning}, nil
+		}
+	case KindItem:
+		count, err := q.GetPlayerItemCount(ctx, player.ID)
+		if err != nil {
+			return nil, err
+		}
+		held, _ := count.(int64)
+		if warning := itemLimitWarning(held, player.ItemLimit); warning != "" {
+			return []string{warning}, nil
+		}
+	}
+	return nil, nil
+}
+
+// immunityWarning reports whether a player holding immunities was just
+// given a status they are immune to.
+func immunityWarning(status string, immunities []models.ListPlayerImmunityRow) string {
+	for _, immunity := range immunities {
+		if immunity.Name != status {
+			continue
+		}
+		if immunity.OneTime {
+			return fmt.Sprintf("has one time immunity for %s; consider removing the immunity", status)
+		}
+		return fmt.Sprintf("is immune to %s; consider removing the immunity or the status", status)
+	}
+	return ""
+}
+
+// itemLimitWarning reports a player holding at least their item limit.
+func itemLimitWarning(held int64, limit int32) string {
+	if held < int64(limit) {
+		return ""
+	}
+	return fmt.Sprintf("%d items out of %d used slots", held, limit)
+}
+
+func (op resolved) applyItem(ctx context.Context, q *models.Queries, playerID int64) (*inventory.Change, error) {
+	if op.Amount > 0 {
+		err := q.UpsertPlayerItemJoin(ctx, models.UpsertPlayerItemJoinParams{PlayerID: playerID, ItemID: op.id, Quantity: op.Amount})
+		return &inventory.Change{Name: op.Name, Delta: op.Amount}, err
+	}
+	items, err := q.ListPlayerItemInventory(ctx, playerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if item.ID != op.id {
+			continue
+		}
+		left := item.Quantity + op.Amount
+		if left <= 0 {
+			err = q.DeletePlayerItem(ctx, models.DeletePlayerItemParams{PlayerID: playerID, ItemID: op.id})
+			return &inventory.Change{Name: op.Name, Delta: -item.Quantity}, err
+		}
+		_, err = q.UpdatePlayerItemQuantity(ctx, models.UpdatePlayerItemQuantityParams{PlayerID: playerID, ItemID: op.id, Quantity: left})
+		return &inventory.Change{Name: op.Name, Delta: op.Amount}, err
+	}
+	return nil, nil
+}
+
+func (op resolved) applyStatus(ctx context.Context, q *models.Queries, playerID int64) (*inventory.Change, error) {
+	if op.Amount > 0 {
+		err := q.UpsertPlayerStatusJoin(ctx, models.UpsertPlayerStatusJoinParams{PlayerID: playerID, StatusID: op.id, Quantity: op.Amount})
+		return &inventory.Change{Name: op.Name, Delta: op.Amount}, err
+	}
+	statuses, err := q.ListPlayerStatusInventory(ctx, playerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range statuses {
+		if status.ID != op.id {
+			continue
+		}
+		left := status.Quantity + op.Amount
+		if left <= 0 {
+			err = q.DeletePlayerStatus(ctx, models.DeletePlayerStatusParams{PlayerID: playerID, StatusID: op.id})
+			return &inventory.Change{Name: op.Name, Delta: -status.Quantity}, err
+		}
+		_, err = q.UpdatePlayerStatusQuantity(ctx, models.UpdatePlayerStatusQuantityParams{PlayerID: playerID, StatusID: op.id, Quantity: left})
+		return &inventory.Change{Name: op.Name, Delta: op.Amount}, err
+	}
+	return nil, nil
+}
+
+// applyAbility adds or removes charges; a player without the ability gains
+// it with Amount charges, and one left without charges loses it.
+func (op resolved) applyAbility(ctx context.Context, q *models.Queries, playerID int64) (*inventory.Change, error) {
+	held, err := q.ListPlayerAbilityJoin(ctx, playerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ability := range held {
+		if ability.AbilityID != op.id {
+			continue
+		}
+		if ability.Quantity == unlimitedCharges {
+			return nil, nil
+		}
+		left := ability.Quantity + op.Amount
+		if left <= 0 {
+			err = q.DeletePlayerAbility(ctx, models.DeletePlayerAbilityParams{PlayerID: playerID, AbilityID: op.id})
+			return &inventory.Change{Name: op.Name, Delta: -ability.Quantity}, err
+		}
+		_, err = q.UpdatePlayerAbilityQuantity(ctx, models.UpdatePlayerAbilityQuantityParams{Quantity: left, PlayerID: playerID, AbilityID: op.id})
+		return &inventory.Change{Name: op.Name, Delta: op.Amount}, err
+	}
+	if op.Amount < 0 {
+		return nil, nil
+	}
+	_, err = q.CreatePlayerAbilityJoin(ctx, models.CreatePlayerAbilityJoinParams{PlayerID: playerID, AbilityID: op.id, Quantity: op.Amount})
+	return &inventory.Change{Name: op.Name, Delta: op.Amount}, err
+}
+
+// ParsePlayerIDs reads a space or comma separated list of user IDs and
+// <@id> mentions, as typed into a Discord option.
+func ParsePlayerIDs(raw string) ([]int64, error) {
+	var ids []int64
+	for _, field := range strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == ' ' }) {
+		field = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(field, "<@"), "!"), ">")
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a user ID or mention", field)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
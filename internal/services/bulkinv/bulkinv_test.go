@@ -0,0 +1,83 @@
+package bulkinv
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		req  Request
+		want error
+	}{
+		{"coins", Request{Operations: []Operation{{Kind: KindCoins, Amount: 50}}}, nil},
+		{"named item", Request{Operations: []Operation{{Kind: KindItem, Name: "Lantern", Amount: -1}}}, nil},
+		{"no operations", Request{}, ErrNoOperations},
+		{"unknown kind", Request{Operations: []Operation{{Kind: "perk", Name: "Lucky", Amount: 1}}}, ErrInvalidKind},
+		{"zero amount", Request{Operations: []Operation{{Kind: KindLuck}}}, ErrZeroAmount},
+		{"missing name", Request{Operations: []Operation{{Kind: KindStatus, Amount: 1}}}, ErrMissingName},
+		{"bad alignment", Request{Filter: Filter{Alignment: "CHAOTIC"}, Operations: []Operation{{Kind: KindCoins, Amount: 1}}}, ErrBadAlignment},
+	}
+	for _, tt := range tests {
+		if err := tt.req.Validate(); !errors.Is(err, tt.want) {
+			t.Errorf("%s: Validate() = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestParsePlayerIDs(t *testing.T) {
+	ids, err := ParsePlayerIDs("<@101> <@!102>, 103")
+	if err != nil {
+		t.Fatalf("ParsePlayerIDs: %v", err)
+	}
+	if want := []int64{101, 102, 103}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("ids = %v, want %v", ids, want)
+	}
+	if _, err := ParsePlayerIDs("101 @someone"); err == nil {
+		t.Fatal("expected an error for a non-ID entry")
+	}
+}
+
+func TestSummary(t *testing.T) {
+	report := Report{
+		Operations: []Operation{{Kind: KindCoins, Amount: 50}, {Kind: KindStatus, Name: "Cursed", Amount: -1}},
+		Players: []PlayerResult{
+			{PlayerID: 1, Changes: []string{"+50 Coins", "-1 Cursed"}, Warnings: []string{"4 items out of 4 used slots"}},
+			{PlayerID: 2},
+			{PlayerID: 3, Error: "boom"},
+		},
+	}
+	got := report.Summary(1000)
+	for _, want := range []string{"**+50 coins, -1 Cursed** for 3 players", "<@1>: +50 Coins, -1 Cursed ⚠️ 4 items out of 4 used slots", "<@2>: no change", "<@3>: ❌ boom"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q:\n%s", want, got)
+		}
+	}
+	if got := report.Summary(110); !strings.Contains(got, "…and 2 more players") {
+		t.Errorf("truncated summary = %q", got)
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	immunities := []models.ListPlayerImmunityRow{{Name: "Cursed", OneTime: true}, {Name: "Frozen"}}
+	if got := immunityWarning("Cursed", immunities); !strings.Contains(got, "one time immunity for Cursed") {
+		t.Errorf("one time immunity warning = %q", got)
+	}
+	if got := immunityWarning("Frozen", immunities); !strings.Contains(got, "immune to Frozen") {
+		t.Errorf("immunity warning = %q", got)
+	}
+	if got := immunityWarning("Lucky", immunities); got != "" {
+		t.Errorf("warning without immunity = %q", got)
+	}
+	if got := itemLimitWarning(3, 4); got != "" {
+		t.Errorf("warning under the limit = %q", got)
+	}
+	if got := itemLimitWarning(5, 4); got != "5 items out of 4 used slots" {
+		t.Errorf("item limit warning = %q", got)
+	}
+}
@@ -0,0 +1,298 @@
+package cycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/util"
+)
+
+// Target is one class of channel that receives the cycle announcement.
+type Target string
+
+const (
+	TargetAnnouncement  Target = "announcement"
+	TargetVote          Target = "vote"
+	TargetAction        Target = "action"
+	TargetConfessionals Target = "confessionals"
+	TargetAlliances     Target = "alliances"
+)
+
+// game_config keys read by the broadcaster.
+const (
+	BroadcastTargetsConfigKey    = "cycle_broadcast_targets"
+	AnnouncementChannelConfigKey = "announcement_channel_id"
+)
+
+// AllTargets lists every supported target in display order.
+var AllTargets = []Target{TargetAnnouncement, TargetVote, TargetAction, TargetConfessionals, TargetAlliances}
+
+// DefaultTargets is used when no broadcast targets are configured. It matches
+// the original /cycle behaviour: funnel channels, confessionals and alliances.
+var DefaultTargets = []Target{TargetVote, TargetAction, TargetConfessionals, TargetAlliances}
+
+// AllianceCategory is the Discord category whose channels are alliance chats;
+// the alliance service creates its channels there.
+const AllianceCategory = "alliances"
+
+// ParseTargets parses a comma separated target list, dropping duplicates.
+func ParseTargets(raw string) ([]Target, error) {
+	var targets []Target
+	seen := make(map[Target]bool)
+	for _, part := range strings.Split(raw, ",") {
+		name := Target(strings.ToLower(strings.TrimSpace(part)))
+		if name == "" {
+			continue
+		}
+		known := false
+		for _, target := range AllTargets {
+			if name == target {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown broadcast target %q", name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			targets = append(targets, name)
+		}
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("at least one broadcast target is required")
+	}
+	return targets, nil
+}
+
+// FormatTargets renders targets as stored in game_config.
+func FormatTargets(targets []Target) string {
+	names := make([]string, len(targets))
+	for i, target := range targets {
+		names[i] = string(target)
+	}
+	return strings.Join(names, ",")
+}
+
+// Broadcaster posts cycle announcements to the configured targets. The
+// /cycle command and the web advance endpoint share it so both surfaces
+// notify the same channels.
+type Broadcaster struct {
+	pool    *pgxpool.Pool
+	session *discordgo.Session
+}
+
+// NewBroadcaster returns a Broadcaster posting through session.
+func NewBroadcaster(pool *pgxpool.Pool, session *discordgo.Session) *Broadcaster {
+	return &Broadcaster{pool: pool, session: session}
+}
+
+// Targets returns the configured broadcast targets, or DefaultTargets when
+// none are stored.
+func (b *Broadcaster) Targets(ctx context.Context) ([]Target, error) {
+	raw, err := models.New(b.pool).GetGameConfig(ctx, BroadcastTargetsConfigKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return DefaultTargets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseTargets(raw)
+}
+
+// SetTargets stores the broadcast targets.
+func (b *Broadcaster) SetTargets(ctx context.Context, targets []Target) error {
+	_, err := models.New(b.pool).UpsertGameConfig(ctx, models.UpsertGameConfigParams{
+		Key:   BroadcastTargetsConfigKey,
+		Value: FormatTargets(targets),
+	})
+	return err
+}
+
+// Channels resolves the configured targets to channel IDs, in target order
+// and without duplicates. Every missing singleton channel is reported in one
+// error so hosts can fix the configuration in a single pass. guildID scopes the
+// alliance category lookup; when empty it is derived from a resolved channel.
+func (b *Broadcaster) Channels(ctx context.Context, guildID string) ([]string, error) {
+	targets, err := b.Targets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	q := models.New(b.pool)
+	resolved := make(map[Target][]string, len(targets))
+	var missing []error
+	singleton := func(target Target, load func(context.Context) (string, error), hint string) {
+		id, err := load(ctx)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			missing = append(missing, fmt.Errorf("%s channel not configured (run %s)", target, hint))
+		case err != nil:
+			missing = append(missing, err)
+		default:
+			resolved[target] = []string{id}
+		}
+	}
+
+	want := make(map[Target]bool, len(targets))
+	for _, target := range targets {
+		want[target] = true
+	}
+	if want[TargetConfessionals] {
+		confessionals, err := q.ListPlayerConfessional(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, conf := range confessionals {
+			channel, err := b.session.Channel(util.Itoa64(conf.ChannelID))
+			if err != nil {
+				return nil, fmt.Errorf("Unable to get confessional channel %s", util.Itoa64(conf.ChannelID))
+			}
+			resolved[TargetConfessionals] = append(resolved[TargetConfessionals], channel.ID)
+		}
+	}
+	if want[TargetAnnouncement] {
+		singleton(TargetAnnouncement, func(ctx context.Context) (string, error) {
+			return q.GetGameConfig(ctx, AnnouncementChannelConfigKey)
+		}, "/channel announcement update")
+	}
+	if want[TargetAction] {
+		singleton(TargetAction, q.GetActionChannel, "/channel action update")
+	}
+	if want[TargetVote] {
+		singleton(TargetVote, q.GetVoteChannel, "/channel vote update")
+	}
+	if len(missing) > 0 {
+		return nil, errors.Join(missing...)
+	}
+	if want[TargetAlliances] {
+		resolved[TargetAlliances] = b.allianceChannels(guildID, resolved)
+	}
+
+	var channels []string
+	seen := make(map[string]bool)
+	for _, target := range targets {
+		for _, id := range resolved[target] {
+			if !seen[id] {
+				seen[id] = true
+				channels = append(channels, id)
+			}
+		}
+	}
+	return channels, nil
+}
+
+// allianceChannels lists the channels in the alliances category. A missing
+// category is logged and skipped rather than failing the broadcast.
+func (b *Broadcaster) allianceChannels(guildID string, resolved map[Target][]string) []string {
+	if guildID == "" {
+		guildID = b.guildOf(resolved)
+	}
+	channels, err := b.session.GuildChannels(guildID)
+	if err != nil {
+		logger.Get().Warn().Err(err).Msg("unable to get alliance channels; continuing without alliance broadcasts")
+		return nil
+	}
+	categoryID := ""
+	for _, c := range channels {
+		if c.Type == discordgo.ChannelTypeGuildCategory && c.Name == AllianceCategory {
+			categoryID = c.ID
+			break
+		}
+	}
+	if categoryID == "" {
+		logger.Get().Warn().Msg("no alliances category; continuing without alliance broadcasts")
+		return nil
+	}
+	var ids []string
+	for _, c := range channels {
+		if c.ParentID == categoryID {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids
+}
+
+// guildOf derives the guild from any already resolved channel. The web panel
+// has no interaction to read the guild from.
+func (b *Broadcaster) guildOf(resolved map[Target][]string) string {
+	for _, target := range AllTargets {
+		for _, id := range resolved[target] {
+			if channel, err := b.session.Channel(id); err == nil && channel.GuildID != "" {
+				return channel.GuildID
+			}
+		}
+	}
+	return ""
+}
+
+// Send posts msg to every channel, continuing past failures. It returns the
+// number of channels that received the message and the joined send errors.
+func (b *Broadcaster) Send(channelIDs []string, msg string) (int, error) {
+	sent := 0
+	var errs []error
+	for _, channelID := range channelIDs {
+		if _, err := b.session.ChannelMessageSend(channelID, msg); err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", channelID, err))
+			continue
+		}
+		sent++
+	}
+	return sent, errors.Join(errs...)
+}
+
+// AnnounceHookName names the hook that posts each new phase to the broadcast
+// targets.
+const AnnounceHookName = "Announce new phase"
+
+// AnnounceError reports an announcement that reached only some channels.
+type AnnounceError struct {
+	Sent    int
+	Targets int
+	Err     error
+}
+
+func (e *AnnounceError) Error() string {
+	return fmt.Sprintf("announced in %d of %d channels: %v", e.Sent, e.Targets, e.Err)
+}
+
+func (e *AnnounceError) Unwrap() error { return e.Err }
+
+// Announce posts the message for cycle c to every broadcast target.
+func (b *Broadcaster) Announce(ctx context.Context, c models.GameCycle) error {
+	channels, err := b.Channels(ctx, "")
+	if err != nil {
+		return err
+	}
+	sent, err := b.Send(channels, FormatMessage(c))
+	if err != nil {
+		return &AnnounceError{Sent: sent, Targets: len(channels), Err: err}
+	}
+	return nil
+}
+
+// RegisterAnnounceHook announces every cycle change, in the database of the
+// game that changed, so /cycle, the web panel and the scheduler all post the
+// same message. It runs before the other hooks so players hear of the new
+// phase first.
+func RegisterAnnounceHook(pool *pgxpool.Pool, sesh *discordgo.Session) {
+	hook := Hook{
+		Name:  AnnounceHookName,
+		Order: 10,
+		AfterCommit: func(ctx context.Context, t Transition) error {
+			gamePool := pool
+			if t.Pool != nil {
+				gamePool = t.Pool
+			}
+			return NewBroadcaster(gamePool, sesh).Announce(ctx, t.To)
+		},
+	}
+	OnAdvance(hook)
+	OnSet(hook)
+}
@@ -0,0 +1,71 @@
+package cycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+// DefaultHistoryLimit caps History when no limit is given.
+const DefaultHistoryLimit = 25
+
+// ErrNoPhase is returned by PhaseAt when no logged phase covers the time, e.g.
+// before the history log existed.
+var ErrNoPhase = errors.New("no cycle phase recorded at that time")
+
+// recordHistory closes the open cycle_history row and opens one for cycle.
+// It runs inside the transition transaction, so both rows share NOW().
+func recordHistory(ctx context.Context, q *models.Queries, cycle models.GameCycle, by string) error {
+	if err := q.CloseCycleHistory(ctx); err != nil {
+		return err
+	}
+	_, err := q.CreateCycleHistory(ctx, models.CreateCycleHistoryParams{
+		Day:           cycle.Day,
+		IsElimination: cycle.IsElimination,
+		AdvancedBy:    by,
+	})
+	return err
+}
+
+// History returns the most recent phases, newest first.
+func (s *Service) History(ctx context.Context, limit int32) ([]models.CycleHistory, error) {
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+	return models.New(s.pool).ListCycleHistory(ctx, limit)
+}
+
+// PhaseAt returns the phase that was running at t.
+func (s *Service) PhaseAt(ctx context.Context, t time.Time) (models.CycleHistory, error) {
+	entry, err := models.New(s.pool).GetCycleHistoryAt(ctx, pgtype.Timestamptz{Time: t, Valid: true})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.CycleHistory{}, ErrNoPhase
+	}
+	return entry, err
+}
+
+// PhaseLabel names a phase like "Elimination 2".
+func PhaseLabel(isElimination bool, day int32) string {
+	if isElimination {
+		return fmt.Sprintf("Elimination %d", day)
+	}
+	return fmt.Sprintf("Day %d", day)
+}
+
+// PhaseDuration returns how long entry lasted, measured up to now while it is
+// still running.
+func PhaseDuration(entry models.CycleHistory, now time.Time) time.Duration {
+	end := now
+	if entry.EndedAt.Valid {
+		end = entry.EndedAt.Time
+	}
+	if !entry.StartedAt.Valid || end.Before(entry.StartedAt.Time) {
+		return 0
+	}
+	return end.Sub(entry.StartedAt.Time)
+}
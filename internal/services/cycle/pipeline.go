@@ -0,0 +1,152 @@
+package cycle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+// Kind distinguishes a normal one-phase advance from a host hard-set.
+type Kind string
+
+const (
+	KindAdvance Kind = "advance"
+	KindSet     Kind = "set"
+)
+
+// Transition is the cycle change handed to every hook.
+type Transition struct {
+	Kind Kind
+	From models.GameCycle
+	To   models.GameCycle
+	// By identifies who triggered the change (Discord user ID, "web", ...).
+	By string
+	// Pool is the database of the game whose cycle changed, for
+	// AfterCommit hooks that read more state.
+	Pool *pgxpool.Pool
+}
+
+// Hook is one step of the transition pipeline. Either stage may be nil.
+//
+// InTx runs inside the transaction that persists the new cycle, wrapped in its
+// own savepoint: a failing hook rolls back only its own writes and is reported,
+// while the cycle change and the other hooks still commit.
+//
+// AfterCommit runs once the transition is committed, for side effects that
+// cannot join a database transaction (Discord messages). It is skipped when
+// the same hook's InTx stage failed.
+type Hook struct {
+	Name string
+	// Order sorts hooks ascending; ties keep registration order.
+	Order       int
+	InTx        func(ctx context.Context, q *models.Queries, t Transition) error
+	AfterCommit func(ctx context.Context, t Transition) error
+}
+
+// HookResult reports how one hook fared during a transition.
+type HookResult struct {
+	Name string
+	Err  error
+}
+
+// OK reports whether the hook succeeded.
+func (r HookResult) OK() bool { return r.Err == nil }
+
+// Report is the outcome of a transition: the committed cycle change plus one
+// result per hook, in execution order.
+type Report struct {
+	Transition
+	Hooks []HookResult
+}
+
+// Failed returns the hooks that reported an error.
+func (r Report) Failed() []HookResult {
+	var failed []HookResult
+	for _, result := range r.Hooks {
+		if !result.OK() {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Summary renders one line per hook for host-facing replies. It is empty when
+// no hooks ran.
+func (r Report) Summary() string {
+	lines := make([]string, 0, len(r.Hooks))
+	for _, result := range r.Hooks {
+		if result.OK() {
+			lines = append(lines, "✅ "+result.Name)
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("❌ %s: %v", result.Name, result.Err))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Pipeline holds the ordered on-advance and on-set hooks.
+type Pipeline struct {
+	mu      sync.RWMutex
+	advance []Hook
+	set     []Hook
+}
+
+// NewPipeline returns an empty pipeline.
+func NewPipeline() *Pipeline {
+	return &Pipeline{}
+}
+
+// OnAdvance registers a hook that runs when the cycle advances one phase.
+func (p *Pipeline) OnAdvance(h Hook) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.advance = append(p.advance, h)
+}
+
+// OnSet registers a hook that runs when a host hard-sets the cycle.
+func (p *Pipeline) OnSet(h Hook) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.set = append(p.set, h)
+}
+
+// hooks returns the hooks registered for kind, sorted by Order.
+func (p *Pipeline) hooks(kind Kind) []Hook {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	source := p.advance
+	if kind == KindSet {
+		source = p.set
+	}
+	hooks := append([]Hook(nil), source...)
+	sort.SliceStable(hooks, func(i, j int) bool { return hooks[i].Order < hooks[j].Order })
+	return hooks
+}
+
+// defaultPipeline is shared by every Service built with New so that the
+// Discord /cycle command and the web panel run the same registered hooks.
+var defaultPipeline = NewPipeline()
+
+// OnAdvance registers h on the shared pipeline used by /cycle next and the
+// web advance endpoint.
+func OnAdvance(h Hook) { defaultPipeline.OnAdvance(h) }
+
+// OnSet registers h on the shared pipeline used by /cycle set and the web set
+// endpoint.
+func OnSet(h Hook) { defaultPipeline.OnSet(h) }
+
+// runHook invokes fn, converting a panic into an error so one broken hook
+// cannot take down the transition.
+func runHook(name string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cycle hook %s panicked: %v", name, r)
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,191 @@
+package cycle
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultTimezone is used when a schedule does not name one; games are run
+// on US Eastern time.
+const DefaultTimezone = "America/New_York"
+
+// WarningLeads are the countdown warnings posted before each boundary.
+var WarningLeads = []time.Duration{time.Hour, 10 * time.Minute}
+
+// Schedule is the host-defined timetable of automatic phase changes, stored
+// as JSON in game_config.
+type Schedule struct {
+	Timezone   string     `json:"timezone"`
+	Boundaries []Boundary `json:"boundaries"`
+}
+
+// Boundary is one local wall-clock time ("20:00") at which the cycle
+// advances. Days limits it to certain weekdays ("mon".."sun"); empty means
+// every day.
+type Boundary struct {
+	At   string   `json:"at"`
+	Days []string `json:"days,omitempty"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// ParseSchedule builds a schedule from host input: comma separated times
+// ("08:00, 20:00"), optional comma separated weekdays applied to every time,
+// and an IANA timezone.
+func ParseSchedule(timezone, times, days string) (Schedule, error) {
+	var dayList []string
+	for _, day := range strings.Split(days, ",") {
+		if day = strings.ToLower(strings.TrimSpace(day)); day != "" {
+			dayList = append(dayList, day)
+		}
+	}
+	s := Schedule{Timezone: strings.TrimSpace(timezone)}
+	for _, at := range strings.Split(times, ",") {
+		if at = strings.TrimSpace(at); at != "" {
+			s.Boundaries = append(s.Boundaries, Boundary{At: at, Days: dayList})
+		}
+	}
+	if err := s.Validate(); err != nil {
+		return Schedule{}, err
+	}
+	return s, nil
+}
+
+// Validate reports the first problem with the timezone or a boundary.
+func (s Schedule) Validate() error {
+	if _, err := s.Location(); err != nil {
+		return err
+	}
+	if len(s.Boundaries) == 0 {
+		return errors.New("at least one boundary time is required")
+	}
+	for _, b := range s.Boundaries {
+		if _, _, err := b.clock(); err != nil {
+			return err
+		}
+		for _, day := range b.Days {
+			if _, ok := weekdays[strings.ToLower(day)]; !ok {
+				return fmt.Errorf("unknown weekday %q", day)
+			}
+		}
+	}
+	return nil
+}
+
+// Location returns the schedule's timezone, defaulting to DefaultTimezone.
+func (s Schedule) Location() (*time.Location, error) {
+	name := s.Timezone
+	if name == "" {
+		name = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	return loc, nil
+}
+
+func (b Boundary) clock() (hour, minute int, err error) {
+	t, err := time.Parse("15:04", b.At)
+	if err != nil {
+		return 0, 0, fmt.Errorf("boundary time %q must be HH:MM (24h)", b.At)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+func (b Boundary) on(day time.Weekday) bool {
+	if len(b.Days) == 0 {
+		return true
+	}
+	for _, name := range b.Days {
+		if weekdays[strings.ToLower(name)] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// occurrences lists every boundary instant on the local dates from..to
+// inclusive, sorted ascending.
+func (s Schedule) occurrences(from, to time.Time) []time.Time {
+	loc, err := s.Location()
+	if err != nil {
+		return nil
+	}
+	from, to = from.In(loc), to.In(loc)
+	var out []time.Time
+	for d := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc); !d.After(to); d = d.AddDate(0, 0, 1) {
+		for _, b := range s.Boundaries {
+			hour, minute, err := b.clock()
+			if err != nil || !b.on(d.Weekday()) {
+				continue
+			}
+			out = append(out, time.Date(d.Year(), d.Month(), d.Day(), hour, minute, 0, 0, loc))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Before(out[j]) })
+	return out
+}
+
+// Next returns the first boundary strictly after t.
+func (s Schedule) Next(t time.Time) (time.Time, bool) {
+	for _, at := range s.occurrences(t, t.AddDate(0, 0, 8)) {
+		if at.After(t) {
+			return at, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Prev returns the latest boundary at or before t.
+func (s Schedule) Prev(t time.Time) (time.Time, bool) {
+	occ := s.occurrences(t.AddDate(0, 0, -8), t)
+	for i := len(occ) - 1; i >= 0; i-- {
+		if !occ[i].After(t) {
+			return occ[i], true
+		}
+	}
+	return time.Time{}, false
+}
+
+// DueWarning returns the countdown warning that applies at now for a boundary
+// at next: the shortest lead already reached. A late start therefore skips
+// straight to the nearest warning.
+func DueWarning(next, now time.Time) (time.Duration, bool) {
+	if !now.Before(next) {
+		return 0, false
+	}
+	var due time.Duration
+	found := false
+	for _, lead := range WarningLeads {
+		if !now.Before(next.Add(-lead)) && (!found || lead < due) {
+			due, found = lead, true
+		}
+	}
+	return due, found
+}
+
+// FormatLead renders a warning lead like "1 hour" or "10 minutes".
+func FormatLead(lead time.Duration) string {
+	if lead%time.Hour == 0 {
+		if hours := int(lead / time.Hour); hours != 1 {
+			return fmt.Sprintf("%d hours", hours)
+		}
+		return "1 hour"
+	}
+	if minutes := int(lead / time.Minute); minutes != 1 {
+		return fmt.Sprintf("%d minutes", minutes)
+	}
+	return "1 minute"
+}
@@ -0,0 +1,260 @@
+package cycle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/rs/zerolog"
+)
+
+// game_config keys owned by the scheduler.
+const (
+	ScheduleConfigKey       = "cycle_schedule"
+	SchedulePausedConfigKey = "cycle_schedule_paused"
+	// Markers claimed with ClaimGameConfig so each boundary and warning fires
+	// once, even across restarts or a second bot process.
+	scheduleFiredConfigKey  = "cycle_schedule_last_boundary"
+	scheduleWarnedConfigKey = "cycle_schedule_last_warning"
+)
+
+// ScheduledBy is recorded as the trigger of scheduled transitions.
+const ScheduledBy = "schedule"
+
+// scheduleTick is how often the worker checks for due boundaries.
+const scheduleTick = 30 * time.Second
+
+// missedBoundaryGrace bounds how late a boundary may still fire, e.g. after a
+// restart. Older boundaries are recorded as missed rather than advancing a
+// game that hosts may already have moved by hand.
+const missedBoundaryGrace = 15 * time.Minute
+
+// ErrNoSchedule is returned when no schedule is configured.
+var ErrNoSchedule = errors.New("no cycle schedule configured")
+
+// ScheduleState is the stored schedule plus what it will do next.
+type ScheduleState struct {
+	Schedule Schedule
+	Paused   bool
+	// Next is the next boundary; zero when the schedule has none.
+	Next time.Time
+}
+
+// Scheduler advances the cycle at scheduled boundaries and posts countdown
+// warnings to the announcement channel. Configuration calls work without a
+// Discord session; Tick then advances without announcing.
+type Scheduler struct {
+	pool    *pgxpool.Pool
+	session *discordgo.Session
+}
+
+// NewScheduler returns a Scheduler announcing through session.
+func NewScheduler(pool *pgxpool.Pool, session *discordgo.Session) *Scheduler {
+	return &Scheduler{pool: pool, session: session}
+}
+
+// State loads the schedule as seen at now.
+func (s *Scheduler) State(ctx context.Context, now time.Time) (ScheduleState, error) {
+	q := models.New(s.pool)
+	raw, err := q.GetGameConfig(ctx, ScheduleConfigKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ScheduleState{}, ErrNoSchedule
+	}
+	if err != nil {
+		return ScheduleState{}, err
+	}
+	var state ScheduleState
+	if err := json.Unmarshal([]byte(raw), &state.Schedule); err != nil {
+		return ScheduleState{}, fmt.Errorf("stored cycle schedule is invalid: %w", err)
+	}
+	paused, err := q.GetGameConfig(ctx, SchedulePausedConfigKey)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return ScheduleState{}, err
+	}
+	state.Paused = paused == "true"
+	state.Next, _ = state.Schedule.Next(now)
+	return state, nil
+}
+
+// Save validates and stores schedule. Boundaries already passed at now are
+// marked as fired so saving never advances the game immediately.
+func (s *Scheduler) Save(ctx context.Context, schedule Schedule, now time.Time) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(schedule)
+	if err != nil {
+		return err
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	q := models.New(tx)
+	if _, err := q.UpsertGameConfig(ctx, models.UpsertGameConfigParams{Key: ScheduleConfigKey, Value: string(raw)}); err != nil {
+		return err
+	}
+	if err := markFired(ctx, q, schedule, now); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Clear removes the schedule and its pause flag.
+func (s *Scheduler) Clear(ctx context.Context) error {
+	q := models.New(s.pool)
+	if err := q.DeleteGameConfig(ctx, ScheduleConfigKey); err != nil {
+		return err
+	}
+	return q.DeleteGameConfig(ctx, SchedulePausedConfigKey)
+}
+
+// SetPaused pauses or resumes the schedule. Resuming skips every boundary
+// that passed while paused.
+func (s *Scheduler) SetPaused(ctx context.Context, paused bool, now time.Time) error {
+	state, err := s.State(ctx, now)
+	if err != nil {
+		return err
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	q := models.New(tx)
+	if _, err := q.UpsertGameConfig(ctx, models.UpsertGameConfigParams{Key: SchedulePausedConfigKey, Value: strconv.FormatBool(paused)}); err != nil {
+		return err
+	}
+	if !paused {
+		if err := markFired(ctx, q, state.Schedule, now); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func markFired(ctx context.Context, q *models.Queries, schedule Schedule, now time.Time) error {
+	prev, ok := schedule.Prev(now)
+	if !ok {
+		return nil
+	}
+	_, err := q.UpsertGameConfig(ctx, models.UpsertGameConfigParams{Key: scheduleFiredConfigKey, Value: strconv.FormatInt(prev.Unix(), 10)})
+	return err
+}
+
+// claim reports whether this caller is the first to record value under key.
+func claim(ctx context.Context, q *models.Queries, key, value string) (bool, error) {
+	_, err := q.ClaimGameConfig(ctx, models.ClaimGameConfigParams{Key: key, Value: value})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Tick advances the cycle when a boundary is due and posts any due countdown
+// warning. It is safe to call repeatedly and from several processes.
+func (s *Scheduler) Tick(ctx context.Context, now time.Time) error {
+	state, err := s.State(ctx, now)
+	if errors.Is(err, ErrNoSchedule) {
+		return nil
+	}
+	if err != nil || state.Paused {
+		return err
+	}
+	q := models.New(s.pool)
+
+	if prev, ok := state.Schedule.Prev(now); ok {
+		claimed, err := claim(ctx, q, scheduleFiredConfigKey, strconv.FormatInt(prev.Unix(), 10))
+		if err != nil {
+			return err
+		}
+		if claimed {
+			if now.Sub(prev) > missedBoundaryGrace {
+				logger.Get().Warn().Time("boundary", prev).Msg("missed scheduled cycle boundary; not advancing late")
+			} else {
+				return s.advance(ctx)
+			}
+		}
+	}
+
+	if state.Next.IsZero() {
+		return nil
+	}
+	lead, ok := DueWarning(state.Next, now)
+	if !ok {
+		return nil
+	}
+	claimed, err := claim(ctx, q, scheduleWarnedConfigKey, fmt.Sprintf("%d/%s", state.Next.Unix(), lead))
+	if err != nil || !claimed {
+		return err
+	}
+	return s.warn(ctx, state.Next, lead)
+}
+
+// advance runs the scheduled transition; the registered announce hook posts
+// it like /cycle next.
+func (s *Scheduler) advance(ctx context.Context) error {
+	report, err := New(s.pool).Advance(ctx, ScheduledBy)
+	if err != nil {
+		return err
+	}
+	for _, result := range report.Failed() {
+		logger.Get().Error().Err(result.Err).Str("hook", result.Name).Msg("cycle hook failed")
+	}
+	logger.Get().Info().Str("phase", PhaseLabel(report.To.IsElimination, report.To.Day)).Msg("scheduled cycle advance")
+	return nil
+}
+
+// warn posts a countdown to the announcement channel.
+func (s *Scheduler) warn(ctx context.Context, next time.Time, lead time.Duration) error {
+	if s.session == nil {
+		return nil
+	}
+	q := models.New(s.pool)
+	channelID, err := q.GetGameConfig(ctx, AnnouncementChannelConfigKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		logger.Get().Warn().Msg("no announcement channel configured; skipping cycle countdown")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	cycle, err := q.GetCycle(ctx)
+	if err != nil {
+		return err
+	}
+	upcoming := NextCycle(cycle)
+	msg := fmt.Sprintf("⏰ **%s** begins in %s (<t:%d:t>).",
+		PhaseLabel(upcoming.IsElimination, upcoming.Day), FormatLead(lead), next.Unix())
+	_, err = s.session.ChannelMessageSend(channelID, msg)
+	return err
+}
+
+// Start runs Tick on a fixed interval until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context, log zerolog.Logger) {
+	logger.SafeGo(log, "cycle_schedule", func() error {
+		ticker := time.NewTicker(scheduleTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case now := <-ticker.C:
+				tickCtx, cancel := context.WithTimeout(ctx, scheduleTick)
+				if err := s.Tick(tickCtx, now); err != nil {
+					log.Error().Err(err).Msg("cycle schedule tick failed")
+				}
+				cancel()
+			}
+		}
+	})
+}
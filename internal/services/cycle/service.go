@@ -1,6 +1,10 @@
 // Package cycle implements the game-cycle rules for the Betrayal bot.
-// The ken /cycle handlers stay thin; transition logic lives here so it can be
-// unit-tested against the local DB without Discord.
+// The ken /cycle handlers and the web ops API stay thin; transition logic
+// lives here so it can be unit-tested against the local DB without Discord.
+// Every transition is logged to cycle_history so other features can resolve
+// which phase was running at a given time with PhaseAt.
+// Subsystems that must react to a phase change register ordered hooks with
+// OnAdvance / OnSet instead of relying on a host checklist.
 package cycle
 
 import (
@@ -11,14 +15,23 @@ import (
 	"github.com/mccune1224/betrayal/internal/models"
 )
 
-// Service is the DB-backed cycle engine used by the /cycle command.
+// Service is the DB-backed cycle engine used by the /cycle command and the
+// web ops API. Every transition runs through the hook pipeline.
 type Service struct {
-	pool *pgxpool.Pool
+	pool     *pgxpool.Pool
+	pipeline *Pipeline
 }
 
-// New returns a cycle Service backed by pool.
+// New returns a cycle Service backed by pool that runs the shared hook
+// pipeline.
 func New(pool *pgxpool.Pool) *Service {
-	return &Service{pool: pool}
+	return &Service{pool: pool, pipeline: defaultPipeline}
+}
+
+// NewWithPipeline returns a cycle Service that runs pipeline instead of the
+// shared one (used by tests and tools that must not trigger live hooks).
+func NewWithPipeline(pool *pgxpool.Pool, pipeline *Pipeline) *Service {
+	return &Service{pool: pool, pipeline: pipeline}
 }
 
 // Current returns the persisted game cycle.
@@ -28,32 +41,94 @@ func (s *Service) Current(ctx context.Context) (models.GameCycle, error) {
 
 // Set hard-sets the cycle to the given phase and day.
 func (s *Service) Set(ctx context.Context, isElimination bool, day int32) (models.GameCycle, error) {
-	q := models.New(s.pool)
-	curr, err := q.GetCycle(ctx)
-	if err != nil {
-		return curr, err
-	}
-	return q.UpdateCycle(ctx, models.UpdateCycleParams{
-		ID:            curr.ID,
-		Day:           day,
-		IsElimination: isElimination,
-	})
+	report, err := s.SetPhase(ctx, isElimination, day, "")
+	return report.To, err
 }
 
 // Increment advances the cycle by one phase and persists it:
 // Day 0 -> Day 1; Day n -> Elimination n; Elimination n -> Day n+1.
 func (s *Service) Increment(ctx context.Context) (models.GameCycle, error) {
-	q := models.New(s.pool)
-	curr, err := q.GetCycle(ctx)
+	report, err := s.Advance(ctx, "")
+	return report.To, err
+}
+
+// Advance moves the cycle forward one phase and runs the on-advance hooks.
+// by records who triggered the transition.
+func (s *Service) Advance(ctx context.Context, by string) (Report, error) {
+	return s.transition(ctx, KindAdvance, by, NextCycle)
+}
+
+// SetPhase hard-sets the cycle and runs the on-set hooks.
+func (s *Service) SetPhase(ctx context.Context, isElimination bool, day int32, by string) (Report, error) {
+	return s.transition(ctx, KindSet, by, func(curr models.GameCycle) models.GameCycle {
+		return models.GameCycle{ID: curr.ID, IsElimination: isElimination, Day: day}
+	})
+}
+
+// transition persists the cycle computed by next and runs the pipeline. The
+// cycle update and every InTx hook share one transaction; AfterCommit hooks
+// run only once it has committed. Hook failures are reported, not returned.
+func (s *Service) transition(ctx context.Context, kind Kind, by string, next func(models.GameCycle) models.GameCycle) (Report, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+	defer tx.Rollback(ctx)
+	q := models.New(tx)
+
+	// The row stays locked until commit: a scheduled advance racing /cycle
+	// next waits here and then advances from the committed phase, so neither
+	// is lost and the hooks run once per phase.
+	curr, err := q.GetCycleForUpdate(ctx)
 	if err != nil {
-		return curr, err
+		return Report{}, err
 	}
-	next := NextCycle(curr)
-	return q.UpdateCycle(ctx, models.UpdateCycleParams{
+	target := next(curr)
+	updated, err := q.UpdateCycle(ctx, models.UpdateCycleParams{
 		ID:            curr.ID,
-		Day:           next.Day,
-		IsElimination: next.IsElimination,
+		Day:           target.Day,
+		IsElimination: target.IsElimination,
 	})
+	if err != nil {
+		return Report{}, err
+	}
+	if err := recordHistory(ctx, q, updated, by); err != nil {
+		return Report{}, fmt.Errorf("record cycle history: %w", err)
+	}
+
+	report := Report{Transition: Transition{Kind: kind, From: curr, To: updated, By: by, Pool: s.pool}}
+	hooks := s.pipeline.hooks(kind)
+	errs := make([]error, len(hooks))
+	for i, hook := range hooks {
+		if hook.InTx == nil {
+			continue
+		}
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			return Report{}, fmt.Errorf("cycle hook %s: begin savepoint: %w", hook.Name, err)
+		}
+		errs[i] = runHook(hook.Name, func() error { return hook.InTx(ctx, models.New(savepoint), report.Transition) })
+		if errs[i] != nil {
+			if err := savepoint.Rollback(ctx); err != nil {
+				return Report{}, fmt.Errorf("cycle hook %s: rollback savepoint: %w", hook.Name, err)
+			}
+			continue
+		}
+		if err := savepoint.Commit(ctx); err != nil {
+			return Report{}, fmt.Errorf("cycle hook %s: release savepoint: %w", hook.Name, err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Report{}, err
+	}
+
+	for i, hook := range hooks {
+		if errs[i] == nil && hook.AfterCommit != nil {
+			errs[i] = runHook(hook.Name, func() error { return hook.AfterCommit(ctx, report.Transition) })
+		}
+		report.Hooks = append(report.Hooks, HookResult{Name: hook.Name, Err: errs[i]})
+	}
+	return report, nil
 }
 
 // NextCycle returns the cycle that follows curr (pure, unit-testable).
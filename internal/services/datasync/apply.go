@@ -8,6 +8,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/provision"
 )
 
 // ApplyRoles executes a role source plan inside ONE transaction. Sheet edits
@@ -48,6 +49,11 @@ func ApplyRolesTx(ctx context.Context, tx models.DBTX, plan *RoleSourcePlan) err
 			}
 			roleIDs[rp.Doc.Name] = roleID
 		}
+		if rp.Doc.Setup != nil {
+			if err := storeSetup(ctx, q, rp.Doc.Name, *rp.Doc.Setup); err != nil {
+				return err
+			}
+		}
 
 		for _, ap := range rp.Abilities {
 			abilityID, ok := abilityIDs[ap.Doc.Name]
@@ -159,6 +165,30 @@ func upsertRole(ctx context.Context, q *models.Queries, rp RolePlan) (int32, err
 	}
 }
 
+// storeSetup replaces a role's setup with the one from its sheet. Names are
+// not checked against the catalog here: items may sync after roles, and
+// player creation skips unknown names with a warning.
+func storeSetup(ctx context.Context, q *models.Queries, roleName string, setup provision.Setup) error {
+	var err error
+	if setup.IsZero() {
+		err = q.DeleteRoleSetup(ctx, provision.SetupKey(roleName))
+	} else {
+		_, err = q.UpsertRoleSetup(ctx, models.UpsertRoleSetupParams{
+			RoleName:       provision.SetupKey(roleName),
+			Statuses:       setup.Statuses,
+			Immunities:     setup.Immunities,
+			Items:          setup.Items,
+			ItemLimitDelta: setup.ItemLimitDelta,
+			BonusCoins:     setup.BonusCoins,
+			BonusLuck:      setup.BonusLuck,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("store setup for role %q: %w", roleName, err)
+	}
+	return nil
+}
+
 func upsertAbility(ctx context.Context, q *models.Queries, doc AbilityDoc) (int32, error) {
 	existing, err := q.GetAbilityInfoByName(ctx, doc.Name)
 	switch {
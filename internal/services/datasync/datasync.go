@@ -21,6 +21,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/provision"
 )
 
 // infiniteCharges is the DB encoding for "∞" (unlimited) charges.
@@ -52,12 +53,15 @@ type PerkDoc struct {
 }
 
 // RoleDoc is one role (plus its abilities and passives) parsed from a sheet.
+// Setup is nil unless the chunk has a "Setup:" section, in which case it
+// replaces the role's stored setup.
 type RoleDoc struct {
 	Name        string
 	Description string
 	Alignment   models.Alignment
 	Abilities   []AbilityDoc
 	Perks       []PerkDoc
+	Setup       *provision.Setup
 }
 
 // ItemDoc is one item parsed from the items sheet.
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/provision"
 )
 
 // ParseRolesCSV parses a roles sheet (good/evil/neutral CSV export) into role
@@ -96,11 +97,11 @@ func parseRoleChunk(chunk [][]string, alignment models.Alignment) (RoleDoc, []st
 	doc.Description = strings.TrimSpace(chunk[1][2])
 	doc.Alignment = alignment
 
-	// Abilities run from row 3 until the "Passives:" marker row.
+	// Abilities run from row 3 until the "Passives:" (or "Setup:") marker row.
 	idx := 3
 	for idx < len(chunk) {
 		row := chunk[idx]
-		if len(row) > 1 && row[1] == "Passives:" {
+		if isMarker(row, "Passives:") || isMarker(row, "Setup:") {
 			break
 		}
 		ab, warn, err := parseAbility(row)
@@ -112,20 +113,77 @@ func parseRoleChunk(chunk [][]string, alignment models.Alignment) (RoleDoc, []st
 		idx++
 	}
 
-	// Perks run after the "Passives:" marker.
-	for i := idx + 1; i < len(chunk); i++ {
-		row := chunk[i]
-		if len(row) < 3 {
-			continue
+	// Perks run after the "Passives:" marker until an optional "Setup:" one.
+	if idx < len(chunk) && isMarker(chunk[idx], "Passives:") {
+		for idx++; idx < len(chunk) && !isMarker(chunk[idx], "Setup:"); idx++ {
+			row := chunk[idx]
+			if len(row) < 3 {
+				continue
+			}
+			doc.Perks = append(doc.Perks, PerkDoc{
+				Name:        strings.TrimSpace(row[1]),
+				Description: strings.TrimSpace(row[2]),
+			})
 		}
-		doc.Perks = append(doc.Perks, PerkDoc{
-			Name:        strings.TrimSpace(row[1]),
-			Description: strings.TrimSpace(row[2]),
-		})
+	}
+
+	// The setup rows follow the "Setup:" marker.
+	if idx < len(chunk) {
+		setup, warn := parseSetup(doc.Name, chunk[idx+1:])
+		warnings = append(warnings, warn...)
+		doc.Setup = &setup
 	}
 	return doc, warnings, nil
 }
 
+func isMarker(row []string, marker string) bool {
+	return len(row) > 1 && strings.TrimSpace(row[1]) == marker
+}
+
+// parseSetup parses the rows of a role's "Setup:" section. Column layout
+// (0-indexed): 1=rule, 2=value. Statuses, Immunities and Items take
+// slash-separated names (an item listed twice is granted twice); Item Limit,
+// Coins and Luck take signed numbers added to the new-player defaults.
+// Unknown rules and bad numbers are skipped with a warning.
+func parseSetup(role string, rows [][]string) (provision.Setup, []string) {
+	var setup provision.Setup
+	var warnings []string
+	for _, row := range rows {
+		if len(row) < 3 || strings.TrimSpace(row[1]) == "" {
+			continue
+		}
+		rule := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(row[1]), ":"))
+		value := strings.TrimSpace(row[2])
+		var number *int32
+		switch rule {
+		case "status", "statuses":
+			setup.Statuses = append(setup.Statuses, splitCategories(value)...)
+		case "immunity", "immunities":
+			setup.Immunities = append(setup.Immunities, splitCategories(value)...)
+		case "item", "items":
+			setup.Items = append(setup.Items, splitCategories(value)...)
+		case "item limit":
+			number = &setup.ItemLimitDelta
+		case "coins":
+			number = &setup.BonusCoins
+		case "luck":
+			number = &setup.BonusLuck
+		default:
+			warnings = append(warnings, fmt.Sprintf("role %q: unknown setup rule %q — skipping", role, row[1]))
+			continue
+		}
+		if number != nil {
+			n, err := strconv.ParseInt(strings.TrimPrefix(value, "+"), 10, 32)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("role %q: setup %s %q is not a number — skipping", role, row[1], value))
+				continue
+			}
+			*number = int32(n)
+		}
+	}
+	return setup.Normalize(), warnings
+}
+
 // parseAbility parses one ability row. Column layout (1-indexed as in the
 // sheet): 2=name, 3=charges ("∞" allowed), 4=type marker (* / ^ / empty),
 // 5=description, 6=categories (slash-separated), 7=rarity (only for * type).
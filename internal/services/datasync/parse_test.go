@@ -124,6 +124,45 @@ func TestParseRolesCSV_TrimsNames(t *testing.T) {
 	require.Equal(t, "Passive One", docs[0].Perks[0].Name, "trailing whitespace trimmed from perk name")
 }
 
+func TestParseRolesCSV_SetupSection(t *testing.T) {
+	csv := `so,,,,,,
+,Name ,Description,,,,
+,RoleS,Role desc S,,,,
+,Abilities:,Charges,Type,Description,Categories,Rarity (if AA)
+,Ability One,1,,Thing,,
+,Passives:,Description,,,,
+,Passive One,Passive desc,,,,
+,Setup:,Value,,,,
+,Immunities,Frozen / Burned,,,,
+,Statuses:,Lucky,,,,
+,Items,Rope/Rope,,,,
+,Item Limit,+2,,,,
+,Coins,-50,,,,
+,Luck,lots,,,,
+,Charm,3,,,,
+`
+	docs, warnings, err := datasync.ParseRolesCSV(strings.NewReader(csv), models.AlignmentNEUTRAL)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	require.Len(t, docs[0].Perks, 1, "setup rows are not read as passives")
+	setup := docs[0].Setup
+	require.NotNil(t, setup)
+	require.Equal(t, []string{"Frozen", "Burned"}, setup.Immunities)
+	require.Equal(t, []string{"Lucky"}, setup.Statuses)
+	require.Equal(t, []string{"Rope", "Rope"}, setup.Items)
+	require.Equal(t, int32(2), setup.ItemLimitDelta)
+	require.Equal(t, int32(-50), setup.BonusCoins)
+	require.Zero(t, setup.BonusLuck)
+	require.Len(t, warnings, 2)
+	require.Contains(t, warnings[0], "not a number")
+	require.Contains(t, warnings[1], "unknown setup rule")
+
+	// Without the section the stored setup is left alone.
+	docs, _, err = datasync.ParseRolesCSV(strings.NewReader(roleCSV), models.AlignmentGOOD)
+	require.NoError(t, err)
+	require.Nil(t, docs[0].Setup)
+}
+
 func TestParseRolesCSV_UnknownRarityWarnsAndSkips(t *testing.T) {
 	csv := `so,,,,,,
 ,Name ,Description,,,,
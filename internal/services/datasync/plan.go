@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/provision"
 )
 
 // PlanRoles diffs parsed role documents against the database (read-only).
@@ -31,6 +32,14 @@ func PlanRoles(ctx context.Context, q *models.Queries, alignment models.Alignmen
 	if err != nil {
 		return nil, fmt.Errorf("list categories: %w", err)
 	}
+	setupRows, err := q.ListRoleSetup(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list role setups: %w", err)
+	}
+	setups := make(map[string]provision.Setup, len(setupRows))
+	for _, row := range setupRows {
+		setups[row.RoleName] = provision.SetupFromRow(row)
+	}
 	rolesByName := make(map[string]models.Role, len(roles))
 	for _, role := range roles {
 		rolesByName[role.Name] = role
@@ -66,6 +75,13 @@ func PlanRoles(ctx context.Context, q *models.Queries, alignment models.Alignmen
 				rp.Changes = append(rp.Changes,
 					fmt.Sprintf("alignment: %s → %s", existing.Alignment, doc.Alignment))
 			}
+		}
+		if doc.Setup != nil {
+			if current := setups[provision.SetupKey(doc.Name)]; !current.Equal(*doc.Setup) {
+				rp.Changes = append(rp.Changes, fmt.Sprintf("setup: %s → %s", current, doc.Setup))
+			}
+		}
+		if exists {
 			rp.Action = actionFor(rp.Changes)
 		}
 		plan.Counts[rp.Action]++
@@ -10,6 +10,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mccune1224/betrayal/internal/models"
 	"github.com/mccune1224/betrayal/internal/services/datasync"
+	"github.com/mccune1224/betrayal/internal/services/provision"
 	"github.com/mccune1224/betrayal/tests/testutil"
 	"github.com/stretchr/testify/require"
 )
@@ -147,6 +148,37 @@ func TestApplyItemsNewCategoryOnUnchangedItem(t *testing.T) {
 	require.True(t, linked)
 }
 
+func TestApplyRolesStoresSetup(t *testing.T) {
+	pool := mustPool(t)
+	ctx := context.Background()
+	q := models.New(pool)
+	t.Cleanup(func() { _ = q.DeleteRoleSetup(ctx, "rolea") })
+
+	docs, _, err := datasync.ParseRolesCSV(strings.NewReader(roleCSV), models.AlignmentGOOD)
+	require.NoError(t, err)
+	docs[0].Setup = &provision.Setup{Immunities: []string{"Frozen"}, ItemLimitDelta: 1}
+	plan, err := datasync.PlanRoles(ctx, q, models.AlignmentGOOD, docs)
+	require.NoError(t, err)
+	require.Contains(t, plan.Roles[0].Changes, "setup: none → immunities Frozen, item limit +1")
+	require.NoError(t, datasync.ApplyRoles(ctx, pool, plan))
+
+	row, err := q.GetRoleSetup(ctx, "rolea")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Frozen"}, row.Immunities)
+	require.Equal(t, int32(1), row.ItemLimitDelta)
+
+	// Re-planning the same setup is a no-op; an empty section clears it.
+	plan, err = datasync.PlanRoles(ctx, q, models.AlignmentGOOD, docs)
+	require.NoError(t, err)
+	require.Equal(t, datasync.ActionSkip, plan.Roles[0].Action)
+	docs[0].Setup = &provision.Setup{}
+	plan, err = datasync.PlanRoles(ctx, q, models.AlignmentGOOD, docs)
+	require.NoError(t, err)
+	require.NoError(t, datasync.ApplyRoles(ctx, pool, plan))
+	_, err = q.GetRoleSetup(ctx, "rolea")
+	require.Error(t, err)
+}
+
 func TestPlanRolesSkipWhenUnchanged(t *testing.T) {
 	pool := mustPool(t)
 	ctx := context.Background()
@@ -0,0 +1,310 @@
+// Package death runs the steps that follow a player dying or being revived:
+// swapping the configured Discord roles, locking or unlocking the
+// confessional, moving it to and from the graveyard category, alliance
+// channel access, refreshing the lifeboard and inventory embeds, an optional
+// announcement and the hosts' win-condition alert. /inv death_status and the web player update share it
+// so both behave the same.
+package death
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/alliance"
+	"github.com/mccune1224/betrayal/internal/services/cycle"
+	"github.com/mccune1224/betrayal/internal/services/inventory"
+	"github.com/mccune1224/betrayal/internal/services/lifeboard"
+	"github.com/mccune1224/betrayal/internal/services/provision"
+	"github.com/mccune1224/betrayal/internal/services/wincon"
+)
+
+// ConfigKey names the game_config row holding the pipeline Config as JSON.
+const ConfigKey = "death_pipeline"
+
+// LuckyCoin is the item hosts must be reminded of when its holder dies.
+const LuckyCoin = "Lucky Coin"
+
+// ReadOnlyAccess is what a dead player keeps in their confessional.
+const ReadOnlyAccess = discordgo.PermissionViewChannel | discordgo.PermissionReadMessageHistory
+
+// readOnlyDeny is explicitly denied while the confessional is read-only.
+const readOnlyDeny = discordgo.PermissionSendMessages | discordgo.PermissionAddReactions
+
+// Config selects the pipeline steps. The zero value only refreshes the
+// lifeboard and inventory, which is what /inv death_status always did.
+type Config struct {
+	// DeadRoleIDs are given on death and taken away on revival.
+	DeadRoleIDs []string `json:"dead_role_ids"`
+	// AliveRoleIDs are taken away on death and given back on revival.
+	AliveRoleIDs []string `json:"alive_role_ids"`
+	// ReadOnly locks the confessional while the player is dead.
+	ReadOnly bool `json:"read_only"`
+	// GraveyardCategory is the category confessionals move to on death; they
+	// return to the confessional category on revival. Empty leaves them.
+	GraveyardCategory string `json:"graveyard_category"`
+	// Announce posts deaths and revivals to the announcement channel.
+	Announce bool `json:"announce"`
+}
+
+// Normalize trims names and drops empty or repeated role IDs.
+func (c Config) Normalize() Config {
+	clean := func(ids []string) []string {
+		out := []string{}
+		seen := map[string]bool{}
+		for _, id := range ids {
+			if id = strings.TrimSpace(id); id != "" && !seen[id] {
+				seen[id] = true
+				out = append(out, id)
+			}
+		}
+		return out
+	}
+	c.DeadRoleIDs, c.AliveRoleIDs = clean(c.DeadRoleIDs), clean(c.AliveRoleIDs)
+	c.GraveyardCategory = strings.TrimSpace(c.GraveyardCategory)
+	return c
+}
+
+var (
+	ErrPlayerNotFound = errors.New("player not found")
+	ErrUnchanged      = errors.New("player is already in that state")
+)
+
+// Options tune a single run. GuildID defaults to the confessional's guild;
+// Announce overrides Config.Announce when set.
+type Options struct {
+	GuildID  string
+	Announce *bool
+}
+
+// Result is the outcome of a run. Warnings lists the Discord steps that
+// failed; the alive flag itself is always stored first.
+type Result struct {
+	Player    models.Player
+	LuckyCoin bool
+	Warnings  []string
+}
+
+type Pipeline struct {
+	pool    *pgxpool.Pool
+	session *discordgo.Session
+}
+
+// New returns a pipeline posting through session, which may be nil in
+// web-only mode; the Discord steps are then skipped with a warning.
+func New(pool *pgxpool.Pool, session *discordgo.Session) *Pipeline {
+	return &Pipeline{pool: pool, session: session}
+}
+
+// Config returns the stored configuration, or the zero Config.
+func (p *Pipeline) Config(ctx context.Context) (Config, error) {
+	raw, err := models.New(p.pool).GetGameConfig(ctx, ConfigKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Config{}.Normalize(), nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+	var cfg Config
+	if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+		return Config{}, fmt.Errorf("invalid %s config: %w", ConfigKey, err)
+	}
+	return cfg.Normalize(), nil
+}
+
+// SetConfig stores cfg.
+func (p *Pipeline) SetConfig(ctx context.Context, cfg Config) (Config, error) {
+	cfg = cfg.Normalize()
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return Config{}, err
+	}
+	_, err = models.New(p.pool).UpsertGameConfig(ctx, models.UpsertGameConfigParams{Key: ConfigKey, Value: string(raw)})
+	return cfg, err
+}
+
+// SetAlive stores the player's alive flag and runs the pipeline. It returns
+// ErrUnchanged when the player already is in that state.
+func (p *Pipeline) SetAlive(ctx context.Context, playerID int64, alive bool, opts Options) (Result, error) {
+	q := models.New(p.pool)
+	player, err := q.GetPlayer(ctx, playerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Result{}, ErrPlayerNotFound
+	}
+	if err != nil {
+		return Result{}, err
+	}
+	if player.Alive == alive {
+		return Result{Player: player}, ErrUnchanged
+	}
+	if player, err = q.UpdatePlayerAlive(ctx, models.UpdatePlayerAliveParams{ID: playerID, Alive: alive}); err != nil {
+		return Result{}, err
+	}
+	return p.Run(ctx, player, opts), nil
+}
+
+// Run performs the pipeline for a player whose alive flag is already stored,
+// in the direction of player.Alive.
+func (p *Pipeline) Run(ctx context.Context, player models.Player, opts Options) Result {
+	result := Result{Player: player}
+	if !player.Alive {
+		if items, err := models.New(p.pool).ListPlayerItem(ctx, player.ID); err == nil {
+			for _, item := range items {
+				if strings.EqualFold(item.Name, LuckyCoin) {
+					result.LuckyCoin = true
+				}
+			}
+		}
+	}
+	if p.session == nil {
+		result.Warnings = append(result.Warnings, "Discord is not connected; roles, confessional and lifeboard were not updated.")
+		return result
+	}
+	cfg, err := p.Config(ctx)
+	if err != nil {
+		logger.Get().Error().Err(err).Msg("death pipeline config unreadable; using defaults")
+		result.Warnings = append(result.Warnings, "The death pipeline config could not be read; only the lifeboard was refreshed.")
+		cfg = Config{}
+	}
+	if opts.Announce != nil {
+		cfg.Announce = *opts.Announce
+	}
+	warn := func(err error, msg string) {
+		logger.Get().Error().Err(err).Int64("player_id", player.ID).Msg(msg)
+		result.Warnings = append(result.Warnings, msg+".")
+	}
+	userID := strconv.FormatInt(player.ID, 10)
+
+	conf, confErr := models.New(p.pool).GetPlayerConfessional(ctx, player.ID)
+	var channelID string
+	if confErr == nil {
+		channelID = strconv.FormatInt(conf.ChannelID, 10)
+	}
+	guildID := opts.GuildID
+	if guildID == "" {
+		guildID = p.guildID(channelID)
+	}
+
+	give, take := cfg.DeadRoleIDs, cfg.AliveRoleIDs
+	if player.Alive {
+		give, take = take, give
+	}
+	if guildID == "" && len(give)+len(take) > 0 {
+		result.Warnings = append(result.Warnings, "The guild could not be determined; Discord roles were not changed.")
+	} else {
+		for _, roleID := range give {
+			if err := p.session.GuildMemberRoleAdd(guildID, userID, roleID); err != nil {
+				warn(err, fmt.Sprintf("Role %s could not be given", roleID))
+			}
+		}
+		for _, roleID := range take {
+			if err := p.session.GuildMemberRoleRemove(guildID, userID, roleID); err != nil {
+				warn(err, fmt.Sprintf("Role %s could not be removed", roleID))
+			}
+		}
+	}
+
+	if channelID == "" && (cfg.ReadOnly || cfg.GraveyardCategory != "") {
+		result.Warnings = append(result.Warnings, "The player has no confessional to update.")
+	} else if channelID != "" {
+		if cfg.ReadOnly {
+			allow, deny := int64(ReadOnlyAccess), int64(readOnlyDeny)
+			if player.Alive {
+				allow, deny = provision.ConfessionalAccess, 0
+			}
+			if err := p.session.ChannelPermissionSet(channelID, userID, discordgo.PermissionOverwriteTypeMember, allow, deny); err != nil {
+				warn(err, "The confessional permissions could not be updated")
+			}
+		}
+		if cfg.GraveyardCategory != "" {
+			category := cfg.GraveyardCategory
+			if player.Alive {
+				category = provision.ConfessionalCategory(ctx, models.New(p.pool))
+			}
+			if err := p.move(guildID, channelID, category); err != nil {
+				warn(err, fmt.Sprintf("The confessional could not be moved to %s", category))
+			}
+		}
+	}
+
+	result.Warnings = append(result.Warnings, alliance.New(p.pool, p.session).SyncPlayer(ctx, player.ID)...)
+
+	if err := lifeboard.Refresh(ctx, p.pool, p.session); err != nil {
+		if errors.Is(err, lifeboard.ErrNotConfigured) {
+			result.Warnings = append(result.Warnings, "No lifeboard is posted; run /channel lifeboard set.")
+		} else {
+			warn(err, "The lifeboard could not be refreshed")
+		}
+	}
+	if err := inventory.NewManualInventoryHandler(player, p.pool).UpdateInventoryMessage(p.session); err != nil {
+		warn(err, "The inventory message could not be re-rendered")
+	}
+	if cfg.Announce {
+		if err := p.announce(ctx, player); err != nil {
+			warn(err, "The announcement could not be posted")
+		}
+	}
+	if _, err := wincon.New(p.pool).Alert(ctx, p.session); errors.Is(err, wincon.ErrNoAlertChannel) {
+		result.Warnings = append(result.Warnings, "A win condition changed but no admin channel is set; check /game status.")
+	} else if err != nil {
+		warn(err, "The win-condition alert could not be posted")
+	}
+	return result
+}
+
+// guildID resolves the guild from the confessional channel, falling back to
+// the bot's only guild.
+func (p *Pipeline) guildID(channelID string) string {
+	if channelID != "" {
+		if channel, err := p.session.Channel(channelID); err == nil && channel.GuildID != "" {
+			return channel.GuildID
+		}
+	}
+	if p.session.State != nil && len(p.session.State.Guilds) == 1 {
+		return p.session.State.Guilds[0].ID
+	}
+	return ""
+}
+
+func (p *Pipeline) move(guildID, channelID, categoryName string) error {
+	// The category helper reads the guild from an interaction; one is
+	// synthesised as in provision.
+	e := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{GuildID: guildID}}
+	category, err := discord.GetGuildChannelCategory(p.session, e, categoryName)
+	if err != nil {
+		return err
+	}
+	_, err = p.session.ChannelEditComplex(channelID, &discordgo.ChannelEdit{ParentID: category.ID})
+	return err
+}
+
+func (p *Pipeline) announce(ctx context.Context, player models.Player) error {
+	channelID, err := models.New(p.pool).GetGameConfig(ctx, cycle.AnnouncementChannelConfigKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errors.New("announcement channel not configured (run /channel announcement update)")
+	}
+	if err != nil {
+		return err
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("%s A player has died", discord.EmojiDead),
+		Description: discord.MentionUser(strconv.FormatInt(player.ID, 10)) + " is dead.",
+		Color:       discord.ColorThemeRed,
+	}
+	if player.Alive {
+		embed.Title = fmt.Sprintf("%s A player has returned", discord.EmojiAlive)
+		embed.Description = discord.MentionUser(strconv.FormatInt(player.ID, 10)) + " is alive again."
+		embed.Color = discord.ColorThemeGreen
+	}
+	_, err = p.session.ChannelMessageSendEmbed(channelID, embed)
+	return err
+}
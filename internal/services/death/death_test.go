@@ -0,0 +1,17 @@
+package death
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigNormalize(t *testing.T) {
+	cfg := Config{
+		DeadRoleIDs:       []string{" 10 ", "10", "", "11"},
+		GraveyardCategory: "  graveyard ",
+	}.Normalize()
+	require.Equal(t, []string{"10", "11"}, cfg.DeadRoleIDs)
+	require.Equal(t, []string{}, cfg.AliveRoleIDs)
+	require.Equal(t, "graveyard", cfg.GraveyardCategory)
+}
@@ -0,0 +1,332 @@
+// Package gameimport restores a game from an archive bundle (see package
+// archive) into a database without one, e.g. after a bad reset or to clone
+// production into a local database. Catalog references are resolved by name
+// against the target's catalog, so the catalog must be synced first.
+package gameimport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/services/archive"
+)
+
+// Tables are imported parents first. game_cycle and cycle_history replace
+// the seeded Day 0 rows; every other table must be empty.
+var Tables = []string{
+	"player",
+	"player_item",
+	"player_ability",
+	"player_perk",
+	"player_status",
+	"player_immunity",
+	"player_note",
+	"player_confessional",
+	"vote",
+	"whisper_group",
+	"whisper_group_member",
+	"game_cycle",
+	"cycle_history",
+}
+
+// replaced are seeded by the migrations and overwritten by the bundle.
+var replaced = map[string]bool{"game_cycle": true, "cycle_history": true}
+
+// reference is a catalog id column resolved by name.
+type reference struct {
+	column  string
+	catalog string
+}
+
+var references = map[string][]reference{
+	"player":          {{"role_id", "role"}},
+	"player_item":     {{"item_id", "item"}},
+	"player_ability":  {{"ability_id", "ability_info"}},
+	"player_perk":     {{"perk_id", "perk_info"}},
+	"player_status":   {{"status_id", "status"}},
+	"player_immunity": {{"status_id", "status"}},
+}
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported bundle version")
+	ErrNoTables           = errors.New("the bundle has no tables")
+)
+
+// Report describes what an import did or, for a dry run, would do. Nothing
+// is committed when Errors is non-empty.
+type Report struct {
+	Version   int              `json:"version"`
+	Label     string           `json:"label"`
+	DryRun    bool             `json:"dry_run"`
+	Committed bool             `json:"committed"`
+	Counts    map[string]int64 `json:"counts"`
+	Errors    []string         `json:"errors"`
+	Warnings  []string         `json:"warnings"`
+}
+
+func (r *Report) errorf(format string, args ...any) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+func (r *Report) warnf(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Decode reads a bundle and checks its version.
+func Decode(r io.Reader) (archive.Bundle, error) {
+	var bundle archive.Bundle
+	if err := json.NewDecoder(r).Decode(&bundle); err != nil {
+		return archive.Bundle{}, fmt.Errorf("decode bundle: %w", err)
+	}
+	if bundle.Version < 1 || bundle.Version > archive.Version {
+		return archive.Bundle{}, fmt.Errorf("%w %d (this build reads up to %d)", ErrUnsupportedVersion, bundle.Version, archive.Version)
+	}
+	if len(bundle.Tables) == 0 {
+		return archive.Bundle{}, ErrNoTables
+	}
+	return bundle, nil
+}
+
+// Service imports bundles one at a time. Build one per database and share it,
+// as the web server does, so concurrent imports queue on mu.
+type Service struct {
+	pool *pgxpool.Pool
+	mu   sync.Mutex
+}
+
+func New(pool *pgxpool.Pool) *Service {
+	return &Service{pool: pool}
+}
+
+// Import loads bundle in one transaction. A dry run performs every insert and
+// rolls back, so the report also catches constraint violations.
+func (s *Service) Import(ctx context.Context, bundle archive.Bundle, dryRun bool) (Report, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	report := Report{Version: bundle.Version, Label: bundle.Label, DryRun: dryRun, Counts: map[string]int64{}, Errors: []string{}, Warnings: []string{}}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return report, err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, table := range Tables {
+		if replaced[table] {
+			continue
+		}
+		var n int64
+		if err := tx.QueryRow(ctx, "SELECT count(*) FROM "+pgx.Identifier{table}.Sanitize()).Scan(&n); err != nil {
+			return report, fmt.Errorf("count %s: %w", table, err)
+		}
+		if n > 0 {
+			report.errorf("%s already has %d rows; import into an empty game", table, n)
+		}
+	}
+	if len(report.Errors) > 0 {
+		return report, nil
+	}
+
+	resolve, err := resolver(ctx, tx, bundle, &report)
+	if err != nil {
+		return report, err
+	}
+	for _, table := range Tables {
+		raw, ok := bundle.Tables[table]
+		if !ok {
+			report.warnf("%s is not in the bundle", table)
+			continue
+		}
+		rows, err := decodeRows(raw)
+		if err != nil {
+			report.errorf("%s: %v", table, err)
+			continue
+		}
+		for _, ref := range references[table] {
+			for _, row := range rows {
+				if err := resolve(ref, row); err != nil {
+					report.errorf("%s: %v", table, err)
+				}
+			}
+		}
+		if len(report.Errors) > 0 {
+			// Keep collecting reference errors, but stop writing.
+			continue
+		}
+		n, err := insert(ctx, tx, table, rows, &report)
+		if err != nil {
+			report.errorf("%s: %v", table, err)
+			continue
+		}
+		report.Counts[table] = n
+	}
+	if len(report.Errors) > 0 || dryRun {
+		return report, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return report, fmt.Errorf("commit import: %w", err)
+	}
+	report.Committed = true
+	return report, nil
+}
+
+// resolver maps the bundle's catalog ids to the target's ids through the
+// catalog names.
+func resolver(ctx context.Context, tx pgx.Tx, bundle archive.Bundle, report *Report) (func(reference, map[string]any) error, error) {
+	archived := map[string]map[string]string{}
+	target := map[string]map[string]json.Number{}
+	for _, refs := range references {
+		for _, ref := range refs {
+			if _, done := target[ref.catalog]; done {
+				continue
+			}
+			names := map[string]string{}
+			if raw, ok := bundle.Tables[ref.catalog]; ok {
+				rows, err := decodeRows(raw)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", ref.catalog, err)
+				}
+				for _, row := range rows {
+					names[fmt.Sprint(row["id"])], _ = row["name"].(string)
+				}
+			} else {
+				report.warnf("%s is not in the bundle; its ids are kept as they are", ref.catalog)
+				names = nil
+			}
+			archived[ref.catalog] = names
+
+			rows, err := tx.Query(ctx, "SELECT id::text, name FROM "+pgx.Identifier{ref.catalog}.Sanitize())
+			if err != nil {
+				return nil, fmt.Errorf("load %s: %w", ref.catalog, err)
+			}
+			ids := map[string]json.Number{}
+			for rows.Next() {
+				var id, name string
+				if err := rows.Scan(&id, &name); err != nil {
+					rows.Close()
+					return nil, err
+				}
+				ids[strings.ToLower(name)] = json.Number(id)
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return nil, err
+			}
+			target[ref.catalog] = ids
+		}
+	}
+	return func(ref reference, row map[string]any) error {
+		value, ok := row[ref.column]
+		if !ok || value == nil {
+			return nil
+		}
+		names := archived[ref.catalog]
+		if names == nil {
+			return nil
+		}
+		name, ok := names[fmt.Sprint(value)]
+		if !ok {
+			return fmt.Errorf("%s %v is not in the bundle's %s table", ref.column, value, ref.catalog)
+		}
+		id, ok := target[ref.catalog][strings.ToLower(name)]
+		if !ok {
+			return fmt.Errorf("%s %q is not in this database's catalog", ref.catalog, name)
+		}
+		row[ref.column] = id
+		return nil
+	}, nil
+}
+
+// insert writes rows through json_populate_recordset, limited to the columns
+// both the bundle and the table have, so newer or older bundles still load.
+func insert(ctx context.Context, tx pgx.Tx, table string, rows []map[string]any, report *Report) (int64, error) {
+	ident := pgx.Identifier{table}.Sanitize()
+	if replaced[table] {
+		if _, err := tx.Exec(ctx, "DELETE FROM "+ident); err != nil {
+			return 0, err
+		}
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	colRows, err := tx.Query(ctx, `SELECT attname FROM pg_attribute
+		WHERE attrelid = $1::regclass AND attnum > 0 AND NOT attisdropped`, table)
+	if err != nil {
+		return 0, err
+	}
+	have, err := pgx.CollectRows(colRows, pgx.RowTo[string])
+	if err != nil {
+		return 0, err
+	}
+	known := map[string]bool{}
+	for _, col := range have {
+		known[col] = true
+	}
+	var columns, dropped []string
+	for col := range rows[0] {
+		if known[col] {
+			columns = append(columns, pgx.Identifier{col}.Sanitize())
+		} else {
+			dropped = append(dropped, col)
+		}
+	}
+	sort.Strings(columns)
+	if len(dropped) > 0 {
+		sort.Strings(dropped)
+		report.warnf("%s: this database has no column %s; dropped", table, strings.Join(dropped, ", "))
+	}
+	body, err := json.Marshal(rows)
+	if err != nil {
+		return 0, err
+	}
+	list := strings.Join(columns, ", ")
+	tag, err := tx.Exec(ctx, "INSERT INTO "+ident+" ("+list+") SELECT "+list+" FROM json_populate_recordset(NULL::"+ident+", $1::json)", string(body))
+	if err != nil {
+		return 0, err
+	}
+	if err := resetSequences(ctx, tx, table); err != nil {
+		return 0, fmt.Errorf("sequence: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// resetSequences moves the table's serial sequences past the imported ids.
+func resetSequences(ctx context.Context, tx pgx.Tx, table string) error {
+	rows, err := tx.Query(ctx, `SELECT attname FROM pg_attribute
+		WHERE attrelid = $1::regclass AND attnum > 0 AND NOT attisdropped
+		AND pg_get_serial_sequence($2, attname) IS NOT NULL`, table, table)
+	if err != nil {
+		return err
+	}
+	columns, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+	for _, column := range columns {
+		col := pgx.Identifier{column}.Sanitize()
+		stmt := "SELECT setval(pg_get_serial_sequence($1, $2), COALESCE(MAX(" + col + "), 0) + 1, false) FROM " + pgx.Identifier{table}.Sanitize()
+		if _, err := tx.Exec(ctx, stmt, table, column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeRows(raw json.RawMessage) ([]map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var rows []map[string]any
+	if err := dec.Decode(&rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,45 @@
+package gameimport
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	bundle, err := Decode(strings.NewReader(`{"version":1,"label":"Season 1","tables":{"player":[{"id":123456789012345678}]}}`))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	rows, err := decodeRows(bundle.Tables["player"])
+	if err != nil {
+		t.Fatalf("rows: %v", err)
+	}
+	// Snowflakes must survive without float rounding.
+	if got := rows[0]["id"].(json.Number).String(); got != "123456789012345678" {
+		t.Fatalf("id = %s", got)
+	}
+
+	if _, err := Decode(strings.NewReader(`{"version":99,"tables":{"player":[]}}`)); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("future version: %v", err)
+	}
+	if _, err := Decode(strings.NewReader(`{"version":1}`)); !errors.Is(err, ErrNoTables) {
+		t.Fatalf("empty bundle: %v", err)
+	}
+	if _, err := Decode(strings.NewReader(`not json`)); err == nil {
+		t.Fatal("expected a decode error")
+	}
+}
+
+func TestTablesHaveParentsFirst(t *testing.T) {
+	pos := map[string]int{}
+	for i, table := range Tables {
+		pos[table] = i
+	}
+	for child, parent := range map[string]string{"player_item": "player", "vote": "player", "whisper_group_member": "whisper_group"} {
+		if pos[child] < pos[parent] {
+			t.Fatalf("%s is imported before %s", child, parent)
+		}
+	}
+}
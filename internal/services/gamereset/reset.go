@@ -8,6 +8,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/archive"
 	"github.com/mccune1224/betrayal/internal/services/datasync"
 )
 
@@ -25,10 +26,12 @@ type Summary struct {
 	LogRows   int64
 }
 
-// Result reports the completed reset/import operation.
+// Result reports the completed reset/import operation. ArchiveID is the
+// game_archive row holding the game as it was before the reset.
 type Result struct {
-	Summary Summary
-	Sources []string
+	Summary   Summary
+	Sources   []string
+	ArchiveID int64
 }
 
 type Service struct {
@@ -98,6 +101,13 @@ func (s *Service) Execute(ctx context.Context) (Result, error) {
 		plans = append(plans, prepared)
 	}
 
+	// Archive last, right before clearing, so the bundle holds every change
+	// made while the sources were fetched. No archive, no reset.
+	archived, err := archive.New(s.pool).Create(ctx, "Before reset "+time.Now().UTC().Format("2006-01-02 15:04 MST"))
+	if err != nil {
+		return Result{}, fmt.Errorf("archive game: %w", err)
+	}
+
 	tx, err := s.pool.Begin(ctx)
 	if err != nil {
 		return Result{}, err
@@ -129,7 +139,7 @@ func (s *Service) Execute(ctx context.Context) (Result, error) {
 	for i, plan := range plans {
 		names[i] = plan.name
 	}
-	return Result{Summary: before, Sources: names}, nil
+	return Result{Summary: before, Sources: names, ArchiveID: archived.ID}, nil
 }
 
 type preparedSource struct {
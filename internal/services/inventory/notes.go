@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/playernotes"
 )
 
 func (ih *InventoryHandler) CreatePlayerNote(playerID int64, info string) (*models.PlayerNote, error) {
@@ -21,10 +22,13 @@ func (ih *InventoryHandler) CreatePlayerNote(playerID int64, info string) (*mode
 			nextPosition = int(note.Position)
 		}
 	}
+	tags, links := playernotes.Parse(info)
 	note, err := q.CreatePlayerNote(ctx, models.CreatePlayerNoteParams{
-		PlayerID: playerID,
-		Position: int32(nextPosition + 1),
-		Info:     info,
+		PlayerID:        playerID,
+		Position:        int32(nextPosition + 1),
+		Info:            info,
+		Tags:            tags,
+		LinkedPlayerIds: links,
 	})
 	if err != nil {
 		return nil, err
@@ -45,10 +49,13 @@ func (ih *InventoryHandler) UpdatePlayerNote(playerID int64, position int, info
 		return nil, errors.New("position is greater than total positions")
 	}
 
+	tags, links := playernotes.Parse(info)
 	note, err := q.UpdatePlayerNoteByPosition(ctx, models.UpdatePlayerNoteByPositionParams{
-		PlayerID: playerID,
-		Position: int32(position),
-		Info:     info,
+		PlayerID:        playerID,
+		Position:        int32(position),
+		Info:            info,
+		Tags:            tags,
+		LinkedPlayerIds: links,
 	})
 	if err != nil {
 		return nil, err
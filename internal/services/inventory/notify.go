@@ -0,0 +1,122 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/util"
+)
+
+// NoticesConfigKey names the game_config row ("true"/"false") that makes
+// Notifier post a short summary in the confessional after each change.
+const NoticesConfigKey = "inventory_change_notices"
+
+// Change is one line of an inventory notice. A non-zero Delta is printed as
+// a signed count before Name ("+1 Knife", "-10 Coins"); otherwise Name is
+// printed as is ("Shield removed").
+type Change struct {
+	Name  string
+	Delta int32
+}
+
+func (c Change) String() string {
+	if c.Delta == 0 {
+		return c.Name
+	}
+	return fmt.Sprintf("%+d %s", c.Delta, c.Name)
+}
+
+// Summary is the notice posted for changes, or "" when there is nothing the
+// player should be told about.
+func Summary(changes []Change) string {
+	parts := make([]string, 0, len(changes))
+	for _, change := range changes {
+		if s := change.String(); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "Inventory updated: " + strings.Join(parts, ", ")
+}
+
+// Notifier carries inventory changes made outside a confessional (the web
+// panel, bulk edits) to Discord: the pinned inventory is re-rendered and,
+// when notices are on, a summary is posted below it. Slash commands already
+// re-render in the confessional they run in and do not need it.
+type Notifier struct {
+	pool    *pgxpool.Pool
+	session *discordgo.Session
+}
+
+// NewNotifier returns a notifier posting through session, which may be nil
+// in web-only mode; Changed then does nothing and the pinned inventory
+// catches up on the next /inv command.
+func NewNotifier(pool *pgxpool.Pool, session *discordgo.Session) *Notifier {
+	return &Notifier{pool: pool, session: session}
+}
+
+// Notices reports whether change notices are posted by default.
+func Notices(ctx context.Context, q *models.Queries) bool {
+	raw, err := q.GetGameConfig(ctx, NoticesConfigKey)
+	if err != nil {
+		return false
+	}
+	enabled, _ := strconv.ParseBool(raw)
+	return enabled
+}
+
+// SetNotices stores whether change notices are posted by default.
+func SetNotices(ctx context.Context, q *models.Queries, enabled bool) error {
+	_, err := q.UpsertGameConfig(ctx, models.UpsertGameConfigParams{Key: NoticesConfigKey, Value: strconv.FormatBool(enabled)})
+	return err
+}
+
+// Changed propagates a committed change to playerID's inventory. notice
+// overrides the NoticesConfigKey default when set. The returned warnings
+// list the Discord steps that could not be done; the change itself stands.
+func (n *Notifier) Changed(ctx context.Context, playerID int64, notice *bool, changes ...Change) []string {
+	if n.session == nil {
+		return nil
+	}
+	q := models.New(n.pool)
+	player, err := q.GetPlayer(ctx, playerID)
+	if err != nil {
+		logger.Get().Error().Err(err).Int64("player_id", playerID).Msg("inventory change not propagated")
+		return []string{"The player could not be reloaded; the pinned inventory was not updated."}
+	}
+	conf, err := q.GetPlayerConfessional(ctx, playerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return []string{"The player has no confessional to update."}
+	}
+	if err != nil {
+		logger.Get().Error().Err(err).Int64("player_id", playerID).Msg("inventory change not propagated")
+		return []string{"The confessional could not be loaded; the pinned inventory was not updated."}
+	}
+
+	var warnings []string
+	if err := NewManualInventoryHandler(player, n.pool).UpdateInventoryMessage(n.session); err != nil {
+		logger.Get().Error().Err(err).Int64("player_id", playerID).Msg("inventory embed not rendered")
+		warnings = append(warnings, "The inventory message could not be re-rendered; run any /inv command to refresh it.")
+	}
+	post := Notices(ctx, q)
+	if notice != nil {
+		post = *notice
+	}
+	if text := Summary(changes); post && text != "" {
+		if _, err := n.session.ChannelMessageSend(util.Itoa64(conf.ChannelID), text); err != nil {
+			logger.Get().Error().Err(err).Int64("player_id", playerID).Msg("inventory notice not posted")
+			warnings = append(warnings, "The inventory notice could not be posted.")
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,16 @@
+package inventory
+
+import "testing"
+
+func TestSummaryFormatsSignedChanges(t *testing.T) {
+	got := Summary([]Change{{Name: "Knife", Delta: 1}, {Name: "Coins", Delta: -10}, {Name: "Shield removed"}})
+	if want := "Inventory updated: +1 Knife, -10 Coins, Shield removed"; got != want {
+		t.Fatalf("Summary = %q, want %q", got, want)
+	}
+}
+
+func TestSummaryEmptyWithoutChanges(t *testing.T) {
+	if got := Summary([]Change{{}}); got != "" {
+		t.Fatalf("Summary = %q, want empty", got)
+	}
+}
@@ -0,0 +1,357 @@
+// Package invsnapshot records every player's inventory at the start of each
+// phase and compares snapshots, so hosts can answer "what did everyone have
+// at the start of Day 4" and recap what changed since.
+package invsnapshot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/cycle"
+	"github.com/mccune1224/betrayal/internal/services/inventory"
+	"github.com/mccune1224/betrayal/internal/util"
+)
+
+var ErrNoSnapshot = errors.New("no inventory snapshot for that phase")
+
+// Inventory is the stored form of a PlayerInventory: names and quantities
+// only, since catalog descriptions can be looked up and would bloat every
+// row.
+type Inventory struct {
+	Role       string           `json:"role,omitempty"`
+	Alignment  string           `json:"alignment"`
+	Alive      bool             `json:"alive"`
+	Coins      int32            `json:"coins"`
+	CoinBonus  string           `json:"coin_bonus,omitempty"`
+	Luck       int32            `json:"luck"`
+	ItemLimit  int32            `json:"item_limit"`
+	Items      map[string]int32 `json:"items,omitempty"`
+	Abilities  map[string]int32 `json:"abilities,omitempty"`
+	Statuses   map[string]int32 `json:"statuses,omitempty"`
+	Perks      []string         `json:"perks,omitempty"`
+	Immunities []string         `json:"immunities,omitempty"`
+	Notes      []string         `json:"notes,omitempty"`
+}
+
+// Phase identifies a snapshot: the phase whose start it records.
+type Phase struct {
+	Day         int32 `json:"day"`
+	Elimination bool  `json:"elimination"`
+}
+
+func (p Phase) String() string {
+	if p.Elimination {
+		return fmt.Sprintf("Elimination %d", p.Day)
+	}
+	return fmt.Sprintf("Day %d", p.Day)
+}
+
+// Change is one difference between two inventories. Name is set for
+// per-entry fields (an item, a perk, a note); From or To is empty when the
+// entry was added or removed.
+type Change struct {
+	Field string `json:"field"`
+	Name  string `json:"name,omitempty"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+func (c Change) String() string {
+	label := c.Field
+	if c.Name != "" {
+		label += " " + c.Name
+	}
+	switch {
+	case c.From == "":
+		return "+ " + label + suffix(c.To)
+	case c.To == "":
+		return "- " + label + suffix(c.From)
+	default:
+		return fmt.Sprintf("%s: %s → %s", label, c.From, c.To)
+	}
+}
+
+func suffix(v string) string {
+	if v == "" || v == "present" {
+		return ""
+	}
+	return " (" + v + ")"
+}
+
+// PlayerDiff holds one player's changes between two phases.
+type PlayerDiff struct {
+	PlayerID int64    `json:"player_id,string"`
+	Changes  []Change `json:"changes"`
+}
+
+// FromInventory compacts a fetched inventory.
+func FromInventory(inv *inventory.PlayerInventory) Inventory {
+	out := Inventory{
+		Role: inv.Role.Name, Alignment: string(inv.Alignment), Alive: inv.Alive,
+		Coins: inv.Coins, Luck: inv.Luck, ItemLimit: inv.ItemLimit,
+		Items: map[string]int32{}, Abilities: map[string]int32{}, Statuses: map[string]int32{},
+	}
+	if bonus, err := util.NumericToString(inv.CoinBonus); err == nil {
+		out.CoinBonus = bonus
+	}
+	for _, item := range inv.Items {
+		out.Items[item.Name] += item.Quantity
+	}
+	for _, ability := range inv.Abilities {
+		out.Abilities[ability.Name] += ability.Quantity
+	}
+	for _, status := range inv.Statuses {
+		out.Statuses[status.Name] += status.Quantity
+	}
+	for _, perk := range inv.Perks {
+		out.Perks = append(out.Perks, perk.Name)
+	}
+	for _, immunity := range inv.Immunities {
+		out.Immunities = append(out.Immunities, immunity.Name)
+	}
+	for _, note := range inv.Notes {
+		out.Notes = append(out.Notes, note.Info)
+	}
+	return out
+}
+
+// Compare lists what changed for each player from one set of inventories to
+// another, by player ID (pure, unit-testable). Players without changes are
+// left out; players in only one set show up as added or removed.
+func Compare(from, to map[int64]Inventory) []PlayerDiff {
+	ids := map[int64]bool{}
+	for id := range from {
+		ids[id] = true
+	}
+	for id := range to {
+		ids[id] = true
+	}
+	sorted := make([]int64, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var out []PlayerDiff
+	for _, id := range sorted {
+		a, inFrom := from[id]
+		b, inTo := to[id]
+		var changes []Change
+		switch {
+		case !inFrom:
+			changes = []Change{{Field: "Player", To: "present"}}
+		case !inTo:
+			changes = []Change{{Field: "Player", From: "present"}}
+		default:
+			changes = diff(a, b)
+		}
+		if len(changes) > 0 {
+			out = append(out, PlayerDiff{PlayerID: id, Changes: changes})
+		}
+	}
+	return out
+}
+
+func diff(a, b Inventory) []Change {
+	var changes []Change
+	scalar := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, Change{Field: field, From: from, To: to})
+		}
+	}
+	scalar("Role", a.Role, b.Role)
+	scalar("Alignment", a.Alignment, b.Alignment)
+	scalar("Alive", fmt.Sprint(a.Alive), fmt.Sprint(b.Alive))
+	scalar("Coins", fmt.Sprint(a.Coins), fmt.Sprint(b.Coins))
+	scalar("Coin bonus", a.CoinBonus, b.CoinBonus)
+	scalar("Luck", fmt.Sprint(a.Luck), fmt.Sprint(b.Luck))
+	scalar("Item limit", fmt.Sprint(a.ItemLimit), fmt.Sprint(b.ItemLimit))
+	changes = append(changes, counted("Item", a.Items, b.Items)...)
+	changes = append(changes, counted("Ability", a.Abilities, b.Abilities)...)
+	changes = append(changes, counted("Status", a.Statuses, b.Statuses)...)
+	changes = append(changes, listed("Perk", a.Perks, b.Perks)...)
+	changes = append(changes, listed("Immunity", a.Immunities, b.Immunities)...)
+	changes = append(changes, listed("Note", a.Notes, b.Notes)...)
+	return changes
+}
+
+func counted(field string, from, to map[string]int32) []Change {
+	names := map[string]bool{}
+	for name := range from {
+		names[name] = true
+	}
+	for name := range to {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	quantity := func(m map[string]int32, name string) string {
+		if n, ok := m[name]; ok {
+			return fmt.Sprintf("x%d", n)
+		}
+		return ""
+	}
+	var changes []Change
+	for _, name := range sorted {
+		a, b := quantity(from, name), quantity(to, name)
+		if a != b {
+			changes = append(changes, Change{Field: field, Name: name, From: a, To: b})
+		}
+	}
+	return changes
+}
+
+func listed(field string, from, to []string) []Change {
+	count := func(list []string) map[string]int32 {
+		m := map[string]int32{}
+		for _, v := range list {
+			m[v]++
+		}
+		return m
+	}
+	a, b := count(from), count(to)
+	var changes []Change
+	for _, c := range counted(field, a, b) {
+		// A list entry is present or not; repeat counts only matter when
+		// the same entry appears twice.
+		if c.From == "x1" {
+			c.From = "present"
+		}
+		if c.To == "x1" {
+			c.To = "present"
+		}
+		changes = append(changes, c)
+	}
+	return changes
+}
+
+type Service struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Service {
+	return &Service{pool: pool}
+}
+
+// Take snapshots every player for phase. A player whose inventory cannot be
+// read is skipped and reported in the returned error.
+func (s *Service) Take(ctx context.Context, phase Phase) error {
+	q := models.New(s.pool)
+	current, err := s.Live(ctx)
+	if err != nil {
+		return err
+	}
+	var failed []string
+	for id, inv := range current {
+		body, err := json.Marshal(inv)
+		if err == nil {
+			err = q.UpsertInventorySnapshot(ctx, models.UpsertInventorySnapshotParams{
+				PlayerID: id, Day: phase.Day, IsElimination: phase.Elimination, Inventory: body,
+			})
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%d: %v", id, err))
+		}
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("%d inventories not snapshotted: %s", len(failed), strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// Live returns every player's current inventory.
+func (s *Service) Live(ctx context.Context) (map[int64]Inventory, error) {
+	players, err := models.New(s.pool).ListPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int64]Inventory, len(players))
+	for _, p := range players {
+		inv, err := inventory.NewManualInventoryHandler(p, s.pool).FetchInventory()
+		if err != nil {
+			return nil, fmt.Errorf("inventory of %d: %w", p.ID, err)
+		}
+		out[p.ID] = FromInventory(inv)
+	}
+	return out, nil
+}
+
+// Load returns the inventories snapshotted at the start of phase.
+func (s *Service) Load(ctx context.Context, phase Phase) (map[int64]Inventory, error) {
+	rows, err := models.New(s.pool).ListInventorySnapshots(ctx, models.ListInventorySnapshotsParams{Day: phase.Day, IsElimination: phase.Elimination})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrNoSnapshot
+	}
+	out := make(map[int64]Inventory, len(rows))
+	for _, row := range rows {
+		var inv Inventory
+		if err := json.Unmarshal(row.Inventory, &inv); err != nil {
+			return nil, fmt.Errorf("snapshot of %d: %w", row.PlayerID, err)
+		}
+		out[row.PlayerID] = inv
+	}
+	return out, nil
+}
+
+// Diff compares the snapshot of from with the snapshot of to, or with the
+// live inventories when to is nil. playerID limits the result to one player;
+// 0 is game-wide.
+func (s *Service) Diff(ctx context.Context, from Phase, to *Phase, playerID int64) ([]PlayerDiff, error) {
+	a, err := s.Load(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	var b map[int64]Inventory
+	if to != nil {
+		b, err = s.Load(ctx, *to)
+	} else {
+		b, err = s.Live(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if playerID != 0 {
+		a = only(a, playerID)
+		b = only(b, playerID)
+	}
+	return Compare(a, b), nil
+}
+
+func only(m map[int64]Inventory, id int64) map[int64]Inventory {
+	out := map[int64]Inventory{}
+	if inv, ok := m[id]; ok {
+		out[id] = inv
+	}
+	return out
+}
+
+// RegisterCycleHooks snapshots every inventory after each cycle change, in
+// the database of the game that changed.
+func RegisterCycleHooks(pool *pgxpool.Pool) {
+	hook := cycle.Hook{
+		Name:  "Snapshot inventories",
+		Order: 50,
+		AfterCommit: func(ctx context.Context, t cycle.Transition) error {
+			gamePool := pool
+			if t.Pool != nil {
+				gamePool = t.Pool
+			}
+			return New(gamePool).Take(ctx, Phase{Day: t.To.Day, Elimination: t.To.IsElimination})
+		},
+	}
+	cycle.OnAdvance(hook)
+	cycle.OnSet(hook)
+}
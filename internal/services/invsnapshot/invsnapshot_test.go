@@ -0,0 +1,60 @@
+package invsnapshot
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	day4 := map[int64]Inventory{
+		1: {Role: "Seer", Coins: 200, Items: map[string]int32{"Lantern": 2, "Rope": 1}, Perks: []string{"Lucky"}},
+		2: {Role: "Thief", Coins: 50},
+		3: {Role: "Jester"},
+	}
+	now := map[int64]Inventory{
+		1: {Role: "Seer", Coins: 150, Items: map[string]int32{"Lantern": 1, "Key": 1}, Notes: []string{"stole a key"}},
+		2: {Role: "Thief", Coins: 50},
+		4: {Role: "Sub"},
+	}
+	got := Compare(day4, now)
+	want := []PlayerDiff{
+		{PlayerID: 1, Changes: []Change{
+			{Field: "Coins", From: "200", To: "150"},
+			{Field: "Item", Name: "Key", To: "x1"},
+			{Field: "Item", Name: "Lantern", From: "x2", To: "x1"},
+			{Field: "Item", Name: "Rope", From: "x1"},
+			{Field: "Perk", Name: "Lucky", From: "present"},
+			{Field: "Note", Name: "stole a key", To: "present"},
+		}},
+		{PlayerID: 3, Changes: []Change{{Field: "Player", From: "present"}}},
+		{PlayerID: 4, Changes: []Change{{Field: "Player", To: "present"}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Compare() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestChangeString(t *testing.T) {
+	for _, tc := range []struct {
+		change Change
+		want   string
+	}{
+		{Change{Field: "Coins", From: "200", To: "150"}, "Coins: 200 → 150"},
+		{Change{Field: "Item", Name: "Key", To: "x1"}, "+ Item Key (x1)"},
+		{Change{Field: "Perk", Name: "Lucky", From: "present"}, "- Perk Lucky"},
+		{Change{Field: "Item", Name: "Lantern", From: "x2", To: "x1"}, "Item Lantern: x2 → x1"},
+	} {
+		if got := tc.change.String(); got != tc.want {
+			t.Errorf("%+v = %q, want %q", tc.change, got, tc.want)
+		}
+	}
+}
+
+func TestPhaseString(t *testing.T) {
+	if got := (Phase{Day: 4}).String(); got != "Day 4" {
+		t.Fatalf("got %q", got)
+	}
+	if got := (Phase{Day: 4, Elimination: true}).String(); got != "Elimination 4" {
+		t.Fatalf("got %q", got)
+	}
+}
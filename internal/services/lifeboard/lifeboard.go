@@ -0,0 +1,198 @@
+// Package lifeboard renders the pinned player status board and keeps the
+// posted message in sync. The death pipeline, roster onboarding, player
+// deletion, substitution and every cycle change call Refresh, so the board
+// follows the game without hosts re-posting it.
+package lifeboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/cycle"
+	"github.com/mccune1224/betrayal/internal/util"
+)
+
+// RevealRolesConfigKey names the game_config row ("true"/"false") that shows
+// the roles of dead players on the board.
+const RevealRolesConfigKey = "lifeboard_reveal_roles"
+
+// ErrNotConfigured is returned when no lifeboard message has been posted yet.
+var ErrNotConfigured = errors.New("lifeboard not configured (run /channel lifeboard set)")
+
+// Entry is one player on the board. Left marks members no longer in the
+// guild, whose Name falls back to their username or ID.
+type Entry struct {
+	Name  string
+	Alive bool
+	Role  string
+	Left  bool
+}
+
+// Options are the board settings besides the players.
+type Options struct {
+	// Cycle is the current phase; nil omits it.
+	Cycle       *models.GameCycle
+	RevealRoles bool
+}
+
+// Render draws the board: alive players first, then the dead, each sorted by
+// name (pure, unit-testable).
+func Render(entries []Entry, opts Options) *discordgo.MessageEmbed {
+	sorted := append([]Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Alive != sorted[j].Alive {
+			return sorted[i].Alive
+		}
+		return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+	})
+	aliveTally := 0
+	fields := make([]*discordgo.MessageEmbedField, 0, len(sorted))
+	for _, entry := range sorted {
+		name := entry.Name
+		if entry.Left {
+			name += " (left)"
+		}
+		if entry.Alive {
+			aliveTally++
+			fields = append(fields, &discordgo.MessageEmbedField{
+				Name: fmt.Sprintf("%s %s", discord.EmojiAlive, name),
+			})
+			continue
+		}
+		field := &discordgo.MessageEmbedField{Name: fmt.Sprintf("%s %s", discord.EmojiDead, name)}
+		if opts.RevealRoles && entry.Role != "" {
+			field.Value = "Role: " + entry.Role
+		}
+		fields = append(fields, field)
+	}
+	description := fmt.Sprintf("%d/%d players alive", aliveTally, len(entries))
+	if opts.Cycle != nil {
+		description = fmt.Sprintf("**%s** · %s", cycle.PhaseLabel(opts.Cycle.IsElimination, opts.Cycle.Day), description)
+	}
+	return &discordgo.MessageEmbed{
+		Title:       "Player Status Board",
+		Description: description,
+		Fields:      fields,
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Last updated: " + util.GetEstTimeStamp() + " (EST)",
+		},
+	}
+}
+
+// Entries resolves the guild display name of every player. A failed member
+// lookup (the player left or the ID is wrong) falls back to the Discord
+// username and then to the raw ID instead of failing the board.
+func Entries(sesh *discordgo.Session, guildID string, rows []models.ListPlayerLifeboardRow) []Entry {
+	entries := make([]Entry, 0, len(rows))
+	for _, row := range rows {
+		id := strconv.FormatInt(row.ID, 10)
+		entry := Entry{Alive: row.Alive, Role: row.RoleName}
+		if member, err := sesh.GuildMember(guildID, id); err == nil && member != nil && member.User != nil {
+			entry.Name = member.DisplayName()
+		} else if user, err := sesh.User(id); err == nil && user != nil {
+			entry.Name, entry.Left = user.Username, true
+		} else {
+			entry.Name, entry.Left = id, true
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Message renders the current board for guildID.
+func Message(ctx context.Context, pool *pgxpool.Pool, sesh *discordgo.Session, guildID string) (*discordgo.MessageEmbed, error) {
+	q := models.New(pool)
+	rows, err := q.ListPlayerLifeboard(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts := Options{RevealRoles: RevealRoles(ctx, q)}
+	if current, err := q.GetCycle(ctx); err == nil {
+		opts.Cycle = &current
+	}
+	return Render(Entries(sesh, guildID, rows), opts), nil
+}
+
+// Refresh re-renders the posted lifeboard message in place. The guild is
+// taken from the lifeboard channel.
+func Refresh(ctx context.Context, pool *pgxpool.Pool, sesh *discordgo.Session) error {
+	board, err := models.New(pool).GetPlayerLifeboard(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotConfigured
+	}
+	if err != nil {
+		return err
+	}
+	channel, err := sesh.Channel(board.ChannelID)
+	if err != nil {
+		return fmt.Errorf("lifeboard channel %s: %w", board.ChannelID, err)
+	}
+	embed, err := Message(ctx, pool, sesh, channel.GuildID)
+	if err != nil {
+		return err
+	}
+	_, err = sesh.ChannelMessageEditEmbed(board.ChannelID, board.MessageID, embed)
+	return err
+}
+
+// RefreshQuietly refreshes the board for callers whose own work already
+// succeeded: a missing board is ignored and other failures are only logged.
+// It reports whether the posted board is up to date (or absent).
+func RefreshQuietly(ctx context.Context, pool *pgxpool.Pool, sesh *discordgo.Session) bool {
+	if sesh == nil {
+		return false
+	}
+	err := Refresh(ctx, pool, sesh)
+	if err == nil || errors.Is(err, ErrNotConfigured) {
+		return true
+	}
+	logger.Get().Error().Err(err).Msg("lifeboard not refreshed")
+	return false
+}
+
+// RevealRoles reports whether dead players' roles are shown.
+func RevealRoles(ctx context.Context, q *models.Queries) bool {
+	raw, err := q.GetGameConfig(ctx, RevealRolesConfigKey)
+	if err != nil {
+		return false
+	}
+	reveal, _ := strconv.ParseBool(raw)
+	return reveal
+}
+
+// SetRevealRoles stores whether dead players' roles are shown.
+func SetRevealRoles(ctx context.Context, q *models.Queries, reveal bool) error {
+	_, err := q.UpsertGameConfig(ctx, models.UpsertGameConfigParams{Key: RevealRolesConfigKey, Value: strconv.FormatBool(reveal)})
+	return err
+}
+
+// RegisterCycleHooks refreshes the board after every cycle change so it
+// always shows the current phase.
+func RegisterCycleHooks(pool *pgxpool.Pool, sesh *discordgo.Session) {
+	hook := cycle.Hook{
+		Name:  "Refresh lifeboard",
+		Order: 100,
+		AfterCommit: func(ctx context.Context, t cycle.Transition) error {
+			gamePool := pool
+			if t.Pool != nil {
+				gamePool = t.Pool
+			}
+			if err := Refresh(ctx, gamePool, sesh); err != nil && !errors.Is(err, ErrNotConfigured) {
+				return err
+			}
+			return nil
+		},
+	}
+	cycle.OnAdvance(hook)
+	cycle.OnSet(hook)
+}
@@ -0,0 +1,39 @@
+package lifeboard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSortsAliveFirstAndMarksLeftMembers(t *testing.T) {
+	embed := Render([]Entry{
+		{Name: "zed", Alive: true},
+		{Name: "Bob", Alive: false, Role: "Mafia"},
+		{Name: "amy", Alive: true},
+		{Name: "123", Alive: false, Left: true},
+	}, Options{})
+	names := make([]string, len(embed.Fields))
+	for i, field := range embed.Fields {
+		names[i] = field.Name
+	}
+	require.Len(t, names, 4)
+	require.True(t, strings.HasSuffix(names[0], "amy"))
+	require.True(t, strings.HasSuffix(names[1], "zed"))
+	require.True(t, strings.HasSuffix(names[2], "123 (left)"))
+	require.True(t, strings.HasSuffix(names[3], "Bob"))
+	require.Equal(t, "2/4 players alive", embed.Description)
+	require.Empty(t, embed.Fields[3].Value, "roles stay hidden unless revealed")
+}
+
+func TestRenderRevealsDeadRolesAndShowsCycle(t *testing.T) {
+	embed := Render([]Entry{
+		{Name: "amy", Alive: true, Role: "Doctor"},
+		{Name: "bob", Alive: false, Role: "Mafia"},
+	}, Options{RevealRoles: true, Cycle: &models.GameCycle{Day: 3, IsElimination: true}})
+	require.Empty(t, embed.Fields[0].Value, "living players' roles are never revealed")
+	require.Equal(t, "Role: Mafia", embed.Fields[1].Value)
+	require.Equal(t, "**Elimination 3** · 1/2 players alive", embed.Description)
+}
@@ -4,8 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mccune1224/betrayal/internal/models"
 )
@@ -13,6 +19,14 @@ import (
 var ErrUnauthorized = errors.New("player notes authorization required")
 var ErrNotFound = errors.New("player note not found")
 
+// DefaultSearchLimit caps a search without an explicit limit.
+const DefaultSearchLimit = 50
+
+var (
+	tagPattern     = regexp.MustCompile(`#([\p{L}\p{N}_-]+)`)
+	mentionPattern = regexp.MustCompile(`<@!?(\d+)>`)
+)
+
 // Authorization identifies the already-authenticated transport principal.
 // The service accepts only explicit principals so callers cannot accidentally
 // rely on route or command location as authorization.
@@ -43,6 +57,117 @@ func authorize(auth Authorization) error {
 	return nil
 }
 
+// Draft is a note as a host wrote it. #tags and <@id> mentions in Info are
+// added to Tags and Links when the note is saved.
+type Draft struct {
+	Info   string
+	Tags   []string
+	Author string
+	// Day is the cycle day the note is about; nil records the current day.
+	Day   *int32
+	Links []int64
+}
+
+// Search filters notes across every player. Zero fields match everything.
+type Search struct {
+	// Query is full-text (English stemming, so "protect" finds
+	// "protected"); quotes, OR and -word work as in web search engines.
+	Query string
+	// Tags must all be present on a note.
+	Tags []string
+	Day  *int32
+	// PlayerID matches notes on that player and notes mentioning them.
+	PlayerID int64
+	Limit    int32
+}
+
+// Parse pulls the #tags and <@id> mentions out of a note's text (pure,
+// unit-testable).
+func Parse(info string) (tags []string, links []int64) {
+	for _, m := range tagPattern.FindAllStringSubmatch(info, -1) {
+		tags = append(tags, m[1])
+	}
+	for _, m := range mentionPattern.FindAllStringSubmatch(info, -1) {
+		if id, err := strconv.ParseInt(m[1], 10, 64); err == nil {
+			links = append(links, id)
+		}
+	}
+	return NormalizeTags(tags), normalizeLinks(links)
+}
+
+// NormalizeTags lowercases tags, drops a leading '#', and sorts and dedupes
+// them. The result is never nil so it can be stored as-is.
+func NormalizeTags(tags []string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func normalizeLinks(links []int64) []int64 {
+	seen := map[int64]bool{}
+	out := []int64{}
+	for _, id := range links {
+		if id <= 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+// meta resolves what a draft stores besides its text. Tags and links always
+// follow the new text. kept is the note being updated, if any: the tags and
+// links it had beyond what its old text carried survive unless the draft
+// replaces them, so editing a #tag or mention out of the text drops it.
+func (d Draft) meta(kept *models.PlayerNote) (tags []string, links []int64) {
+	tags, links = Parse(d.Info)
+	explicitTags, explicitLinks := d.Tags, d.Links
+	if kept != nil {
+		oldTags, oldLinks := Parse(kept.Info)
+		if explicitTags == nil {
+			explicitTags = without(kept.Tags, oldTags)
+		}
+		if explicitLinks == nil {
+			explicitLinks = without(kept.LinkedPlayerIds, oldLinks)
+		}
+	}
+	return NormalizeTags(append(tags, explicitTags...)), normalizeLinks(append(links, explicitLinks...))
+}
+
+func without[T comparable](all, drop []T) []T {
+	out := []T{}
+	for _, v := range all {
+		if !slices.Contains(drop, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// day resolves the draft's cycle day, defaulting to the current one. A game
+// without a cycle yet records no day.
+func (d Draft) day(ctx context.Context, q *models.Queries) pgtype.Int4 {
+	if d.Day != nil {
+		return pgtype.Int4{Int32: *d.Day, Valid: true}
+	}
+	cycle, err := q.GetCycle(ctx)
+	if err != nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: cycle.Day, Valid: true}
+}
+
 func (s *Service) List(ctx context.Context, auth Authorization, playerID int64) ([]models.PlayerNote, error) {
 	if err := authorize(auth); err != nil {
 		return nil, err
@@ -50,13 +175,18 @@ func (s *Service) List(ctx context.Context, auth Authorization, playerID int64)
 	return models.New(s.pool).ListPlayerNote(ctx, playerID)
 }
 
-// Add appends a note after the highest existing position. Positions are not
-// reused after deletion so references shown to admins remain stable.
+// Add appends a note with no metadata beyond what its text carries.
 func (s *Service) Add(ctx context.Context, auth Authorization, playerID int64, info string) (*models.PlayerNote, error) {
+	return s.AddNote(ctx, auth, playerID, Draft{Info: info})
+}
+
+// AddNote appends a note after the highest existing position. Positions are
+// not reused after deletion so references shown to admins remain stable.
+func (s *Service) AddNote(ctx context.Context, auth Authorization, playerID int64, draft Draft) (*models.PlayerNote, error) {
 	if err := authorize(auth); err != nil {
 		return nil, err
 	}
-	if info == "" {
+	if draft.Info == "" {
 		return nil, errors.New("note cannot be empty")
 	}
 	tx, err := s.pool.Begin(ctx)
@@ -78,8 +208,10 @@ func (s *Service) Add(ctx context.Context, auth Authorization, playerID int64, i
 			next = note.Position
 		}
 	}
+	tags, links := draft.meta(nil)
 	note, err := q.CreatePlayerNote(ctx, models.CreatePlayerNoteParams{
-		PlayerID: playerID, Position: next + 1, Info: info,
+		PlayerID: playerID, Position: next + 1, Info: draft.Info,
+		Tags: tags, Author: draft.Author, CycleDay: draft.day(ctx, q), LinkedPlayerIds: links,
 	})
 	if err != nil {
 		return nil, err
@@ -90,20 +222,56 @@ func (s *Service) Add(ctx context.Context, auth Authorization, playerID int64, i
 	return &note, nil
 }
 
-// Update changes an existing note. It deliberately does not create a missing
-// position because Discord's /inv notes update command is update-only.
+// CreateNote inserts a note at position without touching notes already there,
+// for callers that choose positions themselves but must never overwrite.
+func (s *Service) CreateNote(ctx context.Context, auth Authorization, playerID int64, position int, draft Draft) (*models.PlayerNote, error) {
+	if err := authorize(auth); err != nil {
+		return nil, err
+	}
+	if position < 1 {
+		return nil, errors.New("position must be positive")
+	}
+	if draft.Info == "" {
+		return nil, errors.New("note cannot be empty")
+	}
+	q := models.New(s.pool)
+	tags, links := draft.meta(nil)
+	note, err := q.CreatePlayerNote(ctx, models.CreatePlayerNoteParams{
+		PlayerID: playerID, Position: int32(position), Info: draft.Info,
+		Tags: tags, Author: draft.Author, CycleDay: draft.day(ctx, q), LinkedPlayerIds: links,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// Update replaces a note's text, keeping the tags and links added beside it.
 func (s *Service) Update(ctx context.Context, auth Authorization, playerID int64, position int, info string) (*models.PlayerNote, error) {
+	return s.UpdateNote(ctx, auth, playerID, position, Draft{Info: info})
+}
+
+// UpdateNote changes an existing note. It deliberately does not create a
+// missing position because Discord's /inv notes update command is
+// update-only. Author and day stay as first written.
+func (s *Service) UpdateNote(ctx context.Context, auth Authorization, playerID int64, position int, draft Draft) (*models.PlayerNote, error) {
 	if err := authorize(auth); err != nil {
 		return nil, err
 	}
 	if position < 1 {
 		return nil, errors.New("position must be positive")
 	}
-	if info == "" {
+	if draft.Info == "" {
 		return nil, errors.New("note cannot be empty")
 	}
-	note, err := models.New(s.pool).UpdatePlayerNoteByPosition(ctx, models.UpdatePlayerNoteByPositionParams{
-		PlayerID: playerID, Position: int32(position), Info: info,
+	q := models.New(s.pool)
+	kept, err := q.GetPlayerNoteByPosition(ctx, models.GetPlayerNoteByPositionParams{PlayerID: playerID, Position: int32(position)})
+	if err != nil {
+		return nil, err
+	}
+	tags, links := draft.meta(&kept)
+	note, err := q.UpdatePlayerNoteByPosition(ctx, models.UpdatePlayerNoteByPositionParams{
+		PlayerID: playerID, Position: int32(position), Info: draft.Info, Tags: tags, LinkedPlayerIds: links,
 	})
 	if err != nil {
 		return nil, err
@@ -111,17 +279,22 @@ func (s *Service) Update(ctx context.Context, auth Authorization, playerID int64
 	return &note, nil
 }
 
-// Save updates a requested position or creates it when absent. The pair is a
-// single transaction so concurrent web saves cannot observe a half-completed
-// upsert.
+// Save writes a note's text at position with no further metadata.
 func (s *Service) Save(ctx context.Context, auth Authorization, playerID int64, position int, info string) (*models.PlayerNote, error) {
+	return s.SaveNote(ctx, auth, playerID, position, Draft{Info: info})
+}
+
+// SaveNote updates a requested position or creates it when absent. The pair
+// is a single transaction so concurrent web saves cannot observe a
+// half-completed upsert.
+func (s *Service) SaveNote(ctx context.Context, auth Authorization, playerID int64, position int, draft Draft) (*models.PlayerNote, error) {
 	if err := authorize(auth); err != nil {
 		return nil, err
 	}
 	if position < 1 {
 		return nil, errors.New("position must be positive")
 	}
-	if info == "" {
+	if draft.Info == "" {
 		return nil, errors.New("note cannot be empty")
 	}
 	tx, err := s.pool.Begin(ctx)
@@ -133,12 +306,19 @@ func (s *Service) Save(ctx context.Context, auth Authorization, playerID int64,
 		return nil, fmt.Errorf("lock player notes: %w", err)
 	}
 	q := models.New(tx)
-	note, err := q.UpdatePlayerNoteByPosition(ctx, models.UpdatePlayerNoteByPositionParams{
-		PlayerID: playerID, Position: int32(position), Info: info,
-	})
-	if errors.Is(err, pgx.ErrNoRows) {
+	var note models.PlayerNote
+	kept, err := q.GetPlayerNoteByPosition(ctx, models.GetPlayerNoteByPositionParams{PlayerID: playerID, Position: int32(position)})
+	switch {
+	case err == nil:
+		tags, links := draft.meta(&kept)
+		note, err = q.UpdatePlayerNoteByPosition(ctx, models.UpdatePlayerNoteByPositionParams{
+			PlayerID: playerID, Position: int32(position), Info: draft.Info, Tags: tags, LinkedPlayerIds: links,
+		})
+	case errors.Is(err, pgx.ErrNoRows):
+		tags, links := draft.meta(nil)
 		note, err = q.CreatePlayerNote(ctx, models.CreatePlayerNoteParams{
-			PlayerID: playerID, Position: int32(position), Info: info,
+			PlayerID: playerID, Position: int32(position), Info: draft.Info,
+			Tags: tags, Author: draft.Author, CycleDay: draft.day(ctx, q), LinkedPlayerIds: links,
 		})
 	}
 	if err != nil {
@@ -183,3 +363,47 @@ func (s *Service) DeleteByID(ctx context.Context, auth Authorization, playerID i
 		PlayerID: playerID, NoteID: noteID,
 	})
 }
+
+// Search finds notes across every player, newest cycle day first.
+func (s *Service) Search(ctx context.Context, auth Authorization, search Search) ([]models.PlayerNote, error) {
+	if err := authorize(auth); err != nil {
+		return nil, err
+	}
+	arg := models.SearchPlayerNotesParams{Tags: NormalizeTags(search.Tags), RowLimit: search.Limit}
+	if query := strings.TrimSpace(search.Query); query != "" {
+		arg.Query = pgtype.Text{String: query, Valid: true}
+	}
+	if search.Day != nil {
+		arg.CycleDay = pgtype.Int4{Int32: *search.Day, Valid: true}
+	}
+	if search.PlayerID != 0 {
+		arg.PlayerID = pgtype.Int8{Int64: search.PlayerID, Valid: true}
+	}
+	if arg.RowLimit <= 0 {
+		arg.RowLimit = DefaultSearchLimit
+	}
+	return models.New(s.pool).SearchPlayerNotes(ctx, arg)
+}
+
+// Describe renders a note's metadata on one line for Discord, e.g.
+// "Day 2 · #protect · by alex · mentions <@1>"; "" when it has none.
+func Describe(note models.PlayerNote) string {
+	var parts []string
+	if note.CycleDay.Valid {
+		parts = append(parts, fmt.Sprintf("Day %d", note.CycleDay.Int32))
+	}
+	if len(note.Tags) > 0 {
+		parts = append(parts, "#"+strings.Join(note.Tags, " #"))
+	}
+	if note.Author != "" {
+		parts = append(parts, "by "+note.Author)
+	}
+	if len(note.LinkedPlayerIds) > 0 {
+		mentions := make([]string, len(note.LinkedPlayerIds))
+		for i, id := range note.LinkedPlayerIds {
+			mentions[i] = fmt.Sprintf("<@%d>", id)
+		}
+		parts = append(parts, "mentions "+strings.Join(mentions, " "))
+	}
+	return strings.Join(parts, " · ")
+}
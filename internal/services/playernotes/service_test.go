@@ -192,3 +192,79 @@ func TestServiceSaveUsesPlayerNotesAdvisoryLock(t *testing.T) {
 	_, err = playernotes.New(pool).Save(ctx, playernotes.WebAdminAuthorization(), player.ID, 1, "blocked")
 	require.ErrorIs(t, err, context.DeadlineExceeded)
 }
+
+func TestParsePicksUpTagsAndMentions(t *testing.T) {
+	tags, links := playernotes.Parse("Used #Protect on <@!202> and <@101>, #suspicious #protect")
+	require.Equal(t, []string{"protect", "suspicious"}, tags)
+	require.Equal(t, []int64{101, 202}, links)
+
+	tags, links = playernotes.Parse("plain note")
+	require.NotNil(t, tags)
+	require.Empty(t, tags)
+	require.Empty(t, links)
+	require.Equal(t, []string{"ability-used", "sus"}, playernotes.NormalizeTags([]string{" #SUS", "ability-used", "sus", ""}))
+}
+
+func TestServiceSearchFiltersAcrossPlayers(t *testing.T) {
+	pool := testutil.NewTestPool(t)
+	testutil.TruncateAll(t, pool)
+	q := models.New(pool)
+	ctx := context.Background()
+	for _, id := range []int64{100000000000000006, 100000000000000007} {
+		_, err := q.CreatePlayer(ctx, models.CreatePlayerParams{ID: id, Alive: true, Alignment: models.AlignmentGOOD})
+		require.NoError(t, err)
+	}
+
+	svc := playernotes.New(pool)
+	admin := playernotes.DiscordAdminAuthorization()
+	day2, day3 := int32(2), int32(3)
+	_, err := svc.AddNote(ctx, admin, 100000000000000006, playernotes.Draft{Info: "Protected <@100000000000000007> tonight", Tags: []string{"ability-used"}, Author: "alex", Day: &day2})
+	require.NoError(t, err)
+	_, err = svc.AddNote(ctx, admin, 100000000000000007, playernotes.Draft{Info: "Claims to protect people #suspicious", Day: &day3})
+	require.NoError(t, err)
+
+	notes, err := svc.Search(ctx, admin, playernotes.Search{Query: "protect"})
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+	require.Equal(t, int32(3), notes[0].CycleDay.Int32, "newest day first")
+
+	notes, err = svc.Search(ctx, admin, playernotes.Search{Query: "protect", Day: &day2})
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	require.Equal(t, "alex", notes[0].Author)
+	require.Equal(t, []string{"ability-used"}, notes[0].Tags)
+
+	notes, err = svc.Search(ctx, admin, playernotes.Search{Tags: []string{"#Suspicious"}})
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	require.Equal(t, int64(100000000000000007), notes[0].PlayerID)
+
+	// A player matches their own notes and the notes mentioning them.
+	notes, err = svc.Search(ctx, admin, playernotes.Search{PlayerID: 100000000000000007})
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+
+	updated, err := svc.Update(ctx, admin, 100000000000000006, 1, "Protected someone tonight")
+	require.NoError(t, err)
+	require.Equal(t, []string{"ability-used"}, updated.Tags, "update keeps the tags")
+	require.Empty(t, updated.LinkedPlayerIds, "a mention edited out of the text is unlinked")
+
+	updated, err = svc.Update(ctx, admin, 100000000000000006, 1, "Protected <@100000000000000006> #watch")
+	require.NoError(t, err)
+	require.Equal(t, []string{"ability-used", "watch"}, updated.Tags)
+	require.Equal(t, []int64{100000000000000006}, updated.LinkedPlayerIds)
+
+	updated, err = svc.Update(ctx, admin, 100000000000000006, 1, "Protected nobody")
+	require.NoError(t, err)
+	require.Equal(t, []string{"ability-used"}, updated.Tags, "a #tag edited out of the text is dropped; explicit tags stay")
+	require.Empty(t, updated.LinkedPlayerIds)
+
+	// CreateNote adds beside an existing position instead of overwriting it.
+	_, err = svc.CreateNote(ctx, playernotes.WebAdminAuthorization(), 100000000000000006, 1, playernotes.Draft{Info: "second at one"})
+	require.NoError(t, err)
+	notes, err = svc.List(ctx, admin, 100000000000000006)
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+	infos := []string{notes[0].Info, notes[1].Info}
+	require.ElementsMatch(t, []string{"Protected nobody", "second at one"}, infos)
+}
@@ -0,0 +1,500 @@
+// Package poll implements host-defined polls for the Betrayal bot.
+// A poll asks one question over either player options or free-text options,
+// restricts who may vote (everyone, the living, or the living members of a
+// role or alignment; dead players never vote in role and alignment polls), and
+// is tallied either by plurality or by ranked-choice instant runoff. The ken
+// /poll handlers and the button component handler stay thin; eligibility and
+// tallying are pure functions so they can be unit-tested without a database.
+package poll
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+// OptionKind describes what a poll's options refer to.
+type OptionKind string
+
+const (
+	OptionKindPlayer OptionKind = "player"
+	OptionKindText   OptionKind = "text"
+)
+
+// Eligibility restricts which players may cast a ballot.
+type Eligibility string
+
+const (
+	EligibleAll   Eligibility = "all"
+	EligibleAlive Eligibility = "alive"
+	// EligibleRole and EligibleAlignment admit only living players of the
+	// role or alignment, as the ballot embed states.
+	EligibleRole      Eligibility = "role"
+	EligibleAlignment Eligibility = "alignment"
+)
+
+// MaxOptions is the most options a poll may carry. Discord allows five rows
+// of five buttons; one row is reserved for the ballot controls.
+const MaxOptions = 20
+
+var (
+	ErrNotAPlayer     = errors.New("not a registered player")
+	ErrPollNotFound   = errors.New("poll not found")
+	ErrPollClosed     = errors.New("poll is closed")
+	ErrNotEligible    = errors.New("not eligible to vote in this poll")
+	ErrUnknownOption  = errors.New("option does not belong to this poll")
+	ErrAlreadyRanked  = errors.New("option already ranked on this ballot")
+	ErrInvalidOptions = errors.New("poll needs between 2 and 20 options")
+)
+
+// Poll is a persisted poll together with its options in display order.
+type Poll struct {
+	models.Poll
+	Options []models.PollOption
+}
+
+// Open reports whether the poll still accepts ballots at now.
+func (p Poll) Open(now time.Time) bool {
+	if p.ClosedAt.Valid {
+		return false
+	}
+	return !p.ClosesAt.Valid || now.Before(p.ClosesAt.Time)
+}
+
+// Option returns the option with id, if it belongs to the poll.
+func (p Poll) Option(id int64) (models.PollOption, bool) {
+	for _, option := range p.Options {
+		if option.ID == id {
+			return option, true
+		}
+	}
+	return models.PollOption{}, false
+}
+
+// OptionInput is one option supplied when creating a poll. PlayerID is zero
+// for free-text options.
+type OptionInput struct {
+	Label    string
+	PlayerID int64
+}
+
+// CreateParams describes a new poll.
+type CreateParams struct {
+	Question         string
+	Kind             OptionKind
+	Options          []OptionInput
+	Eligibility      Eligibility
+	EligibilityValue string
+	Ranked           bool
+	// ClosesAt is optional; the zero value leaves the poll open until closed
+	// by a host.
+	ClosesAt  time.Time
+	CreatedBy string
+}
+
+// Service is the DB-backed poll engine used by /poll and the web ops API.
+type Service struct {
+	pool *pgxpool.Pool
+}
+
+// New returns a poll Service backed by pool.
+func New(pool *pgxpool.Pool) *Service {
+	return &Service{pool: pool}
+}
+
+// Create validates and persists a poll and its options in one transaction.
+func (s *Service) Create(ctx context.Context, params CreateParams) (Poll, error) {
+	if err := validateCreate(params); err != nil {
+		return Poll{}, err
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Poll{}, err
+	}
+	defer tx.Rollback(ctx)
+	q := models.New(tx)
+
+	var eligibilityValue pgtype.Text
+	if params.EligibilityValue != "" {
+		eligibilityValue = pgtype.Text{String: params.EligibilityValue, Valid: true}
+	}
+	var closesAt pgtype.Timestamptz
+	if !params.ClosesAt.IsZero() {
+		closesAt = pgtype.Timestamptz{Time: params.ClosesAt, Valid: true}
+	}
+	created, err := q.CreatePoll(ctx, models.CreatePollParams{
+		Question:         strings.TrimSpace(params.Question),
+		OptionKind:       string(params.Kind),
+		Eligibility:      string(params.Eligibility),
+		EligibilityValue: eligibilityValue,
+		Ranked:           params.Ranked,
+		ClosesAt:         closesAt,
+		CreatedBy:        params.CreatedBy,
+	})
+	if err != nil {
+		return Poll{}, fmt.Errorf("create poll: %w", err)
+	}
+	result := Poll{Poll: created}
+	for i, input := range params.Options {
+		var playerID pgtype.Int8
+		if params.Kind == OptionKindPlayer {
+			playerID = pgtype.Int8{Int64: input.PlayerID, Valid: true}
+		}
+		option, err := q.CreatePollOption(ctx, models.CreatePollOptionParams{
+			PollID:   created.ID,
+			Position: int32(i + 1),
+			Label:    strings.TrimSpace(input.Label),
+			PlayerID: playerID,
+		})
+		if err != nil {
+			return Poll{}, fmt.Errorf("create poll option %q: %w", input.Label, err)
+		}
+		result.Options = append(result.Options, option)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Poll{}, err
+	}
+	return result, nil
+}
+
+func validateCreate(params CreateParams) error {
+	if strings.TrimSpace(params.Question) == "" {
+		return errors.New("poll question is required")
+	}
+	if len(params.Options) < 2 || len(params.Options) > MaxOptions {
+		return ErrInvalidOptions
+	}
+	switch params.Kind {
+	case OptionKindPlayer, OptionKindText:
+	default:
+		return fmt.Errorf("unknown option kind %q", params.Kind)
+	}
+	switch params.Eligibility {
+	case EligibleAll, EligibleAlive:
+	case EligibleRole, EligibleAlignment:
+		if strings.TrimSpace(params.EligibilityValue) == "" {
+			return fmt.Errorf("eligibility %q requires a value", params.Eligibility)
+		}
+	default:
+		return fmt.Errorf("unknown eligibility %q", params.Eligibility)
+	}
+	seen := make(map[string]bool, len(params.Options))
+	for _, option := range params.Options {
+		label := strings.ToLower(strings.TrimSpace(option.Label))
+		if label == "" {
+			return errors.New("poll options cannot be blank")
+		}
+		if seen[label] {
+			return fmt.Errorf("duplicate poll option %q", option.Label)
+		}
+		seen[label] = true
+	}
+	return nil
+}
+
+// Get loads a poll and its options.
+func (s *Service) Get(ctx context.Context, id int64) (Poll, error) {
+	return loadPoll(ctx, models.New(s.pool), id)
+}
+
+func loadPoll(ctx context.Context, q *models.Queries, id int64) (Poll, error) {
+	row, err := q.GetPoll(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Poll{}, ErrPollNotFound
+		}
+		return Poll{}, err
+	}
+	options, err := q.ListPollOptions(ctx, id)
+	if err != nil {
+		return Poll{}, err
+	}
+	return Poll{Poll: row, Options: options}, nil
+}
+
+// List returns every poll, newest first.
+func (s *Service) List(ctx context.Context) ([]Poll, error) {
+	q := models.New(s.pool)
+	rows, err := q.ListPolls(ctx)
+	if err != nil {
+		return nil, err
+	}
+	polls := make([]Poll, 0, len(rows))
+	for _, row := range rows {
+		options, err := q.ListPollOptions(ctx, row.ID)
+		if err != nil {
+			return nil, err
+		}
+		polls = append(polls, Poll{Poll: row, Options: options})
+	}
+	return polls, nil
+}
+
+// AttachMessage records the Discord message carrying the poll's buttons.
+func (s *Service) AttachMessage(ctx context.Context, id int64, channelID, messageID string) error {
+	return models.New(s.pool).SetPollMessage(ctx, models.SetPollMessageParams{
+		ID:        id,
+		ChannelID: pgtype.Text{String: channelID, Valid: true},
+		MessageID: pgtype.Text{String: messageID, Valid: true},
+	})
+}
+
+// Close stops a poll from accepting further ballots.
+func (s *Service) Close(ctx context.Context, id int64) (Poll, error) {
+	q := models.New(s.pool)
+	if _, err := q.ClosePoll(ctx, id); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return Poll{}, err
+	}
+	return loadPoll(ctx, q, id)
+}
+
+// Cast records voterID's choice of optionID. Single-choice polls replace the
+// voter's previous ballot; ranked polls append the option as the voter's next
+// preference. The voter's ballot, in rank order, is returned.
+func (s *Service) Cast(ctx context.Context, pollID, voterID, optionID int64, now time.Time) ([]models.PollOption, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	q := models.New(tx)
+
+	poll, err := loadPoll(ctx, q, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if !poll.Open(now) {
+		return nil, ErrPollClosed
+	}
+	if _, ok := poll.Option(optionID); !ok {
+		return nil, ErrUnknownOption
+	}
+	if err := checkEligible(ctx, q, poll, voterID); err != nil {
+		return nil, err
+	}
+
+	// Two clicks from the same voter arrive concurrently; serialize them so
+	// the next rank is computed from the committed ballot.
+	if _, err := tx.Exec(ctx, "select pg_advisory_xact_lock($1)", voterID); err != nil {
+		return nil, fmt.Errorf("lock poll ballot: %w", err)
+	}
+	key := models.ListVoterPollBallotParams{PollID: pollID, VoterID: voterID}
+	existing, err := q.ListVoterPollBallot(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	rank := int32(1)
+	if poll.Ranked {
+		for _, entry := range existing {
+			if entry.OptionID == optionID {
+				return nil, ErrAlreadyRanked
+			}
+		}
+		rank = int32(len(existing) + 1)
+	} else if err := q.DeleteVoterPollBallot(ctx, models.DeleteVoterPollBallotParams(key)); err != nil {
+		return nil, err
+	}
+	if err := q.InsertPollBallot(ctx, models.InsertPollBallotParams{
+		PollID:   pollID,
+		VoterID:  voterID,
+		OptionID: optionID,
+		Rank:     rank,
+	}); err != nil {
+		return nil, err
+	}
+	ballot, err := voterBallot(ctx, q, poll, voterID)
+	if err != nil {
+		return nil, err
+	}
+	return ballot, tx.Commit(ctx)
+}
+
+// ClearBallot removes voterID's ballot from an open poll.
+func (s *Service) ClearBallot(ctx context.Context, pollID, voterID int64, now time.Time) error {
+	q := models.New(s.pool)
+	poll, err := loadPoll(ctx, q, pollID)
+	if err != nil {
+		return err
+	}
+	if !poll.Open(now) {
+		return ErrPollClosed
+	}
+	return q.DeleteVoterPollBallot(ctx, models.DeleteVoterPollBallotParams{PollID: pollID, VoterID: voterID})
+}
+
+func voterBallot(ctx context.Context, q *models.Queries, poll Poll, voterID int64) ([]models.PollOption, error) {
+	entries, err := q.ListVoterPollBallot(ctx, models.ListVoterPollBallotParams{PollID: poll.ID, VoterID: voterID})
+	if err != nil {
+		return nil, err
+	}
+	ballot := make([]models.PollOption, 0, len(entries))
+	for _, entry := range entries {
+		if option, ok := poll.Option(entry.OptionID); ok {
+			ballot = append(ballot, option)
+		}
+	}
+	return ballot, nil
+}
+
+func checkEligible(ctx context.Context, q *models.Queries, poll Poll, voterID int64) error {
+	voter, err := q.GetPlayer(ctx, voterID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotAPlayer
+		}
+		return err
+	}
+	roleName := ""
+	if voter.RoleID.Valid {
+		role, err := q.GetRole(ctx, voter.RoleID.Int32)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		roleName = role.Name
+	}
+	if !Eligible(poll.Poll, voter, roleName) {
+		return ErrNotEligible
+	}
+	return nil
+}
+
+// Eligible reports whether voter (whose role is roleName) may vote in poll.
+// Role and alignment polls are limited to living players.
+func Eligible(poll models.Poll, voter models.Player, roleName string) bool {
+	switch Eligibility(poll.Eligibility) {
+	case EligibleAll:
+		return true
+	case EligibleAlive:
+		return voter.Alive
+	case EligibleRole:
+		return voter.Alive && strings.EqualFold(roleName, poll.EligibilityValue.String)
+	case EligibleAlignment:
+		return voter.Alive && strings.EqualFold(string(voter.Alignment), poll.EligibilityValue.String)
+	}
+	return false
+}
+
+// OptionTally is the vote count for one option in one counting round.
+type OptionTally struct {
+	OptionID int64
+	Label    string
+	Votes    int
+}
+
+// Results is the tally of a poll. For single-choice polls Rounds holds one
+// round; ranked polls hold one round per instant-runoff elimination. Winners
+// holds more than one option only when the final round is tied.
+type Results struct {
+	Poll    Poll
+	Voters  int
+	Rounds  [][]OptionTally
+	Winners []models.PollOption
+}
+
+// Final returns the last counting round.
+func (r Results) Final() []OptionTally {
+	if len(r.Rounds) == 0 {
+		return nil
+	}
+	return r.Rounds[len(r.Rounds)-1]
+}
+
+// Results loads and tallies a poll.
+func (s *Service) Results(ctx context.Context, id int64) (Results, error) {
+	q := models.New(s.pool)
+	poll, err := loadPoll(ctx, q, id)
+	if err != nil {
+		return Results{}, err
+	}
+	ballots, err := q.ListPollBallots(ctx, id)
+	if err != nil {
+		return Results{}, err
+	}
+	return Tally(poll, ballots), nil
+}
+
+// Tally counts ballots for poll (pure, unit-testable). Ballot rows may arrive
+// in any order; they are grouped per voter and ordered by rank.
+func Tally(poll Poll, ballots []models.PollBallot) Results {
+	byVoter := make(map[int64][]models.PollBallot)
+	for _, ballot := range ballots {
+		if _, ok := poll.Option(ballot.OptionID); !ok {
+			continue
+		}
+		byVoter[ballot.VoterID] = append(byVoter[ballot.VoterID], ballot)
+	}
+	preferences := make([][]int64, 0, len(byVoter))
+	for _, entries := range byVoter {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Rank < entries[j].Rank })
+		prefs := make([]int64, 0, len(entries))
+		for _, entry := range entries {
+			prefs = append(prefs, entry.OptionID)
+		}
+		preferences = append(preferences, prefs)
+	}
+
+	result := Results{Poll: poll, Voters: len(preferences)}
+	active := make(map[int64]bool, len(poll.Options))
+	for _, option := range poll.Options {
+		active[option.ID] = true
+	}
+	for {
+		round := countRound(poll, preferences, active)
+		result.Rounds = append(result.Rounds, round)
+		if len(round) == 0 {
+			return result
+		}
+		counted := 0
+		for _, tally := range round {
+			counted += tally.Votes
+		}
+		top, low := round[0].Votes, round[len(round)-1].Votes
+		if counted == 0 {
+			return result
+		}
+		if !poll.Ranked || top*2 > counted || top == low {
+			for _, tally := range round {
+				if tally.Votes == top {
+					option, _ := poll.Option(tally.OptionID)
+					result.Winners = append(result.Winners, option)
+				}
+			}
+			return result
+		}
+		for _, tally := range round {
+			if tally.Votes == low {
+				delete(active, tally.OptionID)
+			}
+		}
+	}
+}
+
+// countRound counts each ballot's highest-ranked active option. Tallies are
+// sorted by votes, then by option position.
+func countRound(poll Poll, preferences [][]int64, active map[int64]bool) []OptionTally {
+	counts := make(map[int64]int, len(active))
+	for _, prefs := range preferences {
+		for _, optionID := range prefs {
+			if active[optionID] {
+				counts[optionID]++
+				break
+			}
+		}
+	}
+	round := make([]OptionTally, 0, len(active))
+	for _, option := range poll.Options {
+		if active[option.ID] {
+			round = append(round, OptionTally{OptionID: option.ID, Label: option.Label, Votes: counts[option.ID]})
+		}
+	}
+	sort.SliceStable(round, func(i, j int) bool { return round[i].Votes > round[j].Votes })
+	return round
+}
@@ -0,0 +1,166 @@
+package poll
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+func testPoll(ranked bool, labels ...string) Poll {
+	p := Poll{Poll: models.Poll{ID: 1, Ranked: ranked}}
+	for i, label := range labels {
+		p.Options = append(p.Options, models.PollOption{ID: int64(i + 1), PollID: 1, Position: int32(i + 1), Label: label})
+	}
+	return p
+}
+
+func ballot(voter int64, options ...int64) []models.PollBallot {
+	rows := make([]models.PollBallot, 0, len(options))
+	for i, option := range options {
+		rows = append(rows, models.PollBallot{PollID: 1, VoterID: voter, OptionID: option, Rank: int32(i + 1)})
+	}
+	return rows
+}
+
+func winnerLabels(r Results) []string {
+	labels := make([]string, 0, len(r.Winners))
+	for _, w := range r.Winners {
+		labels = append(labels, w.Label)
+	}
+	return labels
+}
+
+func TestEligible(t *testing.T) {
+	alive := models.Player{ID: 1, Alive: true, Alignment: models.AlignmentEVIL}
+	dead := models.Player{ID: 2, Alive: false, Alignment: models.AlignmentEVIL}
+	value := func(v string) pgtype.Text { return pgtype.Text{String: v, Valid: true} }
+
+	cases := []struct {
+		name  string
+		poll  models.Poll
+		voter models.Player
+		role  string
+		want  bool
+	}{
+		{"all includes dead", models.Poll{Eligibility: "all"}, dead, "", true},
+		{"alive excludes dead", models.Poll{Eligibility: "alive"}, dead, "", false},
+		{"alive includes living", models.Poll{Eligibility: "alive"}, alive, "", true},
+		{"role matches case-insensitively", models.Poll{Eligibility: "role", EligibilityValue: value("Wizard")}, alive, "wizard", true},
+		{"role mismatch", models.Poll{Eligibility: "role", EligibilityValue: value("Wizard")}, alive, "Knight", false},
+		{"role excludes dead", models.Poll{Eligibility: "role", EligibilityValue: value("Wizard")}, dead, "Wizard", false},
+		{"alignment matches", models.Poll{Eligibility: "alignment", EligibilityValue: value("evil")}, alive, "", true},
+		{"alignment mismatch", models.Poll{Eligibility: "alignment", EligibilityValue: value("GOOD")}, alive, "", false},
+		{"unknown eligibility", models.Poll{Eligibility: "nobody"}, alive, "", false},
+	}
+	for _, tc := range cases {
+		if got := Eligible(tc.poll, tc.voter, tc.role); got != tc.want {
+			t.Errorf("%s: Eligible = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestOpenHonoursCloseTimeAndManualClose(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	p := testPoll(false, "a", "b")
+	if !p.Open(now) {
+		t.Fatal("poll without close time should be open")
+	}
+	p.ClosesAt = pgtype.Timestamptz{Time: now.Add(time.Minute), Valid: true}
+	if !p.Open(now) {
+		t.Fatal("poll before close time should be open")
+	}
+	if p.Open(now.Add(time.Minute)) {
+		t.Fatal("poll at close time should be closed")
+	}
+	p.ClosesAt = pgtype.Timestamptz{}
+	p.ClosedAt = pgtype.Timestamptz{Time: now, Valid: true}
+	if p.Open(now) {
+		t.Fatal("manually closed poll should be closed")
+	}
+}
+
+func TestTallySingleChoicePlurality(t *testing.T) {
+	p := testPoll(false, "Alice", "Bob", "Cara")
+	var rows []models.PollBallot
+	rows = append(rows, ballot(10, 2)...)
+	rows = append(rows, ballot(11, 2)...)
+	rows = append(rows, ballot(12, 1)...)
+
+	r := Tally(p, rows)
+	if r.Voters != 3 || len(r.Rounds) != 1 {
+		t.Fatalf("voters=%d rounds=%d, want 3 voters in 1 round", r.Voters, len(r.Rounds))
+	}
+	if got := winnerLabels(r); len(got) != 1 || got[0] != "Bob" {
+		t.Fatalf("winners = %v, want [Bob]", got)
+	}
+	final := r.Final()
+	if final[0].Label != "Bob" || final[0].Votes != 2 || final[2].Label != "Cara" || final[2].Votes != 0 {
+		t.Fatalf("final round = %+v", final)
+	}
+}
+
+func TestTallyRankedRunsInstantRunoff(t *testing.T) {
+	p := testPoll(true, "Alice", "Bob", "Cara")
+	var rows []models.PollBallot
+	// First preferences: Alice 2, Bob 2, Cara 1. Nobody has a majority, so
+	// Cara is eliminated and her voter's second preference elects Bob.
+	rows = append(rows, ballot(10, 1, 2)...)
+	rows = append(rows, ballot(11, 1)...)
+	rows = append(rows, ballot(12, 2, 1)...)
+	rows = append(rows, ballot(13, 2)...)
+	rows = append(rows, ballot(14, 3, 2)...)
+
+	r := Tally(p, rows)
+	if len(r.Rounds) != 2 {
+		t.Fatalf("rounds = %d, want 2: %+v", len(r.Rounds), r.Rounds)
+	}
+	if got := winnerLabels(r); len(got) != 1 || got[0] != "Bob" {
+		t.Fatalf("winners = %v, want [Bob]", got)
+	}
+	if final := r.Final(); final[0].Votes != 3 || len(final) != 2 {
+		t.Fatalf("final round = %+v, want Bob with 3 of 2 remaining options", final)
+	}
+}
+
+func TestTallyReportsTiesAndEmptyPolls(t *testing.T) {
+	p := testPoll(true, "Alice", "Bob")
+	if r := Tally(p, nil); len(r.Winners) != 0 || r.Voters != 0 {
+		t.Fatalf("empty poll = %+v, want no winners", r)
+	}
+	var rows []models.PollBallot
+	rows = append(rows, ballot(10, 1)...)
+	rows = append(rows, ballot(11, 2)...)
+	r := Tally(p, rows)
+	if got := winnerLabels(r); len(got) != 2 {
+		t.Fatalf("winners = %v, want a two-way tie", got)
+	}
+}
+
+func TestValidateCreate(t *testing.T) {
+	base := CreateParams{
+		Question:    "Who is lying?",
+		Kind:        OptionKindText,
+		Options:     []OptionInput{{Label: "Yes"}, {Label: "No"}},
+		Eligibility: EligibleAlive,
+	}
+	if err := validateCreate(base); err != nil {
+		t.Fatalf("valid poll rejected: %v", err)
+	}
+	tooFew := base
+	tooFew.Options = base.Options[:1]
+	if err := validateCreate(tooFew); err != ErrInvalidOptions {
+		t.Fatalf("one option error = %v, want %v", err, ErrInvalidOptions)
+	}
+	dup := base
+	dup.Options = []OptionInput{{Label: "Yes"}, {Label: " yes "}}
+	if err := validateCreate(dup); err == nil {
+		t.Fatal("duplicate options accepted")
+	}
+	role := base
+	role.Eligibility = EligibleRole
+	if err := validateCreate(role); err == nil {
+		t.Fatal("role eligibility without a role accepted")
+	}
+}
@@ -0,0 +1,259 @@
+// Package provision creates players. All database work for a new player —
+// the player row, the role's abilities and perks and the role's creation-time
+// adjustments — runs in one transaction, so a failure never leaves a half
+// built inventory behind. Provision additionally opens a hidden confessional
+// in the configured category and pins the player's inventory there.
+package provision
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/inventory"
+	"github.com/mccune1224/betrayal/internal/services/lifeboard"
+	"github.com/mccune1224/betrayal/internal/util"
+)
+
+// Configurable defaults for new players, backed by the game_config table
+// (seeded by migration 000029). These constants are the fallback when a row is
+// missing or unparseable.
+const (
+	cfgDefaultCoins      = 200
+	cfgDefaultItemsLimit = 4
+	cfgDefaultLuck       = 0
+
+	configKeyDefaultCoins      = "default_coins"
+	configKeyDefaultItemsLimit = "default_items_limit"
+	configKeyDefaultLuck       = "default_luck"
+)
+
+// ConfessionalCategoryConfigKey names the game_config row holding the Discord
+// category new confessionals are created in.
+const ConfessionalCategoryConfigKey = "confessional_category"
+
+// DefaultConfessionalCategory is used when no category is configured.
+const DefaultConfessionalCategory = "confessionals"
+
+// ConfessionalAccess is what a player may do in their own confessional.
+const ConfessionalAccess = discordgo.PermissionViewChannel | discordgo.PermissionSendMessages | discordgo.PermissionReadMessageHistory
+
+var ErrRoleNotFound = errors.New("role not found")
+
+type Service struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Service {
+	return &Service{pool: pool}
+}
+
+// Created is a freshly inserted player and the role it was built from.
+type Created struct {
+	Player models.Player
+	Role   models.Role
+}
+
+// Provisioned is a created player with its confessional. Warnings lists the
+// cosmetic steps that failed after the player was committed, such as pinning
+// the inventory message.
+type Provisioned struct {
+	Created
+	ChannelID string
+	Warnings  []string
+}
+
+// CreatePlayer inserts playerID with the fuzzy-matched role, its abilities,
+// perks and role setup in one transaction. No confessional is created.
+func (s *Service) CreatePlayer(ctx context.Context, playerID int64, roleName string) (Created, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Created{}, err
+	}
+	defer tx.Rollback(ctx)
+	created, err := createPlayer(ctx, models.New(tx), playerID, roleName)
+	if err != nil {
+		return Created{}, err
+	}
+	return created, tx.Commit(ctx)
+}
+
+// Provision creates the player like CreatePlayer and, before committing, opens
+// a hidden confessional in guildID that only the player and the hosts can see.
+// The channel is deleted again if any database step fails. Once committed the
+// real inventory embed replaces the placeholder and is pinned.
+func (s *Service) Provision(ctx context.Context, sesh *discordgo.Session, guildID string, user *discordgo.User, roleName string) (Provisioned, error) {
+	playerID, err := util.Atoi64(user.ID)
+	if err != nil {
+		return Provisioned{}, fmt.Errorf("invalid Discord user %q: %w", user.ID, err)
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Provisioned{}, err
+	}
+	defer tx.Rollback(ctx)
+	q := models.New(tx)
+	created, err := createPlayer(ctx, q, playerID, roleName)
+	if err != nil {
+		return Provisioned{}, err
+	}
+
+	category := ConfessionalCategory(ctx, q)
+	// The channel helpers read the guild from an interaction; web callers have
+	// none, so one is synthesised.
+	e := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{GuildID: guildID}}
+	channel, err := discord.CreateChannelWithinCategory(sesh, e, category, ConfessionalName(user.Username), true)
+	if err != nil {
+		return Provisioned{}, fmt.Errorf("create confessional in category %q: %w", category, err)
+	}
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		if _, err := sesh.ChannelDelete(channel.ID); err != nil {
+			logger.Get().Error().Err(err).Str("channel_id", channel.ID).Msg("orphaned confessional not deleted")
+		}
+	}()
+	if err := sesh.ChannelPermissionSet(channel.ID, user.ID, discordgo.PermissionOverwriteTypeMember, ConfessionalAccess, 0); err != nil {
+		return Provisioned{}, fmt.Errorf("grant confessional access: %w", err)
+	}
+	pinMsg, err := sesh.ChannelMessageSendEmbed(channel.ID, &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("🏗 %s Inventory in creation 🏗 ", user.Username),
+	})
+	if err != nil {
+		return Provisioned{}, fmt.Errorf("send inventory message: %w", err)
+	}
+	channelID, _ := util.Atoi64(channel.ID)
+	pinMessageID, _ := util.Atoi64(pinMsg.ID)
+	if _, err := q.CreatePlayerConfessional(ctx, models.CreatePlayerConfessionalParams{
+		PlayerID:     playerID,
+		ChannelID:    channelID,
+		PinMessageID: pinMessageID,
+	}); err != nil {
+		return Provisioned{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Provisioned{}, err
+	}
+	committed = true
+
+	result := Provisioned{Created: created, ChannelID: channel.ID}
+	if err := inventory.NewManualInventoryHandler(created.Player, s.pool).UpdateInventoryMessage(sesh); err != nil {
+		logger.Get().Error().Err(err).Int64("player_id", playerID).Msg("inventory embed not rendered")
+		result.Warnings = append(result.Warnings, "The inventory message could not be rendered; run any /inv command to refresh it.")
+	}
+	if err := sesh.ChannelMessagePin(channel.ID, pinMsg.ID); err != nil {
+		logger.Get().Error().Err(err).Int64("player_id", playerID).Msg("inventory message not pinned")
+		result.Warnings = append(result.Warnings, "The inventory message could not be pinned.")
+	}
+	if !lifeboard.RefreshQuietly(ctx, s.pool, sesh) {
+		result.Warnings = append(result.Warnings, "The lifeboard could not be refreshed.")
+	}
+	return result, nil
+}
+
+func createPlayer(ctx context.Context, q *models.Queries, playerID int64, roleName string) (Created, error) {
+	role, err := q.GetRoleByFuzzy(ctx, roleName)
+	if err != nil {
+		return Created{}, fmt.Errorf("%w: %s", ErrRoleNotFound, roleName)
+	}
+	abilities, err := q.ListRoleAbilityForRole(ctx, role.ID)
+	if err != nil {
+		return Created{}, err
+	}
+	perks, err := q.ListRolePerkForRole(ctx, role.ID)
+	if err != nil {
+		return Created{}, err
+	}
+	setup, err := GetSetup(ctx, q, role.Name)
+	if err != nil {
+		return Created{}, fmt.Errorf("load role setup: %w", err)
+	}
+	coinBonus, err := util.Numeric(0.0)
+	if err != nil {
+		return Created{}, err
+	}
+	player, err := q.CreatePlayer(ctx, models.CreatePlayerParams{
+		ID:        playerID,
+		RoleID:    pgtype.Int4{Int32: role.ID, Valid: true},
+		Alive:     true,
+		Coins:     max(0, gameConfigInt(ctx, q, configKeyDefaultCoins, cfgDefaultCoins)+setup.BonusCoins),
+		CoinBonus: coinBonus,
+		Luck:      max(0, gameConfigInt(ctx, q, configKeyDefaultLuck, cfgDefaultLuck)+setup.BonusLuck),
+		ItemLimit: max(0, gameConfigInt(ctx, q, configKeyDefaultItemsLimit, cfgDefaultItemsLimit)+setup.ItemLimitDelta),
+		Alignment: role.Alignment,
+	})
+	if err != nil {
+		return Created{}, fmt.Errorf("create player: %w", err)
+	}
+	for _, ability := range abilities {
+		if _, err := q.CreatePlayerAbilityJoin(ctx, models.CreatePlayerAbilityJoinParams{
+			PlayerID:  player.ID,
+			AbilityID: ability.ID,
+			Quantity:  ability.DefaultCharges,
+		}); err != nil {
+			return Created{}, fmt.Errorf("add ability %s: %w", ability.Name, err)
+		}
+	}
+	for _, perk := range perks {
+		if _, err := q.CreatePlayerPerkJoin(ctx, models.CreatePlayerPerkJoinParams{
+			PlayerID: player.ID,
+			PerkID:   perk.ID,
+		}); err != nil {
+			return Created{}, fmt.Errorf("add perk %s: %w", perk.Name, err)
+		}
+	}
+
+	// The role's starting statuses, immunities and items come from its
+	// role_setup row; the numeric deltas were folded into the player above.
+	if err := applySetup(ctx, q, player, setup); err != nil {
+		return Created{}, fmt.Errorf("apply role setup: %w", err)
+	}
+	return Created{Player: player, Role: role}, nil
+}
+
+// ConfessionalCategory returns the configured confessional category name.
+func ConfessionalCategory(ctx context.Context, q *models.Queries) string {
+	if name, err := q.GetGameConfig(ctx, ConfessionalCategoryConfigKey); err == nil && strings.TrimSpace(name) != "" {
+		return strings.TrimSpace(name)
+	}
+	return DefaultConfessionalCategory
+}
+
+var channelNameDisallowed = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// ConfessionalName derives a Discord channel name from a username (pure,
+// unit-testable).
+func ConfessionalName(username string) string {
+	name := channelNameDisallowed.ReplaceAllString(strings.ToLower(strings.TrimSpace(username)), "-")
+	name = strings.Trim(name, "-")
+	if name == "" {
+		name = "player"
+	}
+	return name + "-confessional"
+}
+
+// gameConfigInt reads an integer game config value, falling back to the given
+// default when the row is missing or not a valid integer.
+func gameConfigInt(ctx context.Context, q *models.Queries, key string, fallback int32) int32 {
+	raw, err := q.GetGameConfig(ctx, key)
+	if err != nil {
+		return fallback
+	}
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		logger.Get().Warn().Str("key", key).Str("value", raw).Msg("game config value is not an integer; using fallback")
+		return fallback
+	}
+	return int32(n)
+}
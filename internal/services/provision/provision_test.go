@@ -0,0 +1,241 @@
+package provision
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/tests/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.Bootstrap(m))
+}
+
+// knownStatusNames is the set of statuses seeded by migration 000008. Every
+// immunity/status referenced by the seeded role_setup rows must exist in it — a typo'd
+// name previously inserted status id 0 and failed player creation with a foreign
+// key violation.
+var knownStatusNames = map[string]bool{
+	"Cursed":       true,
+	"Death Cursed": true,
+	"Frozen":       true,
+	"Paralyzed":    true,
+	"Burned":       true,
+	"Empowered":    true,
+	"Drunk":        true,
+	"Restrained":   true,
+	"Disabled":     true,
+	"Blackmailed":  true,
+	"Despaired":    true,
+	"Madness":      true,
+	"Lucky":        true,
+	"Unlucky":      true,
+}
+
+// TestRoleSetupSeed guards the per-role creation-time adjustments seeded by
+// migration 000049: every role that had a switch arm is present, all
+// referenced statuses exist, and the roles fixed during the switch→map
+// conversion behave correctly.
+func TestRoleSetupSeed(t *testing.T) {
+	pool := testutil.NewTestPool(t)
+	rows, err := models.New(pool).ListRoleSetup(context.Background())
+	require.NoError(t, err)
+	setups := make(map[string]Setup, len(rows))
+	for _, row := range rows {
+		setups[row.RoleName] = SetupFromRow(row)
+	}
+	for role, setup := range setups {
+		for _, immunity := range setup.Immunities {
+			assert.True(t, knownStatusNames[immunity], "role %q references unknown immunity status %q", role, immunity)
+		}
+		for _, status := range setup.Statuses {
+			assert.True(t, knownStatusNames[status], "role %q references unknown status %q", role, status)
+		}
+	}
+
+	// Every role covered by the old switch must still be covered.
+	for _, role := range []string{
+		"cerberus", "detective", "fisherman", "hero", "nurse", "terminal",
+		"wizard", "yeti", "cyborg", "entertainer", "magician", "masochist",
+		"succubus", "arsonist", "cultist", "director", "gatekeeper", "hacker",
+		"highwayman", "imp", "threatener",
+	} {
+		_, ok := setups[role]
+		assert.True(t, ok, "missing role setup for %q", role)
+	}
+
+	// Regressions from the switch→map conversion:
+	// - magician's "Lucky" was mislabeled as an immunity; it should be a status
+	//   (like entertainer, same perk).
+	assert.Contains(t, setups["magician"].Statuses, "Lucky")
+	assert.Contains(t, setups["magician"].Immunities, "Unlucky")
+	// - succubus referenced "Blackmail" (no such status); must be "Blackmailed".
+	assert.Contains(t, setups["succubus"].Immunities, "Blackmailed")
+	assert.NotContains(t, setups["succubus"].Immunities, "Blackmail")
+	// - cultist referenced "Curse" (no such status); must be "Cursed".
+	assert.Contains(t, setups["cultist"].Immunities, "Cursed")
+	assert.NotContains(t, setups["cultist"].Immunities, "Curse")
+
+	// Item limit overrides (8 and 6) preserved as deltas on the default of 4.
+	assert.Equal(t, int32(4), setups["fisherman"].ItemLimitDelta)
+	assert.Equal(t, int32(2), setups["threatener"].ItemLimitDelta)
+}
+
+func TestSetupNormalize(t *testing.T) {
+	s := Setup{
+		Statuses:   []string{" Lucky ", "lucky", ""},
+		Immunities: nil,
+		Items:      []string{"Rope", "Rope"},
+	}.Normalize()
+	assert.Equal(t, []string{"Lucky"}, s.Statuses)
+	assert.Equal(t, []string{}, s.Immunities, "nil lists become empty")
+	assert.Equal(t, []string{"Rope", "Rope"}, s.Items, "repeated items are quantities")
+	assert.False(t, s.IsZero())
+	assert.True(t, Setup{Items: []string{" "}}.Normalize().IsZero())
+	assert.True(t, s.Equal(Setup{Statuses: []string{"Lucky"}, Items: []string{"Rope", "Rope"}}))
+	assert.Equal(t, "statuses Lucky, items Rope/Rope", s.String())
+	assert.Equal(t, "item limit +2, coins -50", Setup{ItemLimitDelta: 2, BonusCoins: -50}.String())
+}
+
+func testPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	godotenv.Load("../../../.env")
+	pool, err := pgxpool.New(context.Background(), os.Getenv("DATABASE_URL"))
+	require.NoError(t, err, "failed to connect to local test database (DATABASE_URL)")
+	return pool
+}
+
+// TestGameConfigInt verifies the new-player defaults: rows from game_config
+// win, missing or unparseable rows fall back to the current defaults.
+func TestGameConfigInt(t *testing.T) {
+	pool := testPool(t)
+	defer pool.Close()
+
+	q := models.New(pool)
+	ctx := context.Background()
+	const key = "unit_test_default_coins"
+
+	require.NoError(t, q.DeleteGameConfig(ctx, key))
+	defer q.DeleteGameConfig(ctx, key)
+
+	// Missing row -> fallback.
+	assert.Equal(t, int32(200), gameConfigInt(ctx, q, key, 200))
+
+	// Valid row -> configured value.
+	_, err := q.UpsertGameConfig(ctx, models.UpsertGameConfigParams{Key: key, Value: "250"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(250), gameConfigInt(ctx, q, key, 200))
+
+	// Garbage row -> fallback.
+	_, err = q.UpsertGameConfig(ctx, models.UpsertGameConfigParams{Key: key, Value: "lots"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(200), gameConfigInt(ctx, q, key, 200))
+}
+
+func TestConfessionalName(t *testing.T) {
+	assert.Equal(t, "mr-fox-confessional", ConfessionalName("Mr. Fox"))
+	assert.Equal(t, "player-confessional", ConfessionalName("!!!"))
+}
+
+// TestCreatePlayerBuildsInventoryInOneTransaction checks that the role's
+// abilities, perks and setup land with the player, and that a failed creation
+// leaves nothing behind.
+func TestCreatePlayerBuildsInventoryInOneTransaction(t *testing.T) {
+	pool := testutil.NewTestPool(t)
+	testutil.TruncateAll(t, pool)
+	q := models.New(pool)
+	ctx := context.Background()
+
+	role, err := q.CreateRole(ctx, models.CreateRoleParams{Name: "Fisherman", Description: "x", Alignment: models.AlignmentGOOD})
+	require.NoError(t, err)
+	ability, err := q.CreateAbilityInfo(ctx, models.CreateAbilityInfoParams{Name: "Cast Net", Description: "x", DefaultCharges: 2, Rarity: models.RarityCOMMON})
+	require.NoError(t, err)
+	require.NoError(t, q.CreateRoleAbilityJoin(ctx, models.CreateRoleAbilityJoinParams{RoleID: role.ID, AbilityID: ability.ID}))
+	perk, err := q.CreatePerkInfo(ctx, models.CreatePerkInfoParams{Name: "Barrels", Description: "x"})
+	require.NoError(t, err)
+	require.NoError(t, q.CreateRolePerkJoin(ctx, models.CreateRolePerkJoinParams{RoleID: role.ID, PerkID: perk.ID}))
+
+	svc := New(pool)
+	created, err := svc.CreatePlayer(ctx, 42, "fisherman")
+	require.NoError(t, err)
+	assert.Equal(t, role.ID, created.Role.ID)
+	assert.Equal(t, int32(8), created.Player.ItemLimit, "role setup is applied before returning")
+	abilities, err := q.ListPlayerAbilityInventory(ctx, 42)
+	require.NoError(t, err)
+	require.Len(t, abilities, 1)
+	assert.Equal(t, int32(2), abilities[0].Quantity)
+	perks, err := q.ListPlayerPerk(ctx, 42)
+	require.NoError(t, err)
+	assert.Len(t, perks, 1)
+
+	_, err = svc.CreatePlayer(ctx, 42, "fisherman")
+	require.Error(t, err, "a second player with the same ID fails")
+	abilities, err = q.ListPlayerAbilityInventory(ctx, 42)
+	require.NoError(t, err)
+	assert.Len(t, abilities, 1, "the failed creation left no extra rows")
+}
+
+// TestCreatePlayerAppliesRoleSetup checks that every part of a role_setup row
+// reaches the new player, and that SaveSetup rejects names missing from the
+// catalog.
+func TestCreatePlayerAppliesRoleSetup(t *testing.T) {
+	pool := testutil.NewTestPool(t)
+	testutil.TruncateAll(t, pool)
+	q := models.New(pool)
+	ctx := context.Background()
+
+	_, err := q.CreateRole(ctx, models.CreateRoleParams{Name: "Setup Tester", Description: "x", Alignment: models.AlignmentNEUTRAL})
+	require.NoError(t, err)
+	_, err = q.CreateStatus(ctx, models.CreateStatusParams{Name: "Lucky", Description: "x"})
+	require.NoError(t, err)
+	_, err = q.CreateStatus(ctx, models.CreateStatusParams{Name: "Frozen", Description: "x"})
+	require.NoError(t, err)
+	_, err = q.CreateItem(ctx, models.CreateItemParams{Name: "Rope", Description: "x", Cost: 10, Rarity: models.RarityCOMMON})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = q.DeleteRoleSetup(ctx, SetupKey("Setup Tester")) })
+
+	_, err = SaveSetup(ctx, q, "Setup Tester", Setup{Statuses: []string{"Blessed"}, Items: []string{"Lasso"}})
+	require.ErrorIs(t, err, ErrUnknownSetupName)
+	assert.Contains(t, err.Error(), "status Blessed")
+	assert.Contains(t, err.Error(), "item Lasso")
+
+	saved, err := SaveSetup(ctx, q, "Setup Tester", Setup{
+		Statuses:       []string{"lucky"},
+		Immunities:     []string{"Frozen"},
+		Items:          []string{"Rope", "Rope"},
+		ItemLimitDelta: -1,
+		BonusCoins:     50,
+		BonusLuck:      3,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lucky"}, saved.Statuses)
+
+	created, err := New(pool).CreatePlayer(ctx, 77, "Setup Tester")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), created.Player.ItemLimit)
+	assert.Equal(t, int32(250), created.Player.Coins)
+	assert.Equal(t, int32(3), created.Player.Luck)
+	statuses, err := q.ListPlayerStatus(ctx, 77)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "Lucky", statuses[0].Name)
+	immunities, err := q.ListPlayerImmunity(ctx, 77)
+	require.NoError(t, err)
+	require.Len(t, immunities, 1)
+	assert.Equal(t, "Frozen", immunities[0].Name)
+	items, err := q.ListPlayerItemInventory(ctx, 77)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, int32(2), items[0].Quantity)
+
+	_, err = SaveSetup(ctx, q, "setup tester", Setup{})
+	require.NoError(t, err)
+	_, err = q.GetRoleSetup(ctx, SetupKey("Setup Tester"))
+	require.Error(t, err, "saving an empty setup removes the row")
+}
@@ -0,0 +1,252 @@
+package provision
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+// ErrUnknownSetupName is returned by SaveSetup when a rule names a status or
+// item that is not in the catalog.
+var ErrUnknownSetupName = errors.New("unknown name in role setup")
+
+// Setup is a role's creation-time adjustments, stored in the role_setup
+// table (seeded by migration 000049 from the map that used to live here).
+// Statuses and immunities name rows of the status table; Items names catalog
+// items, where a name listed twice starts the player with two. The deltas
+// are added to the game_config defaults for new players.
+type Setup struct {
+	Statuses       []string `json:"statuses"`
+	Immunities     []string `json:"immunities"`
+	Items          []string `json:"items"`
+	ItemLimitDelta int32    `json:"item_limit_delta"`
+	BonusCoins     int32    `json:"bonus_coins"`
+	BonusLuck      int32    `json:"bonus_luck"`
+}
+
+// SetupKey is the role_setup key for a role name.
+func SetupKey(roleName string) string {
+	return strings.ToLower(strings.TrimSpace(roleName))
+}
+
+// SetupFromRow converts a role_setup row.
+func SetupFromRow(row models.RoleSetup) Setup {
+	return Setup{
+		Statuses:       row.Statuses,
+		Immunities:     row.Immunities,
+		Items:          row.Items,
+		ItemLimitDelta: row.ItemLimitDelta,
+		BonusCoins:     row.BonusCoins,
+		BonusLuck:      row.BonusLuck,
+	}.Normalize()
+}
+
+// Normalize trims the names, drops blanks and repeated statuses or
+// immunities, and replaces nil lists with empty ones so the setup serialises
+// as arrays. Repeated items are kept since they are quantities.
+func (s Setup) Normalize() Setup {
+	s.Statuses = cleanNames(s.Statuses, true)
+	s.Immunities = cleanNames(s.Immunities, true)
+	s.Items = cleanNames(s.Items, false)
+	return s
+}
+
+// IsZero reports whether the setup changes nothing.
+func (s Setup) IsZero() bool {
+	return len(s.Statuses) == 0 && len(s.Immunities) == 0 && len(s.Items) == 0 &&
+		s.ItemLimitDelta == 0 && s.BonusCoins == 0 && s.BonusLuck == 0
+}
+
+// Equal reports whether two setups hold the same rules.
+func (s Setup) Equal(o Setup) bool {
+	s, o = s.Normalize(), o.Normalize()
+	return slices.Equal(s.Statuses, o.Statuses) && slices.Equal(s.Immunities, o.Immunities) &&
+		slices.Equal(s.Items, o.Items) && s.ItemLimitDelta == o.ItemLimitDelta &&
+		s.BonusCoins == o.BonusCoins && s.BonusLuck == o.BonusLuck
+}
+
+// String renders the setup for sync previews and logs.
+func (s Setup) String() string {
+	s = s.Normalize()
+	var parts []string
+	if len(s.Statuses) > 0 {
+		parts = append(parts, "statuses "+strings.Join(s.Statuses, "/"))
+	}
+	if len(s.Immunities) > 0 {
+		parts = append(parts, "immunities "+strings.Join(s.Immunities, "/"))
+	}
+	if len(s.Items) > 0 {
+		parts = append(parts, "items "+strings.Join(s.Items, "/"))
+	}
+	if s.ItemLimitDelta != 0 {
+		parts = append(parts, fmt.Sprintf("item limit %+d", s.ItemLimitDelta))
+	}
+	if s.BonusCoins != 0 {
+		parts = append(parts, fmt.Sprintf("coins %+d", s.BonusCoins))
+	}
+	if s.BonusLuck != 0 {
+		parts = append(parts, fmt.Sprintf("luck %+d", s.BonusLuck))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// GetSetup returns the setup for a role; the zero Setup when it has none.
+func GetSetup(ctx context.Context, q *models.Queries, roleName string) (Setup, error) {
+	row, err := q.GetRoleSetup(ctx, SetupKey(roleName))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Setup{}.Normalize(), nil
+	}
+	if err != nil {
+		return Setup{}, err
+	}
+	return SetupFromRow(row), nil
+}
+
+// SaveSetup validates the names against the catalog and stores the setup
+// for a role. Saving a zero setup removes the row.
+func SaveSetup(ctx context.Context, q *models.Queries, roleName string, s Setup) (Setup, error) {
+	s = s.Normalize()
+	if err := ValidateSetup(ctx, q, s); err != nil {
+		return Setup{}, err
+	}
+	if s.IsZero() {
+		return s, q.DeleteRoleSetup(ctx, SetupKey(roleName))
+	}
+	row, err := q.UpsertRoleSetup(ctx, models.UpsertRoleSetupParams{
+		RoleName:       SetupKey(roleName),
+		Statuses:       s.Statuses,
+		Immunities:     s.Immunities,
+		Items:          s.Items,
+		ItemLimitDelta: s.ItemLimitDelta,
+		BonusCoins:     s.BonusCoins,
+		BonusLuck:      s.BonusLuck,
+	})
+	if err != nil {
+		return Setup{}, err
+	}
+	return SetupFromRow(row), nil
+}
+
+// ValidateSetup checks every status and item name against the catalog,
+// case-insensitively, and reports all unknown names at once.
+func ValidateSetup(ctx context.Context, q *models.Queries, s Setup) error {
+	statuses, items, err := setupCatalog(ctx, q)
+	if err != nil {
+		return err
+	}
+	var unknown []string
+	for _, name := range append(slices.Clone(s.Statuses), s.Immunities...) {
+		if _, ok := statuses[strings.ToLower(name)]; !ok {
+			unknown = append(unknown, "status "+name)
+		}
+	}
+	for _, name := range s.Items {
+		if _, ok := items[strings.ToLower(name)]; !ok {
+			unknown = append(unknown, "item "+name)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownSetupName, strings.Join(cleanNames(unknown, true), ", "))
+	}
+	return nil
+}
+
+// setupCatalog maps lowercased status and item names to their ids.
+func setupCatalog(ctx context.Context, q *models.Queries) (statuses, items map[string]int32, err error) {
+	statusRows, err := q.ListStatus(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	statuses = make(map[string]int32, len(statusRows))
+	for _, status := range statusRows {
+		statuses[strings.ToLower(status.Name)] = status.ID
+	}
+	itemRows, err := q.ListItem(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	items = make(map[string]int32, len(itemRows))
+	for _, item := range itemRows {
+		items[strings.ToLower(item.Name)] = item.ID
+	}
+	return statuses, items, nil
+}
+
+// applySetup grants a new player the statuses, immunities and items of their
+// role's setup. Unknown names are skipped with a warning instead of aborting
+// the player creation (previously a typo'd name inserted status id 0 and
+// failed the foreign key, deleting the freshly created player); the web
+// editor rejects them, but a status or item can be removed after the fact.
+func applySetup(ctx context.Context, q *models.Queries, player models.Player, s Setup) error {
+	if len(s.Statuses) == 0 && len(s.Immunities) == 0 && len(s.Items) == 0 {
+		return nil
+	}
+	statuses, items, err := setupCatalog(ctx, q)
+	if err != nil {
+		return err
+	}
+	for _, name := range s.Statuses {
+		statusID, ok := statuses[strings.ToLower(name)]
+		if !ok {
+			logger.Get().Warn().Str("status", name).Msg("unknown status name in role setup; skipping")
+			continue
+		}
+		if _, err := q.CreatePlayerStatusJoin(ctx, models.CreatePlayerStatusJoinParams{
+			PlayerID: player.ID,
+			StatusID: statusID,
+		}); err != nil {
+			return fmt.Errorf("add status %s: %w", name, err)
+		}
+	}
+	for _, name := range s.Immunities {
+		statusID, ok := statuses[strings.ToLower(name)]
+		if !ok {
+			logger.Get().Warn().Str("status", name).Msg("unknown immunity name in role setup; skipping")
+			continue
+		}
+		if _, err := q.CreatePlayerImmunityJoin(ctx, models.CreatePlayerImmunityJoinParams{
+			PlayerID: player.ID,
+			StatusID: statusID,
+		}); err != nil {
+			return fmt.Errorf("add immunity %s: %w", name, err)
+		}
+	}
+	for _, name := range s.Items {
+		itemID, ok := items[strings.ToLower(name)]
+		if !ok {
+			logger.Get().Warn().Str("item", name).Msg("unknown item name in role setup; skipping")
+			continue
+		}
+		if err := q.UpsertPlayerItemJoin(ctx, models.UpsertPlayerItemJoinParams{
+			PlayerID: player.ID,
+			ItemID:   itemID,
+			Quantity: 1,
+		}); err != nil {
+			return fmt.Errorf("add item %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func cleanNames(names []string, unique bool) []string {
+	out := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || (unique && seen[strings.ToLower(name)]) {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		out = append(out, name)
+	}
+	return out
+}
@@ -4,32 +4,97 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"sort"
+	"strings"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/mccune1224/betrayal/internal/models"
 )
 
-// ActiveRoleNames is the curated role list used for a new game setup.
-var ActiveRoleNames = []string{"Agent", "Amalgamation", "Anarchist", "Analyst", "Backstabber", "Arsonist", "Biker", "Bard", "Bartender", "Cerberus", "Bomber", "Consort", "Detective", "Cheater", "Director", "Fisherman", "Entertainer", "Doll", "Gunman", "Empress", "Forsaken Angel", "Hero", "Ghost", "Gatekeeper", "Hydra", "Goliath", "Hacker", "Judge", "Incubus", "Highwayman", "Knight", "Magician", "Hunter", "The Major", "Masochist", "Jester", "Medium", "Mercenary", "Juggernaut", "Nurse", "Mimic", "Overlord", "Seraph", "Pathologist", "Parasite", "Terminal", "Salesman", "Phantom", "Time Traveler", "Siren", "Psychotherapist", "Undercover", "Sidekick", "Slaughterer", "Wizard", "Villager", "Threatener", "Yeti", "Wanderer", "Witchdoctor"}
+// Alignments lists the alignments in the order drafts fill them.
+var Alignments = []models.Alignment{models.AlignmentGOOD, models.AlignmentEVIL, models.AlignmentNEUTRAL}
 
 type Pool struct {
+	Seed             int64
 	DeceptionOptions [][]models.Role
 	RandomPool       []models.Role
 }
 
+// Constraints bound the random pool of a draft. Min and Max are per
+// alignment; a missing key means unbounded. Banned roles are never drawn,
+// required roles are always in the random pool. Names match
+// case-insensitively.
+type Constraints struct {
+	Min      map[models.Alignment]int `json:"min,omitempty"`
+	Max      map[models.Alignment]int `json:"max,omitempty"`
+	Banned   []string                 `json:"banned,omitempty"`
+	Required []string                 `json:"required,omitempty"`
+}
+
+// Options describe one draw. The same seed, options and active roles always
+// produce the same pool.
+type Options struct {
+	Players       int
+	Deceptionists int
+	Seed          int64
+	Constraints
+}
+
+// LoadRoles returns the catalog roles named in the active role list.
 func LoadRoles(ctx context.Context, pool *pgxpool.Pool) ([]models.Role, error) {
-	return models.New(pool).ListRolesByName(ctx, ActiveRoleNames)
+	q := models.New(pool)
+	names, err := q.ListActiveRoleNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return q.ListRolesByName(ctx, names)
 }
 
+// Generate draws an unconstrained pool with a random seed.
 func Generate(roles []models.Role, players, deceptionists int) (*Pool, error) {
-	if players < 0 || players > len(roles) {
+	return Draw(roles, Options{Players: players, Deceptionists: deceptionists, Seed: rand.Int63()})
+}
+
+// Draw builds a pool from roles honouring opts (pure, unit-testable).
+// Deceptionist slots each get one good, neutral and evil option; the random
+// pool starts with the required roles, then tops up every alignment to its
+// minimum and finally fills the remaining seats without exceeding any maximum.
+func Draw(roles []models.Role, opts Options) (*Pool, error) {
+	if opts.Players < 0 || opts.Players > len(roles) {
 		return nil, fmt.Errorf("player count must be between 0 and %d", len(roles))
 	}
-	if deceptionists < 0 {
+	if opts.Deceptionists < 0 {
 		return nil, fmt.Errorf("deceptionist count cannot be negative")
 	}
-	good, evil, neutral := Group(roles)
-	max := deceptionists
+	for _, alignment := range Alignments {
+		min, hasMin := opts.Min[alignment]
+		max, hasMax := opts.Max[alignment]
+		if min < 0 || max < 0 {
+			return nil, fmt.Errorf("%s limits cannot be negative", strings.ToLower(string(alignment)))
+		}
+		if hasMin && hasMax && min > max {
+			return nil, fmt.Errorf("at least %d %s roles cannot be at most %d", min, strings.ToLower(string(alignment)), max)
+		}
+	}
+
+	// Sort a copy so the seed alone decides the draw, whatever order the
+	// roles were loaded in.
+	eligible := make([]models.Role, 0, len(roles))
+	banned := nameSet(opts.Banned)
+	for _, role := range roles {
+		if !banned[strings.ToLower(role.Name)] {
+			eligible = append(eligible, role)
+		}
+	}
+	sort.Slice(eligible, func(i, j int) bool { return eligible[i].Name < eligible[j].Name })
+	if opts.Players > len(eligible) {
+		return nil, fmt.Errorf("only %d roles remain after bans for %d players", len(eligible), opts.Players)
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	out := &Pool{Seed: opts.Seed}
+	good, evil, neutral := Group(eligible)
+	max := opts.Deceptionists
 	if max > len(good) {
 		max = len(good)
 	}
@@ -39,15 +104,82 @@ func Generate(roles []models.Role, players, deceptionists int) (*Pool, error) {
 	if max > len(neutral) {
 		max = len(neutral)
 	}
-	out := &Pool{}
-	gp, ep, np := rand.Perm(len(good)), rand.Perm(len(evil)), rand.Perm(len(neutral))
+	gp, ep, np := rng.Perm(len(good)), rng.Perm(len(evil)), rng.Perm(len(neutral))
 	for i := 0; i < max; i++ {
 		out.DeceptionOptions = append(out.DeceptionOptions, []models.Role{good[gp[i]], neutral[np[i]], evil[ep[i]]})
 	}
-	rp := rand.Perm(len(roles))
-	for i := 0; i < players; i++ {
-		out.RandomPool = append(out.RandomPool, roles[rp[i]])
+
+	byName := make(map[string]int, len(eligible))
+	for i, role := range eligible {
+		byName[strings.ToLower(role.Name)] = i
+	}
+	used := make(map[int]bool, opts.Players)
+	counts := map[models.Alignment]int{}
+	take := func(i int) {
+		used[i] = true
+		counts[eligible[i].Alignment]++
+		out.RandomPool = append(out.RandomPool, eligible[i])
+	}
+	for _, name := range opts.Required {
+		i, ok := byName[strings.ToLower(strings.TrimSpace(name))]
+		switch {
+		case !ok && banned[strings.ToLower(strings.TrimSpace(name))]:
+			return nil, fmt.Errorf("required role %q is also banned", name)
+		case !ok:
+			return nil, fmt.Errorf("required role %q is not an active role", name)
+		case !used[i]:
+			take(i)
+		}
+	}
+	if len(out.RandomPool) > opts.Players {
+		return nil, fmt.Errorf("%d required roles do not fit %d players", len(out.RandomPool), opts.Players)
+	}
+	seats := len(out.RandomPool)
+	for _, alignment := range Alignments {
+		if max, ok := opts.Max[alignment]; ok && counts[alignment] > max {
+			return nil, fmt.Errorf("required roles exceed the %s maximum of %d", strings.ToLower(string(alignment)), max)
+		}
+		if opts.Min[alignment] > counts[alignment] {
+			seats += opts.Min[alignment] - counts[alignment]
+		}
+	}
+	if seats > opts.Players {
+		return nil, fmt.Errorf("the alignment minimums need %d seats but there are %d players", seats, opts.Players)
+	}
+
+	perm := rng.Perm(len(eligible))
+	for _, alignment := range Alignments {
+		for _, i := range perm {
+			if counts[alignment] >= opts.Min[alignment] {
+				break
+			}
+			if !used[i] && eligible[i].Alignment == alignment {
+				take(i)
+			}
+		}
+		if counts[alignment] < opts.Min[alignment] {
+			return nil, fmt.Errorf("only %d %s roles are available, at least %d are required",
+				counts[alignment], strings.ToLower(string(alignment)), opts.Min[alignment])
+		}
 	}
+	for _, i := range perm {
+		if len(out.RandomPool) == opts.Players {
+			break
+		}
+		if used[i] {
+			continue
+		}
+		if max, ok := opts.Max[eligible[i].Alignment]; ok && counts[eligible[i].Alignment] >= max {
+			continue
+		}
+		take(i)
+	}
+	if len(out.RandomPool) < opts.Players {
+		return nil, fmt.Errorf("the alignment maximums leave only %d roles for %d players", len(out.RandomPool), opts.Players)
+	}
+	rng.Shuffle(len(out.RandomPool), func(i, j int) {
+		out.RandomPool[i], out.RandomPool[j] = out.RandomPool[j], out.RandomPool[i]
+	})
 	return out, nil
 }
 
@@ -64,3 +196,13 @@ func Group(roles []models.Role) (good, evil, neutral []models.Role) {
 	}
 	return
 }
+
+func nameSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			set[name] = true
+		}
+	}
+	return set
+}
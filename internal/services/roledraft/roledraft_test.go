@@ -1,6 +1,8 @@
 package roledraft
 
 import (
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/mccune1224/betrayal/internal/models"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -28,3 +30,78 @@ func TestGenerateRejectsTooManyPlayers(t *testing.T) {
 	_, err := Generate(testRoles(), 7, 0)
 	require.Error(t, err)
 }
+func manyRoles() []models.Role {
+	var roles []models.Role
+	for i := 1; i <= 6; i++ {
+		roles = append(roles,
+			models.Role{ID: int32(i), Name: fmt.Sprintf("Good %d", i), Alignment: models.AlignmentGOOD},
+			models.Role{ID: int32(10 + i), Name: fmt.Sprintf("Evil %d", i), Alignment: models.AlignmentEVIL},
+			models.Role{ID: int32(20 + i), Name: fmt.Sprintf("Neutral %d", i), Alignment: models.AlignmentNEUTRAL})
+	}
+	return roles
+}
+func TestDrawIsReproducibleFromSeed(t *testing.T) {
+	roles := manyRoles()
+	a, err := Draw(roles, Options{Players: 8, Deceptionists: 2, Seed: 42})
+	require.NoError(t, err)
+	reversed := make([]models.Role, len(roles))
+	for i, role := range roles {
+		reversed[len(roles)-1-i] = role
+	}
+	b, err := Draw(reversed, Options{Players: 8, Deceptionists: 2, Seed: 42})
+	require.NoError(t, err)
+	require.Equal(t, a, b)
+	require.Equal(t, int64(42), a.Seed)
+}
+func TestDrawHonoursConstraints(t *testing.T) {
+	for seed := int64(0); seed < 25; seed++ {
+		p, err := Draw(manyRoles(), Options{Players: 8, Seed: seed, Constraints: Constraints{
+			Min:      map[models.Alignment]int{models.AlignmentEVIL: 3},
+			Max:      map[models.Alignment]int{models.AlignmentNEUTRAL: 1},
+			Banned:   []string{"good 1"},
+			Required: []string{"Good 2", "neutral 6"},
+		}})
+		require.NoError(t, err)
+		require.Len(t, p.RandomPool, 8)
+		good, evil, neutral := Group(p.RandomPool)
+		require.GreaterOrEqual(t, len(evil), 3)
+		require.Len(t, neutral, 1)
+		require.Equal(t, "Neutral 6", neutral[0].Name)
+		names := map[string]bool{}
+		for _, role := range good {
+			names[role.Name] = true
+		}
+		require.True(t, names["Good 2"])
+		require.False(t, names["Good 1"])
+	}
+}
+func TestDrawRejectsImpossibleConstraints(t *testing.T) {
+	cases := map[string]Constraints{
+		"min above max":         {Min: map[models.Alignment]int{models.AlignmentGOOD: 3}, Max: map[models.Alignment]int{models.AlignmentGOOD: 2}},
+		"minimums exceed seats": {Min: map[models.Alignment]int{models.AlignmentGOOD: 3, models.AlignmentEVIL: 3}},
+		"maximums leave gaps":   {Max: map[models.Alignment]int{models.AlignmentGOOD: 1, models.AlignmentEVIL: 1, models.AlignmentNEUTRAL: 1}},
+		"required and banned":   {Banned: []string{"Evil 1"}, Required: []string{"Evil 1"}},
+		"required unknown":      {Required: []string{"Nobody"}},
+		"too few of alignment":  {Min: map[models.Alignment]int{models.AlignmentEVIL: 5}, Banned: []string{"Evil 1", "Evil 2"}},
+	}
+	for name, c := range cases {
+		_, err := Draw(manyRoles(), Options{Players: 5, Seed: 1, Constraints: c})
+		require.Error(t, err, name)
+	}
+}
+func TestLineupSeatsPicksBeforeRandomPool(t *testing.T) {
+	roles := testRoles()
+	for i := range roles {
+		roles[i].ID = int32(i + 1)
+	}
+	draft := Draft{
+		RoleDraft: models.RoleDraft{PlayerCount: 3},
+		Pool:      Pool{DeceptionOptions: [][]models.Role{{roles[0], roles[4], roles[2]}}, RandomPool: []models.Role{roles[2], roles[1], roles[3], roles[5]}},
+		Picks:     []models.RoleDraftPick{{Slot: 0, RoleID: roles[2].ID, PlayerID: pgtype.Int8{Int64: 99, Valid: true}}},
+	}
+	seats := draft.Lineup()
+	require.Len(t, seats, 3)
+	require.Equal(t, Seat{Role: roles[2], PlayerID: 99}, seats[0])
+	require.Equal(t, "Good 2", seats[1].Role.Name)
+	require.Equal(t, "Evil 2", seats[2].Role.Name)
+}
@@ -0,0 +1,334 @@
+package roledraft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/util"
+)
+
+const (
+	StatusOpen     = "open"
+	StatusFinished = "finished"
+)
+
+var (
+	ErrInvalidDraw   = errors.New("cannot draw roles")
+	ErrDraftNotFound = errors.New("role draft not found")
+	ErrDraftFinished = errors.New("role draft is already finished")
+	ErrDraftOpen     = errors.New("role draft is not finished yet")
+	ErrInvalidPick   = errors.New("invalid deceptionist pick")
+	ErrUnknownRole   = errors.New("unknown role")
+	ErrRoleNotActive = errors.New("role is not in the active role list")
+)
+
+// Draft is a persisted draw together with the deceptionist picks made so far.
+type Draft struct {
+	models.RoleDraft
+	Pool  Pool
+	Picks []models.RoleDraftPick
+}
+
+// Finished reports whether the draft is frozen.
+func (d Draft) Finished() bool {
+	return d.Status == StatusFinished
+}
+
+// Constraints rebuilds the constraints the draft was drawn with.
+func (d Draft) Constraints() Constraints {
+	c := Constraints{Min: map[models.Alignment]int{}, Max: map[models.Alignment]int{}, Banned: d.BannedRoles, Required: d.RequiredRoles}
+	for alignment, limit := range map[models.Alignment][2]pgtype.Int4{
+		models.AlignmentGOOD:    {d.MinGood, d.MaxGood},
+		models.AlignmentEVIL:    {d.MinEvil, d.MaxEvil},
+		models.AlignmentNEUTRAL: {d.MinNeutral, d.MaxNeutral},
+	} {
+		if limit[0].Valid {
+			c.Min[alignment] = int(limit[0].Int32)
+		}
+		if limit[1].Valid {
+			c.Max[alignment] = int(limit[1].Int32)
+		}
+	}
+	return c
+}
+
+// Seat is one role of a draft's final lineup. PlayerID is set when a
+// deceptionist picked the role.
+type Seat struct {
+	Role     models.Role `json:"role"`
+	PlayerID int64       `json:"player_id,omitempty"`
+}
+
+// Lineup lists the roles a draft hands to player creation: the deceptionist
+// picks first, then the random pool minus the picked roles, PlayerCount seats
+// in total.
+func (d Draft) Lineup() []Seat {
+	picked := make(map[int32]bool, len(d.Picks))
+	seats := make([]Seat, 0, d.PlayerCount)
+	for _, pick := range d.Picks {
+		role, ok := d.optionRole(int(pick.Slot), pick.RoleID)
+		if !ok {
+			continue
+		}
+		picked[role.ID] = true
+		seats = append(seats, Seat{Role: role, PlayerID: pick.PlayerID.Int64})
+	}
+	for _, role := range d.Pool.RandomPool {
+		if len(seats) >= int(d.PlayerCount) {
+			break
+		}
+		if !picked[role.ID] {
+			seats = append(seats, Seat{Role: role})
+		}
+	}
+	return seats
+}
+
+func (d Draft) optionRole(slot int, roleID int32) (models.Role, bool) {
+	if slot < 0 || slot >= len(d.Pool.DeceptionOptions) {
+		return models.Role{}, false
+	}
+	for _, role := range d.Pool.DeceptionOptions[slot] {
+		if role.ID == roleID {
+			return role, true
+		}
+	}
+	return models.Role{}, false
+}
+
+type Service struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Service {
+	return &Service{pool: pool}
+}
+
+// ActiveRoleNames returns the active role list.
+func (s *Service) ActiveRoleNames(ctx context.Context) ([]string, error) {
+	return models.New(s.pool).ListActiveRoleNames(ctx)
+}
+
+// AddActiveRole adds a catalog role to the active role list. Unknown names
+// fail with ErrUnknownRole and the closest catalog name.
+func (s *Service) AddActiveRole(ctx context.Context, name string) (models.Role, error) {
+	q := models.New(s.pool)
+	roles, err := q.Listrole(ctx)
+	if err != nil {
+		return models.Role{}, err
+	}
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		if strings.EqualFold(role.Name, strings.TrimSpace(name)) {
+			return role, q.AddActiveRole(ctx, role.Name)
+		}
+		names[i] = role.Name
+	}
+	if best, _ := util.FuzzyFind(name, names); best != "" {
+		return models.Role{}, fmt.Errorf("%w %q, did you mean %s?", ErrUnknownRole, name, best)
+	}
+	return models.Role{}, fmt.Errorf("%w %q", ErrUnknownRole, name)
+}
+
+// RemoveActiveRole drops name from the active role list.
+func (s *Service) RemoveActiveRole(ctx context.Context, name string) error {
+	q := models.New(s.pool)
+	active, err := q.ListActiveRoleNames(ctx)
+	if err != nil {
+		return err
+	}
+	for _, candidate := range active {
+		if strings.EqualFold(candidate, strings.TrimSpace(name)) {
+			_, err := q.DeleteActiveRole(ctx, candidate)
+			return err
+		}
+	}
+	return ErrRoleNotActive
+}
+
+// Create draws a pool from the active roles and stores it as an open draft.
+// Options the active roles cannot satisfy fail with ErrInvalidDraw.
+func (s *Service) Create(ctx context.Context, opts Options, createdBy string) (Draft, error) {
+	roles, err := LoadRoles(ctx, s.pool)
+	if err != nil {
+		return Draft{}, err
+	}
+	drawn, err := Draw(roles, opts)
+	if err != nil {
+		return Draft{}, fmt.Errorf("%w: %v", ErrInvalidDraw, err)
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Draft{}, err
+	}
+	defer tx.Rollback(ctx)
+	q := models.New(tx)
+	row, err := q.CreateRoleDraft(ctx, models.CreateRoleDraftParams{
+		Seed:              opts.Seed,
+		PlayerCount:       int32(opts.Players),
+		DeceptionistCount: int32(len(drawn.DeceptionOptions)),
+		MinGood:           limit(opts.Min, models.AlignmentGOOD),
+		MaxGood:           limit(opts.Max, models.AlignmentGOOD),
+		MinEvil:           limit(opts.Min, models.AlignmentEVIL),
+		MaxEvil:           limit(opts.Max, models.AlignmentEVIL),
+		MinNeutral:        limit(opts.Min, models.AlignmentNEUTRAL),
+		MaxNeutral:        limit(opts.Max, models.AlignmentNEUTRAL),
+		BannedRoles:       nonNil(opts.Banned),
+		RequiredRoles:     nonNil(opts.Required),
+		CreatedBy:         createdBy,
+	})
+	if err != nil {
+		return Draft{}, err
+	}
+	for i, role := range drawn.RandomPool {
+		if err := q.AddRoleDraftRole(ctx, models.AddRoleDraftRoleParams{DraftID: row.ID, Position: int32(i), RoleID: role.ID}); err != nil {
+			return Draft{}, err
+		}
+	}
+	for slot, options := range drawn.DeceptionOptions {
+		for i, role := range options {
+			if err := q.AddRoleDraftOption(ctx, models.AddRoleDraftOptionParams{DraftID: row.ID, Slot: int32(slot), Position: int32(i), RoleID: role.ID}); err != nil {
+				return Draft{}, err
+			}
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Draft{}, err
+	}
+	return Draft{RoleDraft: row, Pool: *drawn}, nil
+}
+
+// Get loads a draft with its pool and picks.
+func (s *Service) Get(ctx context.Context, id int64) (Draft, error) {
+	q := models.New(s.pool)
+	row, err := q.GetRoleDraft(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Draft{}, ErrDraftNotFound
+	}
+	if err != nil {
+		return Draft{}, err
+	}
+	return load(ctx, q, row)
+}
+
+// Latest loads the most recent draft.
+func (s *Service) Latest(ctx context.Context) (Draft, error) {
+	q := models.New(s.pool)
+	rows, err := q.ListRoleDrafts(ctx, 1)
+	if err != nil {
+		return Draft{}, err
+	}
+	if len(rows) == 0 {
+		return Draft{}, ErrDraftNotFound
+	}
+	return load(ctx, q, rows[0])
+}
+
+// List returns the most recent drafts without their pools.
+func (s *Service) List(ctx context.Context, limit int32) ([]models.RoleDraft, error) {
+	return models.New(s.pool).ListRoleDrafts(ctx, limit)
+}
+
+// Pick records the role a deceptionist chose from their slot's options,
+// replacing an earlier pick for the slot. playerID is optional (zero).
+func (s *Service) Pick(ctx context.Context, id int64, slot int, roleName string, playerID int64, pickedBy string) (Draft, error) {
+	draft, err := s.Get(ctx, id)
+	if err != nil {
+		return Draft{}, err
+	}
+	if draft.Finished() {
+		return Draft{}, ErrDraftFinished
+	}
+	if slot < 0 || slot >= len(draft.Pool.DeceptionOptions) {
+		return Draft{}, fmt.Errorf("%w: the draft has %d deceptionist slots", ErrInvalidPick, len(draft.Pool.DeceptionOptions))
+	}
+	options := draft.Pool.DeceptionOptions[slot]
+	names := make([]string, len(options))
+	for i, role := range options {
+		names[i] = role.Name
+		if !strings.EqualFold(role.Name, strings.TrimSpace(roleName)) {
+			continue
+		}
+		pick, err := models.New(s.pool).UpsertRoleDraftPick(ctx, models.UpsertRoleDraftPickParams{
+			DraftID:  id,
+			Slot:     int32(slot),
+			RoleID:   role.ID,
+			PlayerID: pgtype.Int8{Int64: playerID, Valid: playerID != 0},
+			PickedBy: pickedBy,
+		})
+		if err != nil {
+			return Draft{}, err
+		}
+		draft.Picks = upsertPick(draft.Picks, pick)
+		return draft, nil
+	}
+	return Draft{}, fmt.Errorf("%w: slot %d offers %s", ErrInvalidPick, slot+1, strings.Join(names, ", "))
+}
+
+// Finish freezes a draft so it can be handed to player creation.
+func (s *Service) Finish(ctx context.Context, id int64) (Draft, error) {
+	q := models.New(s.pool)
+	row, err := q.FinishRoleDraft(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, err := q.GetRoleDraft(ctx, id); err == nil {
+			return Draft{}, ErrDraftFinished
+		}
+		return Draft{}, ErrDraftNotFound
+	}
+	if err != nil {
+		return Draft{}, err
+	}
+	return load(ctx, q, row)
+}
+
+func load(ctx context.Context, q *models.Queries, row models.RoleDraft) (Draft, error) {
+	draft := Draft{RoleDraft: row, Pool: Pool{Seed: row.Seed}}
+	roles, err := q.ListRoleDraftRoles(ctx, row.ID)
+	if err != nil {
+		return Draft{}, err
+	}
+	draft.Pool.RandomPool = roles
+	options, err := q.ListRoleDraftOptions(ctx, row.ID)
+	if err != nil {
+		return Draft{}, err
+	}
+	for _, option := range options {
+		for int(option.Slot) >= len(draft.Pool.DeceptionOptions) {
+			draft.Pool.DeceptionOptions = append(draft.Pool.DeceptionOptions, nil)
+		}
+		draft.Pool.DeceptionOptions[option.Slot] = append(draft.Pool.DeceptionOptions[option.Slot],
+			models.Role{ID: option.ID, Name: option.Name, Description: option.Description, Alignment: option.Alignment})
+	}
+	if draft.Picks, err = q.ListRoleDraftPicks(ctx, row.ID); err != nil {
+		return Draft{}, err
+	}
+	return draft, nil
+}
+
+func upsertPick(picks []models.RoleDraftPick, pick models.RoleDraftPick) []models.RoleDraftPick {
+	for i := range picks {
+		if picks[i].Slot == pick.Slot {
+			picks[i] = pick
+			return picks
+		}
+	}
+	return append(picks, pick)
+}
+
+func limit(limits map[models.Alignment]int, alignment models.Alignment) pgtype.Int4 {
+	value, ok := limits[alignment]
+	return pgtype.Int4{Int32: int32(value), Valid: ok}
+}
+
+func nonNil(names []string) []string {
+	if names == nil {
+		return []string{}
+	}
+	return names
+}
@@ -0,0 +1,354 @@
+// Package roster onboards a whole game at once. A roster maps Discord user IDs
+// to role names and comes from an uploaded CSV or JSON file or from a
+// (finished) role draft. Every row is validated against the role catalog first, with a "did
+// you mean" suggestion for misspelled roles, so the host can fix the file
+// before anything is created. Applying a valid roster provisions each player
+// and their confessional through the provision service.
+package roster
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/provision"
+	"github.com/mccune1224/betrayal/internal/services/roledraft"
+	"github.com/mccune1224/betrayal/internal/util"
+)
+
+// Entry is one roster line before validation. Line is 1-based and zero for
+// entries that did not come from a file.
+type Entry struct {
+	Line   int    `json:"line,omitempty"`
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// Row is the validation outcome of one entry. Matched is the catalog name the
+// input resolved to; Suggestion is offered when it did not resolve.
+type Row struct {
+	Entry
+	Matched    string `json:"matched_role,omitempty"`
+	Alignment  string `json:"alignment,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"`
+	Problem    string `json:"problem,omitempty"`
+}
+
+// Report is the preview of a roster.
+type Report struct {
+	Rows []Row `json:"rows"`
+	// Alignments counts the valid rows per alignment.
+	Alignments map[string]int `json:"alignments"`
+}
+
+// Valid reports whether every row resolved without a problem.
+func (r Report) Valid() bool {
+	for _, row := range r.Rows {
+		if row.Problem != "" {
+			return false
+		}
+	}
+	return len(r.Rows) > 0
+}
+
+// Problems lists the rows that need fixing, one line each.
+func (r Report) Problems() []string {
+	var lines []string
+	for _, row := range r.Rows {
+		if row.Problem == "" {
+			continue
+		}
+		line := row.Problem
+		if row.Suggestion != "" {
+			line += fmt.Sprintf(" — did you mean **%s**?", row.Suggestion)
+		}
+		if row.Line > 0 {
+			line = fmt.Sprintf("line %d: %s", row.Line, line)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Result is what applying one row produced.
+type Result struct {
+	UserID    string   `json:"user_id"`
+	Role      string   `json:"role"`
+	ChannelID string   `json:"channel_id,omitempty"`
+	Error     string   `json:"error,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+var (
+	ErrEmptyRoster   = errors.New("roster has no entries")
+	ErrInvalidRoster = errors.New("roster has problems; fix them and preview again")
+)
+
+// Parse reads a roster file. JSON may be an array of {"user_id", "role"}
+// objects or an object mapping user IDs to roles; anything else is read as
+// CSV with a user ID and a role per line and an optional header (pure,
+// unit-testable).
+func Parse(raw []byte) ([]Entry, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return nil, ErrEmptyRoster
+	}
+	switch trimmed[0] {
+	case '[':
+		var entries []Entry
+		if err := json.Unmarshal(trimmed, &entries); err != nil {
+			return nil, fmt.Errorf("invalid JSON roster: %w", err)
+		}
+		for i := range entries {
+			entries[i].Line = i + 1
+		}
+		return entries, nil
+	case '{':
+		// Decode through a token stream to keep the file's order.
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		if _, err := dec.Token(); err != nil {
+			return nil, fmt.Errorf("invalid JSON roster: %w", err)
+		}
+		var entries []Entry
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSON roster: %w", err)
+			}
+			var role string
+			if err := dec.Decode(&role); err != nil {
+				return nil, fmt.Errorf("invalid JSON roster: role for %v must be a string", key)
+			}
+			entries = append(entries, Entry{Line: len(entries) + 1, UserID: fmt.Sprint(key), Role: role})
+		}
+		return entries, nil
+	}
+	reader := csv.NewReader(bytes.NewReader(trimmed))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	var entries []Entry
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV roster: %w", err)
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if line == 1 && isHeader(record) {
+			continue
+		}
+		entry := Entry{Line: line, UserID: strings.TrimSpace(record[0])}
+		if len(record) > 1 {
+			entry.Role = strings.TrimSpace(record[1])
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		return nil, ErrEmptyRoster
+	}
+	return entries, nil
+}
+
+func isHeader(record []string) bool {
+	_, err := strconv.ParseInt(normalizeUserID(record[0]), 10, 64)
+	return err != nil && len(record) > 1 && strings.EqualFold(strings.TrimSpace(record[1]), "role")
+}
+
+// FromDraft deals the random pool of a role draft to userIDs in order.
+func FromDraft(userIDs []string, draft *roledraft.Pool) ([]Entry, error) {
+	if draft == nil || len(draft.RandomPool) < len(userIDs) {
+		return nil, fmt.Errorf("the draft has fewer roles than the %d players", len(userIDs))
+	}
+	entries := make([]Entry, len(userIDs))
+	for i, id := range userIDs {
+		entries[i] = Entry{UserID: id, Role: draft.RandomPool[i].Name}
+	}
+	return entries, nil
+}
+
+// FromLineup hands a finished draft to player creation. Seats a deceptionist
+// already picked go to that player; the other seats are dealt to userIDs in
+// order, skipping users who already hold a picked seat.
+func FromLineup(userIDs []string, lineup []roledraft.Seat) ([]Entry, error) {
+	entries := make([]Entry, 0, len(lineup))
+	seated := map[string]bool{}
+	var open []roledraft.Seat
+	for _, seat := range lineup {
+		if seat.PlayerID == 0 {
+			open = append(open, seat)
+			continue
+		}
+		id := strconv.FormatInt(seat.PlayerID, 10)
+		seated[id] = true
+		entries = append(entries, Entry{UserID: id, Role: seat.Role.Name})
+	}
+	var rest []string
+	for _, id := range userIDs {
+		if !seated[normalizeUserID(id)] {
+			rest = append(rest, id)
+		}
+	}
+	if len(rest) != len(open) {
+		return nil, fmt.Errorf("the draft has %d open seats but %d players were listed", len(open), len(rest))
+	}
+	for i, seat := range open {
+		entries = append(entries, Entry{UserID: rest[i], Role: seat.Role.Name})
+	}
+	return entries, nil
+}
+
+// Template renders a lineup as a roster CSV. Seats without a player get an
+// empty user ID for the host to fill in before uploading it.
+func Template(lineup []roledraft.Seat) []byte {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"user_id", "role"})
+	for _, seat := range lineup {
+		id := ""
+		if seat.PlayerID != 0 {
+			id = strconv.FormatInt(seat.PlayerID, 10)
+		}
+		w.Write([]string{id, seat.Role.Name})
+	}
+	w.Flush()
+	return buf.Bytes()
+}
+
+// Validate resolves every entry against the role catalog. Role names match
+// case-insensitively; anything else is a problem with the closest catalog
+// name as suggestion. Malformed or repeated user IDs and users that already
+// have a player are problems too.
+func Validate(entries []Entry, roles []models.Role, existing map[int64]bool) Report {
+	byName := make(map[string]models.Role, len(roles))
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		byName[strings.ToLower(role.Name)] = role
+		names = append(names, role.Name)
+	}
+	report := Report{Rows: make([]Row, 0, len(entries)), Alignments: map[string]int{}}
+	seen := make(map[int64]int, len(entries))
+	for i, entry := range entries {
+		row := Row{Entry: entry}
+		row.UserID = normalizeUserID(entry.UserID)
+		id, err := strconv.ParseInt(row.UserID, 10, 64)
+		role, known := byName[strings.ToLower(strings.TrimSpace(entry.Role))]
+		switch {
+		case err != nil || id <= 0:
+			row.Problem = fmt.Sprintf("%q is not a Discord user ID", entry.UserID)
+		case seen[id] > 0:
+			row.Problem = fmt.Sprintf("user %s is listed twice (also row %d)", row.UserID, seen[id])
+		case existing[id]:
+			row.Problem = fmt.Sprintf("user %s already has a player", row.UserID)
+		case strings.TrimSpace(entry.Role) == "":
+			row.Problem = fmt.Sprintf("user %s has no role", row.UserID)
+		case !known:
+			row.Problem = fmt.Sprintf("unknown role %q", entry.Role)
+			row.Suggestion, _ = util.FuzzyFind(entry.Role, names)
+		}
+		if err == nil && id > 0 && seen[id] == 0 {
+			seen[id] = i + 1
+		}
+		if known {
+			row.Matched, row.Alignment = role.Name, string(role.Alignment)
+		}
+		if row.Problem == "" {
+			report.Alignments[row.Alignment]++
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	return report
+}
+
+// normalizeUserID accepts raw IDs and <@id> / <@!id> mentions.
+func normalizeUserID(raw string) string {
+	id := strings.TrimSpace(raw)
+	id = strings.TrimPrefix(id, "<@")
+	id = strings.TrimPrefix(id, "!")
+	return strings.TrimSuffix(id, ">")
+}
+
+type Service struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Service {
+	return &Service{pool: pool}
+}
+
+// Preview validates entries against the current catalog and players.
+func (s *Service) Preview(ctx context.Context, entries []Entry) (Report, error) {
+	if len(entries) == 0 {
+		return Report{}, ErrEmptyRoster
+	}
+	q := models.New(s.pool)
+	roles, err := q.Listrole(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+	players, err := q.ListPlayer(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+	existing := make(map[int64]bool, len(players))
+	for _, player := range players {
+		existing[player.ID] = true
+	}
+	return Validate(entries, roles, existing), nil
+}
+
+// Apply validates entries again and, when every row is valid, provisions the
+// players one by one. Each player is created in its own transaction; a failed
+// row is reported and the rest continue. Without a Discord session the
+// players are created without confessionals.
+func (s *Service) Apply(ctx context.Context, entries []Entry, sesh *discordgo.Session, guildID string) (Report, []Result, error) {
+	report, err := s.Preview(ctx, entries)
+	if err != nil {
+		return Report{}, nil, err
+	}
+	if !report.Valid() {
+		return report, nil, ErrInvalidRoster
+	}
+	svc := provision.New(s.pool)
+	results := make([]Result, 0, len(report.Rows))
+	for _, row := range report.Rows {
+		result := Result{UserID: row.UserID, Role: row.Matched}
+		id, _ := strconv.ParseInt(row.UserID, 10, 64)
+		if sesh == nil {
+			if _, err := svc.CreatePlayer(ctx, id, row.Matched); err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Warnings = []string{"Discord is not connected; no confessional was created."}
+			}
+			results = append(results, result)
+			continue
+		}
+		user, err := sesh.User(row.UserID)
+		if err != nil {
+			user = &discordgo.User{ID: row.UserID, Username: row.UserID}
+		}
+		provisioned, err := svc.Provision(ctx, sesh, guildID, user, row.Matched)
+		if err != nil {
+			logger.Get().Error().Err(err).Str("player_id", row.UserID).Msg("roster player not provisioned")
+			result.Error = err.Error()
+		} else {
+			result.ChannelID = provisioned.ChannelID
+			result.Warnings = provisioned.Warnings
+		}
+		results = append(results, result)
+	}
+	return report, results, nil
+}
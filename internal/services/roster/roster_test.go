@@ -0,0 +1,110 @@
+package roster
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/roledraft"
+)
+
+var catalog = []models.Role{
+	{ID: 1, Name: "Fisherman", Alignment: models.AlignmentGOOD},
+	{ID: 2, Name: "Arsonist", Alignment: models.AlignmentEVIL},
+	{ID: 3, Name: "Jester", Alignment: models.AlignmentNEUTRAL},
+}
+
+func TestParseCSVSkipsHeaderAndAcceptsMentions(t *testing.T) {
+	entries, err := Parse([]byte("user_id,role\n101, Fisherman\n<@!102>,arsonist\n\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %#v", entries)
+	}
+	if entries[0] != (Entry{Line: 2, UserID: "101", Role: "Fisherman"}) {
+		t.Fatalf("first entry = %#v", entries[0])
+	}
+	report := Validate(entries, catalog, nil)
+	if !report.Valid() || report.Rows[1].UserID != "102" || report.Rows[1].Matched != "Arsonist" {
+		t.Fatalf("report = %#v", report)
+	}
+}
+
+func TestParseJSONForms(t *testing.T) {
+	list, err := Parse([]byte(`[{"user_id":"101","role":"Jester"}]`))
+	if err != nil || len(list) != 1 || list[0].Role != "Jester" {
+		t.Fatalf("list = %#v, err = %v", list, err)
+	}
+	mapping, err := Parse([]byte(`{"102":"Arsonist","101":"Jester"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mapping) != 2 || mapping[0].UserID != "102" || mapping[1].UserID != "101" {
+		t.Fatalf("object rosters keep file order, got %#v", mapping)
+	}
+	if _, err := Parse([]byte("  ")); err != ErrEmptyRoster {
+		t.Fatalf("err = %v, want ErrEmptyRoster", err)
+	}
+}
+
+func TestValidateReportsProblemsWithSuggestions(t *testing.T) {
+	report := Validate([]Entry{
+		{Line: 1, UserID: "101", Role: "Fishermn"},
+		{Line: 2, UserID: "101", Role: "Jester"},
+		{Line: 3, UserID: "abc", Role: "Jester"},
+		{Line: 4, UserID: "103", Role: "Jester"},
+		{Line: 5, UserID: "104", Role: "jester"},
+	}, catalog, map[int64]bool{103: true})
+	if report.Valid() {
+		t.Fatal("report must not be valid")
+	}
+	if report.Rows[0].Suggestion != "Fisherman" {
+		t.Fatalf("suggestion = %q", report.Rows[0].Suggestion)
+	}
+	problems := report.Problems()
+	if len(problems) != 4 {
+		t.Fatalf("problems = %#v", problems)
+	}
+	if !strings.Contains(problems[0], "did you mean **Fisherman**") || !strings.Contains(problems[1], "listed twice") ||
+		!strings.Contains(problems[2], "not a Discord user ID") || !strings.Contains(problems[3], "already has a player") {
+		t.Fatalf("problems = %#v", problems)
+	}
+	if report.Alignments[string(models.AlignmentNEUTRAL)] != 1 {
+		t.Fatalf("alignments = %#v", report.Alignments)
+	}
+}
+
+func TestFromDraftDealsRandomPoolInOrder(t *testing.T) {
+	draft := &roledraft.Pool{RandomPool: catalog}
+	entries, err := FromDraft([]string{"101", "102"}, draft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries[0].Role != "Fisherman" || entries[1].Role != "Arsonist" {
+		t.Fatalf("entries = %#v", entries)
+	}
+	if _, err := FromDraft([]string{"1", "2", "3", "4"}, draft); err == nil {
+		t.Fatal("a draft smaller than the roster must fail")
+	}
+}
+
+func TestFromLineupKeepsDeceptionistPicks(t *testing.T) {
+	lineup := []roledraft.Seat{{Role: catalog[1], PlayerID: 102}, {Role: catalog[0]}, {Role: catalog[2]}}
+	entries, err := FromLineup([]string{"101", "<@102>", "103"}, lineup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Entry{{UserID: "102", Role: "Arsonist"}, {UserID: "101", Role: "Fisherman"}, {UserID: "103", Role: "Jester"}}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Fatalf("entries = %#v", entries)
+		}
+	}
+	if _, err := FromLineup([]string{"101"}, lineup); err == nil {
+		t.Fatal("a player count that does not match the open seats must fail")
+	}
+	if got := string(Template(lineup)); got != "user_id,role\n102,Arsonist\n,Fisherman\n,Jester\n" {
+		t.Fatalf("template = %q", got)
+	}
+}
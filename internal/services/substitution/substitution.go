@@ -0,0 +1,224 @@
+// Package substitution hands a player's seat to a different Discord user.
+// Player IDs are Discord user IDs and are referenced from every player_*
+// table, votes, polls, whispers and logs, so a substitution re-keys all of
+// them in one transaction, moves the confessional over to the new user and
+// records the change in player_substitution.
+package substitution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/inventory"
+	"github.com/mccune1224/betrayal/internal/services/lifeboard"
+	"github.com/mccune1224/betrayal/internal/services/provision"
+)
+
+// Column is a table column holding a player ID.
+type Column struct {
+	Table  string
+	Column string
+}
+
+// PlayerColumns lists every column that stores a player ID. Tables added
+// later must be listed here; tests/substitution checks the list against the
+// foreign keys to player(id).
+var PlayerColumns = []Column{
+	{"player_item", "player_id"},
+	{"player_status", "player_id"},
+	{"player_perk", "player_id"},
+	{"player_ability", "player_id"},
+	{"player_confessional", "player_id"},
+	{"player_immunity", "player_id"},
+	{"player_note", "player_id"},
+	{"player_substitution", "player_id"},
+	{"vote", "voter_id"},
+	{"vote", "target_id"},
+	{"poll_option", "player_id"},
+	{"poll_ballot", "voter_id"},
+	{"whisper_group_member", "player_id"},
+	{"whisper_eavesdrop", "player_id"},
+	{"whisper_quota_adjustment", "player_id"},
+	{"whisper_transcript", "sender_id"},
+	{"whisper_usage", "sender_id"},
+	{"role_draft_pick", "player_id"},
+	{"alliance", "created_by"},
+	{"alliance_member", "player_id"},
+	{"alliance_member", "invited_by"},
+	{"inventory_snapshot", "player_id"},
+	{"logs", "user_id"},
+}
+
+var (
+	ErrPlayerNotFound = errors.New("player not found")
+	ErrAlreadyPlayer  = errors.New("the new user already has a player")
+	ErrSameUser       = errors.New("the new user is the current player")
+)
+
+// Request describes one substitution. By names the host for the history.
+type Request struct {
+	OldID  int64
+	NewID  int64
+	Reason string
+	By     string
+}
+
+// Result is the completed substitution. Warnings lists the Discord steps that
+// failed after the database was committed.
+type Result struct {
+	Record    models.PlayerSubstitution
+	Player    models.Player
+	ChannelID string
+	Warnings  []string
+}
+
+type Service struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Service {
+	return &Service{pool: pool}
+}
+
+// Substitute moves req.OldID's seat to req.NewID. With a Discord session the
+// new user is granted the confessional before the transaction commits, so a
+// failed grant leaves nothing changed; revoking the old user, renaming the
+// channel and re-rendering the inventory happen afterwards and only warn.
+// Without a session only the database is updated.
+func (s *Service) Substitute(ctx context.Context, sesh *discordgo.Session, req Request) (Result, error) {
+	if req.OldID == req.NewID {
+		return Result{}, ErrSameUser
+	}
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	defer tx.Rollback(ctx)
+	q := models.New(tx)
+	if _, err := q.GetPlayer(ctx, req.OldID); errors.Is(err, pgx.ErrNoRows) {
+		return Result{}, ErrPlayerNotFound
+	} else if err != nil {
+		return Result{}, err
+	}
+	if _, err := q.GetPlayer(ctx, req.NewID); err == nil {
+		return Result{}, ErrAlreadyPlayer
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return Result{}, err
+	}
+	if err := rekey(ctx, tx, req.OldID, req.NewID); err != nil {
+		return Result{}, err
+	}
+
+	var phase pgtype.Int8
+	if open, err := q.GetOpenCycleHistory(ctx); err == nil {
+		phase = pgtype.Int8{Int64: open.ID, Valid: true}
+	}
+	record, err := q.CreatePlayerSubstitution(ctx, models.CreatePlayerSubstitutionParams{
+		PlayerID:         req.NewID,
+		PreviousPlayerID: req.OldID,
+		Reason:           req.Reason,
+		CycleHistoryID:   phase,
+		SubstitutedBy:    req.By,
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	player, err := q.GetPlayer(ctx, req.NewID)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Record: record, Player: player}
+
+	conf, confErr := q.GetPlayerConfessional(ctx, req.NewID)
+	hasConfessional := confErr == nil
+	if hasConfessional {
+		result.ChannelID = strconv.FormatInt(conf.ChannelID, 10)
+	}
+	newUser := strconv.FormatInt(req.NewID, 10)
+	granted := false
+	if sesh != nil && hasConfessional {
+		if err := sesh.ChannelPermissionSet(result.ChannelID, newUser, discordgo.PermissionOverwriteTypeMember, provision.ConfessionalAccess, 0); err != nil {
+			return Result{}, fmt.Errorf("grant confessional access: %w", err)
+		}
+		granted = true
+	}
+	if err := tx.Commit(ctx); err != nil {
+		if granted {
+			if err := sesh.ChannelPermissionDelete(result.ChannelID, newUser); err != nil {
+				logger.Get().Error().Err(err).Str("channel_id", result.ChannelID).Msg("confessional grant not reverted")
+			}
+		}
+		return Result{}, err
+	}
+
+	switch {
+	case sesh == nil:
+		result.Warnings = append(result.Warnings, "Discord is not connected; confessional permissions were not changed.")
+	case !hasConfessional:
+		result.Warnings = append(result.Warnings, "The player has no confessional to hand over.")
+	default:
+		result.Warnings = append(result.Warnings, s.handOver(sesh, result, req)...)
+	}
+	return result, nil
+}
+
+// handOver finishes the Discord side once the database is committed.
+func (s *Service) handOver(sesh *discordgo.Session, result Result, req Request) []string {
+	var warnings []string
+	if err := sesh.ChannelPermissionDelete(result.ChannelID, strconv.FormatInt(req.OldID, 10)); err != nil {
+		logger.Get().Error().Err(err).Str("channel_id", result.ChannelID).Msg("old player still sees confessional")
+		warnings = append(warnings, "The previous player could not be removed from the confessional.")
+	}
+	if user, err := sesh.User(strconv.FormatInt(req.NewID, 10)); err == nil {
+		if _, err := sesh.ChannelEdit(result.ChannelID, &discordgo.ChannelEdit{Name: provision.ConfessionalName(user.Username)}); err != nil {
+			logger.Get().Error().Err(err).Str("channel_id", result.ChannelID).Msg("confessional not renamed")
+			warnings = append(warnings, "The confessional could not be renamed.")
+		}
+	}
+	if err := inventory.NewManualInventoryHandler(result.Player, s.pool).UpdateInventoryMessage(sesh); err != nil {
+		logger.Get().Error().Err(err).Int64("player_id", req.NewID).Msg("inventory embed not rendered")
+		warnings = append(warnings, "The inventory message could not be re-rendered; run any /inv command to refresh it.")
+	}
+	if !lifeboard.RefreshQuietly(context.Background(), s.pool, sesh) {
+		warnings = append(warnings, "The lifeboard could not be refreshed.")
+	}
+	return warnings
+}
+
+// History lists the substitutions of the seat now held by playerID.
+func (s *Service) History(ctx context.Context, playerID int64) ([]models.PlayerSubstitution, error) {
+	return models.New(s.pool).ListPlayerSubstitutions(ctx, playerID)
+}
+
+// rekey copies the player row to newID, points every reference at it and
+// drops the old row.
+func rekey(ctx context.Context, tx pgx.Tx, oldID, newID int64) error {
+	if _, err := tx.Exec(ctx, `INSERT INTO player (id, role_id, alive, coins, coin_bonus, luck, item_limit, alignment)
+		SELECT $2, role_id, alive, coins, coin_bonus, luck, item_limit, alignment FROM player WHERE id = $1`, oldID, newID); err != nil {
+		return fmt.Errorf("copy player: %w", err)
+	}
+	for _, ref := range PlayerColumns {
+		stmt := "UPDATE " + ref.Table + " SET " + ref.Column + " = $2 WHERE " + ref.Column + " = $1"
+		if _, err := tx.Exec(ctx, stmt, oldID, newID); err != nil {
+			return fmt.Errorf("re-key %s.%s: %w", ref.Table, ref.Column, err)
+		}
+	}
+	if _, err := tx.Exec(ctx, "UPDATE whisper_transcript SET recipient_ids = array_replace(recipient_ids, $1, $2) WHERE $1 = ANY(recipient_ids)", oldID, newID); err != nil {
+		return fmt.Errorf("re-key whisper_transcript.recipient_ids: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "UPDATE player_note SET linked_player_ids = array_replace(linked_player_ids, $1, $2) WHERE $1 = ANY(linked_player_ids)", oldID, newID); err != nil {
+		return fmt.Errorf("re-key player_note.linked_player_ids: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM player WHERE id = $1", oldID); err != nil {
+		return fmt.Errorf("drop old player: %w", err)
+	}
+	return nil
+}
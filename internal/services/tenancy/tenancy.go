@@ -0,0 +1,364 @@
+// Package tenancy runs several games side by side, one per Discord guild.
+// The primary game lives in the public schema and is played by every guild
+// without a registered game. Each other game gets its own Postgres schema
+// holding the full table set, so players, cycle, channels, votes, whispers,
+// config and audit rows stay apart while every query stays unchanged: only
+// the pool differs.
+package tenancy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	dbmigrate "github.com/mccune1224/betrayal/internal/db/migrate"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+// CatalogTables are copied from the primary game into a new one, parents
+// first, so a test game can start without a sync.
+var CatalogTables = []string{
+	"category",
+	"status",
+	"role",
+	"role_setup",
+	"ability_info",
+	"perk_info",
+	"item",
+	"role_ability",
+	"role_perk",
+	"ability_category",
+	"item_category",
+	"sync_source",
+}
+
+// gamePoolConns caps each game pool; test games are small and share the
+// database's connection limit with the primary pool.
+const gamePoolConns = 4
+
+var (
+	ErrInvalidGuild = errors.New("guild IDs are numeric Discord snowflakes")
+	ErrInvalidName  = errors.New("a game needs a name")
+	ErrGameExists   = errors.New("this server already has its own game")
+	ErrNoGame       = errors.New("this server plays the primary game")
+	ErrNoDSN        = errors.New("no database URL to migrate the game schema with")
+)
+
+// Registry resolves guilds to games and keeps one pool per game schema.
+type Registry struct {
+	primary *pgxpool.Pool
+	dsn     string
+
+	mu       sync.Mutex
+	guilds   map[string]string // guild ID to schema; "" plays the primary game
+	pools    map[string]*pgxpool.Pool
+	onCreate []func(models.Game, *pgxpool.Pool)
+	onDelete []func(models.Game)
+}
+
+// NewRegistry returns a registry over primary. dsn is the URL primary was
+// built from; game pools and migrations derive from it.
+func NewRegistry(primary *pgxpool.Pool, dsn string) *Registry {
+	return &Registry{primary: primary, dsn: dsn, guilds: map[string]string{}, pools: map[string]*pgxpool.Pool{}}
+}
+
+// SchemaName derives the schema of guildID's game (pure, unit-testable).
+func SchemaName(guildID string) (string, error) {
+	if guildID == "" || strings.Trim(guildID, "0123456789") != "" {
+		return "", ErrInvalidGuild
+	}
+	return "game_" + guildID, nil
+}
+
+// Primary returns the pool of the primary game.
+func (r *Registry) Primary() *pgxpool.Pool {
+	return r.primary
+}
+
+// Games lists the registered games; the primary game is not among them.
+func (r *Registry) Games(ctx context.Context) ([]models.Game, error) {
+	return models.New(r.primary).ListGames(ctx)
+}
+
+// Pool returns the pool of the game guildID plays. Lookup failures fall back
+// to the primary game and are logged.
+func (r *Registry) Pool(ctx context.Context, guildID string) *pgxpool.Pool {
+	r.mu.Lock()
+	schema, known := r.guilds[guildID]
+	r.mu.Unlock()
+	if !known {
+		game, err := models.New(r.primary).GetGameByGuild(ctx, guildID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			logger.Get().Error().Err(err).Str("guild_id", guildID).Msg("game lookup failed; using the primary game")
+			return r.primary
+		}
+		schema = game.SchemaName
+		r.mu.Lock()
+		r.guilds[guildID] = schema
+		r.mu.Unlock()
+	}
+	if schema == "" {
+		return r.primary
+	}
+	pool, err := r.schemaPool(schema)
+	if err != nil {
+		logger.Get().Error().Err(err).Str("schema", schema).Msg("game pool unavailable; using the primary game")
+		return r.primary
+	}
+	return pool
+}
+
+// GamePool returns the pool of the game with id; 0 is the primary game.
+func (r *Registry) GamePool(ctx context.Context, id int64) (*pgxpool.Pool, error) {
+	if id == 0 {
+		return r.primary, nil
+	}
+	game, err := models.New(r.primary).GetGame(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.schemaPool(game.SchemaName)
+}
+
+// OnCreate registers fn to run once a new game is ready, e.g. to start its
+// cycle scheduler.
+func (r *Registry) OnCreate(fn func(models.Game, *pgxpool.Pool)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onCreate = append(r.onCreate, fn)
+}
+
+// OnDelete registers fn to run once a game is deleted, before its pool is
+// closed, e.g. to stop its cycle scheduler.
+func (r *Registry) OnDelete(fn func(models.Game)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onDelete = append(r.onDelete, fn)
+}
+
+// Create gives guildID its own game: a migrated schema seeded with the
+// primary game's catalog. The schema is dropped again if any step fails.
+func (r *Registry) Create(ctx context.Context, guildID, name string) (models.Game, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.Game{}, ErrInvalidName
+	}
+	schema, err := SchemaName(guildID)
+	if err != nil {
+		return models.Game{}, err
+	}
+	if r.dsn == "" {
+		return models.Game{}, ErrNoDSN
+	}
+	q := models.New(r.primary)
+	if _, err := q.GetGameByGuild(ctx, guildID); err == nil {
+		return models.Game{}, ErrGameExists
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return models.Game{}, err
+	}
+	ident := pgx.Identifier{schema}.Sanitize()
+	if _, err := r.primary.Exec(ctx, "CREATE SCHEMA "+ident); err != nil {
+		return models.Game{}, fmt.Errorf("create schema %s: %w", schema, err)
+	}
+	created := false
+	defer func() {
+		if created {
+			return
+		}
+		r.closePool(schema)
+		if _, err := r.primary.Exec(context.Background(), "DROP SCHEMA IF EXISTS "+ident+" CASCADE"); err != nil {
+			logger.Get().Error().Err(err).Str("schema", schema).Msg("orphaned game schema not dropped")
+		}
+	}()
+	dsn, err := dbmigrate.SchemaDSN(r.dsn, schema)
+	if err != nil {
+		return models.Game{}, err
+	}
+	if err := dbmigrate.EnsureUpToDate(dsn); err != nil {
+		return models.Game{}, err
+	}
+	pool, err := r.schemaPool(schema)
+	if err != nil {
+		return models.Game{}, err
+	}
+	if err := copyCatalog(ctx, pool, schema); err != nil {
+		return models.Game{}, fmt.Errorf("copy catalog: %w", err)
+	}
+	game, err := q.CreateGame(ctx, models.CreateGameParams{GuildID: guildID, Name: name, SchemaName: schema})
+	if err != nil {
+		return models.Game{}, err
+	}
+	created = true
+	r.mu.Lock()
+	r.guilds[guildID] = schema
+	hooks := append([]func(models.Game, *pgxpool.Pool){}, r.onCreate...)
+	r.mu.Unlock()
+	for _, fn := range hooks {
+		fn(game, pool)
+	}
+	return game, nil
+}
+
+// Delete drops guildID's game and everything in it; the guild goes back to
+// the primary game. The game's pool stays open until the drop has committed,
+// so a failed delete leaves the game fully working.
+func (r *Registry) Delete(ctx context.Context, guildID string) (models.Game, error) {
+	q := models.New(r.primary)
+	game, err := q.GetGameByGuild(ctx, guildID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Game{}, ErrNoGame
+	}
+	if err != nil {
+		return models.Game{}, err
+	}
+	tx, err := r.primary.Begin(ctx)
+	if err != nil {
+		return models.Game{}, err
+	}
+	defer tx.Rollback(ctx)
+	if err := models.New(tx).DeleteGame(ctx, game.ID); err != nil {
+		return models.Game{}, err
+	}
+	if _, err := tx.Exec(ctx, "DROP SCHEMA IF EXISTS "+pgx.Identifier{game.SchemaName}.Sanitize()+" CASCADE"); err != nil {
+		return models.Game{}, fmt.Errorf("drop schema %s: %w", game.SchemaName, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return models.Game{}, err
+	}
+	r.mu.Lock()
+	r.guilds[guildID] = ""
+	hooks := append([]func(models.Game){}, r.onDelete...)
+	r.mu.Unlock()
+	for _, fn := range hooks {
+		fn(game)
+	}
+	r.closePool(game.SchemaName)
+	return game, nil
+}
+
+// MigrateAll brings every game schema up to date; run it at startup after
+// the public schema.
+func (r *Registry) MigrateAll(ctx context.Context) error {
+	games, err := r.Games(ctx)
+	if err != nil {
+		return err
+	}
+	for _, game := range games {
+		dsn, err := dbmigrate.SchemaDSN(r.dsn, game.SchemaName)
+		if err != nil {
+			return err
+		}
+		if err := dbmigrate.EnsureUpToDate(dsn); err != nil {
+			return fmt.Errorf("migrate game %q: %w", game.Name, err)
+		}
+	}
+	return nil
+}
+
+// Close closes every game pool. The primary pool belongs to the caller.
+func (r *Registry) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for schema, pool := range r.pools {
+		pool.Close()
+		delete(r.pools, schema)
+	}
+}
+
+func (r *Registry) schemaPool(schema string) (*pgxpool.Pool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if pool, ok := r.pools[schema]; ok {
+		return pool, nil
+	}
+	dsn, err := dbmigrate.SchemaDSN(r.dsn, schema)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MaxConns = gamePoolConns
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	if err != nil {
+		return nil, err
+	}
+	r.pools[schema] = pool
+	return pool, nil
+}
+
+func (r *Registry) closePool(schema string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if pool, ok := r.pools[schema]; ok {
+		pool.Close()
+		delete(r.pools, schema)
+	}
+}
+
+// copyCatalog replaces the rows the migrations seeded in the new schema with
+// the primary game's catalog and moves the id sequences past them.
+func copyCatalog(ctx context.Context, pool *pgxpool.Pool, schema string) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	targets := make([]string, len(CatalogTables))
+	for i, table := range CatalogTables {
+		targets[i] = pgx.Identifier{schema, table}.Sanitize()
+	}
+	if _, err := tx.Exec(ctx, "TRUNCATE "+strings.Join(targets, ", ")+" RESTART IDENTITY CASCADE"); err != nil {
+		return err
+	}
+	for i, table := range CatalogTables {
+		if _, err := tx.Exec(ctx, "INSERT INTO "+targets[i]+" SELECT * FROM "+pgx.Identifier{"public", table}.Sanitize()); err != nil {
+			return fmt.Errorf("%s: %w", table, err)
+		}
+		rows, err := tx.Query(ctx, `SELECT column_name FROM information_schema.columns
+			WHERE table_schema = $1 AND table_name = $2 AND column_default LIKE 'nextval(%'`, schema, table)
+		if err != nil {
+			return err
+		}
+		columns, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return err
+		}
+		for _, column := range columns {
+			col := pgx.Identifier{column}.Sanitize()
+			stmt := "SELECT setval(pg_get_serial_sequence($1, $2), COALESCE(MAX(" + col + "), 0) + 1, false) FROM " + targets[i]
+			if _, err := tx.Exec(ctx, stmt, schema+"."+table, column); err != nil {
+				return fmt.Errorf("%s sequence: %w", table, err)
+			}
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+var current *Registry
+
+// Use makes r the registry PoolFor resolves against. main sets it once at
+// startup; without it every guild plays the primary game.
+func Use(r *Registry) {
+	current = r
+}
+
+// Current returns the registry set with Use, or nil.
+func Current() *Registry {
+	return current
+}
+
+// PoolFor returns the pool of the game guildID plays, or fallback when no
+// registry is in use. Commands call it with the interaction's guild.
+func PoolFor(guildID string, fallback *pgxpool.Pool) *pgxpool.Pool {
+	if current == nil || guildID == "" {
+		return fallback
+	}
+	return current.Pool(context.Background(), guildID)
+}
@@ -0,0 +1,18 @@
+package tenancy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaName(t *testing.T) {
+	schema, err := SchemaName("123456789012345678")
+	require.NoError(t, err)
+	require.Equal(t, "game_123456789012345678", schema)
+
+	for _, bad := range []string{"", "12a", "1; DROP SCHEMA public"} {
+		_, err := SchemaName(bad)
+		require.ErrorIs(t, err, ErrInvalidGuild, bad)
+	}
+}
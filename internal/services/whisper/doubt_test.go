@@ -2,6 +2,7 @@ package whisper
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -133,3 +134,61 @@ func TestDeliverNeverSwapsWithoutRecipientsOrRoller(t *testing.T) {
 		}
 	})
 }
+
+// chanceRoller records the chance Deliver asks about and always hits.
+type chanceRoller struct{ asked float64 }
+
+func (r *chanceRoller) Hit(chance float64) bool { r.asked = chance; return true }
+func (r *chanceRoller) Intn(int) int            { return 0 }
+
+// TestDeliverUsesGroupSuspicion verifies a group's own chance replaces the
+// global one and that garble mode corrupts a third of the words without
+// needing a pool.
+func TestDeliverUsesGroupSuspicion(t *testing.T) {
+	sender := &recordingSender{}
+	roller := &chanceRoller{}
+	result, err := Deliver(DeliveryRequest{
+		SenderChannelID:     "sender-channel",
+		RecipientChannelIDs: []string{"twin-channel"},
+		Message:             "meet me at the old well",
+		Suspicion:           &Suspicion{Chance: 0.4, Mode: DoubtGarble},
+	}, sender, roller, nil)
+	if err != nil {
+		t.Fatalf("Deliver returned error: %v", err)
+	}
+	if roller.asked != 0.4 {
+		t.Fatalf("rolled against %v, want the group's 0.4", roller.asked)
+	}
+	if !result.WarningSent {
+		t.Fatal("garble hit not reported")
+	}
+	want := "Your twin whispers:\n\n> ▒▒▒▒ ▒▒ at the old well\n\nA message passed quietly through the mirrors."
+	if len(sender.calls) != 1 || sender.calls[0].Content != want {
+		t.Fatalf("send calls = %#v, want %q", sender.calls, want)
+	}
+	if result.DeliveredMessage != want {
+		t.Fatalf("delivered message = %q, want the garbled text", result.DeliveredMessage)
+	}
+	if result.DeliveredText != "▒▒▒▒ ▒▒ at the old well" {
+		t.Fatalf("delivered text = %q, want the garbled words unframed", result.DeliveredText)
+	}
+}
+
+func TestGarbleKeepsShape(t *testing.T) {
+	message := "The door, is open.\nCome alone tonight!"
+	got := Garble(message, &cyclingRoller{})
+	if len([]rune(got)) != len([]rune(message)) {
+		t.Fatalf("Garble changed the length: %q", got)
+	}
+	if got == message {
+		t.Fatal("Garble corrupted nothing")
+	}
+	for _, keep := range []string{",", "\n", "!", "."} {
+		if !strings.Contains(got, keep) {
+			t.Fatalf("Garble dropped %q: %q", keep, got)
+		}
+	}
+	if Garble("", fixedRoller{}) != "" {
+		t.Fatal("Garble of an empty message must stay empty")
+	}
+}
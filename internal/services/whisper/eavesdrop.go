@@ -0,0 +1,128 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/mccune1224/betrayal/internal/logger"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/util"
+)
+
+// Eavesdrop describes a host-attached interception effect. Phases and
+// Messages of zero mean no limit of that kind.
+type Eavesdrop struct {
+	PlayerID int64
+	GroupID  int64
+	Redacted bool
+	// Phases ends the effect after that many cycle changes.
+	Phases int
+	// Messages ends the effect after that many intercepted whispers.
+	Messages int
+	By       string
+}
+
+var ErrEavesdropNotFound = errors.New("eavesdrop not found or already ended")
+
+// AddEavesdrop attaches an eavesdrop effect starting in the current phase.
+func (s *Service) AddEavesdrop(ctx context.Context, e Eavesdrop) (models.WhisperEavesdrop, error) {
+	if e.Phases < 0 || e.Messages < 0 {
+		return models.WhisperEavesdrop{}, errors.New("eavesdrop limits cannot be negative")
+	}
+	q := models.New(s.pool)
+	phaseID, _, err := openPhase(ctx, q)
+	if err != nil {
+		return models.WhisperEavesdrop{}, err
+	}
+	params := models.CreateWhisperEavesdropParams{
+		PlayerID:              e.PlayerID,
+		GroupID:               e.GroupID,
+		Redacted:              e.Redacted,
+		StartedCycleHistoryID: phaseID,
+		CreatedBy:             e.By,
+	}
+	if e.Phases > 0 {
+		params.Phases = pgtype.Int4{Int32: int32(e.Phases), Valid: true}
+	}
+	if e.Messages > 0 {
+		params.MessagesLeft = pgtype.Int4{Int32: int32(e.Messages), Valid: true}
+	}
+	return q.CreateWhisperEavesdrop(ctx, params)
+}
+
+// Eavesdrops lists the effects still running, optionally for one group.
+func (s *Service) Eavesdrops(ctx context.Context, groupID *int64) ([]models.ListActiveWhisperEavesdropsRow, error) {
+	var filter pgtype.Int8
+	if groupID != nil {
+		filter = pgtype.Int8{Int64: *groupID, Valid: true}
+	}
+	return models.New(s.pool).ListActiveWhisperEavesdrops(ctx, filter)
+}
+
+// EndEavesdrop stops an effect early.
+func (s *Service) EndEavesdrop(ctx context.Context, id int64) (models.WhisperEavesdrop, error) {
+	ended, err := models.New(s.pool).EndWhisperEavesdrop(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.WhisperEavesdrop{}, ErrEavesdropNotFound
+	}
+	return ended, err
+}
+
+// Intercept copies a whisper sent within groupID to every active eavesdropper
+// and returns how many copies went out. message is the text the group read
+// (DeliveryResult.DeliveredText), so eavesdroppers hear the same garbled or
+// doubt-replaced words and cannot tell the whisper was distorted. The group
+// is never told: members of the
+// group, dead eavesdroppers and eavesdroppers without a confessional are
+// skipped. Each copy counts against the effect's message limit.
+func (s *Service) Intercept(ctx context.Context, groupID int64, message string, sender Sender) (int, error) {
+	q := models.New(s.pool)
+	listeners, err := q.ListActiveWhisperEavesdrops(ctx, pgtype.Int8{Int64: groupID, Valid: true})
+	if err != nil || len(listeners) == 0 {
+		return 0, err
+	}
+	memberIDs, err := q.ListWhisperGroupMemberIDs(ctx, groupID)
+	if err != nil {
+		return 0, err
+	}
+	members := make(map[int64]bool, len(memberIDs))
+	for _, id := range memberIDs {
+		members[id] = true
+	}
+	sent := 0
+	var errs []error
+	for _, listener := range listeners {
+		if members[listener.PlayerID] || !listener.Alive || !listener.ChannelID.Valid {
+			continue
+		}
+		if err := sender.Send(util.Itoa64(listener.ChannelID.Int64), InterceptedCopy(message, listener.Redacted)); err != nil {
+			errs = append(errs, fmt.Errorf("eavesdrop %d: %w", listener.ID, err))
+			continue
+		}
+		sent++
+		if listener.MessagesLeft.Valid {
+			if err := q.ConsumeWhisperEavesdrop(ctx, listener.ID); err != nil {
+				logger.Get().Error().Err(err).Int64("eavesdrop_id", listener.ID).Msg("eavesdrop message count not updated")
+			}
+		}
+	}
+	return sent, errors.Join(errs...)
+}
+
+// InterceptedCopy renders what an eavesdropper receives.
+func InterceptedCopy(message string, redacted bool) string {
+	if redacted {
+		message = Redact(message)
+	}
+	return fmt.Sprintf("🕵️ You overhear a whisper passing through the mirrors:\n\n> %s", quoteMessage(message))
+}
+
+// Redact blacks out every second word so an eavesdropper catches only
+// fragments. Punctuation and line breaks survive.
+func Redact(message string) string {
+	words := wordPattern.FindAllStringIndex(message, -1)
+	return obscureWords(message, words, func(i int) bool { return i%2 == 1 }, func() rune { return '█' })
+}
@@ -0,0 +1,24 @@
+package whisper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactBlacksOutEverySecondWord(t *testing.T) {
+	got := Redact("meet me at the well, tonight")
+	if got != "meet ██ at ███ well, ███████" {
+		t.Fatalf("unexpected redaction %q", got)
+	}
+}
+
+func TestInterceptedCopyQuotesMessage(t *testing.T) {
+	full := InterceptedCopy("line one\nline two", false)
+	if !strings.Contains(full, "> line one\n> line two") {
+		t.Fatalf("copy must quote every line, got %q", full)
+	}
+	redacted := InterceptedCopy("line one", true)
+	if !strings.Contains(redacted, "> line ███") {
+		t.Fatalf("redacted copy must hide words, got %q", redacted)
+	}
+}
@@ -0,0 +1,300 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+// Scope decides who draws from a group's whisper allowance.
+type Scope string
+
+const (
+	// ScopePlayer gives every member of the group the full allowance.
+	ScopePlayer Scope = "player"
+	// ScopeGroup shares a single allowance across the whole group.
+	ScopeGroup Scope = "group"
+)
+
+// Adjustment kinds recorded by hosts.
+const (
+	AdjustmentGrant = "grant"
+	AdjustmentReset = "reset"
+)
+
+var (
+	ErrNotInGroup     = errors.New("player is not in a whisper group")
+	ErrInvalidAmount  = errors.New("amount must be positive")
+	ErrQuotaExhausted = errors.New("no whispers remain this phase")
+)
+
+// ParseScope accepts "player" or "group"; empty defaults to ScopePlayer.
+func ParseScope(raw string) (Scope, error) {
+	switch scope := Scope(strings.ToLower(strings.TrimSpace(raw))); scope {
+	case "":
+		return ScopePlayer, nil
+	case ScopePlayer, ScopeGroup:
+		return scope, nil
+	default:
+		return "", fmt.Errorf("unknown allowance scope %q (use player or group)", raw)
+	}
+}
+
+// Quota is a sender's whisper budget for the current phase. Unlimited groups
+// report Limited=false and the remaining fields are zero.
+type Quota struct {
+	Limited   bool
+	Scope     Scope
+	Allowance int
+	// Bonus comes from whisper_bonus items and perks held by the players
+	// sharing the allowance.
+	Bonus int
+	// Granted is the sum of host grants since the last reset.
+	Granted int
+	Used    int
+}
+
+// Total is every whisper available this phase.
+func (q Quota) Total() int {
+	return q.Allowance + q.Bonus + q.Granted
+}
+
+// Remaining is how many whispers are left, never negative. It is only
+// meaningful when Limited.
+func (q Quota) Remaining() int {
+	return max(q.Total()-q.Used, 0)
+}
+
+// Allows reports whether one more whisper may be sent.
+func (q Quota) Allows() bool {
+	return !q.Limited || q.Remaining() > 0
+}
+
+// TallyAdjustments applies host adjustments, oldest first, on top of a phase
+// that started at phaseStart. A reset moves the start of usage counting to
+// the reset and drops earlier grants; later grants add up. Under ScopePlayer
+// only whole-group rows and rows for playerID apply (pure, unit-testable).
+func TallyAdjustments(adjustments []models.WhisperQuotaAdjustment, scope Scope, playerID int64, phaseStart time.Time) (since time.Time, granted int) {
+	since = phaseStart
+	for _, adj := range adjustments {
+		if scope == ScopePlayer && adj.PlayerID.Valid && adj.PlayerID.Int64 != playerID {
+			continue
+		}
+		switch adj.Kind {
+		case AdjustmentReset:
+			if adj.CreatedAt.Time.After(since) {
+				since = adj.CreatedAt.Time
+			}
+			granted = 0
+		case AdjustmentGrant:
+			granted += int(adj.Amount)
+		}
+	}
+	return since, granted
+}
+
+// Quota loads the current-phase budget of playerID in groupID. Phases come
+// from cycle_history, so advancing the cycle starts a fresh allowance.
+func (s *Service) Quota(ctx context.Context, groupID, playerID int64) (Quota, error) {
+	return loadQuota(ctx, models.New(s.pool), groupID, playerID)
+}
+
+// Reservation is one whisper drawn from a sender's allowance before it is
+// delivered. ID is zero for unlimited groups, which record no usage.
+type Reservation struct {
+	ID    int64
+	Quota Quota
+}
+
+// Reserve draws one whisper from playerID's allowance, or fails with
+// ErrQuotaExhausted. The group row stays locked while the usage is counted
+// and recorded, so concurrent sends cannot both take the last whisper. The
+// returned Quota already includes the reserved whisper; Release it when the
+// delivery fails.
+func (s *Service) Reserve(ctx context.Context, groupID, playerID int64) (Reservation, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Reservation{}, err
+	}
+	defer tx.Rollback(ctx)
+	q := models.New(tx)
+	if _, err := q.LockWhisperGroup(ctx, groupID); err != nil {
+		return Reservation{}, err
+	}
+	quota, err := loadQuota(ctx, q, groupID, playerID)
+	if err != nil {
+		return Reservation{}, err
+	}
+	if !quota.Limited {
+		return Reservation{Quota: quota}, nil
+	}
+	if !quota.Allows() {
+		return Reservation{Quota: quota}, ErrQuotaExhausted
+	}
+	id, err := q.CreateWhisperUsage(ctx, models.CreateWhisperUsageParams{GroupID: groupID, SenderID: playerID})
+	if err != nil {
+		return Reservation{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Reservation{}, err
+	}
+	quota.Used++
+	return Reservation{ID: id, Quota: quota}, nil
+}
+
+// Release returns a reserved whisper whose delivery failed.
+func (s *Service) Release(ctx context.Context, r Reservation) error {
+	if r.ID == 0 {
+		return nil
+	}
+	return models.New(s.pool).DeleteWhisperUsage(ctx, r.ID)
+}
+
+func loadQuota(ctx context.Context, q *models.Queries, groupID, playerID int64) (Quota, error) {
+	group, err := q.GetWhisperGroup(ctx, groupID)
+	if err != nil {
+		return Quota{}, err
+	}
+	quota := Quota{Scope: Scope(group.AllowanceScope)}
+	if !group.WhisperAllowance.Valid {
+		return quota, nil
+	}
+	quota.Limited = true
+	quota.Allowance = int(group.WhisperAllowance.Int32)
+
+	phaseID, phaseStart, err := openPhase(ctx, q)
+	if err != nil {
+		return Quota{}, err
+	}
+	adjustments, err := q.ListWhisperQuotaAdjustments(ctx, models.ListWhisperQuotaAdjustmentsParams{GroupID: groupID, CycleHistoryID: phaseID})
+	if err != nil {
+		return Quota{}, err
+	}
+	since, granted := TallyAdjustments(adjustments, quota.Scope, playerID, phaseStart)
+	quota.Granted = granted
+
+	count := models.CountWhisperUsageSinceParams{GroupID: groupID, Since: pgtype.Timestamptz{Time: since, Valid: true}}
+	holders := []int64{playerID}
+	if quota.Scope == ScopeGroup {
+		if holders, err = q.ListWhisperGroupMemberIDs(ctx, groupID); err != nil {
+			return Quota{}, err
+		}
+	} else {
+		count.SenderID = pgtype.Int8{Int64: playerID, Valid: true}
+	}
+	used, err := q.CountWhisperUsageSince(ctx, count)
+	if err != nil {
+		return Quota{}, err
+	}
+	quota.Used = int(used)
+	bonus, err := q.SumWhisperBonus(ctx, holders)
+	if err != nil {
+		return Quota{}, err
+	}
+	quota.Bonus = int(bonus)
+	return quota, nil
+}
+
+// openPhase returns the cycle_history row of the current phase. Before any
+// phase is recorded every whisper ever sent counts.
+func openPhase(ctx context.Context, q *models.Queries) (pgtype.Int8, time.Time, error) {
+	phase, err := q.GetOpenCycleHistory(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return pgtype.Int8{}, time.Time{}, nil
+	}
+	if err != nil {
+		return pgtype.Int8{}, time.Time{}, err
+	}
+	return pgtype.Int8{Int64: phase.ID, Valid: true}, phase.StartedAt.Time, nil
+}
+
+// GroupOf returns the whisper group playerID belongs to.
+func (s *Service) GroupOf(ctx context.Context, playerID int64) (int64, error) {
+	groupID, err := models.New(s.pool).GetWhisperGroupIDByPlayer(ctx, playerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrNotInGroup
+	}
+	return groupID, err
+}
+
+// SetAllowance sets the per-phase allowance of a group; nil means unlimited.
+func (s *Service) SetAllowance(ctx context.Context, groupID int64, allowance *int, scope Scope) (models.WhisperGroup, error) {
+	params := models.SetWhisperGroupAllowanceParams{ID: groupID, AllowanceScope: string(scope)}
+	if allowance != nil {
+		if *allowance < 0 {
+			return models.WhisperGroup{}, errors.New("allowance cannot be negative")
+		}
+		params.WhisperAllowance = pgtype.Int4{Int32: int32(*allowance), Valid: true}
+	}
+	return models.New(s.pool).SetWhisperGroupAllowance(ctx, params)
+}
+
+// Grant adds extra whispers for the current phase. A nil playerID grants them
+// to the whole group.
+func (s *Service) Grant(ctx context.Context, groupID int64, playerID *int64, amount int, by string) (models.WhisperQuotaAdjustment, error) {
+	if amount <= 0 {
+		return models.WhisperQuotaAdjustment{}, ErrInvalidAmount
+	}
+	return s.adjust(ctx, groupID, playerID, AdjustmentGrant, amount, by)
+}
+
+// Reset restores the full allowance for the rest of the current phase by
+// forgetting whispers already sent and grants already made. A nil playerID
+// resets every member.
+func (s *Service) Reset(ctx context.Context, groupID int64, playerID *int64, by string) (models.WhisperQuotaAdjustment, error) {
+	return s.adjust(ctx, groupID, playerID, AdjustmentReset, 0, by)
+}
+
+func (s *Service) adjust(ctx context.Context, groupID int64, playerID *int64, kind string, amount int, by string) (models.WhisperQuotaAdjustment, error) {
+	q := models.New(s.pool)
+	phaseID, _, err := openPhase(ctx, q)
+	if err != nil {
+		return models.WhisperQuotaAdjustment{}, err
+	}
+	params := models.CreateWhisperQuotaAdjustmentParams{
+		GroupID:        groupID,
+		Kind:           kind,
+		Amount:         int32(amount),
+		CycleHistoryID: phaseID,
+		CreatedBy:      by,
+	}
+	if playerID != nil {
+		params.PlayerID = pgtype.Int8{Int64: *playerID, Valid: true}
+	}
+	return q.CreateWhisperQuotaAdjustment(ctx, params)
+}
+
+// Bonuses lists the items and perks that grant extra whispers.
+func (s *Service) Bonuses(ctx context.Context) ([]models.ListWhisperBonusesRow, error) {
+	return models.New(s.pool).ListWhisperBonuses(ctx)
+}
+
+// SetItemBonus makes each held copy of itemID worth extra whispers per phase.
+// An extra of zero or less removes the bonus.
+func (s *Service) SetItemBonus(ctx context.Context, itemID int32, extra int) error {
+	q := models.New(s.pool)
+	id := pgtype.Int4{Int32: itemID, Valid: true}
+	if extra <= 0 {
+		return q.DeleteWhisperItemBonus(ctx, id)
+	}
+	_, err := q.UpsertWhisperItemBonus(ctx, models.UpsertWhisperItemBonusParams{ItemID: id, Extra: int32(extra)})
+	return err
+}
+
+// SetPerkBonus makes holding perkID worth extra whispers per phase. An extra
+// of zero or less removes the bonus.
+func (s *Service) SetPerkBonus(ctx context.Context, perkID int32, extra int) error {
+	q := models.New(s.pool)
+	id := pgtype.Int4{Int32: perkID, Valid: true}
+	if extra <= 0 {
+		return q.DeleteWhisperPerkBonus(ctx, id)
+	}
+	_, err := q.UpsertWhisperPerkBonus(ctx, models.UpsertWhisperPerkBonusParams{PerkID: id, Extra: int32(extra)})
+	return err
+}
@@ -0,0 +1,57 @@
+package whisper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+func adjustment(kind string, playerID int64, amount int32, at time.Time) models.WhisperQuotaAdjustment {
+	adj := models.WhisperQuotaAdjustment{Kind: kind, Amount: amount, CreatedAt: pgtype.Timestamptz{Time: at, Valid: true}}
+	if playerID != 0 {
+		adj.PlayerID = pgtype.Int8{Int64: playerID, Valid: true}
+	}
+	return adj
+}
+
+func TestTallyAdjustments(t *testing.T) {
+	start := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	adjustments := []models.WhisperQuotaAdjustment{
+		adjustment(AdjustmentGrant, 0, 1, start.Add(time.Minute)),
+		adjustment(AdjustmentGrant, 2, 5, start.Add(2*time.Minute)),
+		adjustment(AdjustmentReset, 1, 0, start.Add(3*time.Minute)),
+		adjustment(AdjustmentGrant, 1, 2, start.Add(4*time.Minute)),
+	}
+
+	since, granted := TallyAdjustments(adjustments, ScopePlayer, 1, start)
+	if !since.Equal(start.Add(3*time.Minute)) || granted != 2 {
+		t.Fatalf("player 1: since=%v granted=%d, want reset time and only the later grant", since, granted)
+	}
+	since, granted = TallyAdjustments(adjustments, ScopePlayer, 2, start)
+	if !since.Equal(start) || granted != 6 {
+		t.Fatalf("player 2: since=%v granted=%d, want phase start and 1+5", since, granted)
+	}
+	since, granted = TallyAdjustments(adjustments, ScopeGroup, 2, start)
+	if !since.Equal(start.Add(3*time.Minute)) || granted != 2 {
+		t.Fatalf("group scope: since=%v granted=%d, want every row applied", since, granted)
+	}
+}
+
+func TestQuotaRemaining(t *testing.T) {
+	quota := Quota{Limited: true, Allowance: 2, Bonus: 1, Granted: 1, Used: 3}
+	if quota.Total() != 4 || quota.Remaining() != 1 || !quota.Allows() {
+		t.Fatalf("unexpected quota %+v", quota)
+	}
+	quota.Used = 6
+	if quota.Remaining() != 0 || quota.Allows() {
+		t.Fatal("an overspent quota must report zero and refuse")
+	}
+	if !(Quota{}).Allows() {
+		t.Fatal("an unlimited quota always allows")
+	}
+	if _, err := ParseScope("everyone"); err == nil {
+		t.Fatal("unknown scopes must be rejected")
+	}
+}
@@ -0,0 +1,75 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+// ParseDoubtMode accepts "replace" or "garble"; empty defaults to
+// DoubtReplace.
+func ParseDoubtMode(raw string) (DoubtMode, error) {
+	switch mode := DoubtMode(strings.ToLower(strings.TrimSpace(raw))); mode {
+	case "":
+		return DoubtReplace, nil
+	case DoubtReplace, DoubtGarble:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown doubt mode %q (use replace or garble)", raw)
+	}
+}
+
+// SuspicionOf reads a group's stored settings, falling back to the global
+// chance when the group has none.
+func SuspicionOf(group models.WhisperGroup) Suspicion {
+	suspicion := Suspicion{Chance: SuspicionChance, Mode: DoubtMode(group.DoubtMode)}
+	if group.SuspicionChance.Valid {
+		suspicion.Chance = group.SuspicionChance.Float64
+	}
+	if suspicion.Mode == "" {
+		suspicion.Mode = DoubtReplace
+	}
+	return suspicion
+}
+
+// Doubt returns the suspicion settings of groupID and the doubt pool its
+// whispers draw from: the group's own enabled messages when it has any,
+// otherwise the global pool.
+func (s *Service) Doubt(ctx context.Context, groupID int64) (Suspicion, []string, error) {
+	q := models.New(s.pool)
+	group, err := q.GetWhisperGroup(ctx, groupID)
+	if err != nil {
+		return Suspicion{}, nil, err
+	}
+	messages, err := q.ListEnabledWhisperGroupDoubtMessages(ctx, pgtype.Int8{Int64: groupID, Valid: true})
+	if err != nil {
+		return Suspicion{}, nil, err
+	}
+	if len(messages) == 0 {
+		if messages, err = q.ListEnabledWhisperDoubtMessages(ctx); err != nil {
+			return Suspicion{}, nil, err
+		}
+	}
+	pool := make([]string, 0, len(messages))
+	for _, message := range messages {
+		pool = append(pool, message.Message)
+	}
+	return SuspicionOf(group), pool, nil
+}
+
+// SetSuspicion stores a group's suspicion chance (0 to 1) and doubt mode. A
+// nil chance restores the global SuspicionChance.
+func (s *Service) SetSuspicion(ctx context.Context, groupID int64, chance *float64, mode DoubtMode) (models.WhisperGroup, error) {
+	params := models.SetWhisperGroupSuspicionParams{ID: groupID, DoubtMode: string(mode)}
+	if chance != nil {
+		if *chance < 0 || *chance > 1 {
+			return models.WhisperGroup{}, errors.New("suspicion chance must be between 0 and 1")
+		}
+		params.SuspicionChance = pgtype.Float8{Float64: *chance, Valid: true}
+	}
+	return models.New(s.pool).SetWhisperGroupSuspicion(ctx, params)
+}
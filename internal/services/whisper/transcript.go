@@ -0,0 +1,93 @@
+package whisper
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+// DefaultTranscriptLimit caps transcript listings when no limit is given.
+const DefaultTranscriptLimit = 50
+
+// Service persists whisper records. Delivery itself stays in the pure
+// functions above so it can be tested without a database.
+type Service struct {
+	pool *pgxpool.Pool
+}
+
+// New returns a whisper Service backed by pool.
+func New(pool *pgxpool.Pool) *Service {
+	return &Service{pool: pool}
+}
+
+// TranscriptEntry is one delivered whisper as stored for host review.
+type TranscriptEntry struct {
+	SenderID         int64
+	GroupID          int64
+	GroupName        string
+	OriginalMessage  string
+	DeliveredMessage string
+	WarningSent      bool
+	// RecipientIDs are the players whose confessional received the whisper.
+	RecipientIDs   []int64
+	DeadRecipients int
+	Cycle          models.GameCycle
+}
+
+// NewTranscriptEntry combines the resolved delivery and its result into the
+// stored record. Only recipients that were actually sent to are kept, so a
+// delivery that failed part-way is recorded truthfully.
+func NewTranscriptEntry(senderID int64, message string, delivery SenderDelivery, result DeliveryResult) TranscriptEntry {
+	delivered := len(result.DeliveredRecipientChannels)
+	if delivered > len(delivery.RecipientIDs) {
+		delivered = len(delivery.RecipientIDs)
+	}
+	return TranscriptEntry{
+		SenderID:         senderID,
+		OriginalMessage:  message,
+		DeliveredMessage: result.DeliveredMessage,
+		WarningSent:      result.WarningSent,
+		RecipientIDs:     append([]int64{}, delivery.RecipientIDs[:delivered]...),
+		DeadRecipients:   delivery.DeadRecipients,
+	}
+}
+
+// RecordTranscript stores entry.
+func (s *Service) RecordTranscript(ctx context.Context, entry TranscriptEntry) (models.WhisperTranscript, error) {
+	return models.New(s.pool).CreateWhisperTranscript(ctx, models.CreateWhisperTranscriptParams{
+		SenderID:           entry.SenderID,
+		GroupID:            entry.GroupID,
+		GroupName:          entry.GroupName,
+		OriginalMessage:    entry.OriginalMessage,
+		DeliveredMessage:   entry.DeliveredMessage,
+		WarningSent:        entry.WarningSent,
+		RecipientIds:       entry.RecipientIDs,
+		DeadRecipients:     int32(entry.DeadRecipients),
+		CycleDay:           entry.Cycle.Day,
+		CycleIsElimination: entry.Cycle.IsElimination,
+	})
+}
+
+// TranscriptFilter narrows a transcript listing; nil fields match everything.
+type TranscriptFilter struct {
+	GroupID  *int64
+	CycleDay *int32
+	Limit    int32
+}
+
+// Transcripts lists stored whispers, newest first.
+func (s *Service) Transcripts(ctx context.Context, filter TranscriptFilter) ([]models.WhisperTranscript, error) {
+	params := models.ListWhisperTranscriptsParams{RowLimit: filter.Limit}
+	if params.RowLimit <= 0 {
+		params.RowLimit = DefaultTranscriptLimit
+	}
+	if filter.GroupID != nil {
+		params.GroupID = pgtype.Int8{Int64: *filter.GroupID, Valid: true}
+	}
+	if filter.CycleDay != nil {
+		params.CycleDay = pgtype.Int4{Int32: *filter.CycleDay, Valid: true}
+	}
+	return models.New(s.pool).ListWhisperTranscripts(ctx, params)
+}
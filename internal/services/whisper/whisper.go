@@ -3,12 +3,33 @@ package whisper
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
+	"unicode"
 )
 
 const SuspicionChance = 0.05
 
+// DoubtMode decides what a suspicion hit does to a whisper.
+type DoubtMode string
+
+const (
+	// DoubtReplace swaps the whole whisper for a doubt message.
+	DoubtReplace DoubtMode = "replace"
+	// DoubtGarble corrupts some of the whisper's words instead.
+	DoubtGarble DoubtMode = "garble"
+)
+
+// Suspicion is how doubt strikes one whisper group.
+type Suspicion struct {
+	Chance float64
+	Mode   DoubtMode
+}
+
+// DefaultSuspicion applies to groups without their own settings.
+var DefaultSuspicion = Suspicion{Chance: SuspicionChance, Mode: DoubtReplace}
+
 var (
 	ErrSelfTarget       = errors.New("cannot whisper to yourself")
 	ErrDeadSender       = errors.New("dead players cannot whisper")
@@ -26,7 +47,9 @@ type PlayerConfessional struct {
 }
 
 type SenderDelivery struct {
-	ChannelIDs      []string
+	ChannelIDs []string
+	// RecipientIDs holds the player behind each entry of ChannelIDs.
+	RecipientIDs    []int64
 	GroupSize       int
 	AliveRecipients int
 	DeadRecipients  int
@@ -126,7 +149,7 @@ func ResolveSenderDelivery(senderID int64, confessionals []PlayerConfessional) (
 		return SenderDelivery{}, ErrIncompleteGroup
 	}
 	sort.Slice(members, func(i, j int) bool { return members[i].PlayerID < members[j].PlayerID })
-	delivery := SenderDelivery{ChannelIDs: make([]string, 0, len(members)-1), GroupSize: len(members)}
+	delivery := SenderDelivery{ChannelIDs: make([]string, 0, len(members)-1), RecipientIDs: make([]int64, 0, len(members)-1), GroupSize: len(members)}
 	seen := make(map[string]struct{}, len(members))
 	for _, member := range members {
 		if member.PlayerID == senderID {
@@ -148,6 +171,7 @@ func ResolveSenderDelivery(senderID int64, confessionals []PlayerConfessional) (
 		}
 		seen[member.ChannelID] = struct{}{}
 		delivery.ChannelIDs = append(delivery.ChannelIDs, member.ChannelID)
+		delivery.RecipientIDs = append(delivery.RecipientIDs, member.PlayerID)
 	}
 	return delivery, nil
 }
@@ -168,10 +192,17 @@ type DeliveryRequest struct {
 	GroupSize           int
 	AliveRecipients     int
 	DeadRecipients      int
+	// Suspicion overrides DefaultSuspicion for the sender's group.
+	Suspicion *Suspicion
 }
 
 type DeliveryResult struct {
-	WarningSent                bool
+	WarningSent bool
+	// DeliveredMessage is the text recipients actually received.
+	DeliveredMessage string
+	// DeliveredText is the whisper's words as recipients read them: the
+	// original, the garbled original or the doubt replacement, unframed.
+	DeliveredText              string
 	DeliveredRecipientChannels []string
 	SenderStatus               string
 }
@@ -190,12 +221,25 @@ func Deliver(req DeliveryRequest, sender Sender, roller Roller, warningPool []st
 		groupSize = len(req.RecipientChannelIDs) + 1
 	}
 	groupLabel := relationshipLabel(groupSize)
-	warningSent := len(req.RecipientChannelIDs) > 0 && roller != nil && len(warningPool) > 0 && roller.Hit(SuspicionChance)
-	primary := fmt.Sprintf("Your %s whispers:\n\n> %s\n\nA message passed quietly through the mirrors.", groupLabel, quoteMessage(req.Message))
+	suspicion := DefaultSuspicion
+	if req.Suspicion != nil {
+		suspicion = *req.Suspicion
+	}
+	// Garbling needs no pool; replacing does.
+	canDoubt := suspicion.Mode == DoubtGarble || len(warningPool) > 0
+	warningSent := len(req.RecipientChannelIDs) > 0 && roller != nil && canDoubt && roller.Hit(suspicion.Chance)
+	text := req.Message
+	primary := whisperBody(groupLabel, text)
 	if warningSent {
-		warning := warningPool[roller.Intn(len(warningPool))]
-		primary = warning
+		if suspicion.Mode == DoubtGarble {
+			text = Garble(req.Message, roller)
+			primary = whisperBody(groupLabel, text)
+		} else {
+			text = warningPool[roller.Intn(len(warningPool))]
+			primary = text
+		}
 	}
+	result.DeliveredMessage, result.DeliveredText = primary, text
 	for _, channelID := range req.RecipientChannelIDs {
 		if err := sender.Send(channelID, primary); err != nil {
 			return result, fmt.Errorf("send whisper to %s: %w", channelID, err)
@@ -225,6 +269,60 @@ func deadRecipientReceipt(groupLabel string, alive int) string {
 	return "Whisper sent.\n\nA crack runs through the triplet’s mirror. Your words reached the reflections that remain."
 }
 
+func whisperBody(groupLabel, message string) string {
+	return fmt.Sprintf("Your %s whispers:\n\n> %s\n\nA message passed quietly through the mirrors.", groupLabel, quoteMessage(message))
+}
+
+// garbleGlyphs replace the letters and digits of corrupted words.
+var garbleGlyphs = []rune("▒░▓")
+
+var wordPattern = regexp.MustCompile(`\S+`)
+
+// Garble corrupts about a third of the words in message, at least one, by
+// turning their letters and digits into static. Spacing, line breaks and
+// punctuation survive so the whisper keeps its shape.
+func Garble(message string, roller Roller) string {
+	words := wordPattern.FindAllStringIndex(message, -1)
+	if len(words) == 0 {
+		return message
+	}
+	count := max(1, len(words)/3)
+	chosen := make(map[int]bool, count)
+	for len(chosen) < count {
+		// Walk forward from the rolled index so a biased roller still ends.
+		i := roller.Intn(len(words))
+		for chosen[i] {
+			i = (i + 1) % len(words)
+		}
+		chosen[i] = true
+	}
+	return obscureWords(message, words, func(i int) bool { return chosen[i] }, func() rune {
+		return garbleGlyphs[roller.Intn(len(garbleGlyphs))]
+	})
+}
+
+// obscureWords replaces the letters and digits of the chosen word spans with
+// glyphs, leaving everything else in place.
+func obscureWords(message string, words [][]int, chosen func(int) bool, glyph func() rune) string {
+	var out strings.Builder
+	last := 0
+	for i, span := range words {
+		if !chosen(i) {
+			continue
+		}
+		out.WriteString(message[last:span[0]])
+		for _, r := range message[span[0]:span[1]] {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				r = glyph()
+			}
+			out.WriteRune(r)
+		}
+		last = span[1]
+	}
+	out.WriteString(message[last:])
+	return out.String()
+}
+
 func quoteMessage(message string) string {
 	return strings.ReplaceAll(message, "\n", "\n> ")
 }
@@ -58,6 +58,12 @@ func TestDeliverSendsAllRecipientsThenSenderReceiptAndOptionalWarning(t *testing
 	if result.SenderStatus != "Something blurred between intention and arrival. The mirrors did not carry your words as spoken." {
 		t.Fatalf("sender status = %q, want vague doubt status", result.SenderStatus)
 	}
+	if result.DeliveredMessage != "Keep your guard up." {
+		t.Fatalf("delivered message = %q, want the doubt replacement", result.DeliveredMessage)
+	}
+	if result.DeliveredText != "Keep your guard up." {
+		t.Fatalf("delivered text = %q, want the doubt replacement for eavesdroppers", result.DeliveredText)
+	}
 	want := []sendCall{
 		{ChannelID: "twin-1", Content: "Keep your guard up."},
 		{ChannelID: "twin-2", Content: "Keep your guard up."},
@@ -85,7 +91,7 @@ func TestResolveSenderRecipientsSendsToEveryLinkedMemberExceptSender(t *testing.
 func TestDeliverSendsOriginalMessageWhenDoubtDoesNotTrigger(t *testing.T) {
 	sender := &recordingSender{}
 
-	_, err := Deliver(DeliveryRequest{
+	result, err := Deliver(DeliveryRequest{
 		SenderChannelID:     "sender-channel",
 		RecipientChannelIDs: []string{"twin-channel"},
 		Message:             "The door is open.",
@@ -93,6 +99,9 @@ func TestDeliverSendsOriginalMessageWhenDoubtDoesNotTrigger(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Deliver returned error: %v", err)
 	}
+	if result.DeliveredText != "The door is open." {
+		t.Fatalf("delivered text = %q, want the original words", result.DeliveredText)
+	}
 	if len(sender.calls) != 1 {
 		t.Fatalf("sender calls = %#v, want only recipient delivery", sender.calls)
 	}
@@ -130,6 +139,9 @@ func TestResolveSenderDeliveryExcludesDeadRecipientsAndReportsStatus(t *testing.
 	if want := []string{"living-twin"}; !reflect.DeepEqual(got.ChannelIDs, want) {
 		t.Fatalf("ResolveSenderDelivery channels = %#v, want %#v", got.ChannelIDs, want)
 	}
+	if want := []int64{11}; !reflect.DeepEqual(got.RecipientIDs, want) {
+		t.Fatalf("ResolveSenderDelivery recipients = %#v, want %#v", got.RecipientIDs, want)
+	}
 	if got.GroupSize != 3 || got.AliveRecipients != 1 || got.DeadRecipients != 1 {
 		t.Fatalf("ResolveSenderDelivery status = %#v, want triplet with one alive and one dead recipient", got)
 	}
@@ -201,3 +213,20 @@ type fixedRoller struct{ hit bool }
 
 func (r fixedRoller) Hit(float64) bool { return r.hit }
 func (r fixedRoller) Intn(int) int     { return 0 }
+
+func TestNewTranscriptEntryKeepsOnlyDeliveredRecipients(t *testing.T) {
+	delivery := SenderDelivery{ChannelIDs: []string{"a", "b"}, RecipientIDs: []int64{11, 12}, DeadRecipients: 1}
+	result := DeliveryResult{DeliveredRecipientChannels: []string{"a"}, DeliveredMessage: "Keep your guard up.", WarningSent: true}
+
+	entry := NewTranscriptEntry(10, "The door is open.", delivery, result)
+
+	if !reflect.DeepEqual(entry.RecipientIDs, []int64{11}) {
+		t.Fatalf("recipients = %#v, want only the delivered twin", entry.RecipientIDs)
+	}
+	if entry.OriginalMessage != "The door is open." || entry.DeliveredMessage != "Keep your guard up." || !entry.WarningSent {
+		t.Fatalf("entry = %#v, want original, doubt text and warning flag preserved", entry)
+	}
+	if entry.SenderID != 10 || entry.DeadRecipients != 1 {
+		t.Fatalf("entry = %#v, want sender 10 with one dead recipient", entry)
+	}
+}
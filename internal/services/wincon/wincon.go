@@ -0,0 +1,441 @@
+// Package wincon decides when a side has won. Win conditions are data: each
+// alignment and any role may list conditions, any one of which wins, and
+// each condition is a set of checks over the living players. The death
+// pipeline evaluates them after every life-state change and alerts the hosts
+// when a condition is met or one death away.
+package wincon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/discord"
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+// ConfigKey names the game_config row holding the Rules as JSON.
+const ConfigKey = "win_conditions"
+
+// alertedConfigKey remembers the last alerted status so each change is
+// posted once.
+const alertedConfigKey = "win_conditions_last_alert"
+
+var (
+	ErrInvalidRules   = errors.New("invalid win conditions")
+	ErrNoAlertChannel = errors.New("no admin channel to alert (run /channel admin add)")
+)
+
+// Condition is met when every check it sets holds. The subject's players
+// must also be alive, or all dead when Dead is set.
+type Condition struct {
+	// Eliminate lists alignments that must have no living players besides
+	// the subject's own.
+	Eliminate []models.Alignment `json:"eliminate,omitempty"`
+	// Parity requires at least as many living subject players as everyone
+	// else alive.
+	Parity bool `json:"parity,omitempty"`
+	// MaxAlive, when positive, requires at most that many living players.
+	MaxAlive int `json:"max_alive,omitempty"`
+	// Dead requires every holder to be dead, as for a jester.
+	Dead bool `json:"dead,omitempty"`
+}
+
+func (c Condition) empty() bool {
+	return len(c.Eliminate) == 0 && !c.Parity && c.MaxAlive <= 0 && !c.Dead
+}
+
+// String describes the condition for hosts.
+func (c Condition) String() string {
+	var parts []string
+	for _, a := range c.Eliminate {
+		parts = append(parts, string(a)+" eliminated")
+	}
+	if c.Parity {
+		parts = append(parts, "parity with everyone else")
+	}
+	if c.MaxAlive > 0 {
+		parts = append(parts, fmt.Sprintf("at most %d alive", c.MaxAlive))
+	}
+	if c.Dead {
+		parts = append(parts, "dead")
+	} else {
+		parts = append(parts, "alive")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// Rules are the win conditions per alignment and per role name.
+type Rules struct {
+	Alignments map[models.Alignment][]Condition `json:"alignments"`
+	Roles      map[string][]Condition           `json:"roles"`
+}
+
+// DefaultRules are used until hosts store their own: good wins once evil is
+// gone and evil wins at parity. Neutral roles have no default.
+func DefaultRules() Rules {
+	return Rules{
+		Alignments: map[models.Alignment][]Condition{
+			models.AlignmentGOOD: {{Eliminate: []models.Alignment{models.AlignmentEVIL}}},
+			models.AlignmentEVIL: {{Parity: true}},
+		},
+		Roles: map[string][]Condition{},
+	}
+}
+
+// Validate rejects unknown alignments and conditions without checks.
+func (r Rules) Validate() error {
+	known := func(a models.Alignment) bool {
+		return a == models.AlignmentGOOD || a == models.AlignmentEVIL || a == models.AlignmentNEUTRAL
+	}
+	check := func(subject string, conds []Condition) error {
+		for i, c := range conds {
+			if c.empty() {
+				return fmt.Errorf("%w: %s condition %d has no checks", ErrInvalidRules, subject, i+1)
+			}
+			for _, a := range c.Eliminate {
+				if !known(a) {
+					return fmt.Errorf("%w: %s condition %d eliminates unknown alignment %q", ErrInvalidRules, subject, i+1, a)
+				}
+			}
+		}
+		return nil
+	}
+	for a, conds := range r.Alignments {
+		if !known(a) {
+			return fmt.Errorf("%w: unknown alignment %q", ErrInvalidRules, a)
+		}
+		if err := check(string(a), conds); err != nil {
+			return err
+		}
+	}
+	for role, conds := range r.Roles {
+		if strings.TrimSpace(role) == "" {
+			return fmt.Errorf("%w: empty role name", ErrInvalidRules)
+		}
+		if err := check(role, conds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Player is the part of a player the rules look at.
+type Player struct {
+	ID        int64
+	Alive     bool
+	Alignment models.Alignment
+	Role      string
+}
+
+type State string
+
+const (
+	StateMet      State = "met"
+	StateImminent State = "imminent"
+)
+
+// Outcome is a subject whose condition is met or one death away. Deciders
+// are the living players whose death would meet an imminent condition.
+type Outcome struct {
+	// Kind is "alignment" or "role".
+	Kind      string
+	Subject   string
+	State     State
+	Condition Condition
+	Deciders  []int64
+}
+
+type subject struct {
+	kind, name string
+	holds      func(Player) bool
+	conds      []Condition
+}
+
+// Evaluate returns the met and imminent outcomes, alignments first (pure,
+// unit-testable). A subject without players is skipped, and a met subject
+// is not also reported as imminent.
+func Evaluate(rules Rules, players []Player) []Outcome {
+	var outcomes []Outcome
+	for _, s := range subjects(rules) {
+		holders := 0
+		for _, p := range players {
+			if s.holds(p) {
+				holders++
+			}
+		}
+		if holders == 0 {
+			continue
+		}
+		if outcome, ok := evaluate(s, players); ok {
+			outcomes = append(outcomes, outcome)
+		}
+	}
+	return outcomes
+}
+
+func evaluate(s subject, players []Player) (Outcome, bool) {
+	for _, c := range s.conds {
+		if met(c, s.holds, players) {
+			return Outcome{Kind: s.kind, Subject: s.name, State: StateMet, Condition: c}, true
+		}
+	}
+	for _, c := range s.conds {
+		var deciders []int64
+		for i, p := range players {
+			if !p.Alive {
+				continue
+			}
+			after := append([]Player(nil), players...)
+			after[i].Alive = false
+			if met(c, s.holds, after) {
+				deciders = append(deciders, p.ID)
+			}
+		}
+		if len(deciders) > 0 {
+			return Outcome{Kind: s.kind, Subject: s.name, State: StateImminent, Condition: c, Deciders: deciders}, true
+		}
+	}
+	return Outcome{}, false
+}
+
+func subjects(rules Rules) []subject {
+	var out []subject
+	alignments := make([]string, 0, len(rules.Alignments))
+	for a := range rules.Alignments {
+		alignments = append(alignments, string(a))
+	}
+	sort.Strings(alignments)
+	for _, a := range alignments {
+		alignment := models.Alignment(a)
+		out = append(out, subject{
+			kind: "alignment", name: a, conds: rules.Alignments[alignment],
+			holds: func(p Player) bool { return p.Alignment == alignment },
+		})
+	}
+	roles := make([]string, 0, len(rules.Roles))
+	for r := range rules.Roles {
+		roles = append(roles, r)
+	}
+	sort.Strings(roles)
+	for _, r := range roles {
+		role := r
+		out = append(out, subject{
+			kind: "role", name: r, conds: rules.Roles[r],
+			holds: func(p Player) bool { return strings.EqualFold(p.Role, role) },
+		})
+	}
+	return out
+}
+
+func met(c Condition, holds func(Player) bool, players []Player) bool {
+	mine, others, alive := 0, 0, 0
+	eliminated := map[models.Alignment]bool{}
+	for _, a := range c.Eliminate {
+		eliminated[a] = true
+	}
+	for _, p := range players {
+		if !p.Alive {
+			continue
+		}
+		alive++
+		if holds(p) {
+			mine++
+			continue
+		}
+		others++
+		if eliminated[p.Alignment] {
+			return false
+		}
+	}
+	if c.Dead != (mine == 0) {
+		return false
+	}
+	if c.Parity && mine < others {
+		return false
+	}
+	return c.MaxAlive <= 0 || alive <= c.MaxAlive
+}
+
+type Service struct {
+	pool *pgxpool.Pool
+}
+
+func New(pool *pgxpool.Pool) *Service {
+	return &Service{pool: pool}
+}
+
+// Rules returns the stored rules, or DefaultRules.
+func (s *Service) Rules(ctx context.Context) (Rules, error) {
+	raw, err := models.New(s.pool).GetGameConfig(ctx, ConfigKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return DefaultRules(), nil
+	}
+	if err != nil {
+		return Rules{}, err
+	}
+	var rules Rules
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return Rules{}, fmt.Errorf("invalid %s config: %w", ConfigKey, err)
+	}
+	return rules, nil
+}
+
+// SetRules validates and stores rules.
+func (s *Service) SetRules(ctx context.Context, rules Rules) (Rules, error) {
+	if rules.Alignments == nil {
+		rules.Alignments = map[models.Alignment][]Condition{}
+	}
+	if rules.Roles == nil {
+		rules.Roles = map[string][]Condition{}
+	}
+	if err := rules.Validate(); err != nil {
+		return Rules{}, err
+	}
+	raw, err := json.Marshal(rules)
+	if err != nil {
+		return Rules{}, err
+	}
+	_, err = models.New(s.pool).UpsertGameConfig(ctx, models.UpsertGameConfigParams{Key: ConfigKey, Value: string(raw)})
+	return rules, err
+}
+
+// Players loads every player with their role name.
+func (s *Service) Players(ctx context.Context) ([]Player, error) {
+	q := models.New(s.pool)
+	rows, err := q.ListPlayer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roles := map[int32]string{}
+	players := make([]Player, 0, len(rows))
+	for _, row := range rows {
+		p := Player{ID: row.ID, Alive: row.Alive, Alignment: row.Alignment}
+		if row.RoleID.Valid {
+			name, ok := roles[row.RoleID.Int32]
+			if !ok {
+				if role, err := q.GetRole(ctx, row.RoleID.Int32); err == nil {
+					name = role.Name
+				}
+				roles[row.RoleID.Int32] = name
+			}
+			p.Role = name
+		}
+		players = append(players, p)
+	}
+	return players, nil
+}
+
+// Status is the current evaluation.
+type Status struct {
+	Players  []Player
+	Outcomes []Outcome
+}
+
+// Status evaluates the stored rules against the current players.
+func (s *Service) Status(ctx context.Context) (Status, error) {
+	rules, err := s.Rules(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+	players, err := s.Players(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+	return Status{Players: players, Outcomes: Evaluate(rules, players)}, nil
+}
+
+// Alert posts the status to the first admin channel when it differs from
+// the last alert. It reports whether a message was posted.
+func (s *Service) Alert(ctx context.Context, sesh *discordgo.Session) (bool, error) {
+	status, err := s.Status(ctx)
+	if err != nil {
+		return false, err
+	}
+	q := models.New(s.pool)
+	signature := status.signature()
+	if last, err := q.GetGameConfig(ctx, alertedConfigKey); err == nil && last == signature {
+		return false, nil
+	}
+	if len(status.Outcomes) > 0 {
+		channels, err := q.ListAdminChannel(ctx)
+		if err != nil {
+			return false, err
+		}
+		if len(channels) == 0 {
+			return false, ErrNoAlertChannel
+		}
+		if _, err := sesh.ChannelMessageSendEmbed(channels[0], Embed(status)); err != nil {
+			return false, err
+		}
+	}
+	_, err = q.UpsertGameConfig(ctx, models.UpsertGameConfigParams{Key: alertedConfigKey, Value: signature})
+	return len(status.Outcomes) > 0, err
+}
+
+func (s Status) signature() string {
+	parts := make([]string, len(s.Outcomes))
+	for i, o := range s.Outcomes {
+		parts[i] = o.Kind + ":" + o.Subject + ":" + string(o.State)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Tally counts living and total players per alignment.
+func (s Status) Tally() map[models.Alignment][2]int {
+	tally := map[models.Alignment][2]int{}
+	for _, p := range s.Players {
+		t := tally[p.Alignment]
+		if p.Alive {
+			t[0]++
+		}
+		t[1]++
+		tally[p.Alignment] = t
+	}
+	return tally
+}
+
+// Embed renders the status for hosts.
+func Embed(status Status) *discordgo.MessageEmbed {
+	tally := status.Tally()
+	var counts []string
+	for _, a := range []models.Alignment{models.AlignmentGOOD, models.AlignmentEVIL, models.AlignmentNEUTRAL} {
+		if t, ok := tally[a]; ok {
+			counts = append(counts, fmt.Sprintf("%s %d/%d", a, t[0], t[1]))
+		}
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       "Game Status",
+		Description: "Alive: " + strings.Join(counts, " · "),
+		Color:       discord.ColorThemeOrange,
+	}
+	if len(counts) == 0 {
+		embed.Description = "No players."
+	}
+	for _, o := range status.Outcomes {
+		field := &discordgo.MessageEmbedField{Value: "Condition: " + o.Condition.String()}
+		if o.State == StateMet {
+			field.Name = fmt.Sprintf("%s has won", o.Subject)
+			embed.Color = discord.ColorThemeGreen
+		} else {
+			field.Name = fmt.Sprintf("%s is one death from winning", o.Subject)
+			mentions := make([]string, len(o.Deciders))
+			for i, id := range o.Deciders {
+				mentions[i] = discord.MentionUser(strconv.FormatInt(id, 10))
+			}
+			field.Value += "\nDecided by the death of " + strings.Join(mentions, ", ")
+		}
+		embed.Fields = append(embed.Fields, field)
+	}
+	if len(status.Outcomes) == 0 && len(counts) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "No win condition is met or imminent", Value: "Nobody is one death from winning."})
+	}
+	return embed
+}
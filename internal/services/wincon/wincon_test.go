@@ -0,0 +1,55 @@
+package wincon
+
+import (
+	"testing"
+
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/stretchr/testify/require"
+)
+
+func roster(alive ...bool) []Player {
+	alignments := []models.Alignment{models.AlignmentGOOD, models.AlignmentGOOD, models.AlignmentGOOD, models.AlignmentEVIL, models.AlignmentNEUTRAL}
+	players := make([]Player, len(alignments))
+	for i, a := range alignments {
+		players[i] = Player{ID: int64(i + 1), Alive: alive[i], Alignment: a}
+	}
+	players[4].Role = "Jester"
+	return players
+}
+
+func TestEvaluateDefaultRules(t *testing.T) {
+	// With one evil player left, good is always one death away.
+	outcomes := Evaluate(DefaultRules(), roster(true, true, true, true, true))
+	require.Len(t, outcomes, 1)
+	require.Equal(t, "GOOD", outcomes[0].Subject)
+
+	// Evil at 1 vs 2 others: killing either leaves parity.
+	outcomes = Evaluate(DefaultRules(), roster(true, false, false, true, true))
+	require.Len(t, outcomes, 2)
+	require.Equal(t, "EVIL", outcomes[0].Subject)
+	require.Equal(t, StateImminent, outcomes[0].State)
+	require.Equal(t, []int64{1, 5}, outcomes[0].Deciders)
+	require.Equal(t, "GOOD", outcomes[1].Subject)
+	require.Equal(t, []int64{4}, outcomes[1].Deciders)
+
+	outcomes = Evaluate(DefaultRules(), roster(true, true, false, false, true))
+	require.Len(t, outcomes, 1)
+	require.Equal(t, Outcome{Kind: "alignment", Subject: "GOOD", State: StateMet, Condition: DefaultRules().Alignments[models.AlignmentGOOD][0]}, outcomes[0])
+}
+
+func TestEvaluateRoleConditions(t *testing.T) {
+	rules := Rules{Roles: map[string][]Condition{
+		"jester":   {{Dead: true}},
+		"Survivor": {{MaxAlive: 2}},
+	}}
+	outcomes := Evaluate(rules, roster(true, true, true, true, false))
+	require.Len(t, outcomes, 1)
+	require.Equal(t, "role", outcomes[0].Kind)
+	require.Equal(t, StateMet, outcomes[0].State)
+}
+
+func TestValidateRejectsEmptyConditions(t *testing.T) {
+	require.ErrorIs(t, Rules{Roles: map[string][]Condition{"Jester": {{}}}}.Validate(), ErrInvalidRules)
+	require.ErrorIs(t, Rules{Alignments: map[models.Alignment][]Condition{"CHAOS": {{Parity: true}}}}.Validate(), ErrInvalidRules)
+	require.NoError(t, DefaultRules().Validate())
+}
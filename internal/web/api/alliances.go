@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/labstack/echo/v4"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/alliance"
+)
+
+// AlliancesHandler shows hosts who was allied with whom over the game.
+type AlliancesHandler struct {
+	pool    *pgxpool.Pool
+	discord *discordgo.Session
+}
+
+func NewAlliancesHandler(pool *pgxpool.Pool, discord *discordgo.Session) *AlliancesHandler {
+	return &AlliancesHandler{pool: pool, discord: discord}
+}
+
+type AllianceMemberDTO struct {
+	PlayerID  string     `json:"player_id"`
+	Status    string     `json:"status"`
+	InvitedBy string     `json:"invited_by,omitempty"`
+	Day       *int32     `json:"day"`
+	CreatedAt *time.Time `json:"created_at"`
+	JoinedAt  *time.Time `json:"joined_at"`
+	LeftAt    *time.Time `json:"left_at"`
+}
+
+type AllianceDTO struct {
+	ID        int64               `json:"id"`
+	Name      string              `json:"name"`
+	Status    string              `json:"status"`
+	ChannelID string              `json:"channel_id"`
+	CreatedBy string              `json:"created_by,omitempty"`
+	CreatedAt *time.Time          `json:"created_at"`
+	ClosedAt  *time.Time          `json:"closed_at"`
+	Members   []AllianceMemberDTO `json:"members"`
+}
+
+type allianceApprovalDTO struct {
+	Enabled bool `json:"enabled"`
+}
+
+type allianceApprovalInput struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// List returns every alliance, oldest first, with each membership it ever
+// had. Day is the cycle day the membership started in, when known.
+func (h *AlliancesHandler) List(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	history, err := alliance.New(h.pool, h.discord).History(ctx)
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "alliances_unavailable", "could not load the alliances", nil)
+		return nil
+	}
+	q := models.New(h.pool)
+	days := map[int64]int32{}
+	if phases, err := q.ListCycleHistory(ctx, 1000); err == nil {
+		for _, phase := range phases {
+			days[phase.ID] = phase.Day
+		}
+	}
+	out := make([]AllianceDTO, 0, len(history))
+	for _, entry := range history {
+		a := entry.Alliance
+		dto := AllianceDTO{
+			ID: a.ID, Name: a.Name, Status: a.Status, ChannelID: a.ChannelID, CreatedBy: playerRef(a.CreatedBy),
+			CreatedAt: nullableTimestamptz(a.CreatedAt), ClosedAt: nullableTimestamptz(a.ClosedAt),
+			Members: make([]AllianceMemberDTO, 0, len(entry.Members)),
+		}
+		for _, m := range entry.Members {
+			member := AllianceMemberDTO{
+				PlayerID: strconv.FormatInt(m.PlayerID, 10), Status: m.Status,
+				InvitedBy: playerRef(m.InvitedBy), CreatedAt: nullableTimestamptz(m.CreatedAt),
+				JoinedAt: nullableTimestamptz(m.JoinedAt), LeftAt: nullableTimestamptz(m.LeftAt),
+			}
+			if day, ok := days[m.CycleHistoryID.Int64]; ok && m.CycleHistoryID.Valid {
+				member.Day = &day
+			}
+			dto.Members = append(dto.Members, member)
+		}
+		out = append(out, dto)
+	}
+	WriteJSON(c.Response(), http.StatusOK, out)
+	return nil
+}
+
+// Approval reports whether new alliances and joins wait for a host.
+func (h *AlliancesHandler) Approval(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	WriteJSON(c.Response(), http.StatusOK, allianceApprovalDTO{Enabled: alliance.Approval(ctx, models.New(h.pool))})
+	return nil
+}
+
+// SetApproval turns host approval of alliances on or off.
+func (h *AlliancesHandler) SetApproval(c echo.Context) error {
+	var in allianceApprovalInput
+	if decodeJSON(c, &in) != nil {
+		return nil
+	}
+	if in.Enabled == nil {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_alliance_approval", "enabled is required", nil)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	if err := alliance.SetApproval(ctx, models.New(h.pool), *in.Enabled); err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "alliance_approval_unavailable", "could not save the alliance approval setting", nil)
+		return nil
+	}
+	WriteJSON(c.Response(), http.StatusOK, allianceApprovalDTO{Enabled: *in.Enabled})
+	return nil
+}
+
+func playerRef(id pgtype.Int8) string {
+	if !id.Valid {
+		return ""
+	}
+	return strconv.FormatInt(id.Int64, 10)
+}
@@ -4,6 +4,8 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/labstack/echo/v4"
 )
 
 // ErrorBody is the canonical shape for API errors.
@@ -27,6 +29,16 @@ func WriteError(w http.ResponseWriter, status int, code, message string, fields
 	}{Error: ErrorBody{Code: code, Message: message, Fields: fields}})
 }
 
+// decodeJSON decodes the request body into dst and writes the 400 itself when
+// the body is not valid JSON; handlers return nil on error.
+func decodeJSON(c echo.Context, dst any) error {
+	if err := json.NewDecoder(c.Request().Body).Decode(dst); err != nil {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_json", "request body must be valid JSON", nil)
+		return err
+	}
+	return nil
+}
+
 // Health reports that the API shell is available.
 func Health(w http.ResponseWriter, r *http.Request) {
 	WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
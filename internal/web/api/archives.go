@@ -0,0 +1,208 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/labstack/echo/v4"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/archive"
+	"github.com/mccune1224/betrayal/internal/services/gameimport"
+)
+
+// ArchivesHandler lists, browses and downloads game archives. Archives are
+// written before every reset or on demand, and never edited. Imports go
+// through one shared importer so concurrent requests run one at a time.
+type ArchivesHandler struct {
+	pool     *pgxpool.Pool
+	importer *gameimport.Service
+}
+
+func NewArchivesHandler(pool *pgxpool.Pool, importer *gameimport.Service) *ArchivesHandler {
+	return &ArchivesHandler{pool: pool, importer: importer}
+}
+
+type ArchiveDTO struct {
+	ID        int64            `json:"id"`
+	Version   int32            `json:"version"`
+	Label     string           `json:"label"`
+	Counts    map[string]int64 `json:"counts"`
+	CreatedAt *time.Time       `json:"created_at"`
+}
+
+type archiveCreateInput struct {
+	Label string `json:"label"`
+}
+
+// ImportConfirmation must be typed to commit an import, like the reset's.
+const ImportConfirmation = "IMPORT BETRAYAL GAME"
+
+// ImportRequest restores either a stored archive or an uploaded bundle.
+// Without DryRun it needs the same explicit confirmation as a reset.
+type ImportRequest struct {
+	ArchiveID  int64           `json:"archive_id"`
+	Bundle     json.RawMessage `json:"bundle"`
+	DryRun     bool            `json:"dry_run"`
+	Confirm    string          `json:"confirm"`
+	Understand bool            `json:"understand"`
+}
+
+// List returns every archive, newest first, without the bundles.
+func (h *ArchivesHandler) List(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	rows, err := models.New(h.pool).ListGameArchives(ctx)
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "archives_unavailable", "could not load the archives", nil)
+		return nil
+	}
+	out := make([]ArchiveDTO, 0, len(rows))
+	for _, a := range rows {
+		out = append(out, ArchiveDTO{ID: a.ID, Version: a.Version, Label: a.Label, Counts: archive.Counts(a.Counts), CreatedAt: nullableTimestamptz(a.CreatedAt)})
+	}
+	WriteJSON(c.Response(), http.StatusOK, out)
+	return nil
+}
+
+// Create archives the game as it is now, without resetting anything.
+func (h *ArchivesHandler) Create(c echo.Context) error {
+	var in archiveCreateInput
+	if decodeJSON(c, &in) != nil {
+		return nil
+	}
+	label := strings.TrimSpace(in.Label)
+	if label == "" {
+		label = "Manual archive " + time.Now().UTC().Format("2006-01-02 15:04 MST")
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 60*time.Second)
+	defer cancel()
+	a, err := archive.New(h.pool).Create(ctx, label)
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "archive_failed", "could not archive the game", nil)
+		return nil
+	}
+	WriteJSON(c.Response(), http.StatusCreated, ArchiveDTO{ID: a.ID, Version: a.Version, Label: a.Label, Counts: archive.Counts(a.Counts), CreatedAt: nullableTimestamptz(a.CreatedAt)})
+	return nil
+}
+
+// Get returns one archive's summary, or with ?table= the archived rows of
+// that table.
+func (h *ArchivesHandler) Get(c echo.Context) error {
+	a, ok := h.load(c)
+	if !ok {
+		return nil
+	}
+	table := c.QueryParam("table")
+	if table == "" {
+		WriteJSON(c.Response(), http.StatusOK, ArchiveDTO{ID: a.ID, Version: a.Version, Label: a.Label, Counts: archive.Counts(a.Counts), CreatedAt: nullableTimestamptz(a.CreatedAt)})
+		return nil
+	}
+	rows, err := archive.Table(a, table)
+	if errors.Is(err, archive.ErrUnknownTable) {
+		WriteError(c.Response(), http.StatusNotFound, "archive_table_not_found", err.Error(), nil)
+		return nil
+	}
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "archive_unreadable", "could not read the archive", nil)
+		return nil
+	}
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Response().WriteHeader(http.StatusOK)
+	_, _ = c.Response().Write(rows)
+	return nil
+}
+
+// Download serves the full bundle as a JSON attachment.
+func (h *ArchivesHandler) Download(c echo.Context) error {
+	a, ok := h.load(c)
+	if !ok {
+		return nil
+	}
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="betrayal-archive-%d.json"`, a.ID))
+	c.Response().WriteHeader(http.StatusOK)
+	_, _ = c.Response().Write(a.Bundle)
+	return nil
+}
+
+// Import loads a bundle into this game, which must have no players, votes or
+// whisper groups. The response is the import report; 422 when it has errors.
+func (h *ArchivesHandler) Import(c echo.Context) error {
+	var req ImportRequest
+	if decodeJSON(c, &req) != nil {
+		return nil
+	}
+	if !req.DryRun && (req.Confirm != ImportConfirmation || !req.Understand) {
+		WriteError(c.Response(), http.StatusBadRequest, "confirmation_required", "type "+ImportConfirmation+" and acknowledge the import, or set dry_run", nil)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 120*time.Second)
+	defer cancel()
+	var raw []byte
+	switch {
+	case req.ArchiveID != 0 && len(req.Bundle) != 0:
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_import", "send archive_id or bundle, not both", nil)
+		return nil
+	case req.ArchiveID != 0:
+		a, err := models.New(h.pool).GetGameArchive(ctx, req.ArchiveID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			WriteError(c.Response(), http.StatusNotFound, "archive_not_found", "archive not found", nil)
+			return nil
+		}
+		if err != nil {
+			WriteError(c.Response(), http.StatusInternalServerError, "archives_unavailable", "could not load the archive", nil)
+			return nil
+		}
+		raw = a.Bundle
+	case len(req.Bundle) != 0:
+		raw = req.Bundle
+	default:
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_import", "archive_id or bundle is required", nil)
+		return nil
+	}
+	bundle, err := gameimport.Decode(bytes.NewReader(raw))
+	if err != nil {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_bundle", err.Error(), nil)
+		return nil
+	}
+	report, err := h.importer.Import(ctx, bundle, req.DryRun)
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "import_failed", "import cancelled; no database changes were committed", nil)
+		return nil
+	}
+	status := http.StatusOK
+	if len(report.Errors) > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	WriteJSON(c.Response(), status, report)
+	return nil
+}
+
+func (h *ArchivesHandler) load(c echo.Context) (models.GameArchive, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_archive", "id must be numeric", nil)
+		return models.GameArchive{}, false
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 30*time.Second)
+	defer cancel()
+	a, err := models.New(h.pool).GetGameArchive(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		WriteError(c.Response(), http.StatusNotFound, "archive_not_found", "archive not found", nil)
+		return models.GameArchive{}, false
+	}
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "archives_unavailable", "could not load the archive", nil)
+		return models.GameArchive{}, false
+	}
+	return a, true
+}
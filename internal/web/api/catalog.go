@@ -12,6 +12,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
 	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/provision"
 )
 
 // CatalogHandler exposes catalog records as versioned JSON DTOs. sqlc models
@@ -41,6 +42,7 @@ type catalogRoleDTO struct {
 	Alignment   string              `json:"alignment"`
 	Abilities   []catalogAbilityDTO `json:"abilities"`
 	Perks       []catalogPerkDTO    `json:"perks"`
+	Setup       provision.Setup     `json:"setup"`
 }
 type catalogItemDTO struct {
 	ID          int32    `json:"id"`
@@ -62,9 +64,10 @@ type catalogCategoryDTO struct {
 }
 
 type catalogRoleInput struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Alignment   string `json:"alignment"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Alignment   string           `json:"alignment"`
+	Setup       *provision.Setup `json:"setup"`
 }
 type catalogItemInput struct {
 	Name        string `json:"name"`
@@ -142,6 +145,7 @@ func (h *CatalogHandler) roleDTO(ctx context.Context, r models.Role, abilities [
 	for _, p := range perks {
 		d.Perks = append(d.Perks, catalogPerkDTO{p.ID, p.Name, p.Description})
 	}
+	d.Setup, _ = provision.GetSetup(ctx, models.New(h.pool), r.Name)
 	return d
 }
 func (h *CatalogHandler) abilityDTO(ctx context.Context, a models.AbilityInfo) catalogAbilityDTO {
@@ -217,10 +221,22 @@ func (h *CatalogHandler) CreateRole(c echo.Context) error {
 	}
 	ctx, cancel := catalogContext(c)
 	defer cancel()
-	r, err := models.New(h.pool).CreateRole(ctx, models.CreateRoleParams{Name: in.Name, Description: in.Description, Alignment: a})
+	tx, err := h.pool.Begin(ctx)
+	if err != nil {
+		return catalogFailure(c, "role_create_failed")
+	}
+	defer tx.Rollback(ctx)
+	tq := models.New(tx)
+	r, err := tq.CreateRole(ctx, models.CreateRoleParams{Name: in.Name, Description: in.Description, Alignment: a})
 	if err != nil {
 		return catalogFailure(c, "role_create_failed")
 	}
+	if in.Setup != nil && !saveRoleSetup(ctx, c, tq, r.Name, *in.Setup) {
+		return nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return catalogFailure(c, "role_create_failed")
+	}
 	WriteJSON(c.Response(), http.StatusCreated, h.roleDTO(ctx, r, nil, nil))
 	return nil
 }
@@ -239,7 +255,8 @@ func (h *CatalogHandler) UpdateRole(c echo.Context) error {
 	}
 	ctx, cancel := catalogContext(c)
 	defer cancel()
-	r, err := models.New(h.pool).UpdateRole(ctx, models.UpdateRoleParams{ID: id, Name: in.Name, Description: in.Description, Alignment: a})
+	q := models.New(h.pool)
+	old, err := q.GetRole(ctx, id)
 	if err != nil {
 		if errors.Is(err, context.Canceled) {
 			return catalogFailure(c, "role_update_failed")
@@ -247,6 +264,29 @@ func (h *CatalogHandler) UpdateRole(c echo.Context) error {
 		WriteError(c.Response(), http.StatusNotFound, "role_not_found", "role not found", nil)
 		return nil
 	}
+	// The setup is keyed by role name, so it follows a rename in the same
+	// transaction as the role and any replacement setup.
+	tx, err := h.pool.Begin(ctx)
+	if err != nil {
+		return catalogFailure(c, "role_update_failed")
+	}
+	defer tx.Rollback(ctx)
+	tq := models.New(tx)
+	r, err := tq.UpdateRole(ctx, models.UpdateRoleParams{ID: id, Name: in.Name, Description: in.Description, Alignment: a})
+	if err != nil {
+		return catalogFailure(c, "role_update_failed")
+	}
+	if provision.SetupKey(old.Name) != provision.SetupKey(r.Name) {
+		if err := tq.RenameRoleSetup(ctx, models.RenameRoleSetupParams{NewName: provision.SetupKey(r.Name), OldName: provision.SetupKey(old.Name)}); err != nil {
+			return catalogFailure(c, "role_update_failed")
+		}
+	}
+	if in.Setup != nil && !saveRoleSetup(ctx, c, tq, r.Name, *in.Setup) {
+		return nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return catalogFailure(c, "role_update_failed")
+	}
 	WriteJSON(c.Response(), http.StatusOK, h.roleDTO(ctx, r, nil, nil))
 	return nil
 }
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/provision"
+)
+
+// GetRoleSetup returns the creation-time adjustments for a role.
+func (h *CatalogHandler) GetRoleSetup(c echo.Context) error {
+	id, err := catalogID(c)
+	if err != nil {
+		return catalogBad(c, "invalid role id")
+	}
+	ctx, cancel := catalogContext(c)
+	defer cancel()
+	q := models.New(h.pool)
+	role, err := q.GetRole(ctx, id)
+	if err != nil {
+		WriteError(c.Response(), http.StatusNotFound, "role_not_found", "role not found", nil)
+		return nil
+	}
+	setup, err := provision.GetSetup(ctx, q, role.Name)
+	if err != nil {
+		return catalogFailure(c, "role_setup_unavailable")
+	}
+	WriteJSON(c.Response(), http.StatusOK, setup)
+	return nil
+}
+
+// UpdateRoleSetup replaces the creation-time adjustments for a role and
+// returns the role. An empty setup clears them.
+func (h *CatalogHandler) UpdateRoleSetup(c echo.Context) error {
+	id, err := catalogID(c)
+	if err != nil {
+		return catalogBad(c, "invalid role id")
+	}
+	var in provision.Setup
+	if decodeCatalog(c, &in) != nil {
+		return nil
+	}
+	ctx, cancel := catalogContext(c)
+	defer cancel()
+	role, err := models.New(h.pool).GetRole(ctx, id)
+	if err != nil {
+		WriteError(c.Response(), http.StatusNotFound, "role_not_found", "role not found", nil)
+		return nil
+	}
+	if !saveRoleSetup(ctx, c, models.New(h.pool), role.Name, in) {
+		return nil
+	}
+	out, err := h.roleLinkResponse(ctx, id)
+	if err != nil {
+		return catalogFailure(c, "role_setup_update_failed")
+	}
+	WriteJSON(c.Response(), http.StatusOK, out)
+	return nil
+}
+
+// saveRoleSetup stores a setup and writes the error response when it fails,
+// reporting whether the caller should carry on.
+func saveRoleSetup(ctx context.Context, c echo.Context, q *models.Queries, roleName string, setup provision.Setup) bool {
+	_, err := provision.SaveSetup(ctx, q, roleName, setup)
+	if errors.Is(err, provision.ErrUnknownSetupName) {
+		WriteError(c.Response(), http.StatusBadRequest, "unknown_setup_name", err.Error(), map[string]any{})
+		return false
+	}
+	if err != nil {
+		catalogFailure(c, "role_setup_update_failed")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/labstack/echo/v4"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/wincon"
+)
+
+// GameHandler reports on the game as a whole.
+type GameHandler struct {
+	pool *pgxpool.Pool
+}
+
+func NewGameHandler(pool *pgxpool.Pool) *GameHandler {
+	return &GameHandler{pool: pool}
+}
+
+type GameAlignmentDTO struct {
+	Alignment string `json:"alignment"`
+	Alive     int    `json:"alive"`
+	Total     int    `json:"total"`
+}
+
+type GameOutcomeDTO struct {
+	Kind      string           `json:"kind"`
+	Subject   string           `json:"subject"`
+	State     string           `json:"state"`
+	Condition wincon.Condition `json:"condition"`
+	Summary   string           `json:"summary"`
+	Deciders  []string         `json:"deciders"`
+}
+
+type GameStatusDTO struct {
+	Alignments []GameAlignmentDTO `json:"alignments"`
+	Outcomes   []GameOutcomeDTO   `json:"outcomes"`
+	// GameOver is true once any win condition is met.
+	GameOver bool `json:"game_over"`
+}
+
+// Status evaluates the win conditions against the current players.
+func (h *GameHandler) Status(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	status, err := wincon.New(h.pool).Status(ctx)
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "game_status_unavailable", "could not evaluate the win conditions", nil)
+		return nil
+	}
+	out := GameStatusDTO{Alignments: []GameAlignmentDTO{}, Outcomes: []GameOutcomeDTO{}}
+	tally := status.Tally()
+	for _, a := range []models.Alignment{models.AlignmentGOOD, models.AlignmentEVIL, models.AlignmentNEUTRAL} {
+		if t, ok := tally[a]; ok {
+			out.Alignments = append(out.Alignments, GameAlignmentDTO{Alignment: string(a), Alive: t[0], Total: t[1]})
+		}
+	}
+	for _, o := range status.Outcomes {
+		deciders := make([]string, len(o.Deciders))
+		for i, id := range o.Deciders {
+			deciders[i] = strconv.FormatInt(id, 10)
+		}
+		out.Outcomes = append(out.Outcomes, GameOutcomeDTO{o.Kind, o.Subject, string(o.State), o.Condition, o.Condition.String(), deciders})
+		out.GameOver = out.GameOver || o.State == wincon.StateMet
+	}
+	WriteJSON(c.Response(), http.StatusOK, out)
+	return nil
+}
+
+// WinConditions returns the stored win conditions, or the defaults.
+func (h *GameHandler) WinConditions(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	rules, err := wincon.New(h.pool).Rules(ctx)
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "win_conditions_unavailable", "could not load the win conditions", nil)
+		return nil
+	}
+	WriteJSON(c.Response(), http.StatusOK, rules)
+	return nil
+}
+
+// SetWinConditions replaces the win conditions.
+func (h *GameHandler) SetWinConditions(c echo.Context) error {
+	var in wincon.Rules
+	if err := json.NewDecoder(c.Request().Body).Decode(&in); err != nil {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_json", "request body must be valid JSON", nil)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	rules, err := wincon.New(h.pool).SetRules(ctx, in)
+	if errors.Is(err, wincon.ErrInvalidRules) {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_win_conditions", err.Error(), nil)
+		return nil
+	}
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "win_conditions_unavailable", "could not save the win conditions", nil)
+		return nil
+	}
+	WriteJSON(c.Response(), http.StatusOK, rules)
+	return nil
+}
@@ -3,10 +3,14 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"math/rand"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	cyclesvc "github.com/mccune1224/betrayal/internal/services/cycle"
 	"github.com/mccune1224/betrayal/internal/services/roledraft"
 
 	"github.com/bwmarrin/discordgo"
@@ -14,20 +18,50 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
 	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/lifeboard"
 	"github.com/mccune1224/betrayal/internal/util"
 )
 
 // CycleDTO is the public representation of the current game phase.
 type CycleDTO struct {
-	Day           int    `json:"day"`
-	Phase         string `json:"phase"`
-	IsElimination bool   `json:"is_elimination"`
+	Day           int                `json:"day"`
+	Phase         string             `json:"phase"`
+	IsElimination bool               `json:"is_elimination"`
+	Hooks         []CycleHookDTO     `json:"hooks,omitempty"`
+	Broadcast     *CycleBroadcastDTO `json:"broadcast,omitempty"`
+}
+
+// CycleHookDTO reports one transition hook's outcome.
+type CycleHookDTO struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
 }
 
-// CycleHandler exposes read-only cycle state.
-type CycleHandler struct{ pool *pgxpool.Pool }
+// CycleBroadcastDTO reports how the cycle announcement was delivered.
+type CycleBroadcastDTO struct {
+	Sent    int    `json:"sent"`
+	Targets int    `json:"targets"`
+	Skipped string `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
 
-func NewCycleHandler(pool *pgxpool.Pool) *CycleHandler { return &CycleHandler{pool: pool} }
+// CycleBroadcastTargetsDTO lists the configured and supported broadcast targets.
+type CycleBroadcastTargetsDTO struct {
+	Targets   []string `json:"targets"`
+	Available []string `json:"available"`
+}
+
+// CycleHandler exposes cycle state and transitions. Transitions announce the
+// new phase to the configured broadcast targets when Discord is connected.
+type CycleHandler struct {
+	pool    *pgxpool.Pool
+	discord *discordgo.Session
+}
+
+func NewCycleHandler(pool *pgxpool.Pool, discord *discordgo.Session) *CycleHandler {
+	return &CycleHandler{pool: pool, discord: discord}
+}
 
 func (h *CycleHandler) Get(c echo.Context) error {
 	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
@@ -44,25 +78,16 @@ func (h *CycleHandler) Get(c echo.Context) error {
 func (h *CycleHandler) Advance(c echo.Context) error {
 	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
 	defer cancel()
-	curr, err := models.New(h.pool).GetCycle(ctx)
-	if err != nil {
-		WriteError(c.Response(), 500, "cycle_unavailable", "could not load cycle", nil)
+	channels, ok := h.broadcastChannels(ctx, c)
+	if !ok {
 		return nil
 	}
-	day, elimination := curr.Day, curr.IsElimination
-	if day == 0 {
-		day, elimination = 1, false
-	} else if elimination {
-		day, elimination = day+1, false
-	} else {
-		elimination = true
-	}
-	updated, err := models.New(h.pool).UpdateCycle(ctx, models.UpdateCycleParams{IsElimination: elimination, Day: day, ID: curr.ID})
+	report, err := cyclesvc.New(h.pool).Advance(ctx, "web")
 	if err != nil {
 		WriteError(c.Response(), 500, "cycle_update_failed", "could not advance cycle", nil)
 		return nil
 	}
-	WriteJSON(c.Response(), 200, cycleDTO(updated))
+	WriteJSON(c.Response(), 200, h.announce(report, channels))
 	return nil
 }
 
@@ -77,20 +102,282 @@ func (h *CycleHandler) Set(c echo.Context) error {
 	}
 	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
 	defer cancel()
-	curr, err := models.New(h.pool).GetCycle(ctx)
-	if err != nil {
-		WriteError(c.Response(), 500, "cycle_unavailable", "could not load cycle", nil)
+	channels, ok := h.broadcastChannels(ctx, c)
+	if !ok {
 		return nil
 	}
-	updated, err := models.New(h.pool).UpdateCycle(ctx, models.UpdateCycleParams{IsElimination: req.Phase == "Elimination", Day: req.Day, ID: curr.ID})
+	report, err := cyclesvc.New(h.pool).SetPhase(ctx, req.Phase == "Elimination", req.Day, "web")
 	if err != nil {
 		WriteError(c.Response(), 500, "cycle_update_failed", "could not set cycle", nil)
 		return nil
 	}
-	WriteJSON(c.Response(), 200, cycleDTO(updated))
+	WriteJSON(c.Response(), 200, h.announce(report, channels))
 	return nil
 }
 
+// broadcastChannels resolves the announcement channels before the cycle
+// changes, so a misconfigured target rejects the request instead of moving
+// the game silently. It reports false once an error response is written.
+func (h *CycleHandler) broadcastChannels(ctx context.Context, c echo.Context) ([]string, bool) {
+	if h.discord == nil {
+		return nil, true
+	}
+	channels, err := cyclesvc.NewBroadcaster(h.pool, h.discord).Channels(ctx, "")
+	if err != nil {
+		WriteError(c.Response(), http.StatusConflict, "broadcast_unavailable", err.Error(), nil)
+		return nil, false
+	}
+	return channels, true
+}
+
+// announce folds the outcome of the announce hook into the response. Without
+// a Discord session the broadcast is skipped.
+func (h *CycleHandler) announce(report cyclesvc.Report, channels []string) CycleDTO {
+	dto := cycleReportDTO(report)
+	if h.discord == nil {
+		dto.Broadcast = &CycleBroadcastDTO{Skipped: "Discord is disabled"}
+		return dto
+	}
+	dto.Broadcast = &CycleBroadcastDTO{Skipped: "announcements are not enabled"}
+	for _, result := range report.Hooks {
+		if result.Name != cyclesvc.AnnounceHookName {
+			continue
+		}
+		dto.Broadcast = &CycleBroadcastDTO{Sent: len(channels), Targets: len(channels)}
+		if result.OK() {
+			break
+		}
+		dto.Broadcast.Sent, dto.Broadcast.Error = 0, result.Err.Error()
+		var partial *cyclesvc.AnnounceError
+		if errors.As(result.Err, &partial) {
+			dto.Broadcast.Sent, dto.Broadcast.Targets = partial.Sent, partial.Targets
+		}
+	}
+	return dto
+}
+
+func (h *CycleHandler) GetBroadcast(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	targets, err := cyclesvc.NewBroadcaster(h.pool, h.discord).Targets(ctx)
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "cycle_unavailable", "could not load broadcast targets", nil)
+		return nil
+	}
+	WriteJSON(c.Response(), http.StatusOK, broadcastTargetsDTO(targets))
+	return nil
+}
+
+func (h *CycleHandler) SetBroadcast(c echo.Context) error {
+	var req struct {
+		Targets []string `json:"targets"`
+	}
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		WriteError(c.Response(), 400, "invalid_request", "targets must be a list", nil)
+		return nil
+	}
+	targets, err := cyclesvc.ParseTargets(strings.Join(req.Targets, ","))
+	if err != nil {
+		WriteError(c.Response(), 400, "invalid_request", err.Error(), nil)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	if err := cyclesvc.NewBroadcaster(h.pool, h.discord).SetTargets(ctx, targets); err != nil {
+		WriteError(c.Response(), 500, "cycle_update_failed", "could not save broadcast targets", nil)
+		return nil
+	}
+	WriteJSON(c.Response(), http.StatusOK, broadcastTargetsDTO(targets))
+	return nil
+}
+
+// CycleHistoryEntryDTO is one logged phase.
+type CycleHistoryEntryDTO struct {
+	ID              int64      `json:"id"`
+	Day             int        `json:"day"`
+	Phase           string     `json:"phase"`
+	IsElimination   bool       `json:"is_elimination"`
+	StartedAt       *time.Time `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at"`
+	DurationSeconds int64      `json:"duration_seconds"`
+	AdvancedBy      string     `json:"advanced_by,omitempty"`
+}
+
+// History lists recent phases, newest first. With ?at=<RFC3339> it instead
+// returns the single phase that was running at that time.
+func (h *CycleHandler) History(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	svc := cyclesvc.New(h.pool)
+	now := time.Now()
+	if raw := c.QueryParam("at"); raw != "" {
+		at, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			WriteError(c.Response(), 400, "invalid_request", "at must be an RFC3339 timestamp", nil)
+			return nil
+		}
+		entry, err := svc.PhaseAt(ctx, at)
+		if errors.Is(err, cyclesvc.ErrNoPhase) {
+			WriteError(c.Response(), http.StatusNotFound, "cycle_phase_unknown", err.Error(), nil)
+			return nil
+		}
+		if err != nil {
+			WriteError(c.Response(), http.StatusInternalServerError, "cycle_unavailable", "could not load cycle history", nil)
+			return nil
+		}
+		WriteJSON(c.Response(), http.StatusOK, cycleHistoryEntryDTO(entry, now))
+		return nil
+	}
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit > 500 {
+		limit = 500
+	}
+	entries, err := svc.History(ctx, int32(limit))
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "cycle_unavailable", "could not load cycle history", nil)
+		return nil
+	}
+	result := make([]CycleHistoryEntryDTO, len(entries))
+	for i, entry := range entries {
+		result[i] = cycleHistoryEntryDTO(entry, now)
+	}
+	WriteJSON(c.Response(), http.StatusOK, map[string]any{"history": result})
+	return nil
+}
+
+func cycleHistoryEntryDTO(entry models.CycleHistory, now time.Time) CycleHistoryEntryDTO {
+	dto := cycleDTO(models.GameCycle{Day: entry.Day, IsElimination: entry.IsElimination})
+	return CycleHistoryEntryDTO{
+		ID:              entry.ID,
+		Day:             dto.Day,
+		Phase:           dto.Phase,
+		IsElimination:   dto.IsElimination,
+		StartedAt:       nullableTimestamptz(entry.StartedAt),
+		EndedAt:         nullableTimestamptz(entry.EndedAt),
+		DurationSeconds: int64(cyclesvc.PhaseDuration(entry, now) / time.Second),
+		AdvancedBy:      entry.AdvancedBy,
+	}
+}
+
+// CycleScheduleDTO is the automatic advance schedule and what it does next.
+type CycleScheduleDTO struct {
+	Configured   bool                `json:"configured"`
+	Timezone     string              `json:"timezone,omitempty"`
+	Boundaries   []cyclesvc.Boundary `json:"boundaries"`
+	Paused       bool                `json:"paused"`
+	NextBoundary *time.Time          `json:"next_boundary,omitempty"`
+	NextPhase    string              `json:"next_phase,omitempty"`
+	WarningLeads []int64             `json:"warning_lead_seconds"`
+}
+
+func (h *CycleHandler) GetSchedule(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	h.writeSchedule(ctx, c, http.StatusOK)
+	return nil
+}
+
+func (h *CycleHandler) PutSchedule(c echo.Context) error {
+	var req cyclesvc.Schedule
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		WriteError(c.Response(), 400, "invalid_request", "invalid schedule", nil)
+		return nil
+	}
+	if err := req.Validate(); err != nil {
+		WriteError(c.Response(), 400, "invalid_request", err.Error(), nil)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	if err := cyclesvc.NewScheduler(h.pool, nil).Save(ctx, req, time.Now()); err != nil {
+		WriteError(c.Response(), 500, "cycle_update_failed", "could not save schedule", nil)
+		return nil
+	}
+	h.writeSchedule(ctx, c, http.StatusOK)
+	return nil
+}
+
+func (h *CycleHandler) DeleteSchedule(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	if err := cyclesvc.NewScheduler(h.pool, nil).Clear(ctx); err != nil {
+		WriteError(c.Response(), 500, "cycle_update_failed", "could not clear schedule", nil)
+		return nil
+	}
+	h.writeSchedule(ctx, c, http.StatusOK)
+	return nil
+}
+
+func (h *CycleHandler) PauseSchedule(c echo.Context) error  { return h.setPaused(c, true) }
+func (h *CycleHandler) ResumeSchedule(c echo.Context) error { return h.setPaused(c, false) }
+
+func (h *CycleHandler) setPaused(c echo.Context, paused bool) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	err := cyclesvc.NewScheduler(h.pool, nil).SetPaused(ctx, paused, time.Now())
+	if errors.Is(err, cyclesvc.ErrNoSchedule) {
+		WriteError(c.Response(), http.StatusConflict, "schedule_not_configured", err.Error(), nil)
+		return nil
+	}
+	if err != nil {
+		WriteError(c.Response(), 500, "cycle_update_failed", "could not update schedule", nil)
+		return nil
+	}
+	h.writeSchedule(ctx, c, http.StatusOK)
+	return nil
+}
+
+func (h *CycleHandler) writeSchedule(ctx context.Context, c echo.Context, status int) {
+	dto := CycleScheduleDTO{Boundaries: []cyclesvc.Boundary{}}
+	for _, lead := range cyclesvc.WarningLeads {
+		dto.WarningLeads = append(dto.WarningLeads, int64(lead/time.Second))
+	}
+	state, err := cyclesvc.NewScheduler(h.pool, nil).State(ctx, time.Now())
+	if errors.Is(err, cyclesvc.ErrNoSchedule) {
+		WriteJSON(c.Response(), status, dto)
+		return
+	}
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "cycle_unavailable", "could not load schedule", nil)
+		return
+	}
+	loc, _ := state.Schedule.Location()
+	dto.Configured, dto.Timezone, dto.Paused = true, loc.String(), state.Paused
+	dto.Boundaries = append(dto.Boundaries, state.Schedule.Boundaries...)
+	if !state.Next.IsZero() {
+		next := state.Next
+		dto.NextBoundary = &next
+		if cycle, err := models.New(h.pool).GetCycle(ctx); err == nil {
+			upcoming := cyclesvc.NextCycle(cycle)
+			dto.NextPhase = cyclesvc.PhaseLabel(upcoming.IsElimination, upcoming.Day)
+		}
+	}
+	WriteJSON(c.Response(), status, dto)
+}
+
+func broadcastTargetsDTO(targets []cyclesvc.Target) CycleBroadcastTargetsDTO {
+	dto := CycleBroadcastTargetsDTO{Targets: make([]string, len(targets)), Available: make([]string, len(cyclesvc.AllTargets))}
+	for i, target := range targets {
+		dto.Targets[i] = string(target)
+	}
+	for i, target := range cyclesvc.AllTargets {
+		dto.Available[i] = string(target)
+	}
+	return dto
+}
+
+func cycleReportDTO(report cyclesvc.Report) CycleDTO {
+	dto := cycleDTO(report.To)
+	for _, result := range report.Hooks {
+		hook := CycleHookDTO{Name: result.Name, OK: result.OK()}
+		if result.Err != nil {
+			hook.Error = result.Err.Error()
+		}
+		dto.Hooks = append(dto.Hooks, hook)
+	}
+	return dto
+}
+
 type RoleDTO struct {
 	ID          int32  `json:"id"`
 	Name        string `json:"name"`
@@ -105,11 +392,18 @@ type SetupDTO struct {
 	DeceptionistDefault int           `json:"deceptionist_default"`
 	PlayerCount         int           `json:"player_count,omitempty"`
 	DeceptionistCount   int           `json:"deceptionist_count,omitempty"`
+	DraftID             int64         `json:"draft_id,omitempty"`
+	Seed                int64         `json:"seed,omitempty"`
 	Pool                *SetupPoolDTO `json:"pool,omitempty"`
 }
-type SetupHandler struct{ pool *pgxpool.Pool }
+type SetupHandler struct {
+	pool    *pgxpool.Pool
+	discord *discordgo.Session
+}
 
-func NewSetupHandler(pool *pgxpool.Pool) *SetupHandler { return &SetupHandler{pool: pool} }
+func NewSetupHandler(pool *pgxpool.Pool, discord *discordgo.Session) *SetupHandler {
+	return &SetupHandler{pool: pool, discord: discord}
+}
 func (h *SetupHandler) Get(c echo.Context) error {
 	WriteJSON(c.Response(), 200, SetupDTO{})
 	return nil
@@ -129,10 +423,15 @@ func setupPoolDTO(pool *roledraft.Pool) *SetupPoolDTO {
 	return result
 }
 
+// Generate draws a role pool from the active roles and stores it as a draft.
+// seed is optional; constraints bound the random pool per alignment and ban
+// or require roles by name.
 func (h *SetupHandler) Generate(c echo.Context) error {
 	var req struct {
-		PlayerCount       int `json:"player_count"`
-		DeceptionistCount int `json:"deceptionist_count"`
+		PlayerCount       int                   `json:"player_count"`
+		DeceptionistCount int                   `json:"deceptionist_count"`
+		Seed              *int64                `json:"seed"`
+		Constraints       roledraft.Constraints `json:"constraints"`
 	}
 	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
 		WriteError(c.Response(), 400, "invalid_request", "invalid setup request", nil)
@@ -140,17 +439,23 @@ func (h *SetupHandler) Generate(c echo.Context) error {
 	}
 	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
 	defer cancel()
-	roles, err := roledraft.LoadRoles(ctx, h.pool)
-	if err != nil {
-		WriteError(c.Response(), 500, "setup_unavailable", "failed to load active roles", nil)
+	opts := roledraft.Options{Players: req.PlayerCount, Deceptionists: req.DeceptionistCount, Seed: rand.Int63(), Constraints: upperAlignments(req.Constraints)}
+	if req.Seed != nil {
+		opts.Seed = *req.Seed
+	}
+	draft, err := roledraft.New(h.pool).Create(ctx, opts, "web")
+	if errors.Is(err, roledraft.ErrInvalidDraw) {
+		WriteError(c.Response(), 400, "invalid_request", err.Error(), nil)
 		return nil
 	}
-	pool, err := roledraft.Generate(roles, req.PlayerCount, req.DeceptionistCount)
 	if err != nil {
-		WriteError(c.Response(), 400, "invalid_request", err.Error(), nil)
+		WriteError(c.Response(), 500, "setup_unavailable", "failed to create role draft", nil)
 		return nil
 	}
-	WriteJSON(c.Response(), 200, SetupDTO{PlayerCount: req.PlayerCount, DeceptionistCount: req.DeceptionistCount, Pool: setupPoolDTO(pool)})
+	WriteJSON(c.Response(), 200, SetupDTO{
+		PlayerCount: req.PlayerCount, DeceptionistCount: req.DeceptionistCount,
+		DraftID: draft.ID, Seed: draft.Seed, Pool: setupPoolDTO(&draft.Pool),
+	})
 	return nil
 }
 
@@ -208,6 +513,9 @@ func (h *ChannelsHandler) Get(c echo.Context) error {
 	addSingleton("Vote Channel", "vote", q.GetVoteChannel)
 	addSingleton("Action Channel", "action", q.GetActionChannel)
 	addSingleton("Command Log Channel", "log", q.GetCommandLogChannel)
+	addSingleton("Announcement Channel", "announcement", func(ctx context.Context) (string, error) {
+		return q.GetGameConfig(ctx, cyclesvc.AnnouncementChannelConfigKey)
+	})
 	addSingleton("Lifeboard Channel", "lifeboard", func(ctx context.Context) (string, error) {
 		lb, err := q.GetPlayerLifeboard(ctx)
 		return lb.ChannelID, err
@@ -277,6 +585,8 @@ func (h *ChannelsHandler) Mutate(c echo.Context) error {
 		}
 	case "log":
 		_, err = q.SetCommandLogChannel(ctx, req.ChannelID)
+	case "announcement":
+		_, err = q.UpsertGameConfig(ctx, models.UpsertGameConfigParams{Key: cyclesvc.AnnouncementChannelConfigKey, Value: req.ChannelID})
 	case "admin":
 		_, err = q.CreateAdminChannel(ctx, req.ChannelID)
 	case "lifeboard":
@@ -299,6 +609,9 @@ func (h *ChannelsHandler) Mutate(c echo.Context) error {
 				_ = tx.Rollback(ctx)
 			}
 		}
+		if err == nil {
+			lifeboard.RefreshQuietly(ctx, h.pool, h.discord)
+		}
 	default:
 		WriteError(c.Response(), 400, "invalid_request", "unknown channel kind", nil)
 		return nil
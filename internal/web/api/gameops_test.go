@@ -1,9 +1,13 @@
 package api
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/mccune1224/betrayal/internal/models"
+	cyclesvc "github.com/mccune1224/betrayal/internal/services/cycle"
 )
 
 func TestChannelEntryUsesReadableDiscordLabel(t *testing.T) {
@@ -32,3 +36,34 @@ func TestChannelEntryUsesReadableLocalFallback(t *testing.T) {
 		t.Fatal("local fallback must not expose the raw Discord channel ID")
 	}
 }
+
+func TestCycleAnnounceSkipsWithoutDiscord(t *testing.T) {
+	handler := NewCycleHandler(nil, nil)
+	report := cyclesvc.Report{Transition: cyclesvc.Transition{To: models.GameCycle{Day: 2, IsElimination: true}}}
+
+	dto := handler.announce(report, nil)
+
+	if dto.Broadcast == nil || dto.Broadcast.Skipped == "" {
+		t.Fatalf("broadcast = %+v, want skipped in web-only mode", dto.Broadcast)
+	}
+	if dto.Phase != "Elimination" || dto.Day != 2 {
+		t.Fatalf("cycle = %s %d, want Elimination 2", dto.Phase, dto.Day)
+	}
+}
+
+func TestCycleAnnounceReportsTheAnnounceHook(t *testing.T) {
+	handler := NewCycleHandler(nil, &discordgo.Session{})
+	sendErr := &cyclesvc.AnnounceError{Sent: 2, Targets: 3, Err: errors.New("channel 9: missing access")}
+	report := cyclesvc.Report{Hooks: []cyclesvc.HookResult{{Name: "Snapshot inventories"}, {Name: cyclesvc.AnnounceHookName, Err: sendErr}}}
+
+	dto := handler.announce(report, []string{"1", "2", "3"})
+
+	if b := dto.Broadcast; b == nil || b.Sent != 2 || b.Targets != 3 || !strings.Contains(b.Error, "missing access") {
+		t.Fatalf("broadcast = %+v, want 2 of 3 with the send error", dto.Broadcast)
+	}
+
+	report.Hooks[1].Err = nil
+	if b := handler.announce(report, []string{"1", "2", "3"}).Broadcast; b == nil || b.Sent != 3 || b.Error != "" {
+		t.Fatalf("broadcast = %+v, want all 3 sent", b)
+	}
+}
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/sessions"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
+)
+
+// sessionKeyGame holds the game the panel works on; absent or 0 is the
+// primary game.
+const sessionKeyGame = "game_id"
+
+// GamesHandler lists the games and selects the one the panel works on.
+type GamesHandler struct {
+	games *tenancy.Registry
+	store *sessions.CookieStore
+	// forget drops anything cached for a deleted game.
+	forget func(id int64)
+}
+
+func NewGamesHandler(games *tenancy.Registry, store *sessions.CookieStore, forget func(id int64)) *GamesHandler {
+	return &GamesHandler{games: games, store: store, forget: forget}
+}
+
+type GameDTO struct {
+	ID        int64      `json:"id"`
+	GuildID   string     `json:"guild_id"`
+	Name      string     `json:"name"`
+	Schema    string     `json:"schema"`
+	Primary   bool       `json:"primary"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+type GamesDTO struct {
+	Current int64     `json:"current"`
+	Games   []GameDTO `json:"games"`
+}
+
+type gameSelectInput struct {
+	GameID *int64 `json:"game_id"`
+}
+
+type gameCreateInput struct {
+	GuildID string `json:"guild_id"`
+	Name    string `json:"name"`
+}
+
+// SelectedGame returns the game id stored in the request's session.
+func SelectedGame(store *sessions.CookieStore, r *http.Request) int64 {
+	session, err := store.Get(r, sessionName)
+	if err != nil {
+		return 0
+	}
+	id, _ := session.Values[sessionKeyGame].(int64)
+	return id
+}
+
+// SelectGame stores id as the session's game; 0 is the primary game.
+func SelectGame(store *sessions.CookieStore, c echo.Context, id int64) error {
+	session, err := store.Get(c.Request(), sessionName)
+	if err != nil {
+		return err
+	}
+	if id == 0 {
+		delete(session.Values, sessionKeyGame)
+	} else {
+		session.Values[sessionKeyGame] = id
+	}
+	return session.Save(c.Request(), c.Response())
+}
+
+// List returns the primary game (id 0) and every registered game, plus the
+// one this session works on.
+func (h *GamesHandler) List(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	games, err := h.games.Games(ctx)
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "games_unavailable", "could not load the games", nil)
+		return nil
+	}
+	out := GamesDTO{Current: SelectedGame(h.store, c.Request()), Games: []GameDTO{{Name: "Primary", Schema: "public", Primary: true}}}
+	for _, g := range games {
+		out.Games = append(out.Games, gameDTO(g))
+	}
+	WriteJSON(c.Response(), http.StatusOK, out)
+	return nil
+}
+
+// Select switches the panel to another game for this session.
+func (h *GamesHandler) Select(c echo.Context) error {
+	var in gameSelectInput
+	if decodeJSON(c, &in) != nil {
+		return nil
+	}
+	if in.GameID == nil {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_game", "game_id is required", nil)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	if *in.GameID != 0 {
+		if _, err := models.New(h.games.Primary()).GetGame(ctx, *in.GameID); errors.Is(err, pgx.ErrNoRows) {
+			WriteError(c.Response(), http.StatusNotFound, "game_not_found", "game not found", nil)
+			return nil
+		} else if err != nil {
+			WriteError(c.Response(), http.StatusInternalServerError, "games_unavailable", "could not load the game", nil)
+			return nil
+		}
+	}
+	if err := SelectGame(h.store, c, *in.GameID); err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "session_error", "could not save session", nil)
+		return nil
+	}
+	WriteJSON(c.Response(), http.StatusOK, map[string]int64{"current": *in.GameID})
+	return nil
+}
+
+// Create gives a guild its own game, seeded with the primary catalog.
+func (h *GamesHandler) Create(c echo.Context) error {
+	var in gameCreateInput
+	if decodeJSON(c, &in) != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 60*time.Second)
+	defer cancel()
+	game, err := h.games.Create(ctx, in.GuildID, in.Name)
+	switch {
+	case errors.Is(err, tenancy.ErrInvalidGuild), errors.Is(err, tenancy.ErrInvalidName):
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_game", err.Error(), nil)
+		return nil
+	case errors.Is(err, tenancy.ErrGameExists):
+		WriteError(c.Response(), http.StatusConflict, "game_exists", err.Error(), nil)
+		return nil
+	case err != nil:
+		WriteError(c.Response(), http.StatusInternalServerError, "game_create_failed", "could not create the game", nil)
+		return nil
+	}
+	WriteJSON(c.Response(), http.StatusCreated, gameDTO(game))
+	return nil
+}
+
+// Delete drops a game and everything in it. The primary game cannot be
+// deleted.
+func (h *GamesHandler) Delete(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_game", "id must be a registered game", nil)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 30*time.Second)
+	defer cancel()
+	game, err := models.New(h.games.Primary()).GetGame(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		WriteError(c.Response(), http.StatusNotFound, "game_not_found", "game not found", nil)
+		return nil
+	}
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "games_unavailable", "could not load the game", nil)
+		return nil
+	}
+	if h.forget != nil {
+		h.forget(id)
+	}
+	if _, err := h.games.Delete(ctx, game.GuildID); err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "game_delete_failed", "could not delete the game", nil)
+		return nil
+	}
+	if SelectedGame(h.store, c.Request()) == id {
+		_ = SelectGame(h.store, c, 0)
+	}
+	WriteJSON(c.Response(), http.StatusOK, gameDTO(game))
+	return nil
+}
+
+func gameDTO(g models.Game) GameDTO {
+	return GameDTO{ID: g.ID, GuildID: g.GuildID, Name: g.Name, Schema: g.SchemaName, CreatedAt: nullableTimestamptz(g.CreatedAt)}
+}
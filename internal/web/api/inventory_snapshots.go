@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/labstack/echo/v4"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/invsnapshot"
+)
+
+// InventorySnapshotsHandler serves the per-phase inventory snapshots and
+// recaps of what changed between them.
+type InventorySnapshotsHandler struct {
+	pool *pgxpool.Pool
+}
+
+func NewInventorySnapshotsHandler(pool *pgxpool.Pool) *InventorySnapshotsHandler {
+	return &InventorySnapshotsHandler{pool: pool}
+}
+
+type InventorySnapshotPhaseDTO struct {
+	Day         int32      `json:"day"`
+	Elimination bool       `json:"elimination"`
+	Players     int64      `json:"players"`
+	TakenAt     *time.Time `json:"taken_at"`
+}
+
+type InventoryDiffDTO struct {
+	From    invsnapshot.Phase        `json:"from"`
+	To      *invsnapshot.Phase       `json:"to"`
+	Players []invsnapshot.PlayerDiff `json:"players"`
+}
+
+// Phases lists the phases that have snapshots.
+func (h *InventorySnapshotsHandler) Phases(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	rows, err := models.New(h.pool).ListInventorySnapshotPhases(ctx)
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "snapshots_unavailable", "could not load the inventory snapshots", nil)
+		return nil
+	}
+	out := make([]InventorySnapshotPhaseDTO, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, InventorySnapshotPhaseDTO{Day: r.Day, Elimination: r.IsElimination, Players: r.Players, TakenAt: nullableTimestamptz(r.TakenAt)})
+	}
+	WriteJSON(c.Response(), http.StatusOK, out)
+	return nil
+}
+
+// Diff compares the start of ?from= with the start of ?to=, or with the live
+// inventories when to is omitted. from_elimination and to_elimination pick
+// elimination phases; player_id limits the recap to one player.
+func (h *InventorySnapshotsHandler) Diff(c echo.Context) error {
+	from, ok := snapshotPhase(c, "from")
+	if !ok {
+		return nil
+	}
+	var to *invsnapshot.Phase
+	if c.QueryParam("to") != "" {
+		phase, ok := snapshotPhase(c, "to")
+		if !ok {
+			return nil
+		}
+		to = &phase
+	}
+	var playerID int64
+	if raw := c.QueryParam("player_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			WriteError(c.Response(), http.StatusBadRequest, "invalid_player_id", "player_id must be numeric", nil)
+			return nil
+		}
+		playerID = id
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 30*time.Second)
+	defer cancel()
+	diffs, err := invsnapshot.New(h.pool).Diff(ctx, from, to, playerID)
+	if errors.Is(err, invsnapshot.ErrNoSnapshot) {
+		WriteError(c.Response(), http.StatusNotFound, "snapshot_not_found", err.Error(), nil)
+		return nil
+	}
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "snapshots_unavailable", "could not compare the inventories", nil)
+		return nil
+	}
+	if diffs == nil {
+		diffs = []invsnapshot.PlayerDiff{}
+	}
+	WriteJSON(c.Response(), http.StatusOK, InventoryDiffDTO{From: from, To: to, Players: diffs})
+	return nil
+}
+
+func snapshotPhase(c echo.Context, name string) (invsnapshot.Phase, bool) {
+	day, err := strconv.ParseInt(c.QueryParam(name), 10, 32)
+	if err != nil || day < 0 {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_phase", name+" must be a day number", nil)
+		return invsnapshot.Phase{}, false
+	}
+	elimination, _ := strconv.ParseBool(c.QueryParam(name + "_elimination"))
+	return invsnapshot.Phase{Day: int32(day), Elimination: elimination}, true
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/lifeboard"
+)
+
+type lifeboardDTO struct {
+	ChannelID   string `json:"channel_id,omitempty"`
+	MessageID   string `json:"message_id,omitempty"`
+	RevealRoles bool   `json:"reveal_roles"`
+	Refreshed   bool   `json:"refreshed"`
+}
+
+// Lifeboard returns the posted lifeboard and its settings.
+func (h *ChannelsHandler) Lifeboard(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	WriteJSON(c.Response(), http.StatusOK, h.lifeboardDTO(ctx, false))
+	return nil
+}
+
+// SetLifeboard changes whether dead players' roles are revealed and
+// re-renders the posted board.
+func (h *ChannelsHandler) SetLifeboard(c echo.Context) error {
+	var req struct {
+		RevealRoles *bool `json:"reveal_roles"`
+	}
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil || req.RevealRoles == nil {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_request", "reveal_roles is required", nil)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	if err := lifeboard.SetRevealRoles(ctx, models.New(h.pool), *req.RevealRoles); err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "lifeboard_update_failed", "could not update the lifeboard", nil)
+		return nil
+	}
+	WriteJSON(c.Response(), http.StatusOK, h.lifeboardDTO(ctx, lifeboard.RefreshQuietly(ctx, h.pool, h.discord)))
+	return nil
+}
+
+// RefreshLifeboard re-renders the posted board.
+func (h *ChannelsHandler) RefreshLifeboard(c echo.Context) error {
+	if h.discord == nil {
+		WriteError(c.Response(), http.StatusServiceUnavailable, "discord_unavailable", "Discord is not connected", nil)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	err := lifeboard.Refresh(ctx, h.pool, h.discord)
+	if errors.Is(err, lifeboard.ErrNotConfigured) {
+		WriteError(c.Response(), http.StatusNotFound, "lifeboard_not_configured", err.Error(), nil)
+		return nil
+	}
+	if err != nil {
+		WriteError(c.Response(), http.StatusBadGateway, "lifeboard_refresh_failed", "could not refresh the lifeboard", nil)
+		return nil
+	}
+	WriteJSON(c.Response(), http.StatusOK, h.lifeboardDTO(ctx, true))
+	return nil
+}
+
+func (h *ChannelsHandler) lifeboardDTO(ctx context.Context, refreshed bool) lifeboardDTO {
+	q := models.New(h.pool)
+	dto := lifeboardDTO{RevealRoles: lifeboard.RevealRoles(ctx, q), Refreshed: refreshed}
+	if board, err := q.GetPlayerLifeboard(ctx); err == nil {
+		dto.ChannelID, dto.MessageID = board.ChannelID, board.MessageID
+	}
+	return dto
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/bwmarrin/discordgo"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
 	"github.com/mccune1224/betrayal/internal/models"
@@ -13,12 +14,14 @@ import (
 
 // PlayersHandler exposes the player list as explicit API DTOs.
 type PlayersHandler struct {
-	pool *pgxpool.Pool
+	pool    *pgxpool.Pool
+	discord *discordgo.Session
 }
 
-// NewPlayersHandler creates a player-list API handler backed by the shared pool.
-func NewPlayersHandler(pool *pgxpool.Pool) *PlayersHandler {
-	return &PlayersHandler{pool: pool}
+// NewPlayersHandler creates a player-list API handler backed by the shared
+// pool. discord may be nil in web-only mode.
+func NewPlayersHandler(pool *pgxpool.Pool, discord *discordgo.Session) *PlayersHandler {
+	return &PlayersHandler{pool: pool, discord: discord}
 }
 
 type playerListDTO struct {
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -11,8 +12,11 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/labstack/echo/v4"
 	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/death"
 	"github.com/mccune1224/betrayal/internal/services/inventory"
-	"github.com/mccune1224/betrayal/internal/util"
+	"github.com/mccune1224/betrayal/internal/services/lifeboard"
+	"github.com/mccune1224/betrayal/internal/services/playernotes"
+	"github.com/mccune1224/betrayal/internal/services/provision"
 )
 
 type playerDTO struct {
@@ -57,6 +61,12 @@ type playerNoteDTO struct {
 	ID       int32  `json:"id"`
 	Position int32  `json:"position"`
 	Info     string `json:"info"`
+	// PlayerID is the note's player; set in search results only.
+	PlayerID      string   `json:"player_id,omitempty"`
+	Tags          []string `json:"tags"`
+	Author        string   `json:"author"`
+	Day           *int32   `json:"day"`
+	LinkedPlayers []string `json:"linked_players"`
 }
 type playerDetailDTO struct {
 	playerDTO
@@ -66,6 +76,8 @@ type playerDetailDTO struct {
 	Immunities []playerImmunityDTO `json:"immunities"`
 	Perks      []playerPerkDTO     `json:"perks"`
 	Notes      []playerNoteDTO     `json:"notes"`
+	// Warnings lists the Discord steps an inventory change could not do.
+	Warnings []string `json:"warnings,omitempty"`
 }
 type playerCreateInput struct {
 	ID   json.RawMessage `json:"id"`
@@ -78,6 +90,21 @@ type playerUpdateInput struct {
 	Alignment *string `json:"alignment"`
 	ItemLimit *int32  `json:"item_limit"`
 	Role      *string `json:"role"`
+	// Announce overrides the death pipeline's announcement setting when
+	// Alive changes.
+	Announce *bool `json:"announce"`
+	// Notify overrides whether coin and luck changes are noticed in the
+	// player's confessional.
+	Notify *bool `json:"notify"`
+}
+
+// playerUpdateDTO is a player after an update. Warnings and LuckyCoin come
+// from the death pipeline when the update changed the alive state, and from
+// re-rendering the pinned inventory otherwise.
+type playerUpdateDTO struct {
+	playerDTO
+	Warnings  []string `json:"warnings,omitempty"`
+	LuckyCoin bool     `json:"lucky_coin,omitempty"`
 }
 type playerMutationInput struct {
 	Name     string `json:"name"`
@@ -86,6 +113,13 @@ type playerMutationInput struct {
 	Position int32  `json:"position"`
 	Info     string `json:"info"`
 	NoteID   int32  `json:"note_id"`
+	// Tags and Day annotate note_add; #tags and <@id> mentions in Info are
+	// picked up too.
+	Tags []string `json:"tags"`
+	Day  *int32   `json:"day"`
+	// Notify overrides whether an "Inventory updated" notice is posted in
+	// the player's confessional.
+	Notify *bool `json:"notify"`
 }
 
 func parsePlayerID(raw json.RawMessage) (int64, error) {
@@ -137,6 +171,11 @@ func playerDTOFor(p models.Player, role string) playerDTO {
 }
 
 func (h *PlayersHandler) Detail(c echo.Context) error {
+	return h.writeDetail(c, nil)
+}
+
+// writeDetail responds with the player's full inventory and warnings.
+func (h *PlayersHandler) writeDetail(c echo.Context, warnings []string) error {
 	id, ok := playerID(c)
 	if !ok {
 		WriteError(c.Response(), 400, "invalid_player_id", "player ID must be a positive integer", nil)
@@ -174,7 +213,7 @@ func (h *PlayersHandler) Detail(c echo.Context) error {
 	if err != nil {
 		return playerFailure(c)
 	}
-	d := playerDetailDTO{playerDTO: playerDTOFor(p, role), Items: make([]playerItemDTO, 0), Abilities: make([]playerAbilityDTO, 0), Statuses: make([]playerStatusDTO, 0), Immunities: make([]playerImmunityDTO, 0), Perks: make([]playerPerkDTO, 0), Notes: make([]playerNoteDTO, 0)}
+	d := playerDetailDTO{playerDTO: playerDTOFor(p, role), Items: make([]playerItemDTO, 0), Abilities: make([]playerAbilityDTO, 0), Statuses: make([]playerStatusDTO, 0), Immunities: make([]playerImmunityDTO, 0), Perks: make([]playerPerkDTO, 0), Notes: make([]playerNoteDTO, 0), Warnings: warnings}
 	for _, x := range items {
 		d.Items = append(d.Items, playerItemDTO{x.ID, x.Name, x.Description, x.Quantity, x.Cost})
 	}
@@ -191,7 +230,7 @@ func (h *PlayersHandler) Detail(c echo.Context) error {
 		d.Perks = append(d.Perks, playerPerkDTO{x.ID, x.Name})
 	}
 	for _, x := range notes {
-		d.Notes = append(d.Notes, playerNoteDTO{x.NoteID, x.Position, x.Info})
+		d.Notes = append(d.Notes, playerNoteDTOFor(x))
 	}
 	WriteJSON(c.Response(), 200, d)
 	return nil
@@ -212,27 +251,17 @@ func (h *PlayersHandler) Create(c echo.Context) error {
 	}
 	ctx, cn := context.WithTimeout(c.Request().Context(), 10*time.Second)
 	defer cn()
-	q := models.New(h.pool)
-	r, err := q.GetRoleByFuzzy(ctx, in.Role)
-	if err != nil {
+	created, err := provision.New(h.pool).CreatePlayer(ctx, id, in.Role)
+	if errors.Is(err, provision.ErrRoleNotFound) {
 		WriteError(c.Response(), 400, "role_not_found", "role not found", nil)
 		return nil
 	}
-	n, _ := util.Numeric(0)
-	coins, luck, limit := int32(200), int32(0), int32(4)
-	for key, dst := range map[string]*int32{"default_coins": &coins, "default_luck": &luck, "default_item_limit": &limit} {
-		if v, e := q.GetGameConfig(ctx, key); e == nil {
-			if x, e := strconv.ParseInt(v, 10, 32); e == nil {
-				*dst = int32(x)
-			}
-		}
-	}
-	p, err := q.CreatePlayer(ctx, models.CreatePlayerParams{ID: id, RoleID: pgtype.Int4{Int32: r.ID, Valid: true}, Alive: true, Coins: coins, CoinBonus: n, Luck: luck, ItemLimit: limit, Alignment: r.Alignment})
 	if err != nil {
 		WriteError(c.Response(), 400, "player_create_failed", "could not create player", nil)
 		return nil
 	}
-	WriteJSON(c.Response(), 201, playerDTOFor(p, r.Name))
+	lifeboard.RefreshQuietly(ctx, h.pool, h.discord)
+	WriteJSON(c.Response(), 201, playerDTOFor(created.Player, created.Role.Name))
 	return nil
 }
 func (h *PlayersHandler) Update(c echo.Context) error {
@@ -253,12 +282,14 @@ func (h *PlayersHandler) Update(c echo.Context) error {
 		return nil
 	}
 	q := models.New(h.pool)
+	before := p
 	if in.Coins != nil {
 		p.Coins = *in.Coins
 	}
 	if in.Luck != nil {
 		p.Luck = *in.Luck
 	}
+	wasAlive := p.Alive
 	if in.Alive != nil {
 		p.Alive = *in.Alive
 	}
@@ -291,7 +322,25 @@ func (h *PlayersHandler) Update(c echo.Context) error {
 		WriteError(c.Response(), 400, "player_update_failed", "could not update player", nil)
 		return nil
 	}
-	WriteJSON(c.Response(), 200, playerDTOFor(p, role))
+	out := playerUpdateDTO{playerDTO: playerDTOFor(p, role)}
+	if p.Alive != wasAlive {
+		result := death.New(h.pool, h.discord).Run(ctx, p, death.Options{Announce: in.Announce})
+		out.Warnings, out.LuckyCoin = result.Warnings, result.LuckyCoin
+	} else {
+		var changes []inventory.Change
+		if p.Coins != before.Coins {
+			changes = append(changes, inventory.Change{Name: "Coins", Delta: p.Coins - before.Coins})
+		}
+		if p.Luck != before.Luck {
+			changes = append(changes, inventory.Change{Name: "Luck", Delta: p.Luck - before.Luck})
+		}
+		out.Warnings = inventory.NewNotifier(h.pool, h.discord).Changed(ctx, p.ID, in.Notify, changes...)
+		if in.Role != nil {
+			// Dead players' roles may be revealed on the board.
+			lifeboard.RefreshQuietly(ctx, h.pool, h.discord)
+		}
+	}
+	WriteJSON(c.Response(), 200, out)
 	return nil
 }
 
@@ -302,8 +351,7 @@ func (h *PlayersHandler) UpdateState(c echo.Context) error { return h.Update(c)
 
 // Delete removes a player from the roster. Dependent rows (inventory,
 // confessional, votes, whisper membership, notes) cascade via ON DELETE
-// CASCADE; the lifeboard channel is not FK-bound and must be rebuilt with
-// `/channel lifeboard set` afterwards.
+// CASCADE; the posted lifeboard is refreshed when Discord is connected.
 func (h *PlayersHandler) Delete(c echo.Context) error {
 	id, ok := playerID(c)
 	if !ok {
@@ -320,6 +368,7 @@ func (h *PlayersHandler) Delete(c echo.Context) error {
 		WriteError(c.Response(), 500, "player_delete_failed", "could not delete player", nil)
 		return nil
 	}
+	lifeboard.RefreshQuietly(ctx, h.pool, h.discord)
 	c.NoContent(204)
 	return nil
 }
@@ -347,12 +396,22 @@ func (h *PlayersHandler) mutate(c echo.Context, op string) error {
 		name = strings.TrimSpace(in.Info)
 	}
 	ih := inventory.NewManualInventoryHandler(p, h.pool)
+	// changes feeds the confessional notice; notes are host-only and
+	// re-render the inventory without one.
+	var changes []inventory.Change
 	var opErr error
 	switch op {
 	case "item_add":
-		_, opErr = ih.AddItem(name, maxQuantity(in.Quantity, 1))
+		qty := maxQuantity(in.Quantity, 1)
+		item, e := ih.AddItem(name, qty)
+		if opErr = e; e == nil {
+			changes = append(changes, inventory.Change{Name: item.Name, Delta: qty})
+		}
 	case "item_remove":
-		_, opErr = ih.RemoveItem(name, 1)
+		item, e := ih.RemoveItem(name, 1)
+		if opErr = e; e == nil {
+			changes = append(changes, inventory.Change{Name: item.Name, Delta: -1})
+		}
 	case "item_buy":
 		item, e := q.GetItemByFuzzy(ctx, name)
 		if e != nil {
@@ -364,21 +423,38 @@ func (h *PlayersHandler) mutate(c echo.Context, op string) error {
 			if opErr == nil {
 				_, opErr = q.UpdatePlayerCoins(ctx, models.UpdatePlayerCoinsParams{ID: id, Coins: p.Coins - item.Cost})
 			}
+			if opErr == nil {
+				changes = append(changes, inventory.Change{Name: item.Name, Delta: 1}, inventory.Change{Name: "Coins", Delta: -item.Cost})
+			}
 		}
 	case "ability_add":
-		_, opErr = ih.AddAbility(name, in.Quantity)
+		ability, e := ih.AddAbility(name, in.Quantity)
+		if opErr = e; e == nil {
+			changes = append(changes, inventory.Change{Name: ability.Name + " added"})
+		}
 	case "ability_remove":
-		_, opErr = ih.RemoveAbility(name)
+		ability, e := ih.RemoveAbility(name)
+		if opErr = e; e == nil {
+			changes = append(changes, inventory.Change{Name: ability.Name + " removed"})
+		}
 	case "status_add":
-		_, opErr = ih.AddStatus(name, maxQuantity(in.Quantity, 1))
+		qty := maxQuantity(in.Quantity, 1)
+		status, e := ih.AddStatus(name, qty)
+		if opErr = e; e == nil {
+			changes = append(changes, inventory.Change{Name: status.Name, Delta: qty})
+		}
 	case "status_remove":
-		_, opErr = ih.RemoveStatus(name, 1)
+		status, e := ih.RemoveStatus(name, 1)
+		if opErr = e; e == nil {
+			changes = append(changes, inventory.Change{Name: status.Name, Delta: -1})
+		}
 	case "immunity_add":
 		st, e := q.GetStatusByFuzzy(ctx, name)
 		if e != nil {
 			opErr = fmt.Errorf("immunity not found")
 		} else {
 			_, opErr = q.CreateOneTimePlayerImmunityJoin(ctx, models.CreateOneTimePlayerImmunityJoinParams{PlayerID: id, StatusID: st.ID, OneTime: in.OneTime})
+			changes = append(changes, inventory.Change{Name: st.Name + " immunity added"})
 		}
 	case "immunity_remove":
 		st, e := q.GetStatusByFuzzy(ctx, name)
@@ -386,12 +462,14 @@ func (h *PlayersHandler) mutate(c echo.Context, op string) error {
 			opErr = e
 		} else {
 			opErr = q.DeletePlayerImmunity(ctx, models.DeletePlayerImmunityParams{PlayerID: id, StatusID: st.ID})
+			changes = append(changes, inventory.Change{Name: st.Name + " immunity removed"})
 		}
 	case "note_add":
 		if in.Position < 1 || strings.TrimSpace(in.Info) == "" {
 			opErr = fmt.Errorf("position and info are required")
 		} else {
-			_, opErr = q.CreatePlayerNote(ctx, models.CreatePlayerNoteParams{PlayerID: id, Position: in.Position, Info: in.Info})
+			draft := playernotes.Draft{Info: in.Info, Tags: in.Tags, Author: "web", Day: in.Day}
+			_, opErr = playernotes.New(h.pool).CreateNote(ctx, playernotes.WebAdminAuthorization(), id, int(in.Position), draft)
 		}
 	case "note_remove":
 		opErr = q.DeletePlayerNote(ctx, models.DeletePlayerNoteParams{PlayerID: id, NoteID: in.NoteID})
@@ -400,7 +478,8 @@ func (h *PlayersHandler) mutate(c echo.Context, op string) error {
 		WriteError(c.Response(), 400, "player_mutation_failed", opErr.Error(), nil)
 		return nil
 	}
-	return h.Detail(c)
+	warnings := inventory.NewNotifier(h.pool, h.discord).Changed(ctx, id, in.Notify, changes...)
+	return h.writeDetail(c, warnings)
 }
 func maxQuantity(n, def int32) int32 {
 	if n < 1 {
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/bulkinv"
+)
+
+type playerBulkInput struct {
+	Filter struct {
+		Alive     *bool             `json:"alive"`
+		Alignment string            `json:"alignment"`
+		Role      string            `json:"role"`
+		Status    string            `json:"status"`
+		PlayerIDs []json.RawMessage `json:"player_ids"`
+	} `json:"filter"`
+	Operations []bulkinv.Operation `json:"operations"`
+	// Apply writes the changes; without it the request is a preview.
+	Apply bool `json:"apply"`
+	// Notify overrides whether an "Inventory updated" notice is posted in
+	// each changed player's confessional.
+	Notify *bool `json:"notify"`
+}
+
+// Bulk previews or applies the same inventory operations to every player
+// matching the filter. Changes are written in one transaction: if any
+// player fails nothing is written, and the 422 body names that player.
+func (h *PlayersHandler) Bulk(c echo.Context) error {
+	var in playerBulkInput
+	if decodePlayer(c, &in) != nil {
+		return nil
+	}
+	req := bulkinv.Request{
+		Filter: bulkinv.Filter{
+			Alive:     in.Filter.Alive,
+			Alignment: models.Alignment(in.Filter.Alignment),
+			Role:      in.Filter.Role,
+			Status:    in.Filter.Status,
+		},
+		Operations: in.Operations,
+	}
+	for _, raw := range in.Filter.PlayerIDs {
+		id, err := parsePlayerID(raw)
+		if err != nil {
+			WriteError(c.Response(), http.StatusBadRequest, "invalid_player_id", "player_ids must be Discord user IDs", nil)
+			return nil
+		}
+		req.Filter.PlayerIDs = append(req.Filter.PlayerIDs, id)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 30*time.Second)
+	defer cancel()
+	svc := bulkinv.New(h.pool, h.discord)
+	var report bulkinv.Report
+	var err error
+	if in.Apply {
+		report, err = svc.Apply(ctx, req, in.Notify)
+	} else {
+		report, err = svc.Preview(ctx, req)
+	}
+	switch {
+	case errors.Is(err, bulkinv.ErrNoOperations), errors.Is(err, bulkinv.ErrInvalidKind), errors.Is(err, bulkinv.ErrZeroAmount),
+		errors.Is(err, bulkinv.ErrMissingName), errors.Is(err, bulkinv.ErrBadAlignment):
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_bulk_change", err.Error(), nil)
+		return nil
+	case errors.Is(err, bulkinv.ErrUnknownTarget):
+		WriteError(c.Response(), http.StatusNotFound, "not_found", err.Error(), nil)
+		return nil
+	case errors.Is(err, bulkinv.ErrNoPlayers):
+		WriteError(c.Response(), http.StatusUnprocessableEntity, "no_players", err.Error(), nil)
+		return nil
+	case err != nil && len(report.Players) > 0:
+		// A player failed mid-run; the report says which.
+		WriteJSON(c.Response(), http.StatusUnprocessableEntity, report)
+		return nil
+	case err != nil:
+		WriteError(c.Response(), http.StatusInternalServerError, "bulk_change_failed", "could not run the bulk change", nil)
+		return nil
+	}
+	WriteJSON(c.Response(), http.StatusOK, report)
+	return nil
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mccune1224/betrayal/internal/services/death"
+)
+
+// DeathPipeline returns what runs when a player dies or is revived.
+func (h *PlayersHandler) DeathPipeline(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	cfg, err := death.New(h.pool, h.discord).Config(ctx)
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "death_pipeline_unavailable", "could not load the death pipeline config", nil)
+		return nil
+	}
+	WriteJSON(c.Response(), http.StatusOK, cfg)
+	return nil
+}
+
+// SetDeathPipeline replaces the death pipeline config. Role IDs are Discord
+// role snowflakes; graveyard_category is a category name.
+func (h *PlayersHandler) SetDeathPipeline(c echo.Context) error {
+	var in death.Config
+	if decodePlayer(c, &in) != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	cfg, err := death.New(h.pool, h.discord).SetConfig(ctx, in)
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "death_pipeline_unavailable", "could not save the death pipeline config", nil)
+		return nil
+	}
+	WriteJSON(c.Response(), http.StatusOK, cfg)
+	return nil
+}
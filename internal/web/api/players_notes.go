@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/playernotes"
+)
+
+// maxNotesSearchLimit caps ?limit= on the note search.
+const maxNotesSearchLimit = 200
+
+func playerNoteDTOFor(note models.PlayerNote) playerNoteDTO {
+	dto := playerNoteDTO{ID: note.NoteID, Position: note.Position, Info: note.Info, Tags: note.Tags, Author: note.Author, LinkedPlayers: make([]string, len(note.LinkedPlayerIds))}
+	if dto.Tags == nil {
+		dto.Tags = []string{}
+	}
+	if note.CycleDay.Valid {
+		dto.Day = &note.CycleDay.Int32
+	}
+	for i, id := range note.LinkedPlayerIds {
+		dto.LinkedPlayers[i] = strconv.FormatInt(id, 10)
+	}
+	return dto
+}
+
+// SearchNotes searches every player's notes. ?q= is full-text, ?tag= may
+// repeat and every tag must match, ?day= is the cycle day, ?player_id=
+// matches notes on or mentioning that player, and ?limit= defaults to 50.
+func (h *PlayersHandler) SearchNotes(c echo.Context) error {
+	search := playernotes.Search{Query: c.QueryParam("q"), Tags: c.QueryParams()["tag"]}
+	if raw := c.QueryParam("day"); raw != "" {
+		day, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			WriteError(c.Response(), http.StatusBadRequest, "invalid_day", "day must be a number", nil)
+			return nil
+		}
+		d := int32(day)
+		search.Day = &d
+	}
+	if raw := c.QueryParam("player_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			WriteError(c.Response(), http.StatusBadRequest, "invalid_player_id", "player_id must be numeric", nil)
+			return nil
+		}
+		search.PlayerID = id
+	}
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil || limit < 1 || limit > maxNotesSearchLimit {
+			WriteError(c.Response(), http.StatusBadRequest, "invalid_limit", "limit must be between 1 and 200", nil)
+			return nil
+		}
+		search.Limit = int32(limit)
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	notes, err := playernotes.New(h.pool).Search(ctx, playernotes.WebAdminAuthorization(), search)
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "notes_unavailable", "could not search the notes", nil)
+		return nil
+	}
+	out := make([]playerNoteDTO, len(notes))
+	for i, note := range notes {
+		out[i] = playerNoteDTOFor(note)
+		out[i].PlayerID = strconv.FormatInt(note.PlayerID, 10)
+	}
+	WriteJSON(c.Response(), http.StatusOK, out)
+	return nil
+}
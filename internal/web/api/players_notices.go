@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/inventory"
+)
+
+type inventoryNoticesDTO struct {
+	Enabled bool `json:"enabled"`
+}
+
+type inventoryNoticesInput struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// InventoryNotices reports whether web inventory edits post an "Inventory
+// updated" notice in the player's confessional by default.
+func (h *PlayersHandler) InventoryNotices(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	WriteJSON(c.Response(), http.StatusOK, inventoryNoticesDTO{Enabled: inventory.Notices(ctx, models.New(h.pool))})
+	return nil
+}
+
+// SetInventoryNotices changes the default; a mutation's notify field still
+// overrides it.
+func (h *PlayersHandler) SetInventoryNotices(c echo.Context) error {
+	var in inventoryNoticesInput
+	if decodePlayer(c, &in) != nil {
+		return nil
+	}
+	if in.Enabled == nil {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_inventory_notices", "enabled is required", nil)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	if err := inventory.SetNotices(ctx, models.New(h.pool), *in.Enabled); err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "inventory_notices_unavailable", "could not save the inventory notice setting", nil)
+		return nil
+	}
+	WriteJSON(c.Response(), http.StatusOK, inventoryNoticesDTO{Enabled: *in.Enabled})
+	return nil
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/substitution"
+)
+
+type playerSubstitutionDTO struct {
+	ID               int64      `json:"id"`
+	PlayerID         string     `json:"player_id"`
+	PreviousPlayerID string     `json:"previous_player_id"`
+	Reason           string     `json:"reason"`
+	CycleHistoryID   *int64     `json:"cycle_history_id"`
+	SubstitutedBy    string     `json:"substituted_by"`
+	CreatedAt        *time.Time `json:"created_at"`
+}
+
+func playerSubstitutionDTOFor(row models.PlayerSubstitution) playerSubstitutionDTO {
+	dto := playerSubstitutionDTO{
+		ID:               row.ID,
+		PlayerID:         strconv.FormatInt(row.PlayerID, 10),
+		PreviousPlayerID: strconv.FormatInt(row.PreviousPlayerID, 10),
+		Reason:           row.Reason,
+		SubstitutedBy:    row.SubstitutedBy,
+		CreatedAt:        nullableTimestamptz(row.CreatedAt),
+	}
+	if row.CycleHistoryID.Valid {
+		dto.CycleHistoryID = &row.CycleHistoryID.Int64
+	}
+	return dto
+}
+
+// Substitute hands the seat of :id to new_player_id. The player keeps its
+// role, inventory, votes and confessional under the new Discord user.
+func (h *PlayersHandler) Substitute(c echo.Context) error {
+	id, ok := playerID(c)
+	if !ok {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_player_id", "player ID must be a positive integer", nil)
+		return nil
+	}
+	var in struct {
+		NewPlayerID json.RawMessage `json:"new_player_id"`
+		Reason      string          `json:"reason"`
+	}
+	if decodePlayer(c, &in) != nil {
+		return nil
+	}
+	newID, err := parsePlayerID(in.NewPlayerID)
+	if err != nil {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_player_id", "new_player_id must be a Discord user ID", nil)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 30*time.Second)
+	defer cancel()
+	result, err := substitution.New(h.pool).Substitute(ctx, h.discord, substitution.Request{OldID: id, NewID: newID, Reason: in.Reason, By: "web"})
+	switch {
+	case errors.Is(err, substitution.ErrPlayerNotFound):
+		WriteError(c.Response(), http.StatusNotFound, "player_not_found", "player not found", nil)
+		return nil
+	case errors.Is(err, substitution.ErrAlreadyPlayer), errors.Is(err, substitution.ErrSameUser):
+		WriteError(c.Response(), http.StatusConflict, "player_substitution_conflict", err.Error(), nil)
+		return nil
+	case err != nil:
+		WriteError(c.Response(), http.StatusInternalServerError, "player_substitution_failed", "could not substitute player", nil)
+		return nil
+	}
+	warnings := result.Warnings
+	if warnings == nil {
+		warnings = []string{}
+	}
+	WriteJSON(c.Response(), http.StatusOK, map[string]any{
+		"substitution": playerSubstitutionDTOFor(result.Record),
+		"channel_id":   result.ChannelID,
+		"warnings":     warnings,
+	})
+	return nil
+}
+
+// Substitutions lists who held the seat of :id before, oldest first.
+func (h *PlayersHandler) Substitutions(c echo.Context) error {
+	id, ok := playerID(c)
+	if !ok {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_player_id", "player ID must be a positive integer", nil)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	rows, err := substitution.New(h.pool).History(ctx, id)
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "players_unavailable", "could not load substitutions", nil)
+		return nil
+	}
+	out := make([]playerSubstitutionDTO, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, playerSubstitutionDTOFor(row))
+	}
+	WriteJSON(c.Response(), http.StatusOK, map[string]any{"substitutions": out})
+	return nil
+}
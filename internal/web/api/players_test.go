@@ -9,7 +9,7 @@ import (
 
 func TestPlayersHandlerDetailRejectsInvalidIDAsJSON(t *testing.T) {
 	e := echo.New()
-	h := NewPlayersHandler(nil)
+	h := NewPlayersHandler(nil, nil)
 	req := httptest.NewRequest("GET", "/api/v1/players/not-an-id", nil)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
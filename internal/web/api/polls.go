@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/labstack/echo/v4"
+	pollsvc "github.com/mccune1224/betrayal/internal/services/poll"
+)
+
+type PollTallyDTO struct {
+	OptionID int64  `json:"option_id"`
+	Label    string `json:"label"`
+	Votes    int    `json:"votes"`
+}
+type PollOptionDTO struct {
+	ID       int64  `json:"id"`
+	Position int32  `json:"position"`
+	Label    string `json:"label"`
+	PlayerID string `json:"player_id,omitempty"`
+}
+type PollDTO struct {
+	ID               int64            `json:"id"`
+	Question         string           `json:"question"`
+	OptionKind       string           `json:"option_kind"`
+	Eligibility      string           `json:"eligibility"`
+	EligibilityValue string           `json:"eligibility_value,omitempty"`
+	Ranked           bool             `json:"ranked"`
+	Open             bool             `json:"open"`
+	CreatedBy        string           `json:"created_by"`
+	CreatedAt        *time.Time       `json:"created_at"`
+	ClosesAt         *time.Time       `json:"closes_at"`
+	ClosedAt         *time.Time       `json:"closed_at"`
+	Options          []PollOptionDTO  `json:"options"`
+	Voters           int              `json:"voters"`
+	Rounds           [][]PollTallyDTO `json:"rounds"`
+	Winners          []int64          `json:"winners"`
+}
+
+// PollsHandler exposes poll definitions and their current results.
+type PollsHandler struct{ polls *pollsvc.Service }
+
+func NewPollsHandler(pool *pgxpool.Pool) *PollsHandler {
+	return &PollsHandler{polls: pollsvc.New(pool)}
+}
+
+func (h *PollsHandler) List(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	polls, err := h.polls.List(ctx)
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "polls_unavailable", "could not load polls", nil)
+		return nil
+	}
+	result := make([]PollDTO, 0, len(polls))
+	for _, poll := range polls {
+		results, err := h.polls.Results(ctx, poll.ID)
+		if err != nil {
+			WriteError(c.Response(), http.StatusInternalServerError, "polls_unavailable", "could not tally polls", nil)
+			return nil
+		}
+		result = append(result, pollDTO(results, time.Now()))
+	}
+	WriteJSON(c.Response(), http.StatusOK, map[string]any{"polls": result})
+	return nil
+}
+
+func (h *PollsHandler) Get(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_request", "poll id must be numeric", nil)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	results, err := h.polls.Results(ctx, id)
+	if errors.Is(err, pollsvc.ErrPollNotFound) {
+		WriteError(c.Response(), http.StatusNotFound, "poll_not_found", "poll not found", nil)
+		return nil
+	}
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "polls_unavailable", "could not tally poll", nil)
+		return nil
+	}
+	WriteJSON(c.Response(), http.StatusOK, pollDTO(results, time.Now()))
+	return nil
+}
+
+func pollDTO(results pollsvc.Results, now time.Time) PollDTO {
+	poll := results.Poll
+	dto := PollDTO{
+		ID:               poll.ID,
+		Question:         poll.Question,
+		OptionKind:       poll.OptionKind,
+		Eligibility:      poll.Eligibility,
+		EligibilityValue: nullableText(poll.EligibilityValue),
+		Ranked:           poll.Ranked,
+		Open:             poll.Open(now),
+		CreatedBy:        poll.CreatedBy,
+		CreatedAt:        nullableTimestamptz(poll.CreatedAt),
+		ClosesAt:         nullableTimestamptz(poll.ClosesAt),
+		ClosedAt:         nullableTimestamptz(poll.ClosedAt),
+		Options:          make([]PollOptionDTO, 0, len(poll.Options)),
+		Voters:           results.Voters,
+		Rounds:           make([][]PollTallyDTO, 0, len(results.Rounds)),
+		Winners:          make([]int64, 0, len(results.Winners)),
+	}
+	for _, option := range poll.Options {
+		item := PollOptionDTO{ID: option.ID, Position: option.Position, Label: option.Label}
+		if option.PlayerID.Valid {
+			item.PlayerID = strconv.FormatInt(option.PlayerID.Int64, 10)
+		}
+		dto.Options = append(dto.Options, item)
+	}
+	for _, round := range results.Rounds {
+		tallies := make([]PollTallyDTO, len(round))
+		for i, tally := range round {
+			tallies[i] = PollTallyDTO{OptionID: tally.OptionID, Label: tally.Label, Votes: tally.Votes}
+		}
+		dto.Rounds = append(dto.Rounds, tallies)
+	}
+	for _, winner := range results.Winners {
+		dto.Winners = append(dto.Winners, winner.ID)
+	}
+	return dto
+}
+
+func nullableTimestamptz(value pgtype.Timestamptz) *time.Time {
+	if value.Valid {
+		result := value.Time
+		return &result
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/roledraft"
+)
+
+type roleDraftPickDTO struct {
+	Slot     int32      `json:"slot"`
+	RoleID   int32      `json:"role_id"`
+	PlayerID string     `json:"player_id,omitempty"`
+	PickedBy string     `json:"picked_by"`
+	PickedAt *time.Time `json:"picked_at"`
+}
+
+type roleDraftSeatDTO struct {
+	Role     RoleDTO `json:"role"`
+	PlayerID string  `json:"player_id,omitempty"`
+}
+
+type roleDraftDTO struct {
+	ID                int64                 `json:"id"`
+	Seed              int64                 `json:"seed"`
+	Status            string                `json:"status"`
+	PlayerCount       int32                 `json:"player_count"`
+	DeceptionistCount int32                 `json:"deceptionist_count"`
+	Constraints       roledraft.Constraints `json:"constraints"`
+	CreatedBy         string                `json:"created_by"`
+	CreatedAt         *time.Time            `json:"created_at"`
+	FinishedAt        *time.Time            `json:"finished_at"`
+	Pool              *SetupPoolDTO         `json:"pool,omitempty"`
+	Picks             []roleDraftPickDTO    `json:"picks,omitempty"`
+	Lineup            []roleDraftSeatDTO    `json:"lineup,omitempty"`
+}
+
+// Drafts lists the most recent role drafts without their pools.
+func (h *SetupHandler) Drafts(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	rows, err := roledraft.New(h.pool).List(ctx, 50)
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "setup_unavailable", "failed to load role drafts", nil)
+		return nil
+	}
+	drafts := make([]roleDraftDTO, 0, len(rows))
+	for _, row := range rows {
+		drafts = append(drafts, draftSummaryDTO(roledraft.Draft{RoleDraft: row}))
+	}
+	WriteJSON(c.Response(), http.StatusOK, map[string]any{"drafts": drafts})
+	return nil
+}
+
+// Draft returns one role draft with its pool, picks and current lineup.
+func (h *SetupHandler) Draft(c echo.Context) error {
+	id, ok := draftID(c)
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	draft, err := roledraft.New(h.pool).Get(ctx, id)
+	if err != nil {
+		return draftError(c, err)
+	}
+	WriteJSON(c.Response(), http.StatusOK, draftDTO(draft))
+	return nil
+}
+
+// PickDraftRole records a deceptionist's choice for a slot (1-based).
+func (h *SetupHandler) PickDraftRole(c echo.Context) error {
+	id, ok := draftID(c)
+	if !ok {
+		return nil
+	}
+	var req struct {
+		Slot     int    `json:"slot"`
+		Role     string `json:"role"`
+		PlayerID string `json:"player_id"`
+	}
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil || req.Slot < 1 || strings.TrimSpace(req.Role) == "" {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_request", "slot and role are required", nil)
+		return nil
+	}
+	var playerID int64
+	if req.PlayerID != "" {
+		parsed, err := strconv.ParseInt(req.PlayerID, 10, 64)
+		if err != nil || parsed <= 0 {
+			WriteError(c.Response(), http.StatusBadRequest, "invalid_request", "player_id must be a Discord user ID", nil)
+			return nil
+		}
+		playerID = parsed
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	draft, err := roledraft.New(h.pool).Pick(ctx, id, req.Slot-1, req.Role, playerID, "web")
+	if err != nil {
+		return draftError(c, err)
+	}
+	WriteJSON(c.Response(), http.StatusOK, draftDTO(draft))
+	return nil
+}
+
+// FinishDraft freezes a draft. Its lineup can then be sent to
+// POST /api/v1/ops/setup/roster as draft.draft_id.
+func (h *SetupHandler) FinishDraft(c echo.Context) error {
+	id, ok := draftID(c)
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	draft, err := roledraft.New(h.pool).Finish(ctx, id)
+	if err != nil {
+		return draftError(c, err)
+	}
+	WriteJSON(c.Response(), http.StatusOK, draftDTO(draft))
+	return nil
+}
+
+// ActiveRoles lists the roles new drafts are drawn from.
+func (h *SetupHandler) ActiveRoles(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	names, err := roledraft.New(h.pool).ActiveRoleNames(ctx)
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "setup_unavailable", "failed to load active roles", nil)
+		return nil
+	}
+	if names == nil {
+		names = []string{}
+	}
+	WriteJSON(c.Response(), http.StatusOK, map[string]any{"roles": names})
+	return nil
+}
+
+// AddActiveRole adds a catalog role to the active role list.
+func (h *SetupHandler) AddActiveRole(c echo.Context) error {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_request", "name is required", nil)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	role, err := roledraft.New(h.pool).AddActiveRole(ctx, req.Name)
+	if errors.Is(err, roledraft.ErrUnknownRole) {
+		WriteError(c.Response(), http.StatusNotFound, "role_not_found", err.Error(), nil)
+		return nil
+	}
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "setup_unavailable", "failed to update active roles", nil)
+		return nil
+	}
+	WriteJSON(c.Response(), http.StatusOK, RoleDTO{ID: role.ID, Name: role.Name, Description: role.Description, Alignment: string(role.Alignment)})
+	return nil
+}
+
+// RemoveActiveRole drops a role from the active role list.
+func (h *SetupHandler) RemoveActiveRole(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	err := roledraft.New(h.pool).RemoveActiveRole(ctx, c.Param("name"))
+	if errors.Is(err, roledraft.ErrRoleNotActive) {
+		WriteError(c.Response(), http.StatusNotFound, "role_not_found", err.Error(), nil)
+		return nil
+	}
+	if err != nil {
+		WriteError(c.Response(), http.StatusInternalServerError, "setup_unavailable", "failed to update active roles", nil)
+		return nil
+	}
+	c.NoContent(http.StatusNoContent)
+	return nil
+}
+
+func draftID(c echo.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_request", "draft id must be positive", nil)
+		return 0, false
+	}
+	return id, true
+}
+
+func draftError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, roledraft.ErrDraftNotFound):
+		WriteError(c.Response(), http.StatusNotFound, "draft_not_found", err.Error(), nil)
+	case errors.Is(err, roledraft.ErrDraftFinished), errors.Is(err, roledraft.ErrDraftOpen):
+		WriteError(c.Response(), http.StatusConflict, "draft_conflict", err.Error(), nil)
+	case errors.Is(err, roledraft.ErrInvalidPick):
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_pick", err.Error(), nil)
+	default:
+		WriteError(c.Response(), http.StatusInternalServerError, "setup_unavailable", "failed to update role draft", nil)
+	}
+	return nil
+}
+
+func draftSummaryDTO(draft roledraft.Draft) roleDraftDTO {
+	return roleDraftDTO{
+		ID: draft.ID, Seed: draft.Seed, Status: draft.Status,
+		PlayerCount: draft.PlayerCount, DeceptionistCount: draft.DeceptionistCount,
+		Constraints: draft.Constraints(), CreatedBy: draft.CreatedBy,
+		CreatedAt: nullableTimestamptz(draft.CreatedAt), FinishedAt: nullableTimestamptz(draft.FinishedAt),
+	}
+}
+
+func draftDTO(draft roledraft.Draft) roleDraftDTO {
+	dto := draftSummaryDTO(draft)
+	dto.Pool = setupPoolDTO(&draft.Pool)
+	dto.Picks = make([]roleDraftPickDTO, 0, len(draft.Picks))
+	for _, pick := range draft.Picks {
+		p := roleDraftPickDTO{Slot: pick.Slot + 1, RoleID: pick.RoleID, PickedBy: pick.PickedBy, PickedAt: nullableTimestamptz(pick.PickedAt)}
+		if pick.PlayerID.Valid {
+			p.PlayerID = strconv.FormatInt(pick.PlayerID.Int64, 10)
+		}
+		dto.Picks = append(dto.Picks, p)
+	}
+	for _, seat := range draft.Lineup() {
+		s := roleDraftSeatDTO{Role: RoleDTO{ID: seat.Role.ID, Name: seat.Role.Name, Description: seat.Role.Description, Alignment: string(seat.Role.Alignment)}}
+		if seat.PlayerID != 0 {
+			s.PlayerID = strconv.FormatInt(seat.PlayerID, 10)
+		}
+		dto.Lineup = append(dto.Lineup, s)
+	}
+	return dto
+}
+
+// upperAlignments lets constraint keys be written in any case.
+func upperAlignments(c roledraft.Constraints) roledraft.Constraints {
+	normalize := func(limits map[models.Alignment]int) map[models.Alignment]int {
+		out := make(map[models.Alignment]int, len(limits))
+		for alignment, value := range limits {
+			out[models.Alignment(strings.ToUpper(string(alignment)))] = value
+		}
+		return out
+	}
+	c.Min, c.Max = normalize(c.Min), normalize(c.Max)
+	return c
+}
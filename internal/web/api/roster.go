@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mccune1224/betrayal/internal/services/roledraft"
+	"github.com/mccune1224/betrayal/internal/services/roster"
+)
+
+type rosterDTO struct {
+	Valid    bool            `json:"valid"`
+	Report   roster.Report   `json:"report"`
+	Problems []string        `json:"problems"`
+	Results  []roster.Result `json:"results,omitempty"`
+	Applied  bool            `json:"applied"`
+	Seed     *int64          `json:"seed,omitempty"`
+}
+
+// Roster previews or applies a bulk roster. The roster comes from exactly one
+// of data (raw CSV or JSON file contents), entries, or draft, which deals the
+// lineup of a finished draft (draft_id) or a freshly generated pool to the
+// listed players. A generated pool is dealt from draft.seed (random when the
+// preview omits it) and the preview returns the seed; confirm requires it so
+// the hosts get the lineup they approved. Without confirm only the preview is
+// returned; with confirm a valid roster provisions every player and
+// confessional in guild_id (defaulting to the bot's only guild).
+func (h *SetupHandler) Roster(c echo.Context) error {
+	var req struct {
+		Data    string         `json:"data"`
+		Entries []roster.Entry `json:"entries"`
+		Draft   *struct {
+			DraftID           int64    `json:"draft_id"`
+			PlayerIDs         []string `json:"player_ids"`
+			DeceptionistCount int      `json:"deceptionist_count"`
+			Seed              *int64   `json:"seed"`
+		} `json:"draft"`
+		GuildID string `json:"guild_id"`
+		Confirm bool   `json:"confirm"`
+	}
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_request", "invalid roster request", nil)
+		return nil
+	}
+	sources := 0
+	for _, set := range []bool{req.Data != "", len(req.Entries) > 0, req.Draft != nil} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_request", "provide exactly one of data, entries or draft", nil)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 2*time.Minute)
+	defer cancel()
+
+	entries := req.Entries
+	var seed *int64
+	switch {
+	case req.Data != "":
+		parsed, err := roster.Parse([]byte(req.Data))
+		if err != nil {
+			WriteError(c.Response(), http.StatusBadRequest, "invalid_roster", err.Error(), nil)
+			return nil
+		}
+		entries = parsed
+	case req.Draft != nil && req.Draft.DraftID > 0:
+		draft, err := roledraft.New(h.pool).Get(ctx, req.Draft.DraftID)
+		if err == nil && !draft.Finished() {
+			err = roledraft.ErrDraftOpen
+		}
+		if err != nil {
+			return draftError(c, err)
+		}
+		if entries, err = roster.FromLineup(req.Draft.PlayerIDs, draft.Lineup()); err != nil {
+			WriteError(c.Response(), http.StatusBadRequest, "invalid_request", err.Error(), nil)
+			return nil
+		}
+	case req.Draft != nil:
+		if req.Draft.Seed == nil && req.Confirm {
+			WriteError(c.Response(), http.StatusBadRequest, "invalid_request", "draft.seed from the preview is required to confirm a generated draft", nil)
+			return nil
+		}
+		roles, err := roledraft.LoadRoles(ctx, h.pool)
+		if err != nil {
+			WriteError(c.Response(), http.StatusInternalServerError, "setup_unavailable", "failed to load active roles", nil)
+			return nil
+		}
+		opts := roledraft.Options{Players: len(req.Draft.PlayerIDs), Deceptionists: req.Draft.DeceptionistCount, Seed: rand.Int63()}
+		if req.Draft.Seed != nil {
+			opts.Seed = *req.Draft.Seed
+		}
+		seed = &opts.Seed
+		draft, err := roledraft.Draw(roles, opts)
+		if err != nil {
+			WriteError(c.Response(), http.StatusBadRequest, "invalid_request", err.Error(), nil)
+			return nil
+		}
+		if entries, err = roster.FromDraft(req.Draft.PlayerIDs, draft); err != nil {
+			WriteError(c.Response(), http.StatusBadRequest, "invalid_request", err.Error(), nil)
+			return nil
+		}
+	}
+
+	svc := roster.New(h.pool)
+	if !req.Confirm {
+		report, err := svc.Preview(ctx, entries)
+		if errors.Is(err, roster.ErrEmptyRoster) {
+			WriteError(c.Response(), http.StatusBadRequest, "invalid_roster", err.Error(), nil)
+			return nil
+		}
+		if err != nil {
+			WriteError(c.Response(), http.StatusInternalServerError, "roster_unavailable", "could not validate roster", nil)
+			return nil
+		}
+		WriteJSON(c.Response(), http.StatusOK, rosterDTO{Valid: report.Valid(), Report: report, Problems: report.Problems(), Seed: seed})
+		return nil
+	}
+
+	guildID := req.GuildID
+	if guildID == "" && h.discord != nil && h.discord.State != nil && len(h.discord.State.Guilds) == 1 {
+		guildID = h.discord.State.Guilds[0].ID
+	}
+	if h.discord != nil && guildID == "" {
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_request", "guild_id is required when the bot is in several guilds", nil)
+		return nil
+	}
+	report, results, err := svc.Apply(ctx, entries, h.discord, guildID)
+	switch {
+	case errors.Is(err, roster.ErrInvalidRoster):
+		WriteJSON(c.Response(), http.StatusUnprocessableEntity, rosterDTO{Report: report, Problems: report.Problems(), Seed: seed})
+		return nil
+	case errors.Is(err, roster.ErrEmptyRoster):
+		WriteError(c.Response(), http.StatusBadRequest, "invalid_roster", err.Error(), nil)
+		return nil
+	case err != nil:
+		WriteError(c.Response(), http.StatusInternalServerError, "roster_apply_failed", "could not apply roster", nil)
+		return nil
+	}
+	WriteJSON(c.Response(), http.StatusOK, rosterDTO{Valid: true, Report: report, Problems: []string{}, Results: results, Applied: true, Seed: seed})
+	return nil
+}
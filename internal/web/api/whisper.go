@@ -9,9 +9,11 @@ import (
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
 	"github.com/mccune1224/betrayal/internal/models"
+	whispersvc "github.com/mccune1224/betrayal/internal/services/whisper"
 )
 
 type WhisperHandler struct {
@@ -31,6 +33,12 @@ type whisperGroupDTO struct {
 	ID      int64    `json:"id"`
 	Name    string   `json:"name"`
 	Players []string `json:"players"`
+	// WhisperAllowance is whispers per phase; null means unlimited.
+	WhisperAllowance *int32 `json:"whisper_allowance"`
+	AllowanceScope   string `json:"allowance_scope"`
+	// SuspicionChance is 0 to 1; null means the global chance.
+	SuspicionChance *float64 `json:"suspicion_chance"`
+	DoubtMode       string   `json:"doubt_mode"`
 }
 type whisperPlayerDTO struct {
 	ID     string `json:"id"`
@@ -41,6 +49,8 @@ type whisperMessageDTO struct {
 	ID      int64  `json:"id"`
 	Message string `json:"message"`
 	Enabled bool   `json:"enabled"`
+	// GroupID is set for a group's private doubt pool.
+	GroupID *int64 `json:"group_id"`
 }
 type whisperDTO struct {
 	Groups   []whisperGroupDTO   `json:"groups"`
@@ -70,7 +80,7 @@ func (h *WhisperHandler) Get(c echo.Context) error {
 	for _, row := range groups {
 		index, ok := byID[row.ID]
 		if !ok {
-			result.Groups = append(result.Groups, whisperGroupDTO{ID: row.ID, Name: row.Name, Players: []string{}})
+			result.Groups = append(result.Groups, whisperGroupDTO{ID: row.ID, Name: row.Name, Players: []string{}, WhisperAllowance: nullableInt4(row.WhisperAllowance), AllowanceScope: row.AllowanceScope, SuspicionChance: nullableFloat8(row.SuspicionChance), DoubtMode: row.DoubtMode})
 			index = len(result.Groups) - 1
 			byID[row.ID] = index
 		}
@@ -89,7 +99,7 @@ func (h *WhisperHandler) Get(c echo.Context) error {
 		result.Players = append(result.Players, whisperPlayerDTO{ID: id, Label: label, Detail: detail})
 	}
 	for _, message := range messages {
-		result.Messages = append(result.Messages, whisperMessageDTO{ID: message.ID, Message: message.Message, Enabled: message.Enabled})
+		result.Messages = append(result.Messages, messageDTO(message))
 	}
 	WriteJSON(c.Response(), http.StatusOK, result)
 	return nil
@@ -127,7 +137,7 @@ func (h *WhisperHandler) CreateGroup(c echo.Context) error {
 	if err != nil {
 		return whisperFailure(c, "whisper_group_create_failed")
 	}
-	WriteJSON(c.Response(), http.StatusCreated, whisperGroupDTO{ID: group.ID, Name: group.Name, Players: []string{}})
+	WriteJSON(c.Response(), http.StatusCreated, groupDTO(group))
 	return nil
 }
 
@@ -180,17 +190,25 @@ func (h *WhisperHandler) memberMutation(c echo.Context, add bool) error {
 func (h *WhisperHandler) CreateMessage(c echo.Context) error {
 	var req struct {
 		Message string `json:"message"`
+		GroupID *int64 `json:"group_id"`
 	}
 	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil || strings.TrimSpace(req.Message) == "" || len([]rune(req.Message)) > 1000 {
 		return whisperBad(c, "message is required and must be at most 1000 characters")
 	}
+	params := models.CreateWhisperDoubtMessageParams{Message: strings.TrimSpace(req.Message)}
+	if req.GroupID != nil {
+		if *req.GroupID <= 0 {
+			return whisperBad(c, "group_id must be positive")
+		}
+		params.GroupID = pgtype.Int8{Int64: *req.GroupID, Valid: true}
+	}
 	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
 	defer cancel()
-	message, err := models.New(h.pool).CreateWhisperDoubtMessage(ctx, strings.TrimSpace(req.Message))
+	message, err := models.New(h.pool).CreateWhisperDoubtMessage(ctx, params)
 	if err != nil {
 		return whisperFailure(c, "whisper_message_create_failed")
 	}
-	WriteJSON(c.Response(), http.StatusCreated, whisperMessageDTO{ID: message.ID, Message: message.Message, Enabled: message.Enabled})
+	WriteJSON(c.Response(), http.StatusCreated, messageDTO(message))
 	return nil
 }
 
@@ -212,7 +230,7 @@ func (h *WhisperHandler) UpdateMessage(c echo.Context) error {
 	if err != nil {
 		return whisperFailure(c, "whisper_message_update_failed")
 	}
-	WriteJSON(c.Response(), http.StatusOK, whisperMessageDTO{ID: message.ID, Message: message.Message, Enabled: message.Enabled})
+	WriteJSON(c.Response(), http.StatusOK, messageDTO(message))
 	return nil
 }
 
@@ -230,6 +248,97 @@ func (h *WhisperHandler) DeleteMessage(c echo.Context) error {
 	return nil
 }
 
+type whisperTranscriptDTO struct {
+	ID               int64      `json:"id"`
+	SenderID         string     `json:"sender_id"`
+	SenderLabel      string     `json:"sender_label,omitempty"`
+	GroupID          int64      `json:"group_id"`
+	GroupName        string     `json:"group_name"`
+	OriginalMessage  string     `json:"original_message"`
+	DeliveredMessage string     `json:"delivered_message"`
+	WarningSent      bool       `json:"warning_sent"`
+	RecipientIDs     []string   `json:"recipient_ids"`
+	DeadRecipients   int32      `json:"dead_recipients"`
+	CycleDay         int32      `json:"cycle_day"`
+	CyclePhase       string     `json:"cycle_phase"`
+	CreatedAt        *time.Time `json:"created_at"`
+}
+
+// Transcripts lists delivered whispers, newest first, optionally filtered by
+// ?group_id= and ?day= (cycle day).
+func (h *WhisperHandler) Transcripts(c echo.Context) error {
+	filter := whispersvc.TranscriptFilter{}
+	if raw := c.QueryParam("group_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			return whisperBad(c, "group_id must be positive")
+		}
+		filter.GroupID = &id
+	}
+	if raw := c.QueryParam("day"); raw != "" {
+		day, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil || day < 0 {
+			return whisperBad(c, "day must be a non-negative integer")
+		}
+		day32 := int32(day)
+		filter.CycleDay = &day32
+	}
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > 500 {
+			return whisperBad(c, "limit must be between 1 and 500")
+		}
+		filter.Limit = int32(limit)
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	entries, err := whispersvc.New(h.pool).Transcripts(ctx, filter)
+	if err != nil {
+		return whisperFailure(c, "whisper_transcripts_unavailable")
+	}
+	names := h.discordPlayerNames()
+	result := make([]whisperTranscriptDTO, 0, len(entries))
+	for _, entry := range entries {
+		dto := whisperTranscriptDTO{
+			ID:               entry.ID,
+			SenderID:         strconv.FormatInt(entry.SenderID, 10),
+			GroupID:          entry.GroupID,
+			GroupName:        entry.GroupName,
+			OriginalMessage:  entry.OriginalMessage,
+			DeliveredMessage: entry.DeliveredMessage,
+			WarningSent:      entry.WarningSent,
+			RecipientIDs:     make([]string, 0, len(entry.RecipientIds)),
+			DeadRecipients:   entry.DeadRecipients,
+			CycleDay:         entry.CycleDay,
+			CyclePhase:       cycleDTO(models.GameCycle{Day: entry.CycleDay, IsElimination: entry.CycleIsElimination}).Phase,
+			CreatedAt:        nullableTimestamptz(entry.CreatedAt),
+		}
+		dto.SenderLabel = names[dto.SenderID][0]
+		for _, id := range entry.RecipientIds {
+			dto.RecipientIDs = append(dto.RecipientIDs, strconv.FormatInt(id, 10))
+		}
+		result = append(result, dto)
+	}
+	WriteJSON(c.Response(), http.StatusOK, map[string]any{"transcripts": result})
+	return nil
+}
+
+func groupDTO(group models.WhisperGroup) whisperGroupDTO {
+	return whisperGroupDTO{
+		ID: group.ID, Name: group.Name, Players: []string{},
+		WhisperAllowance: nullableInt4(group.WhisperAllowance), AllowanceScope: group.AllowanceScope,
+		SuspicionChance: nullableFloat8(group.SuspicionChance), DoubtMode: group.DoubtMode,
+	}
+}
+
+func messageDTO(message models.WhisperDoubtMessage) whisperMessageDTO {
+	dto := whisperMessageDTO{ID: message.ID, Message: message.Message, Enabled: message.Enabled}
+	if message.GroupID.Valid {
+		dto.GroupID = &message.GroupID.Int64
+	}
+	return dto
+}
+
 func whisperBad(c echo.Context, message string) error {
 	WriteError(c.Response(), http.StatusBadRequest, "invalid_whisper_request", message, nil)
 	return nil
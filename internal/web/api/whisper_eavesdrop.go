@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mccune1224/betrayal/internal/models"
+	whispersvc "github.com/mccune1224/betrayal/internal/services/whisper"
+)
+
+type whisperEavesdropDTO struct {
+	ID       int64  `json:"id"`
+	PlayerID string `json:"player_id"`
+	GroupID  int64  `json:"group_id"`
+	Redacted bool   `json:"redacted"`
+	// Phases and MessagesLeft are null when the effect has no such limit.
+	Phases        *int32 `json:"phases"`
+	PhasesElapsed int32  `json:"phases_elapsed"`
+	MessagesLeft  *int32 `json:"messages_left"`
+	// Paused is set while the listener is dead or has no confessional.
+	Paused    bool       `json:"paused"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+// Eavesdrops lists the running eavesdrop effects, optionally for one group.
+func (h *WhisperHandler) Eavesdrops(c echo.Context) error {
+	var groupID *int64
+	if raw := c.QueryParam("group_id"); raw != "" {
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || value <= 0 {
+			return whisperBad(c, "group_id must be a positive integer")
+		}
+		groupID = &value
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	rows, err := whispersvc.New(h.pool).Eavesdrops(ctx, groupID)
+	if err != nil {
+		return whisperFailure(c, "whisper_eavesdrops_unavailable")
+	}
+	result := make([]whisperEavesdropDTO, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, eavesdropDTO(row))
+	}
+	WriteJSON(c.Response(), http.StatusOK, map[string]any{"eavesdrops": result})
+	return nil
+}
+
+// AddEavesdrop lets a player secretly receive copies of a group's whispers.
+// Omitted or zero limits mean the effect runs until removed.
+func (h *WhisperHandler) AddEavesdrop(c echo.Context) error {
+	var req struct {
+		PlayerID string `json:"player_id"`
+		GroupID  int64  `json:"group_id"`
+		Phases   int    `json:"phases"`
+		Messages int    `json:"messages"`
+		Redacted bool   `json:"redacted"`
+	}
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return whisperBad(c, "player_id and group_id are required")
+	}
+	playerID, err := strconv.ParseInt(req.PlayerID, 10, 64)
+	if err != nil || playerID <= 0 || req.GroupID <= 0 {
+		return whisperBad(c, "player_id and group_id are required")
+	}
+	if req.Phases < 0 || req.Messages < 0 {
+		return whisperBad(c, "phases and messages must not be negative")
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	q := models.New(h.pool)
+	if _, err := q.GetWhisperGroup(ctx, req.GroupID); err != nil {
+		WriteError(c.Response(), http.StatusNotFound, "whisper_group_not_found", "whisper group not found", nil)
+		return nil
+	}
+	if _, err := q.GetPlayer(ctx, playerID); err != nil {
+		WriteError(c.Response(), http.StatusNotFound, "player_not_found", "player not found", nil)
+		return nil
+	}
+	row, err := whispersvc.New(h.pool).AddEavesdrop(ctx, whispersvc.Eavesdrop{
+		PlayerID: playerID, GroupID: req.GroupID, Redacted: req.Redacted,
+		Phases: req.Phases, Messages: req.Messages, By: "web",
+	})
+	if err != nil {
+		return whisperFailure(c, "whisper_eavesdrop_create_failed")
+	}
+	WriteJSON(c.Response(), http.StatusCreated, whisperEavesdropDTO{
+		ID: row.ID, PlayerID: strconv.FormatInt(row.PlayerID, 10), GroupID: row.GroupID, Redacted: row.Redacted,
+		Phases: nullableInt4(row.Phases), MessagesLeft: nullableInt4(row.MessagesLeft),
+		CreatedBy: row.CreatedBy, CreatedAt: nullableTimestamptz(row.CreatedAt),
+	})
+	return nil
+}
+
+// RemoveEavesdrop ends an eavesdrop early.
+func (h *WhisperHandler) RemoveEavesdrop(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return whisperBad(c, "eavesdrop id must be positive")
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	if _, err := whispersvc.New(h.pool).EndEavesdrop(ctx, id); errors.Is(err, whispersvc.ErrEavesdropNotFound) {
+		WriteError(c.Response(), http.StatusNotFound, "whisper_eavesdrop_not_found", "eavesdrop not found or already ended", nil)
+		return nil
+	} else if err != nil {
+		return whisperFailure(c, "whisper_eavesdrop_delete_failed")
+	}
+	c.NoContent(http.StatusNoContent)
+	return nil
+}
+
+func eavesdropDTO(row models.ListActiveWhisperEavesdropsRow) whisperEavesdropDTO {
+	return whisperEavesdropDTO{
+		ID: row.ID, PlayerID: strconv.FormatInt(row.PlayerID, 10), GroupID: row.GroupID, Redacted: row.Redacted,
+		Phases: nullableInt4(row.Phases), PhasesElapsed: row.PhasesElapsed, MessagesLeft: nullableInt4(row.MessagesLeft),
+		Paused:    !row.Alive || !row.ChannelID.Valid,
+		CreatedBy: row.CreatedBy, CreatedAt: nullableTimestamptz(row.CreatedAt),
+	}
+}
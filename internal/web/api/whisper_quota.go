@@ -0,0 +1,254 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v4"
+	whispersvc "github.com/mccune1224/betrayal/internal/services/whisper"
+)
+
+type whisperQuotaDTO struct {
+	PlayerID string `json:"player_id"`
+	GroupID  int64  `json:"group_id"`
+	Limited  bool   `json:"limited"`
+	Scope    string `json:"scope"`
+	// Remaining and Total are null when the group is unlimited.
+	Remaining *int `json:"remaining"`
+	Total     *int `json:"total"`
+	Allowance int  `json:"allowance"`
+	Bonus     int  `json:"bonus"`
+	Granted   int  `json:"granted"`
+	Used      int  `json:"used"`
+}
+
+type whisperBonusDTO struct {
+	ID     int64  `json:"id"`
+	ItemID *int32 `json:"item_id"`
+	PerkID *int32 `json:"perk_id"`
+	Name   string `json:"name"`
+	Extra  int32  `json:"extra"`
+}
+
+// SetQuota sets a group's per-phase allowance. A null allowance removes the
+// limit.
+func (h *WhisperHandler) SetQuota(c echo.Context) error {
+	groupID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || groupID <= 0 {
+		return whisperBad(c, "group id must be positive")
+	}
+	var req struct {
+		Allowance *int   `json:"allowance"`
+		Scope     string `json:"scope"`
+	}
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return whisperBad(c, "allowance must be a non-negative integer or null")
+	}
+	if req.Allowance != nil && *req.Allowance < 0 {
+		return whisperBad(c, "allowance must be a non-negative integer or null")
+	}
+	scope, err := whispersvc.ParseScope(req.Scope)
+	if err != nil {
+		return whisperBad(c, err.Error())
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	group, err := whispersvc.New(h.pool).SetAllowance(ctx, groupID, req.Allowance, scope)
+	if errors.Is(err, pgx.ErrNoRows) {
+		WriteError(c.Response(), http.StatusNotFound, "whisper_group_not_found", "whisper group not found", nil)
+		return nil
+	}
+	if err != nil {
+		return whisperFailure(c, "whisper_quota_update_failed")
+	}
+	WriteJSON(c.Response(), http.StatusOK, groupDTO(group))
+	return nil
+}
+
+// SetSuspicion sets a group's doubt chance (0 to 1) and mode. A null chance
+// restores the global chance.
+func (h *WhisperHandler) SetSuspicion(c echo.Context) error {
+	groupID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || groupID <= 0 {
+		return whisperBad(c, "group id must be positive")
+	}
+	var req struct {
+		Chance *float64 `json:"chance"`
+		Mode   string   `json:"mode"`
+	}
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil || (req.Chance != nil && (*req.Chance < 0 || *req.Chance > 1)) {
+		return whisperBad(c, "chance must be between 0 and 1 or null")
+	}
+	mode, err := whispersvc.ParseDoubtMode(req.Mode)
+	if err != nil {
+		return whisperBad(c, err.Error())
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	group, err := whispersvc.New(h.pool).SetSuspicion(ctx, groupID, req.Chance, mode)
+	if errors.Is(err, pgx.ErrNoRows) {
+		WriteError(c.Response(), http.StatusNotFound, "whisper_group_not_found", "whisper group not found", nil)
+		return nil
+	}
+	if err != nil {
+		return whisperFailure(c, "whisper_suspicion_update_failed")
+	}
+	WriteJSON(c.Response(), http.StatusOK, groupDTO(group))
+	return nil
+}
+
+// Quota reports a player's whisper budget for the current phase.
+func (h *WhisperHandler) Quota(c echo.Context) error {
+	playerID, err := strconv.ParseInt(c.Param("player_id"), 10, 64)
+	if err != nil || playerID <= 0 {
+		return whisperBad(c, "player_id must be a positive integer")
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	svc := whispersvc.New(h.pool)
+	groupID, err := svc.GroupOf(ctx, playerID)
+	if errors.Is(err, whispersvc.ErrNotInGroup) {
+		WriteError(c.Response(), http.StatusNotFound, "whisper_player_not_grouped", "player is not in a whisper group", nil)
+		return nil
+	}
+	if err != nil {
+		return whisperFailure(c, "whisper_quota_unavailable")
+	}
+	quota, err := svc.Quota(ctx, groupID, playerID)
+	if err != nil {
+		return whisperFailure(c, "whisper_quota_unavailable")
+	}
+	WriteJSON(c.Response(), http.StatusOK, quotaDTO(playerID, groupID, quota))
+	return nil
+}
+
+func (h *WhisperHandler) GrantQuota(c echo.Context) error { return h.adjustQuota(c, true) }
+func (h *WhisperHandler) ResetQuota(c echo.Context) error { return h.adjustQuota(c, false) }
+
+// adjustQuota grants or resets whispers for the current phase. The body names
+// a player_id or, for the whole group, a group_id.
+func (h *WhisperHandler) adjustQuota(c echo.Context, grant bool) error {
+	var req struct {
+		PlayerID string `json:"player_id"`
+		GroupID  int64  `json:"group_id"`
+		Amount   int    `json:"amount"`
+	}
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return whisperBad(c, "player_id or group_id is required")
+	}
+	if grant && req.Amount <= 0 {
+		return whisperBad(c, "amount must be positive")
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	svc := whispersvc.New(h.pool)
+	var target *int64
+	groupID := req.GroupID
+	if req.PlayerID != "" {
+		playerID, err := strconv.ParseInt(req.PlayerID, 10, 64)
+		if err != nil || playerID <= 0 {
+			return whisperBad(c, "player_id must be a positive integer")
+		}
+		if groupID, err = svc.GroupOf(ctx, playerID); errors.Is(err, whispersvc.ErrNotInGroup) {
+			WriteError(c.Response(), http.StatusNotFound, "whisper_player_not_grouped", "player is not in a whisper group", nil)
+			return nil
+		} else if err != nil {
+			return whisperFailure(c, "whisper_quota_update_failed")
+		}
+		target = &playerID
+	} else if groupID <= 0 {
+		return whisperBad(c, "player_id or group_id is required")
+	}
+	var err error
+	if grant {
+		_, err = svc.Grant(ctx, groupID, target, req.Amount, "web")
+	} else {
+		_, err = svc.Reset(ctx, groupID, target, "web")
+	}
+	if err != nil {
+		return whisperFailure(c, "whisper_quota_update_failed")
+	}
+	c.NoContent(http.StatusNoContent)
+	return nil
+}
+
+// Bonuses lists the items and perks that grant extra whispers per phase.
+func (h *WhisperHandler) Bonuses(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	rows, err := whispersvc.New(h.pool).Bonuses(ctx)
+	if err != nil {
+		return whisperFailure(c, "whisper_bonuses_unavailable")
+	}
+	result := make([]whisperBonusDTO, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, whisperBonusDTO{
+			ID: row.ID, ItemID: nullableInt4(row.ItemID), PerkID: nullableInt4(row.PerkID),
+			Name: row.SourceName, Extra: row.Extra,
+		})
+	}
+	WriteJSON(c.Response(), http.StatusOK, map[string]any{"bonuses": result})
+	return nil
+}
+
+// SetBonus sets the extra whispers granted by one item or perk; an extra of
+// zero removes the bonus.
+func (h *WhisperHandler) SetBonus(c echo.Context) error {
+	var req struct {
+		ItemID *int32 `json:"item_id"`
+		PerkID *int32 `json:"perk_id"`
+		Extra  int    `json:"extra"`
+	}
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil || (req.ItemID == nil) == (req.PerkID == nil) {
+		return whisperBad(c, "exactly one of item_id or perk_id is required")
+	}
+	if req.Extra < 0 {
+		return whisperBad(c, "extra must not be negative")
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+	svc := whispersvc.New(h.pool)
+	var err error
+	if req.ItemID != nil {
+		err = svc.SetItemBonus(ctx, *req.ItemID, req.Extra)
+	} else {
+		err = svc.SetPerkBonus(ctx, *req.PerkID, req.Extra)
+	}
+	if err != nil {
+		return whisperFailure(c, "whisper_bonus_update_failed")
+	}
+	return h.Bonuses(c)
+}
+
+func quotaDTO(playerID, groupID int64, quota whispersvc.Quota) whisperQuotaDTO {
+	dto := whisperQuotaDTO{
+		PlayerID: strconv.FormatInt(playerID, 10), GroupID: groupID,
+		Limited: quota.Limited, Scope: string(quota.Scope),
+		Allowance: quota.Allowance, Bonus: quota.Bonus, Granted: quota.Granted, Used: quota.Used,
+	}
+	if quota.Limited {
+		remaining, total := quota.Remaining(), quota.Total()
+		dto.Remaining, dto.Total = &remaining, &total
+	}
+	return dto
+}
+
+func nullableInt4(value pgtype.Int4) *int32 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Int32
+}
+
+func nullableFloat8(value pgtype.Float8) *float64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Float64
+}
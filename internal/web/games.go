@@ -0,0 +1,98 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/labstack/echo/v4"
+	"github.com/mccune1224/betrayal/internal/web/api"
+)
+
+// serverWide are the /api/v1 paths that act on the deployment rather than on
+// a game, so they never follow the session's game selection.
+var serverWide = []string{
+	"/api/v1/auth",
+	"/api/v1/health",
+	"/api/v1/games",
+	"/api/v1/admin/migrations",
+	"/api/v1/admin/redeploy",
+}
+
+// gameServer is the panel bound to one game's pool.
+type gameServer struct {
+	pool   *pgxpool.Pool
+	server *Server
+}
+
+// dispatchGames forwards game-scoped API requests to a server bound to the
+// game the session selected. Without a selection the request stays on the
+// primary game.
+func (s *Server) dispatchGames(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		path := c.Request().URL.Path
+		if !strings.HasPrefix(path, "/api/v1/") {
+			return next(c)
+		}
+		for _, prefix := range serverWide {
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				return next(c)
+			}
+		}
+		id := api.SelectedGame(s.sessionStore, c.Request())
+		if id == 0 {
+			return next(c)
+		}
+		pool, err := s.config.Games.GamePool(c.Request().Context(), id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			_ = api.SelectGame(s.sessionStore, c, 0)
+			api.WriteError(c.Response(), http.StatusConflict, "game_unavailable", "the selected game no longer exists; switched back to the primary game", nil)
+			return nil
+		}
+		if err != nil {
+			s.logger.Error().Err(err).Int64("game_id", id).Msg("game pool unavailable")
+			api.WriteError(c.Response(), http.StatusServiceUnavailable, "game_unavailable", "could not open the selected game", nil)
+			return nil
+		}
+		game, err := s.gameServer(id, pool)
+		if err != nil {
+			s.logger.Error().Err(err).Int64("game_id", id).Msg("game server unavailable")
+			api.WriteError(c.Response(), http.StatusServiceUnavailable, "game_unavailable", "could not open the selected game", nil)
+			return nil
+		}
+		game.Handler().ServeHTTP(c.Response(), c.Request())
+		return nil
+	}
+}
+
+// gameServer returns the cached server for game id, rebuilding it when the
+// game's pool was replaced.
+func (s *Server) gameServer(id int64, pool *pgxpool.Pool) (*Server, error) {
+	s.gamesMu.Lock()
+	defer s.gamesMu.Unlock()
+	if cached, ok := s.games[id]; ok && cached.pool == pool {
+		return cached.server, nil
+	}
+	cfg := s.config
+	cfg.Games = nil
+	server, err := New(pool, s.discordSession, s.logger, cfg)
+	if err != nil {
+		return nil, err
+	}
+	s.games[id] = gameServer{pool: pool, server: server}
+	return server, nil
+}
+
+// forgetGame drops the cached server of a deleted game.
+func (s *Server) forgetGame(id int64) {
+	s.gamesMu.Lock()
+	defer s.gamesMu.Unlock()
+	if cached, ok := s.games[id]; ok {
+		if cached.server.syncHandler != nil {
+			cached.server.syncHandler.Shutdown()
+		}
+		delete(s.games, id)
+	}
+}
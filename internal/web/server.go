@@ -17,7 +17,9 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	dbmigrate "github.com/mccune1224/betrayal/internal/db/migrate"
 	"github.com/mccune1224/betrayal/internal/services/datasync"
+	"github.com/mccune1224/betrayal/internal/services/gameimport"
 	"github.com/mccune1224/betrayal/internal/services/gamereset"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
 	"github.com/mccune1224/betrayal/internal/web/api"
 	webmiddleware "github.com/mccune1224/betrayal/internal/web/middleware"
 	"github.com/mccune1224/betrayal/internal/web/railway"
@@ -53,6 +55,9 @@ type Config struct {
 	// AllowUnsafeSyncURLs is intended only for localhost fixture tests. It
 	// permits HTTP/private hosts; production configuration must leave it false.
 	AllowUnsafeSyncURLs bool
+	// Games, when set, lets the panel switch between the primary game and
+	// the per-guild games it registers.
+	Games *tenancy.Registry
 
 	// Railway API configuration
 	RailwayToken     string
@@ -78,6 +83,9 @@ type Server struct {
 	// connect to boot and an unused connection for web-only runs).
 	migrateRunner     *dbmigrate.Runner
 	migrateRunnerOnce sync.Once
+
+	gamesMu sync.Mutex
+	games   map[int64]gameServer
 }
 
 // New creates a new web server. The admin password is also the sole source for
@@ -120,6 +128,7 @@ func New(pool *pgxpool.Pool, discord *discordgo.Session, logger zerolog.Logger,
 		sessionStore:   store,
 		railwayClient:  railwayClient,
 		syncService:    syncService,
+		games:          map[int64]gameServer{},
 	}
 
 	// Seed the canonical sync sources (URLs only when rows still have the
@@ -197,20 +206,28 @@ func (s *Server) setupMiddleware() {
 		},
 	}))
 
+	if s.config.Games != nil {
+		s.echo.Use(s.dispatchGames)
+	}
 }
 
 func (s *Server) setupRoutes() {
 	apiAuthHandler := api.NewAuthHandler(s.sessionStore, s.config.AdminPassword)
 	apiDashboardHandler := api.NewDashboardHandler(s.dbPool)
-	apiPlayersHandler := api.NewPlayersHandler(s.dbPool)
-	apiPlayersAdminHandler := api.NewPlayersHandler(s.dbPool)
+	apiPlayersHandler := api.NewPlayersHandler(s.dbPool, s.discordSession)
+	apiPlayersAdminHandler := api.NewPlayersHandler(s.dbPool, s.discordSession)
 	apiCatalogHandler := api.NewCatalogHandler(s.dbPool)
-	apiCycleHandler := api.NewCycleHandler(s.dbPool)
+	apiCycleHandler := api.NewCycleHandler(s.dbPool, s.discordSession)
 	apiChannelsHandler := api.NewChannelsHandler(s.dbPool, s.discordSession)
-	apiSetupHandler := api.NewSetupHandler(s.dbPool)
+	apiSetupHandler := api.NewSetupHandler(s.dbPool, s.discordSession)
 	apiVotesHandler := api.NewVotesHandler(s.dbPool)
+	apiGameHandler := api.NewGameHandler(s.dbPool)
+	apiAlliancesHandler := api.NewAlliancesHandler(s.dbPool, s.discordSession)
+	apiSnapshotsHandler := api.NewInventorySnapshotsHandler(s.dbPool)
+	apiPollsHandler := api.NewPollsHandler(s.dbPool)
 	apiReadinessHandler := api.NewReadinessHandler(s.dbPool, s.discordSession)
 	apiAdminHandler := api.NewAdminHandler(s.dbPool, s.railwayClient, s.getMigrateRunner, gamereset.New(s.dbPool, s.syncService))
+	apiArchivesHandler := api.NewArchivesHandler(s.dbPool, gameimport.New(s.dbPool))
 	apiSyncHandler := api.NewSyncHandler(s.dbPool, s.syncService)
 	s.syncHandler = apiSyncHandler
 	apiDiscordResourceCache := api.NewResourceCache(s.discordSession, api.ResourcesCacheTTL)
@@ -250,10 +267,23 @@ func (s *Server) setupRoutes() {
 	apiWhisper.POST("/messages", apiWhisperHandler.CreateMessage)
 	apiWhisper.PUT("/messages/:id", apiWhisperHandler.UpdateMessage)
 	apiWhisper.DELETE("/messages/:id", apiWhisperHandler.DeleteMessage)
+	apiWhisper.GET("/transcripts", apiWhisperHandler.Transcripts)
+	apiWhisper.PUT("/groups/:id/quota", apiWhisperHandler.SetQuota)
+	apiWhisper.PUT("/groups/:id/suspicion", apiWhisperHandler.SetSuspicion)
+	apiWhisper.GET("/quota/:player_id", apiWhisperHandler.Quota)
+	apiWhisper.POST("/quota/grant", apiWhisperHandler.GrantQuota)
+	apiWhisper.POST("/quota/reset", apiWhisperHandler.ResetQuota)
+	apiWhisper.GET("/bonuses", apiWhisperHandler.Bonuses)
+	apiWhisper.PUT("/bonuses", apiWhisperHandler.SetBonus)
+	apiWhisper.GET("/eavesdrops", apiWhisperHandler.Eavesdrops)
+	apiWhisper.POST("/eavesdrops", apiWhisperHandler.AddEavesdrop)
+	apiWhisper.DELETE("/eavesdrops/:id", apiWhisperHandler.RemoveEavesdrop)
 	apiV1.GET("/dashboard", apiDashboardHandler.Dashboard, apiAuthMiddleware.RequireAuth)
 	apiV1.GET("/players", apiPlayersHandler.List, apiAuthMiddleware.RequireAuth)
 	apiV1.GET("/players/:id", apiPlayersAdminHandler.Detail, apiAuthMiddleware.RequireAuth)
 	apiV1.POST("/players", apiPlayersAdminHandler.Create, apiAuthMiddleware.RequireAuth)
+	apiV1.POST("/players/bulk", apiPlayersAdminHandler.Bulk, apiAuthMiddleware.RequireAuth)
+	apiV1.GET("/notes/search", apiPlayersAdminHandler.SearchNotes, apiAuthMiddleware.RequireAuth)
 	apiV1.PUT("/players/:id", apiPlayersAdminHandler.Update, apiAuthMiddleware.RequireAuth)
 	apiV1.PATCH("/players/:id", apiPlayersAdminHandler.Update, apiAuthMiddleware.RequireAuth)
 	apiV1.DELETE("/players/:id", apiPlayersAdminHandler.Delete, apiAuthMiddleware.RequireAuth)
@@ -271,6 +301,8 @@ func (s *Server) setupRoutes() {
 	apiPlayers.POST("/immunities/remove", apiPlayersAdminHandler.ImmunityRemove)
 	apiPlayers.POST("/notes/add", apiPlayersAdminHandler.NoteAdd)
 	apiPlayers.POST("/notes/remove", apiPlayersAdminHandler.NoteRemove)
+	apiPlayers.POST("/substitute", apiPlayersAdminHandler.Substitute)
+	apiPlayers.GET("/substitutions", apiPlayersAdminHandler.Substitutions)
 
 	apiCatalog := apiV1.Group("/catalog", apiAuthMiddleware.RequireAuth)
 	apiCatalog.GET("/roles", apiCatalogHandler.ListRoles)
@@ -283,6 +315,8 @@ func (s *Server) setupRoutes() {
 	apiCatalog.DELETE("/roles/:id/abilities/:abilityID", apiCatalogHandler.RoleRemoveAbility)
 	apiCatalog.POST("/roles/:id/perks", apiCatalogHandler.RoleAddPerk)
 	apiCatalog.DELETE("/roles/:id/perks/:perkID", apiCatalogHandler.RoleRemovePerk)
+	apiCatalog.GET("/roles/:id/setup", apiCatalogHandler.GetRoleSetup)
+	apiCatalog.PUT("/roles/:id/setup", apiCatalogHandler.UpdateRoleSetup)
 	apiCatalog.GET("/items", apiCatalogHandler.ListItems)
 	apiCatalog.GET("/items/search", apiCatalogHandler.ListItems)
 	apiCatalog.GET("/items/:id", apiCatalogHandler.GetItem)
@@ -321,15 +355,57 @@ func (s *Server) setupRoutes() {
 	s.echo.GET("/api/v1/ops/cycle", apiCycleHandler.Get, apiAuthMiddleware.RequireAuth)
 	s.echo.POST("/api/v1/ops/cycle/advance", apiCycleHandler.Advance, apiAuthMiddleware.RequireAuth)
 	s.echo.POST("/api/v1/ops/cycle/set", apiCycleHandler.Set, apiAuthMiddleware.RequireAuth)
+	s.echo.GET("/api/v1/ops/cycle/history", apiCycleHandler.History, apiAuthMiddleware.RequireAuth)
+	s.echo.GET("/api/v1/ops/cycle/schedule", apiCycleHandler.GetSchedule, apiAuthMiddleware.RequireAuth)
+	s.echo.PUT("/api/v1/ops/cycle/schedule", apiCycleHandler.PutSchedule, apiAuthMiddleware.RequireAuth)
+	s.echo.DELETE("/api/v1/ops/cycle/schedule", apiCycleHandler.DeleteSchedule, apiAuthMiddleware.RequireAuth)
+	s.echo.POST("/api/v1/ops/cycle/schedule/pause", apiCycleHandler.PauseSchedule, apiAuthMiddleware.RequireAuth)
+	s.echo.POST("/api/v1/ops/cycle/schedule/resume", apiCycleHandler.ResumeSchedule, apiAuthMiddleware.RequireAuth)
+	s.echo.GET("/api/v1/ops/death-pipeline", apiPlayersAdminHandler.DeathPipeline, apiAuthMiddleware.RequireAuth)
+	s.echo.PUT("/api/v1/ops/death-pipeline", apiPlayersAdminHandler.SetDeathPipeline, apiAuthMiddleware.RequireAuth)
+	s.echo.GET("/api/v1/ops/inventory-notices", apiPlayersAdminHandler.InventoryNotices, apiAuthMiddleware.RequireAuth)
+	s.echo.PUT("/api/v1/ops/inventory-notices", apiPlayersAdminHandler.SetInventoryNotices, apiAuthMiddleware.RequireAuth)
+	s.echo.GET("/api/v1/ops/cycle/broadcast", apiCycleHandler.GetBroadcast, apiAuthMiddleware.RequireAuth)
+	s.echo.PUT("/api/v1/ops/cycle/broadcast", apiCycleHandler.SetBroadcast, apiAuthMiddleware.RequireAuth)
 	s.echo.GET("/api/v1/ops/setup", apiSetupHandler.Get, apiAuthMiddleware.RequireAuth)
 	s.echo.POST("/api/v1/ops/setup", apiSetupHandler.Generate, apiAuthMiddleware.RequireAuth)
+	s.echo.POST("/api/v1/ops/setup/roster", apiSetupHandler.Roster, apiAuthMiddleware.RequireAuth)
+	s.echo.GET("/api/v1/ops/setup/drafts", apiSetupHandler.Drafts, apiAuthMiddleware.RequireAuth)
+	s.echo.GET("/api/v1/ops/setup/drafts/:id", apiSetupHandler.Draft, apiAuthMiddleware.RequireAuth)
+	s.echo.POST("/api/v1/ops/setup/drafts/:id/picks", apiSetupHandler.PickDraftRole, apiAuthMiddleware.RequireAuth)
+	s.echo.POST("/api/v1/ops/setup/drafts/:id/finish", apiSetupHandler.FinishDraft, apiAuthMiddleware.RequireAuth)
+	s.echo.GET("/api/v1/ops/setup/active-roles", apiSetupHandler.ActiveRoles, apiAuthMiddleware.RequireAuth)
+	s.echo.POST("/api/v1/ops/setup/active-roles", apiSetupHandler.AddActiveRole, apiAuthMiddleware.RequireAuth)
+	s.echo.DELETE("/api/v1/ops/setup/active-roles/:name", apiSetupHandler.RemoveActiveRole, apiAuthMiddleware.RequireAuth)
 	s.echo.GET("/api/v1/ops/channels", apiChannelsHandler.Get, apiAuthMiddleware.RequireAuth)
 	s.echo.POST("/api/v1/ops/channels", apiChannelsHandler.Mutate, apiAuthMiddleware.RequireAuth)
 	s.echo.POST("/api/v1/ops/channels/update", apiChannelsHandler.Mutate, apiAuthMiddleware.RequireAuth)
 	s.echo.DELETE("/api/v1/ops/channels/:kind/:id", apiChannelsHandler.Delete, apiAuthMiddleware.RequireAuth)
+	s.echo.GET("/api/v1/ops/lifeboard", apiChannelsHandler.Lifeboard, apiAuthMiddleware.RequireAuth)
+	s.echo.PUT("/api/v1/ops/lifeboard", apiChannelsHandler.SetLifeboard, apiAuthMiddleware.RequireAuth)
+	s.echo.POST("/api/v1/ops/lifeboard/refresh", apiChannelsHandler.RefreshLifeboard, apiAuthMiddleware.RequireAuth)
+	s.echo.GET("/api/v1/ops/game/status", apiGameHandler.Status, apiAuthMiddleware.RequireAuth)
+	s.echo.GET("/api/v1/ops/game/win-conditions", apiGameHandler.WinConditions, apiAuthMiddleware.RequireAuth)
+	s.echo.PUT("/api/v1/ops/game/win-conditions", apiGameHandler.SetWinConditions, apiAuthMiddleware.RequireAuth)
+	s.echo.GET("/api/v1/ops/alliances", apiAlliancesHandler.List, apiAuthMiddleware.RequireAuth)
+	s.echo.GET("/api/v1/ops/alliances/approval", apiAlliancesHandler.Approval, apiAuthMiddleware.RequireAuth)
+	s.echo.PUT("/api/v1/ops/alliances/approval", apiAlliancesHandler.SetApproval, apiAuthMiddleware.RequireAuth)
+	s.echo.GET("/api/v1/ops/inventory/snapshots", apiSnapshotsHandler.Phases, apiAuthMiddleware.RequireAuth)
+	s.echo.GET("/api/v1/ops/inventory/diff", apiSnapshotsHandler.Diff, apiAuthMiddleware.RequireAuth)
 	s.echo.GET("/api/v1/ops/votes", apiVotesHandler.Get, apiAuthMiddleware.RequireAuth)
+	s.echo.GET("/api/v1/ops/polls", apiPollsHandler.List, apiAuthMiddleware.RequireAuth)
+	s.echo.GET("/api/v1/ops/polls/:id", apiPollsHandler.Get, apiAuthMiddleware.RequireAuth)
 	s.echo.GET("/api/v1/ops/healthcheck", apiReadinessHandler.Get, apiAuthMiddleware.RequireAuth)
 
+	if s.config.Games != nil {
+		apiGamesHandler := api.NewGamesHandler(s.config.Games, s.sessionStore, s.forgetGame)
+		apiGames := apiV1.Group("/games", apiAuthMiddleware.RequireAuth)
+		apiGames.GET("", apiGamesHandler.List)
+		apiGames.POST("", apiGamesHandler.Create, apiMigrateRate)
+		apiGames.PUT("/current", apiGamesHandler.Select)
+		apiGames.DELETE("/:id", apiGamesHandler.Delete, apiMigrateRate)
+	}
+
 	apiAdmin := apiV1.Group("/admin", apiAuthMiddleware.RequireAuth)
 	apiAdmin.GET("/audit", apiAdminHandler.Audit)
 	apiAdmin.GET("/migrations", apiAdminHandler.Migrations)
@@ -337,6 +413,11 @@ func (s *Server) setupRoutes() {
 	apiAdmin.POST("/migrations/down", apiAdminHandler.MigrationDown, apiMigrateRate)
 	apiAdmin.GET("/reset", apiAdminHandler.ResetPreview)
 	apiAdmin.POST("/reset", apiAdminHandler.ResetExecute, apiMigrateRate)
+	apiAdmin.GET("/archives", apiArchivesHandler.List)
+	apiAdmin.POST("/archives", apiArchivesHandler.Create, apiMigrateRate)
+	apiAdmin.GET("/archives/:id", apiArchivesHandler.Get)
+	apiAdmin.GET("/archives/:id/download", apiArchivesHandler.Download)
+	apiAdmin.POST("/import", apiArchivesHandler.Import, apiMigrateRate)
 	apiAdmin.POST("/redeploy", apiAdminHandler.Redeploy, apiMigrateRate)
 	apiSync := apiV1.Group("/sync", apiAuthMiddleware.RequireAuth)
 	apiSync.GET("/sources", apiSyncHandler.Sources)
@@ -0,0 +1,23 @@
+package cycle
+
+import (
+	"testing"
+
+	cyclesvc "github.com/mccune1224/betrayal/internal/services/cycle"
+)
+
+func TestParseTargets(t *testing.T) {
+	targets, err := cyclesvc.ParseTargets(" Announcement, confessionals,announcement ,, vote")
+	if err != nil {
+		t.Fatalf("ParseTargets() error = %v", err)
+	}
+	if got := cyclesvc.FormatTargets(targets); got != "announcement,confessionals,vote" {
+		t.Fatalf("ParseTargets() = %q, want announcement,confessionals,vote", got)
+	}
+	if _, err := cyclesvc.ParseTargets("vote,graveyard"); err == nil {
+		t.Fatal("unknown target must be rejected")
+	}
+	if _, err := cyclesvc.ParseTargets(" , "); err == nil {
+		t.Fatal("an empty target list must be rejected")
+	}
+}
@@ -0,0 +1,149 @@
+package cycle
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/models"
+	cyclesvc "github.com/mccune1224/betrayal/internal/services/cycle"
+	"github.com/mccune1224/betrayal/tests/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestPhaseDuration(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	closed := models.CycleHistory{
+		StartedAt: pgtype.Timestamptz{Time: start, Valid: true},
+		EndedAt:   pgtype.Timestamptz{Time: start.Add(90 * time.Minute), Valid: true},
+	}
+	open := models.CycleHistory{StartedAt: pgtype.Timestamptz{Time: start, Valid: true}}
+
+	assert.Equal(t, 90*time.Minute, cyclesvc.PhaseDuration(closed, start.Add(5*time.Hour)))
+	assert.Equal(t, 2*time.Hour, cyclesvc.PhaseDuration(open, start.Add(2*time.Hour)))
+	assert.Equal(t, "Elimination 3", cyclesvc.PhaseLabel(true, 3))
+	assert.Equal(t, "Day 0", cyclesvc.PhaseLabel(false, 0))
+}
+
+// CycleHistorySuite checks that transitions are logged and resolvable by time.
+type CycleHistorySuite struct {
+	suite.Suite
+	DB *pgxpool.Pool
+}
+
+func (s *CycleHistorySuite) SetupSuite() {
+	s.DB = testutil.NewTestPool(s.T())
+}
+
+func (s *CycleHistorySuite) SetupTest() {
+	testutil.TruncateAll(s.T(), s.DB)
+}
+
+func (s *CycleHistorySuite) TestTransitionsCloseAndOpenPhases() {
+	ctx := context.Background()
+	svc := cyclesvc.NewWithPipeline(s.DB, cyclesvc.NewPipeline())
+
+	_, err := svc.Advance(ctx, "123456789012345678")
+	s.Require().NoError(err)
+	_, err = svc.Advance(ctx, "web")
+	s.Require().NoError(err)
+
+	history, err := svc.History(ctx, 0)
+	s.Require().NoError(err)
+	s.Require().Len(history, 2)
+
+	latest, first := history[0], history[1]
+	s.Equal(int32(1), latest.Day)
+	s.True(latest.IsElimination)
+	s.Equal("web", latest.AdvancedBy)
+	s.False(latest.EndedAt.Valid, "current phase stays open")
+
+	s.Equal(int32(1), first.Day)
+	s.False(first.IsElimination)
+	s.Equal("123456789012345678", first.AdvancedBy)
+	s.True(first.EndedAt.Valid)
+	s.Equal(first.EndedAt.Time, latest.StartedAt.Time, "phases are contiguous")
+}
+
+// TestConcurrentAdvancesEachApply races advances the way the scheduler and a
+// host can at a phase boundary: every advance must land on its own phase, log
+// one history row and run the hooks once.
+func (s *CycleHistorySuite) TestConcurrentAdvancesEachApply() {
+	ctx := context.Background()
+	pipeline := cyclesvc.NewPipeline()
+	var mu sync.Mutex
+	announced := map[string]int{}
+	pipeline.OnAdvance(cyclesvc.Hook{Name: "count", AfterCommit: func(_ context.Context, t cyclesvc.Transition) error {
+		mu.Lock()
+		defer mu.Unlock()
+		announced[cyclesvc.PhaseLabel(t.To.IsElimination, t.To.Day)]++
+		return nil
+	}})
+	svc := cyclesvc.NewWithPipeline(s.DB, pipeline)
+
+	const advances = 6
+	var wg sync.WaitGroup
+	errs := make(chan error, advances)
+	for i := 0; i < advances; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.Advance(ctx, "race")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		s.Require().NoError(err)
+	}
+
+	cycle, err := svc.Current(ctx)
+	s.Require().NoError(err)
+	s.Equal(int32(3), cycle.Day, "Day 0 advanced six times is Elimination 3")
+	s.True(cycle.IsElimination)
+
+	history, err := svc.History(ctx, 0)
+	s.Require().NoError(err)
+	s.Require().Len(history, advances)
+	open := 0
+	for _, entry := range history {
+		if !entry.EndedAt.Valid {
+			open++
+		}
+	}
+	s.Equal(1, open, "only the current phase stays open")
+	s.Len(announced, advances)
+	for phase, n := range announced {
+		s.Equal(1, n, "hooks ran %d times for %s", n, phase)
+	}
+}
+
+func (s *CycleHistorySuite) TestPhaseAt() {
+	ctx := context.Background()
+	svc := cyclesvc.NewWithPipeline(s.DB, cyclesvc.NewPipeline())
+
+	base := time.Now().Add(-3 * time.Hour).UTC().Truncate(time.Second)
+	_, err := s.DB.Exec(ctx, `INSERT INTO cycle_history (day, is_elimination, started_at, ended_at, advanced_by)
+		VALUES (2, false, $1, $2, 'a'), (2, true, $2, NULL, 'b')`, base, base.Add(time.Hour))
+	s.Require().NoError(err)
+
+	entry, err := svc.PhaseAt(ctx, base.Add(30*time.Minute))
+	s.Require().NoError(err)
+	s.False(entry.IsElimination)
+
+	entry, err = svc.PhaseAt(ctx, base.Add(time.Hour))
+	s.Require().NoError(err)
+	s.True(entry.IsElimination, "a boundary instant belongs to the phase that starts then")
+
+	_, err = svc.PhaseAt(ctx, base.Add(-time.Minute))
+	s.ErrorIs(err, cyclesvc.ErrNoPhase)
+}
+
+func TestCycleHistorySuite(t *testing.T) {
+	suite.Run(t, new(CycleHistorySuite))
+}
@@ -0,0 +1,135 @@
+package cycle
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/models"
+	cyclesvc "github.com/mccune1224/betrayal/internal/services/cycle"
+	"github.com/mccune1224/betrayal/tests/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+// TestReportSummary pins the host-facing per-hook summary.
+func TestReportSummary(t *testing.T) {
+	report := cyclesvc.Report{Hooks: []cyclesvc.HookResult{
+		{Name: "announce"},
+		{Name: "income", Err: errors.New("boom")},
+	}}
+	if got := report.Summary(); got != "✅ announce\n❌ income: boom" {
+		t.Fatalf("Summary() = %q", got)
+	}
+	if failed := report.Failed(); len(failed) != 1 || failed[0].Name != "income" {
+		t.Fatalf("Failed() = %+v, want only income", failed)
+	}
+}
+
+// CyclePipelineSuite exercises hook ordering and savepoint isolation against
+// the LOCAL database using a private pipeline, so the shared hooks registered
+// by other packages never run here.
+type CyclePipelineSuite struct {
+	suite.Suite
+	DB *pgxpool.Pool
+}
+
+func (s *CyclePipelineSuite) SetupSuite() {
+	s.DB = testutil.NewTestPool(s.T())
+}
+
+func (s *CyclePipelineSuite) SetupTest() {
+	testutil.TruncateAll(s.T(), s.DB)
+	// game_config is not part of the truncated inventory; clear this suite's keys.
+	q := models.New(s.DB)
+	for _, key := range []string{"pipeline_first", "pipeline_ok", "pipeline_broken"} {
+		s.Require().NoError(q.DeleteGameConfig(context.Background(), key))
+	}
+}
+
+func configWriter(key string) func(context.Context, *models.Queries, cyclesvc.Transition) error {
+	return func(ctx context.Context, q *models.Queries, _ cyclesvc.Transition) error {
+		_, err := q.UpsertGameConfig(ctx, models.UpsertGameConfigParams{Key: key, Value: "written"})
+		return err
+	}
+}
+
+func (s *CyclePipelineSuite) TestAdvanceRunsHooksInOrderAndReportsEach() {
+	ctx := context.Background()
+	var calls []string
+	pipeline := cyclesvc.NewPipeline()
+	pipeline.OnAdvance(cyclesvc.Hook{Name: "second", Order: 20, AfterCommit: func(_ context.Context, t cyclesvc.Transition) error {
+		calls = append(calls, "second")
+		s.Equal(int32(1), t.To.Day)
+		return nil
+	}})
+	pipeline.OnAdvance(cyclesvc.Hook{Name: "first", Order: 10, InTx: func(ctx context.Context, q *models.Queries, t cyclesvc.Transition) error {
+		calls = append(calls, "first")
+		s.Equal(cyclesvc.KindAdvance, t.Kind)
+		s.Equal("tester", t.By)
+		return configWriter("pipeline_first")(ctx, q, t)
+	}})
+	pipeline.OnSet(cyclesvc.Hook{Name: "set-only", AfterCommit: func(context.Context, cyclesvc.Transition) error {
+		calls = append(calls, "set-only")
+		return nil
+	}})
+
+	report, err := cyclesvc.NewWithPipeline(s.DB, pipeline).Advance(ctx, "tester")
+	s.Require().NoError(err)
+	s.Equal([]string{"first", "second"}, calls)
+	s.Require().Len(report.Hooks, 2)
+	s.Empty(report.Failed())
+	s.Equal(int32(0), report.From.Day)
+	s.Equal(int32(1), report.To.Day)
+
+	value, err := models.New(s.DB).GetGameConfig(ctx, "pipeline_first")
+	s.Require().NoError(err)
+	s.Equal("written", value)
+}
+
+func (s *CyclePipelineSuite) TestFailingHookRollsBackOnlyItsOwnWrites() {
+	ctx := context.Background()
+	afterRan := false
+	pipeline := cyclesvc.NewPipeline()
+	pipeline.OnSet(cyclesvc.Hook{Name: "ok", Order: 1, InTx: configWriter("pipeline_ok")})
+	pipeline.OnSet(cyclesvc.Hook{
+		Name:  "broken",
+		Order: 2,
+		InTx: func(ctx context.Context, q *models.Queries, t cyclesvc.Transition) error {
+			if err := configWriter("pipeline_broken")(ctx, q, t); err != nil {
+				return err
+			}
+			return errors.New("hook failed")
+		},
+		AfterCommit: func(context.Context, cyclesvc.Transition) error {
+			afterRan = true
+			return nil
+		},
+	})
+	pipeline.OnSet(cyclesvc.Hook{Name: "panics", Order: 3, InTx: func(context.Context, *models.Queries, cyclesvc.Transition) error {
+		panic("kaboom")
+	}})
+
+	report, err := cyclesvc.NewWithPipeline(s.DB, pipeline).SetPhase(ctx, true, 5, "tester")
+	s.Require().NoError(err)
+	s.False(afterRan, "AfterCommit must be skipped when InTx failed")
+	s.Require().Len(report.Failed(), 2)
+	s.True(strings.Contains(report.Summary(), "❌ broken: hook failed"))
+	s.True(strings.Contains(report.Summary(), "panicked"))
+
+	q := models.New(s.DB)
+	cycle, err := q.GetCycle(ctx)
+	s.Require().NoError(err)
+	s.Equal(int32(5), cycle.Day)
+	s.True(cycle.IsElimination)
+
+	_, err = q.GetGameConfig(ctx, "pipeline_ok")
+	s.NoError(err, "successful hook writes commit with the cycle change")
+	_, err = q.GetGameConfig(ctx, "pipeline_broken")
+	s.Error(err, "failed hook writes are rolled back to its savepoint")
+}
+
+func TestCyclePipelineSuite(t *testing.T) {
+	suite.Run(t, new(CyclePipelineSuite))
+}
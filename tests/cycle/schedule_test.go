@@ -0,0 +1,144 @@
+package cycle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/models"
+	cyclesvc "github.com/mccune1224/betrayal/internal/services/cycle"
+	"github.com/mccune1224/betrayal/tests/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestParseScheduleRejectsBadInput(t *testing.T) {
+	_, err := cyclesvc.ParseSchedule("Mars/Olympus", "20:00", "")
+	assert.Error(t, err)
+	_, err = cyclesvc.ParseSchedule("", "8pm", "")
+	assert.Error(t, err)
+	_, err = cyclesvc.ParseSchedule("", "20:00", "someday")
+	assert.Error(t, err)
+	_, err = cyclesvc.ParseSchedule("", " , ", "")
+	assert.Error(t, err)
+}
+
+func TestScheduleNextAndPrevUseLocalTime(t *testing.T) {
+	s, err := cyclesvc.ParseSchedule("America/New_York", "08:00, 20:00", "mon,fri")
+	require.NoError(t, err)
+	ny, _ := time.LoadLocation("America/New_York")
+
+	// Friday 2024-03-08 21:00 ET: next is Monday 08:00, which is after the
+	// DST change on Sunday 2024-03-10.
+	now := time.Date(2024, 3, 8, 21, 0, 0, 0, ny)
+	next, ok := s.Next(now)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 3, 11, 8, 0, 0, 0, ny), next)
+	assert.Equal(t, "2024-03-11T12:00:00Z", next.UTC().Format(time.RFC3339), "EDT is UTC-4")
+
+	prev, ok := s.Prev(now)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 3, 8, 20, 0, 0, 0, ny), prev)
+
+	prev, ok = s.Prev(time.Date(2024, 3, 8, 20, 0, 0, 0, ny))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 3, 8, 20, 0, 0, 0, ny), prev, "a boundary is its own Prev")
+}
+
+func TestDueWarning(t *testing.T) {
+	next := time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Duration
+		ok   bool
+	}{
+		{"too early", next.Add(-2 * time.Hour), 0, false},
+		{"one hour", next.Add(-59 * time.Minute), time.Hour, true},
+		{"ten minutes", next.Add(-5 * time.Minute), 10 * time.Minute, true},
+		{"at boundary", next, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := cyclesvc.DueWarning(next, tt.now)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	assert.Equal(t, "1 hour", cyclesvc.FormatLead(time.Hour))
+	assert.Equal(t, "10 minutes", cyclesvc.FormatLead(10*time.Minute))
+}
+
+// CycleScheduleSuite drives Tick with explicit clocks against the LOCAL
+// database. No Discord session is attached, so nothing is announced.
+type CycleScheduleSuite struct {
+	suite.Suite
+	DB *pgxpool.Pool
+}
+
+func (s *CycleScheduleSuite) SetupSuite() {
+	s.DB = testutil.NewTestPool(s.T())
+}
+
+func (s *CycleScheduleSuite) SetupTest() {
+	testutil.TruncateAll(s.T(), s.DB)
+	// game_config is not part of the truncated inventory; clear the scheduler keys.
+	q := models.New(s.DB)
+	for _, key := range []string{cyclesvc.ScheduleConfigKey, cyclesvc.SchedulePausedConfigKey, "cycle_schedule_last_boundary", "cycle_schedule_last_warning"} {
+		s.Require().NoError(q.DeleteGameConfig(context.Background(), key))
+	}
+}
+
+func (s *CycleScheduleSuite) day() int32 {
+	cycle, err := models.New(s.DB).GetCycle(context.Background())
+	s.Require().NoError(err)
+	return cycle.Day
+}
+
+func (s *CycleScheduleSuite) TestTickAdvancesOncePerBoundaryAndHonoursPause() {
+	ctx := context.Background()
+	scheduler := cyclesvc.NewScheduler(s.DB, nil)
+	schedule, err := cyclesvc.ParseSchedule("UTC", "00:00,12:00", "")
+	s.Require().NoError(err)
+
+	start := time.Date(2024, 1, 1, 12, 5, 0, 0, time.UTC)
+	s.Require().NoError(scheduler.Save(ctx, schedule, start))
+	s.Require().NoError(scheduler.Tick(ctx, start))
+	s.Equal(int32(0), s.day(), "saving never fires a boundary that already passed")
+
+	boundary := time.Date(2024, 1, 2, 0, 0, 30, 0, time.UTC)
+	s.Require().NoError(scheduler.Tick(ctx, boundary))
+	s.Require().NoError(scheduler.Tick(ctx, boundary.Add(time.Minute)))
+	s.Equal(int32(1), s.day(), "each boundary advances exactly once")
+
+	s.Require().NoError(scheduler.SetPaused(ctx, true, boundary))
+	paused := time.Date(2024, 1, 2, 12, 1, 0, 0, time.UTC)
+	s.Require().NoError(scheduler.Tick(ctx, paused))
+	s.Equal(int32(1), s.day())
+
+	s.Require().NoError(scheduler.SetPaused(ctx, false, paused))
+	s.Require().NoError(scheduler.Tick(ctx, paused.Add(time.Minute)))
+	s.Equal(int32(1), s.day(), "boundaries missed while paused are skipped")
+
+	state, err := scheduler.State(ctx, paused)
+	s.Require().NoError(err)
+	s.False(state.Paused)
+	s.Equal(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), state.Next.UTC())
+}
+
+func (s *CycleScheduleSuite) TestLateBoundaryIsNotFired() {
+	ctx := context.Background()
+	scheduler := cyclesvc.NewScheduler(s.DB, nil)
+	schedule, err := cyclesvc.ParseSchedule("UTC", "12:00", "")
+	s.Require().NoError(err)
+	s.Require().NoError(scheduler.Save(ctx, schedule, time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)))
+
+	s.Require().NoError(scheduler.Tick(ctx, time.Date(2024, 1, 2, 13, 0, 0, 0, time.UTC)))
+	s.Equal(int32(0), s.day(), "a boundary an hour late is recorded as missed")
+}
+
+func TestCycleScheduleSuite(t *testing.T) {
+	suite.Run(t, new(CycleScheduleSuite))
+}
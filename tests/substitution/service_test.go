@@ -0,0 +1,118 @@
+package substitution
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/substitution"
+	"github.com/mccune1224/betrayal/tests/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	oldID   = int64(100000000000000001)
+	newID   = int64(100000000000000002)
+	otherID = int64(100000000000000003)
+)
+
+// SubstitutionSuite re-keys a seat against the LOCAL database and checks
+// that every reference follows the player.
+type SubstitutionSuite struct {
+	suite.Suite
+	DB  *pgxpool.Pool
+	Q   *models.Queries
+	svc *substitution.Service
+}
+
+func (s *SubstitutionSuite) SetupSuite() {
+	s.DB = testutil.NewTestPool(s.T())
+	s.Q = models.New(s.DB)
+	s.svc = substitution.New(s.DB)
+}
+
+func (s *SubstitutionSuite) SetupTest() {
+	testutil.TruncateAll(s.T(), s.DB)
+	ctx := context.Background()
+	role, err := s.Q.CreateRole(ctx, models.CreateRoleParams{Name: "Mafia", Description: "boss", Alignment: models.AlignmentEVIL})
+	s.Require().NoError(err)
+	for _, id := range []int64{oldID, otherID} {
+		_, err := s.Q.CreatePlayer(ctx, models.CreatePlayerParams{
+			ID: id, RoleID: pgtype.Int4{Int32: role.ID, Valid: true}, Alive: true, Coins: 200, Alignment: models.AlignmentEVIL,
+		})
+		s.Require().NoError(err)
+	}
+}
+
+// TestPlayerColumnsCoverForeignKeys fails when a migration adds a player
+// reference that substitution does not re-key.
+func (s *SubstitutionSuite) TestPlayerColumnsCoverForeignKeys() {
+	rows, err := s.DB.Query(context.Background(), `
+		SELECT kcu.table_name, kcu.column_name
+		FROM information_schema.referential_constraints rc
+		JOIN information_schema.key_column_usage kcu ON kcu.constraint_name = rc.constraint_name
+		JOIN information_schema.constraint_column_usage ccu ON ccu.constraint_name = rc.unique_constraint_name
+		WHERE ccu.table_name = 'player' AND ccu.column_name = 'id'`)
+	s.Require().NoError(err)
+	defer rows.Close()
+	known := map[substitution.Column]bool{}
+	for _, col := range substitution.PlayerColumns {
+		known[col] = true
+	}
+	for rows.Next() {
+		var col substitution.Column
+		s.Require().NoError(rows.Scan(&col.Table, &col.Column))
+		s.True(known[col], "%s.%s references player(id) but is missing from substitution.PlayerColumns", col.Table, col.Column)
+	}
+	s.Require().NoError(rows.Err())
+}
+
+func (s *SubstitutionSuite) TestSubstituteMovesInventoryVotesAndHistory() {
+	ctx := context.Background()
+	item, err := s.Q.CreateItem(ctx, models.CreateItemParams{Name: "Knife", Description: "sharp", Rarity: models.RarityCOMMON, Cost: 10})
+	s.Require().NoError(err)
+	s.Require().NoError(s.Q.UpsertPlayerItemJoin(ctx, models.UpsertPlayerItemJoinParams{PlayerID: oldID, ItemID: item.ID, Quantity: 2}))
+	_, err = s.Q.UpsertVote(ctx, models.UpsertVoteParams{VoterID: oldID, TargetID: otherID, Weight: 1})
+	s.Require().NoError(err)
+	_, err = s.Q.UpsertVote(ctx, models.UpsertVoteParams{VoterID: otherID, TargetID: oldID, Weight: 1})
+	s.Require().NoError(err)
+
+	result, err := s.svc.Substitute(ctx, nil, substitution.Request{OldID: oldID, NewID: newID, Reason: "left the game", By: "host"})
+	s.Require().NoError(err)
+	s.Equal(newID, result.Player.ID)
+	s.Equal(int32(200), result.Player.Coins)
+	s.NotEmpty(result.Warnings)
+
+	_, err = s.Q.GetPlayer(ctx, oldID)
+	s.Error(err)
+	items, err := s.Q.ListPlayerItem(ctx, newID)
+	s.Require().NoError(err)
+	s.Len(items, 1)
+	votes, err := s.Q.ListVotesByVoter(ctx, newID)
+	s.Require().NoError(err)
+	s.Len(votes, 1)
+	votes, err = s.Q.ListVotesByVoter(ctx, otherID)
+	s.Require().NoError(err)
+	s.Require().Len(votes, 1)
+	s.Equal(newID, votes[0].TargetID)
+
+	history, err := s.svc.History(ctx, newID)
+	s.Require().NoError(err)
+	s.Require().Len(history, 1)
+	s.Equal(oldID, history[0].PreviousPlayerID)
+	s.Equal("left the game", history[0].Reason)
+}
+
+func (s *SubstitutionSuite) TestSubstituteRejectsExistingPlayer() {
+	_, err := s.svc.Substitute(context.Background(), nil, substitution.Request{OldID: oldID, NewID: otherID})
+	s.True(errors.Is(err, substitution.ErrAlreadyPlayer))
+	_, err = s.svc.Substitute(context.Background(), nil, substitution.Request{OldID: newID, NewID: 42})
+	s.True(errors.Is(err, substitution.ErrPlayerNotFound))
+}
+
+func TestSubstitutionSuite(t *testing.T) {
+	suite.Run(t, new(SubstitutionSuite))
+}
@@ -40,7 +40,8 @@ const advisoryLockKey = int64(0xB374A9A1)
 
 // allTables is the full table inventory (from internal/db/migrate/migrations) truncated
 // between tests. game_cycle is included and re-seeded afterwards because its
-// Day-0 row is inserted by migration 000023.
+// Day-0 row is inserted by migration 000023. Seeded configuration (game_config,
+// role_setup) is left out; suites that write to it clean up after themselves.
 var allTables = []string{
 	"sync_run",
 	"sync_source",
@@ -73,6 +74,26 @@ var allTables = []string{
 	"whisper_group_member",
 	"whisper_group",
 	"whisper_doubt_message",
+	"poll_ballot",
+	"poll_option",
+	"poll",
+	"cycle_history",
+	"whisper_transcript",
+	"whisper_usage",
+	"whisper_quota_adjustment",
+	"whisper_bonus",
+	"whisper_eavesdrop",
+	"active_role",
+	"role_draft",
+	"role_draft_role",
+	"role_draft_option",
+	"role_draft_pick",
+	"player_substitution",
+	"alliance_member",
+	"alliance",
+	"game",
+	"game_archive",
+	"inventory_snapshot",
 }
 
 // repoRoot returns the absolute path of the repository root (parent of tests/).
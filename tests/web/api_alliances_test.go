@@ -0,0 +1,72 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/alliance"
+)
+
+func TestAPIAlliances(t *testing.T) {
+	pool := mustPool(t)
+	ctx := context.Background()
+	q := models.New(pool)
+
+	const playerID int64 = 805
+	if _, err := q.CreatePlayer(ctx, models.CreatePlayerParams{ID: playerID, Alive: true, ItemLimit: 4, Alignment: models.AlignmentGOOD}); err != nil {
+		t.Fatalf("create player: %v", err)
+	}
+
+	client := newTestClient(t, testServer(t, pool))
+	client.login()
+
+	if resp := apiRequest(t, client, http.MethodPut, "/api/v1/ops/alliances/approval", []byte(`{}`), true); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("missing enabled: expected 400, got %d", resp.StatusCode)
+	}
+	if resp := apiRequest(t, client, http.MethodPut, "/api/v1/ops/alliances/approval", []byte(`{"enabled":true}`), true); resp.StatusCode != http.StatusOK {
+		t.Fatalf("set approval: %d %s", resp.StatusCode, client.body(resp))
+	}
+
+	// With approval on the request is stored without Discord; only the host
+	// notice fails.
+	res, err := alliance.New(pool, nil).Create(ctx, "", playerID, "Night Owls")
+	if err != nil {
+		t.Fatalf("create alliance: %v", err)
+	}
+	if !res.Pending || len(res.Warnings) != 1 {
+		t.Fatalf("result = %+v", res)
+	}
+	if _, err := alliance.New(pool, nil).Create(ctx, "", playerID, "night owls"); err != alliance.ErrNameTaken {
+		t.Fatalf("duplicate name: %v", err)
+	}
+	if _, err := alliance.New(pool, nil).DeclineAlliance(ctx, "Night Owls"); err != nil {
+		t.Fatalf("decline alliance: %v", err)
+	}
+
+	resp := apiRequest(t, client, http.MethodGet, "/api/v1/ops/alliances", nil, false)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("list alliances: %d %s", resp.StatusCode, client.body(resp))
+	}
+	var alliances []struct {
+		Name      string  `json:"name"`
+		Status    string  `json:"status"`
+		CreatedBy string  `json:"created_by"`
+		ClosedAt  *string `json:"closed_at"`
+		Members   []struct {
+			PlayerID string `json:"player_id"`
+			Status   string `json:"status"`
+		} `json:"members"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&alliances); err != nil {
+		t.Fatalf("decode alliances: %v", err)
+	}
+	if len(alliances) != 1 || alliances[0].Status != alliance.StatusDeclined || alliances[0].CreatedBy != "805" || alliances[0].ClosedAt == nil {
+		t.Fatalf("alliances = %+v", alliances)
+	}
+	if len(alliances[0].Members) != 1 || alliances[0].Members[0].Status != alliance.MemberDeclined {
+		t.Fatalf("members = %+v", alliances[0].Members)
+	}
+}
@@ -0,0 +1,84 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+func TestAPIArchives(t *testing.T) {
+	pool := mustPool(t)
+	ctx := context.Background()
+	if _, err := models.New(pool).CreatePlayer(ctx, models.CreatePlayerParams{ID: 901, Alive: true, ItemLimit: 4, Alignment: models.AlignmentEVIL}); err != nil {
+		t.Fatalf("create player: %v", err)
+	}
+
+	client := newTestClient(t, testServer(t, pool))
+	client.login()
+
+	resp := apiRequest(t, client, http.MethodPost, "/api/v1/admin/archives", []byte(`{"label":"Season 1"}`), true)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create archive: %d %s", resp.StatusCode, client.body(resp))
+	}
+	var created struct {
+		ID     int64            `json:"id"`
+		Label  string           `json:"label"`
+		Counts map[string]int64 `json:"counts"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("decode archive: %v", err)
+	}
+	if created.Label != "Season 1" || created.Counts["player"] != 1 {
+		t.Fatalf("archive = %+v", created)
+	}
+
+	// The archive outlives the rows it captured.
+	if _, err := pool.Exec(ctx, "DELETE FROM player"); err != nil {
+		t.Fatalf("delete players: %v", err)
+	}
+	resp = apiRequest(t, client, http.MethodGet, fmt.Sprintf("/api/v1/admin/archives/%d?table=player", created.ID), nil, false)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("browse archive: %d %s", resp.StatusCode, client.body(resp))
+	}
+	var players []struct {
+		ID        int64  `json:"id"`
+		Alignment string `json:"alignment"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&players); err != nil {
+		t.Fatalf("decode players: %v", err)
+	}
+	if len(players) != 1 || players[0].ID != 901 || players[0].Alignment != "EVIL" {
+		t.Fatalf("players = %+v", players)
+	}
+	if resp := apiRequest(t, client, http.MethodGet, fmt.Sprintf("/api/v1/admin/archives/%d?table=game_archive", created.ID), nil, false); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown table: expected 404, got %d", resp.StatusCode)
+	}
+
+	resp = apiRequest(t, client, http.MethodGet, fmt.Sprintf("/api/v1/admin/archives/%d/download", created.ID), nil, false)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(resp.Header.Get("Content-Disposition"), "attachment") {
+		t.Fatalf("download: %d %q", resp.StatusCode, resp.Header.Get("Content-Disposition"))
+	}
+	var bundle struct {
+		Version int                        `json:"version"`
+		Tables  map[string]json.RawMessage `json:"tables"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&bundle); err != nil {
+		t.Fatalf("decode bundle: %v", err)
+	}
+	if bundle.Version != 1 || bundle.Tables["command_audit"] == nil {
+		t.Fatalf("bundle version %d with %d tables", bundle.Version, len(bundle.Tables))
+	}
+
+	resp = apiRequest(t, client, http.MethodGet, "/api/v1/admin/archives", nil, false)
+	var list []struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil || len(list) != 1 || list[0].ID != created.ID {
+		t.Fatalf("list = %+v, err = %v", list, err)
+	}
+}
@@ -0,0 +1,90 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+type roleSetupAPIDTO struct {
+	Statuses       []string `json:"statuses"`
+	Immunities     []string `json:"immunities"`
+	Items          []string `json:"items"`
+	ItemLimitDelta int32    `json:"item_limit_delta"`
+	BonusCoins     int32    `json:"bonus_coins"`
+	BonusLuck      int32    `json:"bonus_luck"`
+}
+
+func TestAPICatalogRoleSetup(t *testing.T) {
+	pool := mustPool(t)
+	ctx := context.Background()
+	q := models.New(pool)
+	if _, err := q.CreateStatus(ctx, models.CreateStatusParams{Name: "Frozen", Description: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.CreateItem(ctx, models.CreateItemParams{Name: "Spanner", Description: "x", Rarity: models.RarityCOMMON, Cost: 5}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(context.Background(), "DELETE FROM role_setup WHERE role_name IN ('tinker', 'mechanic')")
+	})
+	client := newTestClient(t, testServer(t, pool))
+	client.login()
+
+	// Unknown names are rejected and the role is not created.
+	resp := apiRequest(t, client, http.MethodPost, "/api/v1/catalog/roles", []byte(`{"name":"Tinker","description":"Builds","alignment":"NEUTRAL","setup":{"immunities":["Frostbite"]}}`), true)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unknown status: expected 400, got %d %s", resp.StatusCode, client.body(resp))
+	}
+	if _, err := q.GetRoleByName(ctx, "Tinker"); err == nil {
+		t.Fatal("role was created despite the rejected setup")
+	}
+
+	resp = apiRequest(t, client, http.MethodPost, "/api/v1/catalog/roles", []byte(`{"name":"Tinker","description":"Builds","alignment":"NEUTRAL","setup":{"immunities":["frozen"],"items":["Spanner","Spanner"],"bonus_coins":25}}`), true)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create role: %d %s", resp.StatusCode, client.body(resp))
+	}
+	var role struct {
+		ID    int32           `json:"id"`
+		Setup roleSetupAPIDTO `json:"setup"`
+	}
+	decodeAPIJSON(t, resp, &role)
+	if len(role.Setup.Items) != 2 || role.Setup.BonusCoins != 25 || len(role.Setup.Immunities) != 1 {
+		t.Fatalf("created setup = %+v", role.Setup)
+	}
+	path := "/api/v1/catalog/roles/" + strconv.Itoa(int(role.ID))
+
+	resp = apiRequest(t, client, http.MethodPut, path+"/setup", []byte(`{"statuses":[],"items":["Spanner"],"item_limit_delta":2}`), true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update setup: %d %s", resp.StatusCode, client.body(resp))
+	}
+	decodeAPIJSON(t, resp, &role)
+	if len(role.Setup.Items) != 1 || role.Setup.ItemLimitDelta != 2 || role.Setup.BonusCoins != 0 || role.Setup.Immunities == nil {
+		t.Fatalf("updated setup = %+v", role.Setup)
+	}
+
+	// Renaming the role carries its setup along.
+	resp = apiRequest(t, client, http.MethodPut, path, []byte(`{"name":"Mechanic","description":"Builds","alignment":"NEUTRAL"}`), true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("rename role: %d %s", resp.StatusCode, client.body(resp))
+	}
+	var setup roleSetupAPIDTO
+	decodeAPIJSON(t, client.get(path+"/setup"), &setup)
+	if setup.ItemLimitDelta != 2 || len(setup.Items) != 1 {
+		t.Fatalf("setup after rename = %+v", setup)
+	}
+
+	resp = apiRequest(t, client, http.MethodPut, path+"/setup", []byte(`{}`), true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("clear setup: %d %s", resp.StatusCode, client.body(resp))
+	}
+	if _, err := q.GetRoleSetup(ctx, "mechanic"); err == nil {
+		t.Fatal("clearing the setup left its row behind")
+	}
+	if resp := apiRequest(t, client, http.MethodPut, "/api/v1/catalog/roles/999999/setup", []byte(`{}`), true); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("missing role: expected 404, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,79 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+func TestAPIGameStatus(t *testing.T) {
+	pool := mustPool(t)
+	ctx := context.Background()
+	q := models.New(pool)
+
+	players := []struct {
+		id        int64
+		alive     bool
+		alignment models.Alignment
+	}{
+		{805, true, models.AlignmentGOOD},
+		{806, false, models.AlignmentGOOD},
+		{807, true, models.AlignmentEVIL},
+	}
+	for _, p := range players {
+		if _, err := q.CreatePlayer(ctx, models.CreatePlayerParams{ID: p.id, Alive: p.alive, Coins: 200, ItemLimit: 4, Alignment: p.alignment}); err != nil {
+			t.Fatalf("create player: %v", err)
+		}
+	}
+
+	client := newTestClient(t, testServer(t, pool))
+	client.login()
+
+	if resp := apiRequest(t, client, http.MethodPut, "/api/v1/ops/game/win-conditions", []byte(`{"roles":{"Jester":[{}]}}`), true); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("empty condition: expected 400, got %d", resp.StatusCode)
+	}
+
+	// Default rules: evil has reached parity with the one living good player.
+	resp := apiRequest(t, client, http.MethodGet, "/api/v1/ops/game/status", nil, false)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status: %d %s", resp.StatusCode, client.body(resp))
+	}
+	var status struct {
+		Alignments []struct {
+			Alignment string `json:"alignment"`
+			Alive     int    `json:"alive"`
+			Total     int    `json:"total"`
+		} `json:"alignments"`
+		Outcomes []struct {
+			Subject  string   `json:"subject"`
+			State    string   `json:"state"`
+			Deciders []string `json:"deciders"`
+		} `json:"outcomes"`
+		GameOver bool `json:"game_over"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	if !status.GameOver || len(status.Alignments) != 2 || status.Alignments[0].Alive != 1 || status.Alignments[0].Total != 2 {
+		t.Fatalf("status = %+v", status)
+	}
+	if status.Outcomes[0].Subject != "EVIL" || status.Outcomes[0].State != "met" {
+		t.Fatalf("outcomes = %+v", status.Outcomes)
+	}
+
+	resp = apiRequest(t, client, http.MethodPut, "/api/v1/ops/game/win-conditions", []byte(`{"alignments":{"EVIL":[{"eliminate":["GOOD"]}]}}`), true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("set rules: %d %s", resp.StatusCode, client.body(resp))
+	}
+	resp = apiRequest(t, client, http.MethodGet, "/api/v1/ops/game/status", nil, false)
+	status.Outcomes, status.GameOver = nil, false
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	if status.GameOver || len(status.Outcomes) != 1 || status.Outcomes[0].State != "imminent" || len(status.Outcomes[0].Deciders) != 1 || status.Outcomes[0].Deciders[0] != "805" {
+		t.Fatalf("status = %+v", status)
+	}
+}
@@ -0,0 +1,106 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/tenancy"
+	"github.com/mccune1224/betrayal/internal/web"
+	"github.com/rs/zerolog"
+)
+
+func TestAPIGames(t *testing.T) {
+	pool := mustPool(t)
+	games := tenancy.NewRegistry(pool, "")
+	t.Cleanup(games.Close)
+	srv, err := web.New(pool, nil, zerolog.Nop(), web.Config{Port: "0", AdminPassword: testAdminPassword, Games: games})
+	if err != nil {
+		t.Fatalf("web.New: %v", err)
+	}
+	client := newTestClient(t, srv)
+	client.login()
+
+	resp := apiRequest(t, client, http.MethodGet, "/api/v1/games", nil, false)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("list games: %d %s", resp.StatusCode, client.body(resp))
+	}
+	var list struct {
+		Current int64 `json:"current"`
+		Games   []struct {
+			ID      int64 `json:"id"`
+			Primary bool  `json:"primary"`
+		} `json:"games"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatalf("decode games: %v", err)
+	}
+	if list.Current != 0 || len(list.Games) != 1 || !list.Games[0].Primary {
+		t.Fatalf("games = %+v", list)
+	}
+
+	if resp := apiRequest(t, client, http.MethodPut, "/api/v1/games/current", []byte(`{"game_id":42}`), true); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("select unknown game: expected 404, got %d", resp.StatusCode)
+	}
+	if resp := apiRequest(t, client, http.MethodPost, "/api/v1/games", []byte(`{"guild_id":"not-a-guild","name":"Test"}`), true); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("invalid guild: expected 400, got %d", resp.StatusCode)
+	}
+	if resp := apiRequest(t, client, http.MethodPut, "/api/v1/games/current", []byte(`{"game_id":0}`), true); resp.StatusCode != http.StatusOK {
+		t.Fatalf("select primary: %d %s", resp.StatusCode, client.body(resp))
+	}
+	// Game-scoped routes stay on the primary game without a selection.
+	if resp := apiRequest(t, client, http.MethodGet, "/api/v1/ops/alliances", nil, false); resp.StatusCode != http.StatusOK {
+		t.Fatalf("primary route: %d %s", resp.StatusCode, client.body(resp))
+	}
+}
+
+// TestAPIGamesDeleteStopsTheGame checks that deleting a game tells its
+// OnDelete hooks (the bot stops the game's cycle scheduler there) once the
+// schema is gone.
+func TestAPIGamesDeleteStopsTheGame(t *testing.T) {
+	pool := mustPool(t)
+	ctx := context.Background()
+	const guildID = "424242424242424242"
+	schema, err := tenancy.SchemaName(guildID)
+	if err != nil {
+		t.Fatalf("schema name: %v", err)
+	}
+	ident := pgx.Identifier{schema}.Sanitize()
+	t.Cleanup(func() { _, _ = pool.Exec(context.Background(), "DROP SCHEMA IF EXISTS "+ident+" CASCADE") })
+	if _, err := pool.Exec(ctx, "CREATE SCHEMA "+ident); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	game, err := models.New(pool).CreateGame(ctx, models.CreateGameParams{GuildID: guildID, Name: "Doomed", SchemaName: schema})
+	if err != nil {
+		t.Fatalf("create game: %v", err)
+	}
+
+	games := tenancy.NewRegistry(pool, "")
+	t.Cleanup(games.Close)
+	var stopped []string
+	games.OnDelete(func(g models.Game) {
+		var exists bool
+		if err := pool.QueryRow(context.Background(), "SELECT EXISTS (SELECT 1 FROM pg_namespace WHERE nspname = $1)", g.SchemaName).Scan(&exists); err != nil || exists {
+			t.Errorf("OnDelete ran before the drop committed (exists %v, err %v)", exists, err)
+		}
+		stopped = append(stopped, g.GuildID)
+	})
+	srv, err := web.New(pool, nil, zerolog.Nop(), web.Config{Port: "0", AdminPassword: testAdminPassword, Games: games})
+	if err != nil {
+		t.Fatalf("web.New: %v", err)
+	}
+	client := newTestClient(t, srv)
+	client.login()
+
+	resp := apiRequest(t, client, http.MethodDelete, fmt.Sprintf("/api/v1/games/%d", game.ID), nil, true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("delete game: %d %s", resp.StatusCode, client.body(resp))
+	}
+	if len(stopped) != 1 || stopped[0] != guildID {
+		t.Fatalf("OnDelete hooks ran for %v, want once for %s", stopped, guildID)
+	}
+}
@@ -0,0 +1,87 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/archive"
+)
+
+func TestAPIImport(t *testing.T) {
+	pool := mustPool(t)
+	ctx := context.Background()
+	q := models.New(pool)
+
+	item, err := q.CreateItem(ctx, models.CreateItemParams{Name: "Lantern", Description: "Light", Rarity: models.RarityCOMMON, Cost: 10})
+	if err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+	if _, err := q.CreatePlayer(ctx, models.CreatePlayerParams{ID: 902, Alive: true, ItemLimit: 4, Alignment: models.AlignmentGOOD}); err != nil {
+		t.Fatalf("create player: %v", err)
+	}
+	if err := q.UpsertPlayerItemJoin(ctx, models.UpsertPlayerItemJoinParams{PlayerID: 902, ItemID: item.ID, Quantity: 2}); err != nil {
+		t.Fatalf("give item: %v", err)
+	}
+	saved, err := archive.New(pool).Create(ctx, "before the bad reset")
+	if err != nil {
+		t.Fatalf("archive: %v", err)
+	}
+
+	// Simulate the reset: the game is gone and the catalog re-imported under
+	// new ids.
+	if _, err := pool.Exec(ctx, "DELETE FROM player; DELETE FROM item"); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if _, err := q.CreateItem(ctx, models.CreateItemParams{Name: "Filler", Rarity: models.RarityCOMMON}); err != nil {
+		t.Fatalf("create filler: %v", err)
+	}
+	reimported, err := q.CreateItem(ctx, models.CreateItemParams{Name: "Lantern", Description: "Light", Rarity: models.RarityCOMMON, Cost: 10})
+	if err != nil {
+		t.Fatalf("re-create item: %v", err)
+	}
+
+	client := newTestClient(t, testServer(t, pool))
+	client.login()
+
+	type report struct {
+		Committed bool             `json:"committed"`
+		Counts    map[string]int64 `json:"counts"`
+		Errors    []string         `json:"errors"`
+	}
+	body := []byte(fmt.Sprintf(`{"archive_id":%d,"dry_run":true}`, saved.ID))
+	resp := apiRequest(t, client, http.MethodPost, "/api/v1/admin/import", body, true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("dry run: %d %s", resp.StatusCode, client.body(resp))
+	}
+	var dry report
+	if err := json.NewDecoder(resp.Body).Decode(&dry); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if dry.Committed || dry.Counts["player"] != 1 || dry.Counts["player_item"] != 1 || len(dry.Errors) != 0 {
+		t.Fatalf("dry run report = %+v", dry)
+	}
+	if _, err := q.GetPlayer(ctx, 902); err == nil {
+		t.Fatal("dry run committed the player")
+	}
+
+	body = []byte(fmt.Sprintf(`{"archive_id":%d,"confirm":"IMPORT BETRAYAL GAME","understand":true}`, saved.ID))
+	resp = apiRequest(t, client, http.MethodPost, "/api/v1/admin/import", body, true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("import: %d %s", resp.StatusCode, client.body(resp))
+	}
+	var done report
+	if err := json.NewDecoder(resp.Body).Decode(&done); err != nil || !done.Committed {
+		t.Fatalf("import report = %+v, err = %v", done, err)
+	}
+	var itemID, quantity int32
+	if err := pool.QueryRow(ctx, "SELECT item_id, quantity FROM player_item WHERE player_id = 902").Scan(&itemID, &quantity); err != nil {
+		t.Fatalf("imported inventory: %v", err)
+	}
+	if itemID != reimported.ID || quantity != 2 {
+		t.Fatalf("item %d x%d, want %d x2", itemID, quantity, reimported.ID)
+	}
+}
@@ -0,0 +1,71 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+func TestAPIInventoryNotices(t *testing.T) {
+	pool := mustPool(t)
+	ctx := context.Background()
+	q := models.New(pool)
+
+	role, err := q.CreateRole(ctx, models.CreateRoleParams{Name: "Merchant", Description: "notice test role", Alignment: models.AlignmentGOOD})
+	if err != nil {
+		t.Fatalf("create role: %v", err)
+	}
+	const playerID int64 = 804
+	if _, err := q.CreatePlayer(ctx, models.CreatePlayerParams{
+		ID: playerID, RoleID: pgtype.Int4{Int32: role.ID, Valid: true}, Alive: true, Coins: 100, ItemLimit: 4, Alignment: models.AlignmentGOOD,
+	}); err != nil {
+		t.Fatalf("create player: %v", err)
+	}
+	if _, err := q.CreateItem(ctx, models.CreateItemParams{Name: "Lantern", Description: "notice test item", Rarity: models.RarityCOMMON, Cost: 10}); err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+
+	client := newTestClient(t, testServer(t, pool))
+	client.login()
+
+	if resp := apiRequest(t, client, http.MethodPut, "/api/v1/ops/inventory-notices", []byte(`{}`), true); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("missing enabled: expected 400, got %d", resp.StatusCode)
+	}
+	resp := apiRequest(t, client, http.MethodPut, "/api/v1/ops/inventory-notices", []byte(`{"enabled":true}`), true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("set notices: %d %s", resp.StatusCode, client.body(resp))
+	}
+	resp = apiRequest(t, client, http.MethodGet, "/api/v1/ops/inventory-notices", nil, false)
+	var notices struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&notices); err != nil {
+		t.Fatalf("decode notices: %v", err)
+	}
+	if !notices.Enabled {
+		t.Fatalf("notices = %+v", notices)
+	}
+
+	// Without Discord the edit is stored and nothing is reported.
+	resp = apiRequest(t, client, http.MethodPost, "/api/v1/players/804/items/buy", []byte(`{"name":"Lantern","notify":true}`), true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("buy item: %d %s", resp.StatusCode, client.body(resp))
+	}
+	var detail struct {
+		Coins int32 `json:"coins"`
+		Items []struct {
+			Name string `json:"name"`
+		} `json:"items"`
+		Warnings []string `json:"warnings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&detail); err != nil {
+		t.Fatalf("decode detail: %v", err)
+	}
+	if detail.Coins != 90 || len(detail.Items) != 1 || len(detail.Warnings) != 0 {
+		t.Fatalf("detail = %+v", detail)
+	}
+}
@@ -0,0 +1,68 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mccune1224/betrayal/internal/models"
+	"github.com/mccune1224/betrayal/internal/services/invsnapshot"
+)
+
+func TestAPIInventorySnapshots(t *testing.T) {
+	pool := mustPool(t)
+	ctx := context.Background()
+	if _, err := models.New(pool).CreatePlayer(ctx, models.CreatePlayerParams{ID: 903, Alive: true, Coins: 200, ItemLimit: 4, Alignment: models.AlignmentNEUTRAL}); err != nil {
+		t.Fatalf("create player: %v", err)
+	}
+	if err := invsnapshot.New(pool).Take(ctx, invsnapshot.Phase{Day: 4}); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	if _, err := pool.Exec(ctx, "UPDATE player SET coins = 150 WHERE id = 903"); err != nil {
+		t.Fatalf("spend coins: %v", err)
+	}
+
+	client := newTestClient(t, testServer(t, pool))
+	client.login()
+
+	resp := apiRequest(t, client, http.MethodGet, "/api/v1/ops/inventory/snapshots", nil, false)
+	var phases []struct {
+		Day     int32 `json:"day"`
+		Players int64 `json:"players"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&phases); err != nil || len(phases) != 1 || phases[0].Day != 4 || phases[0].Players != 1 {
+		t.Fatalf("phases = %+v, err = %v", phases, err)
+	}
+
+	resp = apiRequest(t, client, http.MethodGet, "/api/v1/ops/inventory/diff?from=4", nil, false)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("diff: %d %s", resp.StatusCode, client.body(resp))
+	}
+	var recap struct {
+		Players []struct {
+			PlayerID string `json:"player_id"`
+			Changes  []struct {
+				Field string `json:"field"`
+				From  string `json:"from"`
+				To    string `json:"to"`
+			} `json:"changes"`
+		} `json:"players"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&recap); err != nil {
+		t.Fatalf("decode diff: %v", err)
+	}
+	if len(recap.Players) != 1 || recap.Players[0].PlayerID != "903" || len(recap.Players[0].Changes) != 1 {
+		t.Fatalf("recap = %+v", recap)
+	}
+	if c := recap.Players[0].Changes[0]; c.Field != "Coins" || c.From != "200" || c.To != "150" {
+		t.Fatalf("change = %+v", c)
+	}
+
+	if resp := apiRequest(t, client, http.MethodGet, "/api/v1/ops/inventory/diff?from=9", nil, false); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("missing snapshot: expected 404, got %d", resp.StatusCode)
+	}
+	if resp := apiRequest(t, client, http.MethodGet, "/api/v1/ops/inventory/diff", nil, false); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("missing from: expected 400, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,36 @@
+package web_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestAPILifeboardSettings(t *testing.T) {
+	pool := mustPool(t)
+	client := newTestClient(t, testServer(t, pool))
+	client.login()
+
+	if resp := apiRequest(t, client, http.MethodPut, "/api/v1/ops/lifeboard", []byte(`{}`), true); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("missing reveal_roles: expected 400, got %d", resp.StatusCode)
+	}
+	resp := apiRequest(t, client, http.MethodPut, "/api/v1/ops/lifeboard", []byte(`{"reveal_roles":true}`), true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("set lifeboard: %d %s", resp.StatusCode, client.body(resp))
+	}
+	resp = apiRequest(t, client, http.MethodGet, "/api/v1/ops/lifeboard", nil, false)
+	var board struct {
+		RevealRoles bool `json:"reveal_roles"`
+		Refreshed   bool `json:"refreshed"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&board); err != nil {
+		t.Fatalf("decode lifeboard: %v", err)
+	}
+	if !board.RevealRoles || board.Refreshed {
+		t.Fatalf("lifeboard = %+v", board)
+	}
+	// Without Discord there is nothing to re-render.
+	if resp := apiRequest(t, client, http.MethodPost, "/api/v1/ops/lifeboard/refresh", nil, true); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("refresh without Discord: expected 503, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,77 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+func TestAPINotesSearch(t *testing.T) {
+	pool := mustPool(t)
+	ctx := context.Background()
+	q := models.New(pool)
+	for _, id := range []int64{1201, 1202} {
+		if _, err := q.CreatePlayer(ctx, models.CreatePlayerParams{ID: id, Alive: true, ItemLimit: 4, Alignment: models.AlignmentGOOD}); err != nil {
+			t.Fatalf("create player %d: %v", id, err)
+		}
+	}
+
+	client := newTestClient(t, testServer(t, pool))
+	client.login()
+
+	body := []byte(`{"position":1,"info":"Used a protect ability on <@1202> #ability-used","tags":["suspicious"],"day":2}`)
+	if resp := apiRequest(t, client, http.MethodPost, "/api/v1/players/1201/notes/add", body, true); resp.StatusCode != http.StatusOK {
+		t.Fatalf("add note: %d %s", resp.StatusCode, client.body(resp))
+	}
+	body = []byte(`{"position":1,"info":"Quiet all day","day":3}`)
+	if resp := apiRequest(t, client, http.MethodPost, "/api/v1/players/1202/notes/add", body, true); resp.StatusCode != http.StatusOK {
+		t.Fatalf("add note: %d %s", resp.StatusCode, client.body(resp))
+	}
+
+	type note struct {
+		PlayerID      string   `json:"player_id"`
+		Info          string   `json:"info"`
+		Tags          []string `json:"tags"`
+		Author        string   `json:"author"`
+		Day           *int32   `json:"day"`
+		LinkedPlayers []string `json:"linked_players"`
+	}
+	search := func(query string) []note {
+		t.Helper()
+		resp := apiRequest(t, client, http.MethodGet, "/api/v1/notes/search"+query, nil, false)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("search %s: %d %s", query, resp.StatusCode, client.body(resp))
+		}
+		var notes []note
+		if err := json.NewDecoder(resp.Body).Decode(&notes); err != nil {
+			t.Fatalf("decode search: %v", err)
+		}
+		return notes
+	}
+
+	notes := search("?q=protected&day=2")
+	if len(notes) != 1 || notes[0].PlayerID != "1201" || notes[0].Author != "web" || notes[0].Day == nil || *notes[0].Day != 2 {
+		t.Fatalf("protect on day 2 = %+v", notes)
+	}
+	if got := notes[0].Tags; len(got) != 2 || got[0] != "ability-used" || got[1] != "suspicious" {
+		t.Fatalf("tags = %v", got)
+	}
+	if got := notes[0].LinkedPlayers; len(got) != 1 || got[0] != "1202" {
+		t.Fatalf("linked players = %v", got)
+	}
+	if notes := search("?tag=suspicious&tag=ability-used"); len(notes) != 1 {
+		t.Fatalf("both tags = %+v", notes)
+	}
+	if notes := search("?player_id=1202"); len(notes) != 2 {
+		t.Fatalf("notes on or mentioning 1202 = %+v", notes)
+	}
+	if notes := search(""); len(notes) != 2 {
+		t.Fatalf("all notes = %+v", notes)
+	}
+	if resp := apiRequest(t, client, http.MethodGet, "/api/v1/notes/search?limit=0", nil, false); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("limit 0: expected 400, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,86 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+func TestAPIPlayerDeathPipeline(t *testing.T) {
+	pool := mustPool(t)
+	ctx := context.Background()
+	q := models.New(pool)
+
+	role, err := q.CreateRole(ctx, models.CreateRoleParams{Name: "Gambler", Description: "death test role", Alignment: models.AlignmentNEUTRAL})
+	if err != nil {
+		t.Fatalf("create role: %v", err)
+	}
+	const playerID int64 = 803
+	if _, err := q.CreatePlayer(ctx, models.CreatePlayerParams{
+		ID: playerID, RoleID: pgtype.Int4{Int32: role.ID, Valid: true}, Alive: true, Coins: 200, ItemLimit: 4, Alignment: models.AlignmentNEUTRAL,
+	}); err != nil {
+		t.Fatalf("create player: %v", err)
+	}
+	coin, err := q.CreateItem(ctx, models.CreateItemParams{Name: "Lucky Coin", Description: "death test item", Rarity: models.RarityRARE, Cost: 10})
+	if err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+	if err := q.UpsertPlayerItemJoin(ctx, models.UpsertPlayerItemJoinParams{PlayerID: playerID, ItemID: coin.ID, Quantity: 1}); err != nil {
+		t.Fatalf("grant item: %v", err)
+	}
+
+	client := newTestClient(t, testServer(t, pool))
+	client.login()
+
+	resp := apiRequest(t, client, http.MethodPut, "/api/v1/ops/death-pipeline", []byte(`{"dead_role_ids":["1"," 1 ",""],"read_only":true,"graveyard_category":" graveyard "}`), true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("set config: %d %s", resp.StatusCode, client.body(resp))
+	}
+	resp = apiRequest(t, client, http.MethodGet, "/api/v1/ops/death-pipeline", nil, false)
+	var cfg struct {
+		DeadRoleIDs       []string `json:"dead_role_ids"`
+		ReadOnly          bool     `json:"read_only"`
+		GraveyardCategory string   `json:"graveyard_category"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&cfg); err != nil {
+		t.Fatalf("decode config: %v", err)
+	}
+	if len(cfg.DeadRoleIDs) != 1 || !cfg.ReadOnly || cfg.GraveyardCategory != "graveyard" {
+		t.Fatalf("config = %+v", cfg)
+	}
+
+	// Without Discord the alive flag is stored and the skipped steps are
+	// reported as warnings.
+	resp = apiRequest(t, client, http.MethodPut, "/api/v1/players/803/state", []byte(`{"alive":false}`), true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update state: %d %s", resp.StatusCode, client.body(resp))
+	}
+	var out struct {
+		Alive     bool     `json:"alive"`
+		Warnings  []string `json:"warnings"`
+		LuckyCoin bool     `json:"lucky_coin"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decode update: %v", err)
+	}
+	if out.Alive || !out.LuckyCoin || len(out.Warnings) == 0 {
+		t.Fatalf("update = %+v", out)
+	}
+	if player, err := q.GetPlayer(ctx, playerID); err != nil || player.Alive {
+		t.Fatalf("player alive after death: %+v %v", player, err)
+	}
+
+	// Unrelated edits do not run the pipeline.
+	resp = apiRequest(t, client, http.MethodPut, "/api/v1/players/803/stats", []byte(`{"coins":150}`), true)
+	out.Warnings = nil
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decode update: %v", err)
+	}
+	if len(out.Warnings) != 0 {
+		t.Fatalf("stats update ran the death pipeline: %+v", out)
+	}
+}
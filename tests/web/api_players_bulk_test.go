@@ -0,0 +1,113 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+func TestAPIPlayersBulk(t *testing.T) {
+	pool := mustPool(t)
+	ctx := context.Background()
+	q := models.New(pool)
+	for _, p := range []models.CreatePlayerParams{
+		{ID: 1101, Alive: true, Coins: 100, ItemLimit: 4, Alignment: models.AlignmentGOOD},
+		{ID: 1102, Alive: true, Coins: 20, ItemLimit: 1, Alignment: models.AlignmentEVIL},
+		{ID: 1103, Alive: false, Coins: 70, ItemLimit: 4, Alignment: models.AlignmentEVIL},
+	} {
+		if _, err := q.CreatePlayer(ctx, p); err != nil {
+			t.Fatalf("create player %d: %v", p.ID, err)
+		}
+	}
+	if _, err := q.CreateItem(ctx, models.CreateItemParams{Name: "Lantern", Description: "bulk test item", Rarity: models.RarityCOMMON, Cost: 10}); err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+
+	client := newTestClient(t, testServer(t, pool))
+	client.login()
+
+	if resp := apiRequest(t, client, http.MethodPost, "/api/v1/players/bulk", []byte(`{"operations":[{"kind":"coins","amount":0}]}`), true); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("zero amount: expected 400, got %d", resp.StatusCode)
+	}
+	if resp := apiRequest(t, client, http.MethodPost, "/api/v1/players/bulk", []byte(`{"filter":{"player_ids":["9999"]},"operations":[{"kind":"coins","amount":5}]}`), true); resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("no players: expected 422, got %d", resp.StatusCode)
+	}
+
+	type report struct {
+		Applied bool `json:"applied"`
+		Players []struct {
+			PlayerID string   `json:"player_id"`
+			Changes  []string `json:"changes"`
+			Warnings []string `json:"warnings"`
+		} `json:"players"`
+	}
+	body := []byte(`{"filter":{"alive":true},"operations":[{"kind":"coins","amount":-50},{"kind":"item","name":"lantern","amount":1}]}`)
+	resp := apiRequest(t, client, http.MethodPost, "/api/v1/players/bulk", body, true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("preview: %d %s", resp.StatusCode, client.body(resp))
+	}
+	var preview report
+	if err := json.NewDecoder(resp.Body).Decode(&preview); err != nil {
+		t.Fatalf("decode preview: %v", err)
+	}
+	if preview.Applied || len(preview.Players) != 2 || preview.Players[1].PlayerID != "1102" {
+		t.Fatalf("preview = %+v", preview)
+	}
+	// Coins stop at zero, so the poorer player only loses what they have.
+	if got := preview.Players[1].Changes; len(got) != 2 || got[0] != "-20 Coins" || got[1] != "+1 Lantern" {
+		t.Fatalf("preview changes = %v", got)
+	}
+	// The Lantern fills the evil player's only slot, as /inv item add warns.
+	if w := preview.Players[1].Warnings; len(w) != 1 || w[0] != "1 items out of 1 used slots" || len(preview.Players[0].Warnings) != 0 {
+		t.Fatalf("preview warnings = %+v", preview.Players)
+	}
+	if player, _ := q.GetPlayer(ctx, 1101); player.Coins != 100 {
+		t.Fatalf("preview changed coins to %d", player.Coins)
+	}
+
+	body = []byte(`{"filter":{"alive":true,"alignment":"EVIL"},"operations":[{"kind":"coins","amount":-50}],"apply":true,"notify":false}`)
+	resp = apiRequest(t, client, http.MethodPost, "/api/v1/players/bulk", body, true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("apply: %d %s", resp.StatusCode, client.body(resp))
+	}
+	var applied report
+	if err := json.NewDecoder(resp.Body).Decode(&applied); err != nil {
+		t.Fatalf("decode apply: %v", err)
+	}
+	if !applied.Applied || len(applied.Players) != 1 || applied.Players[0].PlayerID != "1102" {
+		t.Fatalf("applied = %+v", applied)
+	}
+	for id, want := range map[int64]int32{1101: 100, 1102: 0, 1103: 70} {
+		if player, err := q.GetPlayer(ctx, id); err != nil || player.Coins != want {
+			t.Fatalf("player %d coins = %d (err %v), want %d", id, player.Coins, err, want)
+		}
+	}
+
+	// Giving a status a player is immune to goes through with a warning.
+	frozen, err := q.CreateStatus(ctx, models.CreateStatusParams{Name: "Bulk Frozen", Description: "bulk test status"})
+	if err != nil {
+		t.Fatalf("create status: %v", err)
+	}
+	if _, err := q.CreatePlayerImmunityJoin(ctx, models.CreatePlayerImmunityJoinParams{PlayerID: 1101, StatusID: frozen.ID}); err != nil {
+		t.Fatalf("create immunity: %v", err)
+	}
+	body = []byte(`{"filter":{"alive":true},"operations":[{"kind":"status","name":"Bulk Frozen","amount":1}],"apply":true,"notify":false}`)
+	resp = apiRequest(t, client, http.MethodPost, "/api/v1/players/bulk", body, true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("apply status: %d %s", resp.StatusCode, client.body(resp))
+	}
+	var statused report
+	if err := json.NewDecoder(resp.Body).Decode(&statused); err != nil {
+		t.Fatalf("decode status apply: %v", err)
+	}
+	if !statused.Applied || len(statused.Players) != 2 || len(statused.Players[1].Warnings) != 0 {
+		t.Fatalf("status apply = %+v", statused)
+	}
+	if w := statused.Players[0].Warnings; len(w) != 1 || !strings.Contains(w[0], "immune to Bulk Frozen") {
+		t.Fatalf("immunity warnings = %v", w)
+	}
+}
@@ -0,0 +1,146 @@
+package web_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/mccune1224/betrayal/internal/models"
+)
+
+func TestRoleDraftLifecycleHandsOffToRoster(t *testing.T) {
+	pool := mustPool(t)
+	ctx := context.Background()
+	q := models.New(pool)
+	for _, alignment := range []models.Alignment{models.AlignmentGOOD, models.AlignmentEVIL, models.AlignmentNEUTRAL} {
+		for i := 1; i <= 2; i++ {
+			name := fmt.Sprintf("%s %d", alignment, i)
+			if _, err := q.CreateRole(ctx, models.CreateRoleParams{Name: name, Description: "draft test role", Alignment: alignment}); err != nil {
+				t.Fatalf("create role: %v", err)
+			}
+		}
+	}
+	client := newTestClient(t, testServer(t, pool))
+	client.login()
+	for _, name := range []string{"good 1", "GOOD 2", "Evil 1", "Evil 2", "Neutral 1", "Neutral 2"} {
+		resp := apiRequest(t, client, http.MethodPost, "/api/v1/ops/setup/active-roles", []byte(fmt.Sprintf(`{"name":%q}`, name)), true)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("add active role %s: %d %s", name, resp.StatusCode, client.body(resp))
+		}
+	}
+	if resp := apiRequest(t, client, http.MethodPost, "/api/v1/ops/setup/active-roles", []byte(`{"name":"Evl 1"}`), true); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown active role: expected 404, got %d", resp.StatusCode)
+	}
+
+	resp := apiRequest(t, client, http.MethodPost, "/api/v1/ops/setup", []byte(
+		`{"player_count":3,"deceptionist_count":1,"seed":7,"constraints":{"min":{"evil":2},"required":["GOOD 1"]}}`), true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("generate: %d %s", resp.StatusCode, client.body(resp))
+	}
+	var setup struct {
+		DraftID int64 `json:"draft_id"`
+		Seed    int64 `json:"seed"`
+		Pool    struct {
+			DeceptionOptions [][]struct {
+				Name string `json:"name"`
+			} `json:"deception_options"`
+			RandomPool []struct {
+				Name      string `json:"name"`
+				Alignment string `json:"alignment"`
+			} `json:"random_pool"`
+		} `json:"pool"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&setup); err != nil {
+		t.Fatalf("decode setup: %v", err)
+	}
+	if setup.DraftID == 0 || setup.Seed != 7 || len(setup.Pool.RandomPool) != 3 || len(setup.Pool.DeceptionOptions) != 1 {
+		t.Fatalf("setup = %+v", setup)
+	}
+	evil := 0
+	for _, role := range setup.Pool.RandomPool {
+		if role.Alignment == "EVIL" {
+			evil++
+		}
+	}
+	if evil != 2 {
+		t.Fatalf("random pool must hold 2 evil roles: %+v", setup.Pool.RandomPool)
+	}
+
+	base := fmt.Sprintf("/api/v1/ops/setup/drafts/%d", setup.DraftID)
+	if resp := apiRequest(t, client, http.MethodPost, base+"/picks", []byte(`{"slot":1,"role":"Nobody"}`), true); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("invalid pick: expected 400, got %d", resp.StatusCode)
+	}
+	pick := fmt.Sprintf(`{"slot":1,"role":%q,"player_id":"900"}`, setup.Pool.DeceptionOptions[0][0].Name)
+	if resp := apiRequest(t, client, http.MethodPost, base+"/picks", []byte(pick), true); resp.StatusCode != http.StatusOK {
+		t.Fatalf("pick: %d %s", resp.StatusCode, client.body(resp))
+	}
+
+	rosterReq := []byte(fmt.Sprintf(`{"draft":{"draft_id":%d,"player_ids":["901","902"]}}`, setup.DraftID))
+	if resp := apiRequest(t, client, http.MethodPost, "/api/v1/ops/setup/roster", rosterReq, true); resp.StatusCode != http.StatusConflict {
+		t.Fatalf("roster from open draft: expected 409, got %d", resp.StatusCode)
+	}
+	if resp := apiRequest(t, client, http.MethodPost, base+"/finish", nil, true); resp.StatusCode != http.StatusOK {
+		t.Fatalf("finish: %d %s", resp.StatusCode, client.body(resp))
+	}
+	if resp := apiRequest(t, client, http.MethodPost, base+"/picks", []byte(pick), true); resp.StatusCode != http.StatusConflict {
+		t.Fatalf("pick after finish: expected 409, got %d", resp.StatusCode)
+	}
+
+	resp = apiRequest(t, client, http.MethodPost, "/api/v1/ops/setup/roster", rosterReq, true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("roster preview: %d %s", resp.StatusCode, client.body(resp))
+	}
+	var preview struct {
+		Valid  bool `json:"valid"`
+		Report struct {
+			Rows []struct {
+				UserID  string `json:"user_id"`
+				Matched string `json:"matched_role"`
+			} `json:"rows"`
+		} `json:"report"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&preview); err != nil {
+		t.Fatalf("decode roster preview: %v", err)
+	}
+	if !preview.Valid || len(preview.Report.Rows) != 3 || preview.Report.Rows[0].UserID != "900" ||
+		preview.Report.Rows[0].Matched != setup.Pool.DeceptionOptions[0][0].Name {
+		t.Fatalf("preview = %+v", preview)
+	}
+
+	// A generated pool is dealt from the preview's seed, so the confirm
+	// provisions the lineup the hosts approved.
+	type seededPreview struct {
+		Seed   *int64 `json:"seed"`
+		Report struct {
+			Rows []struct {
+				Matched string `json:"matched_role"`
+			} `json:"rows"`
+		} `json:"report"`
+	}
+	dealt := func(body string) seededPreview {
+		resp := apiRequest(t, client, http.MethodPost, "/api/v1/ops/setup/roster", []byte(body), true)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("generated roster preview: %d %s", resp.StatusCode, client.body(resp))
+		}
+		var out seededPreview
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			t.Fatalf("decode generated preview: %v", err)
+		}
+		return out
+	}
+	first := dealt(`{"draft":{"player_ids":["911","912","913"]}}`)
+	if first.Seed == nil || len(first.Report.Rows) != 3 {
+		t.Fatalf("generated preview = %+v", first)
+	}
+	again := dealt(fmt.Sprintf(`{"draft":{"player_ids":["911","912","913"],"seed":%d}}`, *first.Seed))
+	for i := range first.Report.Rows {
+		if again.Report.Rows[i].Matched != first.Report.Rows[i].Matched {
+			t.Fatalf("seed %d dealt %+v then %+v", *first.Seed, first.Report.Rows, again.Report.Rows)
+		}
+	}
+	if resp := apiRequest(t, client, http.MethodPost, "/api/v1/ops/setup/roster", []byte(`{"draft":{"player_ids":["911","912","913"]},"confirm":true}`), true); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("confirm without seed: expected 400, got %d", resp.StatusCode)
+	}
+}
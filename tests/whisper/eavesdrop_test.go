@@ -0,0 +1,115 @@
+package whisper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/models"
+	whispersvc "github.com/mccune1224/betrayal/internal/services/whisper"
+	"github.com/mccune1224/betrayal/tests/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+// EavesdropSuite checks silent whisper interception against the LOCAL
+// database.
+type EavesdropSuite struct {
+	suite.Suite
+	DB    *pgxpool.Pool
+	group models.WhisperGroup
+}
+
+type channelSender struct{ channels []string }
+
+func (s *channelSender) Send(channelID, _ string) error {
+	s.channels = append(s.channels, channelID)
+	return nil
+}
+
+func (s *EavesdropSuite) SetupSuite() {
+	s.DB = testutil.NewTestPool(s.T())
+}
+
+func (s *EavesdropSuite) SetupTest() {
+	testutil.TruncateAll(s.T(), s.DB)
+	ctx := context.Background()
+	q := models.New(s.DB)
+	_, err := q.CreateCycleHistory(ctx, models.CreateCycleHistoryParams{Day: 1, AdvancedBy: "test"})
+	s.Require().NoError(err)
+	s.group, err = q.CreateWhisperGroup(ctx, "twins")
+	s.Require().NoError(err)
+	for _, id := range []int64{10, 11, 20} {
+		_, err := q.CreatePlayer(ctx, models.CreatePlayerParams{ID: id, Alive: true, Alignment: models.AlignmentNEUTRAL})
+		s.Require().NoError(err)
+		_, err = q.CreatePlayerConfessional(ctx, models.CreatePlayerConfessionalParams{PlayerID: id, ChannelID: id * 100, PinMessageID: id})
+		s.Require().NoError(err)
+	}
+	for _, id := range []int64{10, 11} {
+		s.Require().NoError(q.AddWhisperGroupMember(ctx, models.AddWhisperGroupMemberParams{GroupID: s.group.ID, PlayerID: id}))
+	}
+}
+
+func (s *EavesdropSuite) TestInterceptReachesOnlyOutsideListeners() {
+	ctx := context.Background()
+	svc := whispersvc.New(s.DB)
+	for _, id := range []int64{20, 11} {
+		_, err := svc.AddEavesdrop(ctx, whispersvc.Eavesdrop{PlayerID: id, GroupID: s.group.ID, By: "test"})
+		s.Require().NoError(err)
+	}
+	sender := &channelSender{}
+	sent, err := svc.Intercept(ctx, s.group.ID, "psst", sender)
+	s.Require().NoError(err)
+	s.Equal(1, sent)
+	s.Equal([]string{"2000"}, sender.channels, "group members never receive intercepted copies")
+}
+
+func (s *EavesdropSuite) TestMessageLimitIsConsumed() {
+	ctx := context.Background()
+	svc := whispersvc.New(s.DB)
+	_, err := svc.AddEavesdrop(ctx, whispersvc.Eavesdrop{PlayerID: 20, GroupID: s.group.ID, Messages: 2, By: "test"})
+	s.Require().NoError(err)
+	sender := &channelSender{}
+	for range 3 {
+		_, err := svc.Intercept(ctx, s.group.ID, "psst", sender)
+		s.Require().NoError(err)
+	}
+	s.Len(sender.channels, 2)
+	active, err := svc.Eavesdrops(ctx, nil)
+	s.Require().NoError(err)
+	s.Empty(active)
+}
+
+func (s *EavesdropSuite) TestPhaseLimitExpiresWithTheCycle() {
+	ctx := context.Background()
+	q := models.New(s.DB)
+	svc := whispersvc.New(s.DB)
+	_, err := svc.AddEavesdrop(ctx, whispersvc.Eavesdrop{PlayerID: 20, GroupID: s.group.ID, Phases: 1, Redacted: true, By: "test"})
+	s.Require().NoError(err)
+	active, err := svc.Eavesdrops(ctx, &s.group.ID)
+	s.Require().NoError(err)
+	s.Require().Len(active, 1)
+	s.True(active[0].Redacted)
+
+	s.Require().NoError(q.CloseCycleHistory(ctx))
+	_, err = q.CreateCycleHistory(ctx, models.CreateCycleHistoryParams{Day: 1, IsElimination: true, AdvancedBy: "test"})
+	s.Require().NoError(err)
+	sender := &channelSender{}
+	sent, err := svc.Intercept(ctx, s.group.ID, "psst", sender)
+	s.Require().NoError(err)
+	s.Zero(sent)
+}
+
+func (s *EavesdropSuite) TestEndEavesdrop() {
+	ctx := context.Background()
+	svc := whispersvc.New(s.DB)
+	row, err := svc.AddEavesdrop(ctx, whispersvc.Eavesdrop{PlayerID: 20, GroupID: s.group.ID, By: "test"})
+	s.Require().NoError(err)
+	_, err = svc.EndEavesdrop(ctx, row.ID)
+	s.Require().NoError(err)
+	_, err = svc.EndEavesdrop(ctx, row.ID)
+	s.ErrorIs(err, whispersvc.ErrEavesdropNotFound)
+}
+
+func TestEavesdropSuite(t *testing.T) {
+	suite.Run(t, new(EavesdropSuite))
+}
@@ -0,0 +1,180 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/models"
+	whispersvc "github.com/mccune1224/betrayal/internal/services/whisper"
+	"github.com/mccune1224/betrayal/tests/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+// QuotaSuite checks per-phase whisper budgets, bonuses and host adjustments
+// against the LOCAL database.
+type QuotaSuite struct {
+	suite.Suite
+	DB    *pgxpool.Pool
+	group models.WhisperGroup
+}
+
+func (s *QuotaSuite) SetupSuite() {
+	s.DB = testutil.NewTestPool(s.T())
+}
+
+func (s *QuotaSuite) SetupTest() {
+	testutil.TruncateAll(s.T(), s.DB)
+	ctx := context.Background()
+	q := models.New(s.DB)
+	_, err := q.CreateCycleHistory(ctx, models.CreateCycleHistoryParams{Day: 1, AdvancedBy: "test"})
+	s.Require().NoError(err)
+	s.group, err = q.CreateWhisperGroup(ctx, "twins")
+	s.Require().NoError(err)
+	for _, id := range []int64{10, 11} {
+		_, err := q.CreatePlayer(ctx, models.CreatePlayerParams{ID: id, Alive: true, Alignment: models.AlignmentNEUTRAL})
+		s.Require().NoError(err)
+		s.Require().NoError(q.AddWhisperGroupMember(ctx, models.AddWhisperGroupMemberParams{GroupID: s.group.ID, PlayerID: id}))
+	}
+}
+
+func (s *QuotaSuite) send(senderID int64) {
+	_, err := whispersvc.New(s.DB).Reserve(context.Background(), s.group.ID, senderID)
+	s.Require().NoError(err)
+}
+
+func (s *QuotaSuite) TestUnlimitedByDefault() {
+	quota, err := whispersvc.New(s.DB).Quota(context.Background(), s.group.ID, 10)
+	s.Require().NoError(err)
+	s.False(quota.Limited)
+	s.True(quota.Allows())
+	reservation, err := whispersvc.New(s.DB).Reserve(context.Background(), s.group.ID, 10)
+	s.Require().NoError(err)
+	s.Zero(reservation.ID, "unlimited groups record no usage")
+}
+
+func (s *QuotaSuite) TestPlayerScopeCountsSenderAndBonuses() {
+	ctx := context.Background()
+	svc := whispersvc.New(s.DB)
+	allowance := 2
+	_, err := svc.SetAllowance(ctx, s.group.ID, &allowance, whispersvc.ScopePlayer)
+	s.Require().NoError(err)
+
+	q := models.New(s.DB)
+	item, err := q.CreateItem(ctx, models.CreateItemParams{Name: "Mirror Shard", Description: "x", Rarity: models.RarityCOMMON})
+	s.Require().NoError(err)
+	s.Require().NoError(q.UpsertPlayerItemJoin(ctx, models.UpsertPlayerItemJoinParams{PlayerID: 10, ItemID: item.ID, Quantity: 2}))
+	s.Require().NoError(svc.SetItemBonus(ctx, item.ID, 1))
+
+	s.send(10)
+	s.send(11)
+	quota, err := svc.Quota(ctx, s.group.ID, 10)
+	s.Require().NoError(err)
+	s.Equal(4, quota.Total(), "allowance 2 plus one per held copy")
+	s.Equal(1, quota.Used, "only the sender's own whispers count")
+	s.Equal(3, quota.Remaining())
+}
+
+func (s *QuotaSuite) TestGroupScopeSharesAllowanceAndHostAdjustments() {
+	ctx := context.Background()
+	svc := whispersvc.New(s.DB)
+	allowance := 2
+	_, err := svc.SetAllowance(ctx, s.group.ID, &allowance, whispersvc.ScopeGroup)
+	s.Require().NoError(err)
+
+	s.send(10)
+	s.send(11)
+	quota, err := svc.Quota(ctx, s.group.ID, 10)
+	s.Require().NoError(err)
+	s.False(quota.Allows(), "both members drew from one allowance")
+
+	player := int64(11)
+	_, err = svc.Grant(ctx, s.group.ID, &player, 1, "host")
+	s.Require().NoError(err)
+	quota, err = svc.Quota(ctx, s.group.ID, 10)
+	s.Require().NoError(err)
+	s.Equal(1, quota.Remaining(), "grants to a member feed the shared allowance")
+
+	_, err = svc.Reset(ctx, s.group.ID, nil, "host")
+	s.Require().NoError(err)
+	quota, err = svc.Quota(ctx, s.group.ID, 10)
+	s.Require().NoError(err)
+	s.Equal(0, quota.Used)
+	s.Equal(2, quota.Remaining())
+
+	// A new phase starts from the plain allowance again.
+	q := models.New(s.DB)
+	s.Require().NoError(q.CloseCycleHistory(ctx))
+	_, err = q.CreateCycleHistory(ctx, models.CreateCycleHistoryParams{Day: 1, IsElimination: true, AdvancedBy: "test"})
+	s.Require().NoError(err)
+	s.Require().NoError(svc.SetPerkBonus(ctx, 1, 0), "removing a missing bonus is a no-op")
+	quota, err = svc.Quota(ctx, s.group.ID, 10)
+	s.Require().NoError(err)
+	s.Equal(0, quota.Granted)
+}
+
+// TestConcurrentSendsCannotOverdraw races more sends than the allowance
+// holds; the group lock lets exactly the allowance through.
+func (s *QuotaSuite) TestConcurrentSendsCannotOverdraw() {
+	ctx := context.Background()
+	svc := whispersvc.New(s.DB)
+	allowance := 3
+	_, err := svc.SetAllowance(ctx, s.group.ID, &allowance, whispersvc.ScopeGroup)
+	s.Require().NoError(err)
+
+	const senders = 12
+	var wg sync.WaitGroup
+	var granted, refused atomic.Int32
+	errs := make(chan error, senders)
+	for i := range senders {
+		wg.Add(1)
+		go func(senderID int64) {
+			defer wg.Done()
+			_, err := svc.Reserve(ctx, s.group.ID, senderID)
+			switch {
+			case err == nil:
+				granted.Add(1)
+			case errors.Is(err, whispersvc.ErrQuotaExhausted):
+				refused.Add(1)
+			default:
+				errs <- err
+			}
+		}(int64(10 + i%2))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		s.Require().NoError(err)
+	}
+	s.Equal(int32(3), granted.Load())
+	s.Equal(int32(senders-3), refused.Load())
+	quota, err := svc.Quota(ctx, s.group.ID, 10)
+	s.Require().NoError(err)
+	s.Equal(3, quota.Used)
+}
+
+func (s *QuotaSuite) TestReleaseReturnsAFailedWhisper() {
+	ctx := context.Background()
+	svc := whispersvc.New(s.DB)
+	allowance := 1
+	_, err := svc.SetAllowance(ctx, s.group.ID, &allowance, whispersvc.ScopePlayer)
+	s.Require().NoError(err)
+
+	reservation, err := svc.Reserve(ctx, s.group.ID, 10)
+	s.Require().NoError(err)
+	s.Equal(0, reservation.Quota.Remaining(), "the reservation counts straight away")
+	_, err = svc.Reserve(ctx, s.group.ID, 10)
+	s.Require().ErrorIs(err, whispersvc.ErrQuotaExhausted)
+
+	s.Require().NoError(svc.Release(ctx, reservation))
+	quota, err := svc.Quota(ctx, s.group.ID, 10)
+	s.Require().NoError(err)
+	s.Equal(1, quota.Remaining())
+}
+
+func TestQuotaSuite(t *testing.T) {
+	suite.Run(t, new(QuotaSuite))
+}
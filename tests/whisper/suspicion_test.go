@@ -0,0 +1,65 @@
+package whisper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/models"
+	whispersvc "github.com/mccune1224/betrayal/internal/services/whisper"
+	"github.com/mccune1224/betrayal/tests/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+// SuspicionSuite checks per-group doubt settings and pools against the LOCAL
+// database.
+type SuspicionSuite struct {
+	suite.Suite
+	DB *pgxpool.Pool
+}
+
+func (s *SuspicionSuite) SetupSuite() {
+	s.DB = testutil.NewTestPool(s.T())
+}
+
+func (s *SuspicionSuite) SetupTest() {
+	testutil.TruncateAll(s.T(), s.DB)
+}
+
+func (s *SuspicionSuite) TestGroupPoolReplacesGlobalPool() {
+	ctx := context.Background()
+	q := models.New(s.DB)
+	svc := whispersvc.New(s.DB)
+	cursed, err := q.CreateWhisperGroup(ctx, "cursed triplet")
+	s.Require().NoError(err)
+	plain, err := q.CreateWhisperGroup(ctx, "twins")
+	s.Require().NoError(err)
+	_, err = q.CreateWhisperDoubtMessage(ctx, models.CreateWhisperDoubtMessageParams{Message: "global doubt"})
+	s.Require().NoError(err)
+	_, err = q.CreateWhisperDoubtMessage(ctx, models.CreateWhisperDoubtMessageParams{Message: "cursed doubt", GroupID: pgtype.Int8{Int64: cursed.ID, Valid: true}})
+	s.Require().NoError(err)
+
+	suspicion, pool, err := svc.Doubt(ctx, plain.ID)
+	s.Require().NoError(err)
+	s.Equal(whispersvc.DefaultSuspicion, suspicion)
+	s.Equal([]string{"global doubt"}, pool)
+
+	chance := 0.5
+	_, err = svc.SetSuspicion(ctx, cursed.ID, &chance, whispersvc.DoubtGarble)
+	s.Require().NoError(err)
+	suspicion, pool, err = svc.Doubt(ctx, cursed.ID)
+	s.Require().NoError(err)
+	s.Equal(whispersvc.Suspicion{Chance: 0.5, Mode: whispersvc.DoubtGarble}, suspicion)
+	s.Equal([]string{"cursed doubt"}, pool)
+
+	_, err = svc.SetSuspicion(ctx, cursed.ID, nil, whispersvc.DoubtReplace)
+	s.Require().NoError(err)
+	suspicion, _, err = svc.Doubt(ctx, cursed.ID)
+	s.Require().NoError(err)
+	s.Equal(whispersvc.DefaultSuspicion, suspicion, "a nil chance restores the global default")
+}
+
+func TestSuspicionSuite(t *testing.T) {
+	suite.Run(t, new(SuspicionSuite))
+}
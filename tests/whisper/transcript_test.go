@@ -0,0 +1,73 @@
+package whisper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/mccune1224/betrayal/internal/models"
+	whispersvc "github.com/mccune1224/betrayal/internal/services/whisper"
+	"github.com/mccune1224/betrayal/tests/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+// TranscriptSuite checks transcript storage and its group/day filters against
+// the LOCAL database.
+type TranscriptSuite struct {
+	suite.Suite
+	DB *pgxpool.Pool
+}
+
+func (s *TranscriptSuite) SetupSuite() {
+	s.DB = testutil.NewTestPool(s.T())
+}
+
+func (s *TranscriptSuite) SetupTest() {
+	testutil.TruncateAll(s.T(), s.DB)
+}
+
+func (s *TranscriptSuite) record(groupID int64, day int32, warning bool) {
+	_, err := whispersvc.New(s.DB).RecordTranscript(context.Background(), whispersvc.TranscriptEntry{
+		SenderID:         10,
+		GroupID:          groupID,
+		GroupName:        "twins",
+		OriginalMessage:  "The door is open.",
+		DeliveredMessage: "Keep your guard up.",
+		WarningSent:      warning,
+		RecipientIDs:     []int64{11, 12},
+		DeadRecipients:   1,
+		Cycle:            models.GameCycle{Day: day, IsElimination: true},
+	})
+	s.Require().NoError(err)
+}
+
+func (s *TranscriptSuite) TestFiltersByGroupAndDay() {
+	ctx := context.Background()
+	svc := whispersvc.New(s.DB)
+	s.record(1, 1, false)
+	s.record(1, 2, true)
+	s.record(2, 2, false)
+
+	all, err := svc.Transcripts(ctx, whispersvc.TranscriptFilter{})
+	s.Require().NoError(err)
+	s.Len(all, 3)
+	s.Equal([]int64{11, 12}, all[0].RecipientIds)
+	s.Equal(int32(1), all[0].DeadRecipients)
+	s.True(all[0].CycleIsElimination)
+
+	group := int64(1)
+	day := int32(2)
+	filtered, err := svc.Transcripts(ctx, whispersvc.TranscriptFilter{GroupID: &group, CycleDay: &day})
+	s.Require().NoError(err)
+	s.Require().Len(filtered, 1)
+	s.True(filtered[0].WarningSent)
+	s.Equal("Keep your guard up.", filtered[0].DeliveredMessage)
+
+	limited, err := svc.Transcripts(ctx, whispersvc.TranscriptFilter{Limit: 2})
+	s.Require().NoError(err)
+	s.Len(limited, 2)
+}
+
+func TestTranscriptSuite(t *testing.T) {
+	suite.Run(t, new(TranscriptSuite))
+}